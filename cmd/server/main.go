@@ -20,6 +20,12 @@ import (
 )
 
 func main() {
+	// "server mcp-stdio" serves MCP over stdin and stdout instead of HTTP
+	if len(os.Args) > 1 && os.Args[1] == "mcp-stdio" {
+		runMCPStdio(os.Args[2:])
+		return
+	}
+
 	var configPath string
 	flag.StringVar(&configPath, "config", "", "Path to optional config file (env vars take precedence)")
 	flag.Parse()
@@ -40,36 +46,18 @@ func main() {
 	}
 
 	// Initialize embedding provider and worker
-	var embeddingProvider embeddings.EmbeddingProvider
-	switch cfg.Embedding.Provider {
-	case "azure_openai":
-		provider, err := embeddings.NewAzureOpenAIEmbeddingProvider(
-			cfg.Embedding.AzureEndpoint,
-			cfg.Embedding.AzureAPIKey,
-			cfg.Embedding.DeploymentName,
-		)
-		if err != nil {
-			log.Printf("Failed to initialize Azure OpenAI embeddings, using local: %v", err)
-			embeddingProvider = embeddings.NewLocalEmbeddingProvider("all-MiniLM-L6-v2", "")
-		} else {
-			embeddingProvider = provider
-		}
-	case "openai":
-		embeddingProvider = embeddings.NewOpenAIEmbeddingProvider(cfg.Embedding.AzureAPIKey)
-	default:
-		embeddingProvider = embeddings.NewLocalEmbeddingProvider("all-MiniLM-L6-v2", "")
-	}
+	embeddingProvider := newEmbeddingProvider(cfg)
 
 	// Initialize vector service and worker
 	vectorService := service.NewVectorService(db, embeddingProvider)
 	embeddingWorker := service.InitializeEmbeddingWorker(vectorService)
 
-	// Set up embedding callback for database operations
-	db.SetEmbeddingCallback(func(entityType string, entityID uint) {
-		if embeddingWorker != nil {
-			embeddingWorker.QueueJob(entityType, entityID)
-		}
-	})
+	// Subscribe the background services to domain events
+	service.RegisterNotificationSubscriber(db.Events(), db)
+	service.RegisterWebhookSubscriber(db.Events(), db)
+	service.RegisterEmbeddingSubscriber(db.Events(), db, embeddingWorker)
+	changeFeed := service.InitializeChangeFeed(db.Events())
+	webhookDispatcher := service.NewWebhookDispatcher(db, 10*time.Second)
 
 	router := gin.Default()
 	router.RedirectTrailingSlash = false
@@ -90,8 +78,12 @@ func main() {
 	apiHandler := api.NewHandler(db, fileStorage)
 	webHandler := api.NewWebHandler(db)
 	tokenHandler := api.NewTokenHandler(db)
+	notificationHandler := api.NewNotificationHandler(db)
+	webhookHandler := api.NewWebhookHandler(db, webhookDispatcher)
+	promptHandler := api.NewPromptHandler(db)
+	streamHandler := api.NewStreamHandler(changeFeed)
 	// Use enhanced MCP server with all features
-	mcpServer := mcp.NewEnhancedMCPServer(db, embeddingProvider, embeddingWorker)
+	mcpServer := mcp.NewEnhancedMCPServer(db, embeddingProvider, embeddingWorker, webhookDispatcher)
 
 	// Serve static files (CSS)
 	router.Static("/static", "./web/dist")
@@ -100,10 +92,19 @@ func main() {
 	router.GET("/", webHandler.ProjectsPage)
 	router.GET("/projects/:projectId", webHandler.ProjectOverviewPage)
 	router.GET("/projects/:projectId/tasks", webHandler.ProjectBoardPage)
+	router.GET("/projects/:projectId/stream", streamHandler.ProjectStream)
 	router.GET("/projects/:projectId/epics", webHandler.EpicsPage)
 	router.GET("/projects/:projectId/epics/:epicId", webHandler.EpicDetailPage)
 	router.GET("/projects/:projectId/tasks/:taskId", webHandler.TaskDetailPage)
 	router.GET("/projects/:projectId/archived", webHandler.ArchivedTasksPage)
+	router.GET("/projects/:projectId/sprints/:sprintId", webHandler.ProjectBoardPage)
+
+	// Inboxes are private: callers only see their own, admins anyone's
+	userPages := router.Group("/users/:userId", auth.AuthMiddleware(db))
+	{
+		userPages.GET("/notifications", webHandler.NotificationsPage)
+		userPages.GET("/mentions", webHandler.MentionsPage)
+	}
 
 	api.SetupExtendedRouter(router, db, vectorService)
 
@@ -125,6 +126,27 @@ func main() {
 			tokens.GET("/:id", tokenHandler.GetAPIToken)
 			tokens.DELETE("/:id", tokenHandler.RevokeAPIToken)
 		}
+
+		// Agents are users acting only through API tokens
+		agents := adminGroup.Group("/agents")
+		{
+			agents.GET("", tokenHandler.ListAgents)
+			agents.POST("", tokenHandler.CreateAgent)
+		}
+
+		webhooks := adminGroup.Group("/webhooks")
+		{
+			webhooks.GET("", webhookHandler.ListWebhooks)
+			webhooks.POST("", webhookHandler.CreateWebhook)
+			webhooks.GET("/events", webhookHandler.ListWebhookEvents)
+			webhooks.GET("/:id", webhookHandler.GetWebhook)
+			webhooks.PUT("/:id", webhookHandler.UpdateWebhook)
+			webhooks.DELETE("/:id", webhookHandler.DeleteWebhook)
+			webhooks.POST("/:id/ping", webhookHandler.PingWebhook)
+			webhooks.GET("/:id/deliveries", webhookHandler.ListDeliveries)
+			webhooks.GET("/:id/deliveries/:deliveryId", webhookHandler.GetDelivery)
+			webhooks.POST("/:id/deliveries/:deliveryId/redeliver", webhookHandler.RedeliverDelivery)
+		}
 	}
 
 	// API endpoints (require authentication)
@@ -150,14 +172,58 @@ func main() {
 				projectScope.GET("/tasks/:task_id", apiHandler.GetProjectTask)
 				projectScope.PUT("/tasks/:task_id", apiHandler.UpdateProjectTask)
 
+				// Claim the next available task, leased to the caller
+				projectScope.POST("/tasks/claim", apiHandler.ClaimProjectTask)
+
+				// Atomic import of epics, tasks, subtasks and dependencies
+				projectScope.POST("/plan", apiHandler.ImportProjectPlan)
+
 				// Project labels
 				projectScope.GET("/labels", apiHandler.ListProjectLabels)
 
 				// Project users
 				projectScope.GET("/users", apiHandler.ListProjectUsers)
+
+				// Activity digest of the project
+				projectScope.GET("/digest", apiHandler.GetActivityDigest)
 			}
 		}
 
+		// Notification inbox of the authenticated user
+		notifications := apiGroup.Group("/notifications")
+		{
+			notifications.GET("", notificationHandler.ListNotifications)
+			notifications.GET("/unread-count", notificationHandler.GetUnreadCount)
+			notifications.POST("/read", notificationHandler.MarkRead)
+			notifications.POST("/:id/unread", notificationHandler.MarkUnread)
+			notifications.DELETE("/:id", notificationHandler.DeleteNotification)
+			notifications.GET("/preferences", notificationHandler.GetPreferences)
+			notifications.PUT("/preferences", notificationHandler.UpdatePreferences)
+		}
+
+		// Workflow prompts, also served to MCP clients
+		prompts := apiGroup.Group("/prompts")
+		{
+			prompts.GET("", promptHandler.ListPrompts)
+			prompts.POST("/:name/render", promptHandler.RenderPrompt)
+		}
+
+		promptTemplates := apiGroup.Group("/prompt-templates")
+		{
+			promptTemplates.GET("", promptHandler.ListPromptTemplates)
+			promptTemplates.POST("", promptHandler.CreatePromptTemplate)
+			promptTemplates.GET("/:id", promptHandler.GetPromptTemplate)
+			promptTemplates.PUT("/:id", promptHandler.UpdatePromptTemplate)
+			promptTemplates.DELETE("/:id", promptHandler.DeletePromptTemplate)
+		}
+
+		// Activity digest across projects (add ?format=markdown for a report)
+		apiGroup.GET("/digest", apiHandler.GetActivityDigest)
+
+		// Real-time change feed
+		apiGroup.GET("/stream", streamHandler.Stream)
+		apiGroup.GET("/stream/ws", streamHandler.StreamWebSocket)
+
 		// Legacy task endpoints (kept for backward compatibility)
 		tasks := apiGroup.Group("/tasks")
 		{
@@ -168,6 +234,8 @@ func main() {
 			tasks.DELETE("/:id", apiHandler.DeleteTask)
 			tasks.POST("/:id/comments", apiHandler.AddComment)
 			tasks.POST("/:id/attachments", apiHandler.UploadAttachment)
+			tasks.POST("/:id/heartbeat", apiHandler.HeartbeatTask)
+			tasks.POST("/:id/release", apiHandler.ReleaseTask)
 		}
 	}
 
@@ -193,6 +261,7 @@ func main() {
 			"endpoints": gin.H{
 				"api":    "/api",
 				"mcp":    "/mcp",
+				"stream": "/api/stream",
 				"health": "/health",
 			},
 		})
@@ -205,7 +274,42 @@ func main() {
 	log.Printf("Admin endpoints: http://%s/admin (requires admin token)", addr)
 	log.Printf("Web UI: http://%s (no authentication)", addr)
 
+	// Notify assignees about tasks due within a day
+	dueSoonNotifier := service.NewDueSoonNotifier(db, 15*time.Minute, 24*time.Hour)
+	dueSoonNotifier.Start()
+	defer dueSoonNotifier.Stop()
+
+	// Return claimed tasks whose agents stopped sending heartbeats to the pool
+	leaseReaper := service.NewLeaseReaper(db, 30*time.Second)
+	leaseReaper.Start()
+	defer leaseReaper.Stop()
+
+	// Send queued webhook deliveries and retry failed ones
+	webhookDispatcher.Start()
+	defer webhookDispatcher.Stop()
+
 	if err := router.Run(addr); err != nil {
 		log.Fatalf("Failed to start server: %v", err)
 	}
+}
+
+// newEmbeddingProvider selects the embedding provider configured for semantic search
+func newEmbeddingProvider(cfg *config.Config) embeddings.EmbeddingProvider {
+	switch cfg.Embedding.Provider {
+	case "azure_openai":
+		provider, err := embeddings.NewAzureOpenAIEmbeddingProvider(
+			cfg.Embedding.AzureEndpoint,
+			cfg.Embedding.AzureAPIKey,
+			cfg.Embedding.DeploymentName,
+		)
+		if err != nil {
+			log.Printf("Failed to initialize Azure OpenAI embeddings, using local: %v", err)
+			return embeddings.NewLocalEmbeddingProvider("all-MiniLM-L6-v2", "")
+		}
+		return provider
+	case "openai":
+		return embeddings.NewOpenAIEmbeddingProvider(cfg.Embedding.AzureAPIKey)
+	default:
+		return embeddings.NewLocalEmbeddingProvider("all-MiniLM-L6-v2", "")
+	}
 }
\ No newline at end of file
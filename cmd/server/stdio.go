@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/headless-pm/headless-project-management/internal/database"
+	"github.com/headless-pm/headless-project-management/internal/mcp"
+	"github.com/headless-pm/headless-project-management/internal/service"
+	"github.com/headless-pm/headless-project-management/pkg/config"
+	"gorm.io/gorm/logger"
+)
+
+// runMCPStdio serves MCP as newline-delimited JSON-RPC on stdin and stdout, for
+// agent tools that launch the server as a subprocess. It works on the local
+// database, or with -remote proxies to a running server. Logs go to stderr.
+func runMCPStdio(args []string) {
+	flags := flag.NewFlagSet("mcp-stdio", flag.ExitOnError)
+	configPath := flags.String("config", "", "Path to optional config file (env vars take precedence)")
+	remote := flags.String("remote", os.Getenv("MCP_REMOTE_URL"), "MCP endpoint of a remote server to proxy to, e.g. https://pm.example.com/mcp")
+	token := flags.String("token", os.Getenv("MCP_API_TOKEN"), "API token for the remote server")
+	username := flags.String("user", os.Getenv("MCP_USER"), "Local mode: username the session acts as")
+	flags.Parse(args)
+
+	log.SetOutput(os.Stderr)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var serve func(ctx context.Context) error
+	if *remote != "" {
+		if *token == "" {
+			log.Fatal("mcp-stdio: -token (or MCP_API_TOKEN) is required with -remote")
+		}
+		proxy := mcp.NewStdioProxy(*remote, *token)
+		serve = func(ctx context.Context) error {
+			return proxy.Serve(ctx, os.Stdin, os.Stdout)
+		}
+	} else {
+		server, userID := newLocalMCPServer(*configPath, *username)
+		serve = func(ctx context.Context) error {
+			return server.ServeStdio(ctx, os.Stdin, os.Stdout, userID)
+		}
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- serve(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			log.Fatalf("mcp-stdio: %v", err)
+		}
+	case <-ctx.Done():
+	}
+}
+
+// newLocalMCPServer opens the local database for mcp-stdio. SQL logging goes
+// to stderr since stdout carries the protocol.
+func newLocalMCPServer(configPath, username string) (*mcp.EnhancedMCPServer, *uint) {
+	cfg, err := config.LoadConfig(configPath)
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	sqlLogger := logger.New(log.New(os.Stderr, "", log.LstdFlags), logger.Config{
+		SlowThreshold: 200 * time.Millisecond,
+		LogLevel:      logger.Warn,
+	})
+	db, err := database.NewDatabaseWithLogger(cfg.Database.DataDir, sqlLogger)
+	if err != nil {
+		log.Fatalf("Failed to initialize database: %v", err)
+	}
+
+	var userID *uint
+	if username != "" {
+		user, err := db.GetUserByUsername(username)
+		if err != nil {
+			log.Fatalf("mcp-stdio: user %q not found", username)
+		}
+		userID = &user.ID
+	}
+
+	embeddingProvider := newEmbeddingProvider(cfg)
+	vectorService := service.NewVectorService(db, embeddingProvider)
+	embeddingWorker := service.InitializeEmbeddingWorker(vectorService)
+
+	// Same side effects as the HTTP server; queued webhook deliveries are sent
+	// by the server's dispatcher
+	service.RegisterNotificationSubscriber(db.Events(), db)
+	service.RegisterWebhookSubscriber(db.Events(), db)
+	service.RegisterEmbeddingSubscriber(db.Events(), db, embeddingWorker)
+
+	return mcp.NewEnhancedMCPServer(db, embeddingProvider, embeddingWorker, nil), userID
+}
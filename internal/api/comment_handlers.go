@@ -0,0 +1,255 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/headless-pm/headless-project-management/internal/database"
+	"github.com/headless-pm/headless-project-management/internal/models"
+)
+
+type CommentHandler struct {
+	db *database.Database
+}
+
+func NewCommentHandler(db *database.Database) *CommentHandler {
+	return &CommentHandler{db: db}
+}
+
+func (h *CommentHandler) CreateComment(c *gin.Context) {
+	var comment models.Comment
+	if err := c.ShouldBindJSON(&comment); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := requestDB(c, h.db).AddComment(&comment); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, comment)
+}
+
+// ListTaskComments lists the comments of a task; ?threaded=true nests replies under their parent
+func (h *CommentHandler) ListTaskComments(c *gin.Context) {
+	taskID, err := strconv.ParseUint(c.Param("taskId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid task ID"})
+		return
+	}
+
+	comments, err := h.db.ListComments(uint(taskID), c.Query("threaded") == "true")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get comments"})
+		return
+	}
+
+	c.JSON(http.StatusOK, comments)
+}
+
+func (h *CommentHandler) GetComment(c *gin.Context) {
+	commentID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid comment ID"})
+		return
+	}
+
+	comment, err := h.db.GetCommentWithDetails(uint(commentID))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Comment not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, comment)
+}
+
+func (h *CommentHandler) UpdateComment(c *gin.Context) {
+	commentID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid comment ID"})
+		return
+	}
+
+	var req struct {
+		Content  string `json:"content" binding:"required"`
+		EditorID *uint  `json:"editor_id"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	comment, err := h.db.GetComment(uint(commentID))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Comment not found"})
+		return
+	}
+	if !canChangeComment(c, comment) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the author of a comment or an admin can change it"})
+		return
+	}
+
+	if err := requestDB(c, h.db).EditComment(uint(commentID), req.Content, req.EditorID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	comment, _ = h.db.GetCommentWithDetails(uint(commentID))
+	c.JSON(http.StatusOK, comment)
+}
+
+func (h *CommentHandler) DeleteComment(c *gin.Context) {
+	commentID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid comment ID"})
+		return
+	}
+
+	comment, err := h.db.GetComment(uint(commentID))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Comment not found"})
+		return
+	}
+	if !canChangeComment(c, comment) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the author of a comment or an admin can change it"})
+		return
+	}
+
+	if err := requestDB(c, h.db).DeleteComment(uint(commentID)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete comment"})
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// canChangeComment reports whether the caller may edit or delete a comment:
+// its author, or an admin
+func canChangeComment(c *gin.Context, comment *models.Comment) bool {
+	var authorID uint
+	if comment.AuthorID != nil {
+		authorID = *comment.AuthorID
+	}
+	return canActFor(c, authorID)
+}
+
+// ReplyToComment adds a reply on the task of the parent comment
+func (h *CommentHandler) ReplyToComment(c *gin.Context) {
+	parentID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid comment ID"})
+		return
+	}
+
+	var reply models.Comment
+	if err := c.ShouldBindJSON(&reply); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	id := uint(parentID)
+	reply.ParentID = &id
+	reply.TaskID = 0
+	if err := requestDB(c, h.db).AddComment(&reply); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, reply)
+}
+
+func (h *CommentHandler) GetCommentHistory(c *gin.Context) {
+	commentID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid comment ID"})
+		return
+	}
+
+	revisions, err := h.db.GetCommentHistory(uint(commentID))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Comment not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, revisions)
+}
+
+func (h *CommentHandler) AddReaction(c *gin.Context) {
+	commentID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid comment ID"})
+		return
+	}
+
+	var req struct {
+		UserID uint   `json:"user_id" binding:"required"`
+		Emoji  string `json:"emoji" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	reaction, err := h.db.AddReaction(uint(commentID), req.UserID, req.Emoji)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, reaction)
+}
+
+// RemoveReaction removes the reaction given by ?user_id= and ?emoji=
+func (h *CommentHandler) RemoveReaction(c *gin.Context) {
+	commentID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid comment ID"})
+		return
+	}
+
+	userID, err := strconv.ParseUint(c.Query("user_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	emoji := c.Query("emoji")
+	if emoji == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "emoji is required"})
+		return
+	}
+
+	if err := h.db.RemoveReaction(uint(commentID), uint(userID), emoji); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to remove reaction"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Reaction removed"})
+}
+
+// AttachToComment links attachments uploaded to the task to the comment
+func (h *CommentHandler) AttachToComment(c *gin.Context) {
+	commentID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid comment ID"})
+		return
+	}
+
+	var req struct {
+		AttachmentIDs []uint `json:"attachment_ids" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.db.AttachToComment(uint(commentID), req.AttachmentIDs); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	comment, _ := h.db.GetCommentWithDetails(uint(commentID))
+	c.JSON(http.StatusOK, comment)
+}
@@ -0,0 +1,50 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/headless-pm/headless-project-management/internal/service"
+	"gorm.io/gorm"
+)
+
+// runDeletion serves the two-step delete endpoints. With ?dry_run=true it
+// reports the impact and a confirmation token; otherwise it deletes when the
+// token is passed as ?confirm= or in the X-Confirmation-Token header.
+func runDeletion(c *gin.Context, deletions *service.DeletionService, target service.DeletionTarget) {
+	deletions = deletions.WithContext(c.Request.Context())
+	if dryRun, _ := strconv.ParseBool(c.Query("dry_run")); dryRun {
+		plan, err := deletions.Preview(target)
+		if err != nil {
+			c.JSON(deletionErrorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, plan)
+		return
+	}
+
+	token := c.Query("confirm")
+	if token == "" {
+		token = c.GetHeader("X-Confirmation-Token")
+	}
+	if _, err := deletions.Delete(target, token); err != nil {
+		c.JSON(deletionErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+func deletionErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrConfirmationRequired):
+		return http.StatusPreconditionRequired
+	case errors.Is(err, service.ErrInvalidConfirmation), errors.Is(err, service.ErrDeletionChanged):
+		return http.StatusPreconditionFailed
+	}
+	return http.StatusInternalServerError
+}
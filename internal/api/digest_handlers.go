@@ -0,0 +1,83 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/headless-pm/headless-project-management/internal/database"
+	"github.com/headless-pm/headless-project-management/internal/models"
+	"github.com/headless-pm/headless-project-management/internal/service"
+)
+
+// GetActivityDigest summarises the activity of a period per user, for
+// standups. The project comes from the path or the query string; since and
+// until take a date or a duration ago like 24h or 7d. Use format=markdown to
+// get a report ready to post.
+func (h *Handler) GetActivityDigest(c *gin.Context) {
+	var filter database.DigestFilter
+
+	if c.Param("project") != "" {
+		projectID, err := h.getProjectIDFromParam(c)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		filter.ProjectID = &projectID
+	} else if projectParam := c.Query("project_id"); projectParam != "" {
+		id, err := strconv.ParseUint(projectParam, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
+			return
+		}
+		pid := uint(id)
+		filter.ProjectID = &pid
+	}
+
+	if userParam := c.Query("user_id"); userParam != "" {
+		id, err := strconv.ParseUint(userParam, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+			return
+		}
+		uid := uint(id)
+		filter.UserID = &uid
+	}
+
+	switch userType := models.UserType(c.Query("user_type")); userType {
+	case "", models.UserTypeHuman, models.UserTypeAgent:
+		filter.UserType = userType
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user type, expected human or agent"})
+		return
+	}
+
+	now := time.Now()
+	var err error
+	if filter.From, err = service.ParseRelativeTime(c.DefaultQuery("since", "24h"), now); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if filter.To, err = service.ParseRelativeTime(c.Query("until"), now); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	digest, err := requestDB(c, h.db).GetActivityDigest(filter)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if c.Query("format") == "markdown" {
+		c.Header("Content-Type", "text/markdown; charset=utf-8")
+		c.Status(http.StatusOK)
+		if err := digest.WriteMarkdown(c.Writer); err != nil {
+			c.Error(err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, digest)
+}
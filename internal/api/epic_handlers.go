@@ -7,14 +7,16 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/headless-pm/headless-project-management/internal/database"
 	"github.com/headless-pm/headless-project-management/internal/models"
+	"github.com/headless-pm/headless-project-management/internal/service"
 )
 
 type EpicHandler struct {
-	db *database.Database
+	db        *database.Database
+	deletions *service.DeletionService
 }
 
 func NewEpicHandler(db *database.Database) *EpicHandler {
-	return &EpicHandler{db: db}
+	return &EpicHandler{db: db, deletions: service.NewDeletionService(db)}
 }
 
 func (h *EpicHandler) CreateEpic(c *gin.Context) {
@@ -24,7 +26,7 @@ func (h *EpicHandler) CreateEpic(c *gin.Context) {
 		return
 	}
 
-	if err := h.db.CreateEpic(&epic); err != nil {
+	if err := requestDB(c, h.db).CreateEpic(&epic); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -102,7 +104,7 @@ func (h *EpicHandler) UpdateEpic(c *gin.Context) {
 	}
 
 	epic.ID = uint(id)
-	if err := h.db.UpdateEpic(&epic); err != nil {
+	if err := requestDB(c, h.db).UpdateEpic(&epic); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -117,12 +119,9 @@ func (h *EpicHandler) DeleteEpic(c *gin.Context) {
 		return
 	}
 
-	if err := h.db.DeleteEpic(uint(id)); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-
-	c.JSON(http.StatusNoContent, nil)
+	// ?cascade=true deletes the tasks of the epic instead of unlinking them
+	cascade, _ := strconv.ParseBool(c.Query("cascade"))
+	runDeletion(c, h.deletions, service.DeletionTarget{Entity: "epic", ID: uint(id), Cascade: cascade})
 }
 
 func (h *EpicHandler) GetProjectEpics(c *gin.Context) {
@@ -164,7 +163,7 @@ func (h *EpicHandler) AssignTaskToEpic(c *gin.Context) {
 		return
 	}
 
-	if err := h.db.AssignTaskToEpic(req.TaskID, uint(epicID)); err != nil {
+	if err := requestDB(c, h.db).AssignTaskToEpic(req.TaskID, uint(epicID)); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -185,7 +184,7 @@ func (h *EpicHandler) RemoveTaskFromEpic(c *gin.Context) {
 		return
 	}
 
-	if err := h.db.RemoveTaskFromEpic(uint(taskID)); err != nil {
+	if err := requestDB(c, h.db).RemoveTaskFromEpic(uint(taskID)); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
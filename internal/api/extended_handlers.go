@@ -40,8 +40,8 @@ func (h *ExtendedHandler) AddTaskDependency(c *gin.Context) {
 		dependency.Type = "finish_to_start"
 	}
 
-	if err := h.db.Create(&dependency).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add dependency"})
+	if err := requestDB(c, h.db).CreateTaskDependency(&dependency); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
@@ -59,4 +59,41 @@ func (h *ExtendedHandler) GetTaskDependencies(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, dependencies)
+}
+
+func (h *ExtendedHandler) GetTaskMentions(c *gin.Context) {
+	taskID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid task ID"})
+		return
+	}
+
+	mentions, err := h.db.GetTaskMentions(uint(taskID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get mentions"})
+		return
+	}
+
+	c.JSON(http.StatusOK, mentions)
+}
+
+// GetUserMentions lists the tasks that mention a user; only the user and admins can see them
+func (h *ExtendedHandler) GetUserMentions(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+	if !canActFor(c, uint(userID)) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You can only view your own mentions"})
+		return
+	}
+
+	tasks, err := h.db.GetTasksMentioningUser(uint(userID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get mentions"})
+		return
+	}
+
+	c.JSON(http.StatusOK, tasks)
 }
\ No newline at end of file
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -13,17 +14,25 @@ import (
 )
 
 type Handler struct {
-	db      *database.Database
-	storage *storage.FileStorage
+	db        *database.Database
+	storage   *storage.FileStorage
+	deletions *service.DeletionService
 }
 
 func NewHandler(db *database.Database, storage *storage.FileStorage) *Handler {
 	return &Handler{
-		db:      db,
-		storage: storage,
+		db:        db,
+		storage:   storage,
+		deletions: service.NewDeletionService(db),
 	}
 }
 
+// requestDB binds the database to the request, so writes are attributed to the
+// authenticated caller the auth middleware put in its context
+func requestDB(c *gin.Context, db *database.Database) *database.Database {
+	return db.WithContext(c.Request.Context())
+}
+
 func (h *Handler) CreateProject(c *gin.Context) {
 	var project models.Project
 	if err := c.ShouldBindJSON(&project); err != nil {
@@ -31,16 +40,11 @@ func (h *Handler) CreateProject(c *gin.Context) {
 		return
 	}
 
-	if err := h.db.CreateProject(&project); err != nil {
+	if err := requestDB(c, h.db).CreateProject(&project); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create project"})
 		return
 	}
 
-	// Queue embedding generation
-	if worker := service.GetEmbeddingWorker(); worker != nil {
-		worker.QueueJob("project", project.ID)
-	}
-
 	c.JSON(http.StatusCreated, project)
 }
 
@@ -109,11 +113,6 @@ func (h *Handler) UpdateProject(c *gin.Context) {
 		return
 	}
 
-	// Queue embedding regeneration
-	if worker := service.GetEmbeddingWorker(); worker != nil {
-		worker.QueueJob("project", project.ID)
-	}
-
 	c.JSON(http.StatusOK, project)
 }
 
@@ -124,12 +123,7 @@ func (h *Handler) DeleteProject(c *gin.Context) {
 		return
 	}
 
-	if err := h.db.DeleteProject(projectID); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete project"})
-		return
-	}
-
-	c.JSON(http.StatusNoContent, nil)
+	runDeletion(c, h.deletions, service.DeletionTarget{Entity: "project", ID: projectID})
 }
 
 func (h *Handler) CreateTask(c *gin.Context) {
@@ -143,24 +137,19 @@ func (h *Handler) CreateTask(c *gin.Context) {
 	}
 
 	task := input.Task
-	if err := h.db.CreateTask(&task); err != nil {
+	if err := requestDB(c, h.db).CreateTask(&task); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create task"})
 		return
 	}
 
 	// Handle labels if provided
 	if len(input.Labels) > 0 {
-		if err := h.db.AssignLabelsToTask(task.ID, task.ProjectID, input.Labels); err != nil {
+		if err := requestDB(c, h.db).AssignLabelsToTask(task.ID, task.ProjectID, input.Labels); err != nil {
 			// Log error but don't fail the request
 			_ = err
 		}
 	}
 
-	// Queue embedding generation
-	if worker := service.GetEmbeddingWorker(); worker != nil {
-		worker.QueueJob("task", task.ID)
-	}
-
 	// Reload task with labels
 	taskWithLabels, _ := h.db.GetTask(task.ID)
 	if taskWithLabels != nil {
@@ -253,24 +242,19 @@ func (h *Handler) CreateProjectTask(c *gin.Context) {
 	task := input.Task
 	task.ProjectID = projectID
 
-	if err := h.db.CreateTask(&task); err != nil {
+	if err := requestDB(c, h.db).CreateTask(&task); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create task"})
 		return
 	}
 
 	// Handle labels if provided
 	if len(input.Labels) > 0 {
-		if err := h.db.AssignLabelsToTask(task.ID, task.ProjectID, input.Labels); err != nil {
+		if err := requestDB(c, h.db).AssignLabelsToTask(task.ID, task.ProjectID, input.Labels); err != nil {
 			// Log error but don't fail the request
 			_ = err
 		}
 	}
 
-	// Queue embedding generation
-	if worker := service.GetEmbeddingWorker(); worker != nil {
-		worker.QueueJob("task", task.ID)
-	}
-
 	// Reload task with labels
 	taskWithLabels, _ := h.db.GetTask(task.ID)
 	if taskWithLabels != nil {
@@ -280,6 +264,41 @@ func (h *Handler) CreateProjectTask(c *gin.Context) {
 	}
 }
 
+// ImportProjectPlan creates a nested plan of epics, tasks, subtasks, labels and
+// dependencies in one transaction. With ?dry_run=true the plan is only validated.
+func (h *Handler) ImportProjectPlan(c *gin.Context) {
+	projectID, err := h.getProjectIDFromParam(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var plan database.Plan
+	if err := c.ShouldBindJSON(&plan); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	plan.ProjectID = projectID
+
+	dryRun, _ := strconv.ParseBool(c.Query("dry_run"))
+	result, err := requestDB(c, h.db).ImportPlan(&plan, dryRun)
+	if err != nil {
+		var planErr *database.PlanError
+		if errors.As(err, &planErr) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": planErr.Error(), "issues": planErr.Issues})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if dryRun {
+		c.JSON(http.StatusOK, result)
+		return
+	}
+	c.JSON(http.StatusCreated, result)
+}
+
 func (h *Handler) UpdateProjectTask(c *gin.Context) {
 	projectID, err := h.getProjectIDFromParam(c)
 	if err != nil {
@@ -318,24 +337,19 @@ func (h *Handler) UpdateProjectTask(c *gin.Context) {
 	task.ID = uint(taskID)
 	task.ProjectID = projectID
 
-	if err := h.db.UpdateTask(&task); err != nil {
+	if err := requestDB(c, h.db).UpdateTask(&task); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update task"})
 		return
 	}
 
 	// Handle labels if provided
 	if input.Labels != nil {
-		if err := h.db.AssignLabelsToTask(task.ID, projectID, input.Labels); err != nil {
+		if err := requestDB(c, h.db).AssignLabelsToTask(task.ID, projectID, input.Labels); err != nil {
 			// Log error but don't fail the request
 			_ = err
 		}
 	}
 
-	// Queue embedding regeneration
-	if worker := service.GetEmbeddingWorker(); worker != nil {
-		worker.QueueJob("task", task.ID)
-	}
-
 	// Reload task with labels
 	taskWithLabels, _ := h.db.GetTask(task.ID)
 	if taskWithLabels != nil {
@@ -398,24 +412,19 @@ func (h *Handler) UpdateTask(c *gin.Context) {
 		return
 	}
 
-	if err := h.db.UpdateTask(&task); err != nil {
+	if err := requestDB(c, h.db).UpdateTask(&task); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update task"})
 		return
 	}
 
 	// Handle labels if provided
 	if input.Labels != nil {
-		if err := h.db.AssignLabelsToTask(task.ID, existingTask.ProjectID, input.Labels); err != nil {
+		if err := requestDB(c, h.db).AssignLabelsToTask(task.ID, existingTask.ProjectID, input.Labels); err != nil {
 			// Log error but don't fail the request
 			_ = err
 		}
 	}
 
-	// Queue embedding regeneration
-	if worker := service.GetEmbeddingWorker(); worker != nil {
-		worker.QueueJob("task", task.ID)
-	}
-
 	// Reload task with labels
 	taskWithLabels, _ := h.db.GetTask(task.ID)
 	if taskWithLabels != nil {
@@ -432,12 +441,7 @@ func (h *Handler) DeleteTask(c *gin.Context) {
 		return
 	}
 
-	if err := h.db.DeleteTask(uint(id)); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete task"})
-		return
-	}
-
-	c.JSON(http.StatusNoContent, nil)
+	runDeletion(c, h.deletions, service.DeletionTarget{Entity: "task", ID: uint(id)})
 }
 
 func (h *Handler) AddComment(c *gin.Context) {
@@ -454,16 +458,11 @@ func (h *Handler) AddComment(c *gin.Context) {
 	}
 
 	comment.TaskID = uint(taskID)
-	if err := h.db.AddComment(&comment); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add comment"})
+	if err := requestDB(c, h.db).AddComment(&comment); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	// Queue embedding regeneration for task (includes comments)
-	if worker := service.GetEmbeddingWorker(); worker != nil {
-		worker.QueueJob("task", uint(taskID))
-	}
-
 	c.JSON(http.StatusCreated, comment)
 }
 
@@ -486,6 +485,22 @@ func (h *Handler) UploadAttachment(c *gin.Context) {
 		return
 	}
 
+	// Optionally attach the file to one of the task's comments
+	var commentID *uint
+	if value := c.PostForm("comment_id"); value != "" {
+		id, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid comment ID"})
+			return
+		}
+		comment, err := h.db.GetComment(uint(id))
+		if err != nil || comment.TaskID != task.ID {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Comment not found on this task"})
+			return
+		}
+		commentID = &comment.ID
+	}
+
 	path, err := h.storage.SaveFile(file, task.ProjectID, uint(taskID))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save file"})
@@ -493,11 +508,12 @@ func (h *Handler) UploadAttachment(c *gin.Context) {
 	}
 
 	attachment := models.Attachment{
-		TaskID:   uint(taskID),
-		Filename: file.Filename,
-		Path:     path,
-		Size:     file.Size,
-		MimeType: file.Header.Get("Content-Type"),
+		TaskID:    uint(taskID),
+		CommentID: commentID,
+		Filename:  file.Filename,
+		Path:      path,
+		Size:      file.Size,
+		MimeType:  file.Header.Get("Content-Type"),
 	}
 
 	if err := h.db.AddAttachment(&attachment); err != nil {
@@ -642,7 +658,7 @@ func (h *Handler) AddTaskDependency(c *gin.Context) {
 		Type:        input.Type,
 	}
 
-	if err := h.db.CreateTaskDependency(dependency); err != nil {
+	if err := requestDB(c, h.db).CreateTaskDependency(dependency); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create dependency"})
 		return
 	}
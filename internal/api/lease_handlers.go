@@ -0,0 +1,119 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/headless-pm/headless-project-management/internal/database"
+)
+
+// leaseRequest is the optional body of claims and heartbeats
+type leaseRequest struct {
+	Labels       []string `json:"labels"`
+	LeaseSeconds int      `json:"lease_seconds"`
+}
+
+// bindLeaseRequest reads the optional body of a claim or heartbeat
+func bindLeaseRequest(c *gin.Context) (leaseRequest, bool) {
+	var req leaseRequest
+	if c.Request.ContentLength == 0 {
+		return req, true
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return req, false
+	}
+	return req, true
+}
+
+// ClaimProjectTask assigns the best available task of the project to the
+// caller and leases it to them
+func (h *Handler) ClaimProjectTask(c *gin.Context) {
+	projectID, err := h.getProjectIDFromParam(c)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "user_id is required for admin tokens"})
+		return
+	}
+	req, ok := bindLeaseRequest(c)
+	if !ok {
+		return
+	}
+
+	claim, err := requestDB(c, h.db).ClaimNextTask(database.ClaimRequest{
+		ProjectID:     projectID,
+		UserID:        userID,
+		Labels:        req.Labels,
+		LeaseDuration: time.Duration(req.LeaseSeconds) * time.Second,
+	})
+	if errors.Is(err, database.ErrNoTaskAvailable) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, claim)
+}
+
+// HeartbeatTask extends the caller's lease on a claimed task
+func (h *Handler) HeartbeatTask(c *gin.Context) {
+	taskID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid task ID"})
+		return
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "user_id is required for admin tokens"})
+		return
+	}
+	req, ok := bindLeaseRequest(c)
+	if !ok {
+		return
+	}
+
+	lease, err := requestDB(c, h.db).HeartbeatTaskLease(uint(taskID), userID, time.Duration(req.LeaseSeconds)*time.Second)
+	if errors.Is(err, database.ErrLeaseNotHeld) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, lease)
+}
+
+// ReleaseTask gives up the caller's lease on a claimed task
+func (h *Handler) ReleaseTask(c *gin.Context) {
+	taskID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid task ID"})
+		return
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "user_id is required for admin tokens"})
+		return
+	}
+
+	task, err := requestDB(c, h.db).ReleaseTaskLease(uint(taskID), userID)
+	if errors.Is(err, database.ErrLeaseNotHeld) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, task)
+}
@@ -0,0 +1,196 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/headless-pm/headless-project-management/internal/database"
+	"github.com/headless-pm/headless-project-management/internal/models"
+)
+
+type MilestoneHandler struct {
+	db *database.Database
+}
+
+func NewMilestoneHandler(db *database.Database) *MilestoneHandler {
+	return &MilestoneHandler{db: db}
+}
+
+func (h *MilestoneHandler) CreateMilestone(c *gin.Context) {
+	var milestone models.Milestone
+	if err := c.ShouldBindJSON(&milestone); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.db.CreateMilestone(&milestone); err != nil {
+		c.JSON(planningErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, milestone)
+}
+
+func (h *MilestoneHandler) GetMilestone(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid milestone ID"})
+		return
+	}
+
+	milestone, err := h.db.GetMilestone(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Milestone not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, milestone)
+}
+
+func (h *MilestoneHandler) ListMilestones(c *gin.Context) {
+	var projectID *uint
+	var status *models.MilestoneStatus
+
+	if pid := c.Query("project_id"); pid != "" {
+		id, err := strconv.ParseUint(pid, 10, 32)
+		if err == nil {
+			uid := uint(id)
+			projectID = &uid
+		}
+	}
+
+	if s := c.Query("status"); s != "" {
+		milestoneStatus := models.MilestoneStatus(s)
+		status = &milestoneStatus
+	}
+
+	milestones, err := h.db.ListMilestones(projectID, status)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, milestones)
+}
+
+func (h *MilestoneHandler) UpdateMilestone(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid milestone ID"})
+		return
+	}
+
+	var milestone models.Milestone
+	if err := h.db.First(&milestone, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Milestone not found"})
+		return
+	}
+
+	// Only the details and status are edited here; a milestone stays in its project
+	var req struct {
+		Name        *string                 `json:"name"`
+		Description *string                 `json:"description"`
+		Status      *models.MilestoneStatus `json:"status"`
+		DueDate     *time.Time              `json:"due_date"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if req.Name != nil {
+		if *req.Name == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Milestone name cannot be empty"})
+			return
+		}
+		milestone.Name = *req.Name
+	}
+	if req.Description != nil {
+		milestone.Description = *req.Description
+	}
+	if req.Status != nil {
+		milestone.Status = *req.Status
+	}
+	if req.DueDate != nil {
+		milestone.DueDate = req.DueDate
+	}
+
+	if err := h.db.UpdateMilestone(&milestone); err != nil {
+		c.JSON(planningErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, milestone)
+}
+
+func (h *MilestoneHandler) DeleteMilestone(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid milestone ID"})
+		return
+	}
+
+	if err := h.db.DeleteMilestone(uint(id)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+func (h *MilestoneHandler) GetProjectMilestones(c *gin.Context) {
+	projectID, err := strconv.ParseUint(c.Param("projectId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
+		return
+	}
+
+	pid := uint(projectID)
+	milestones, err := h.db.ListMilestones(&pid, nil)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, milestones)
+}
+
+func (h *MilestoneHandler) AssignTaskToMilestone(c *gin.Context) {
+	milestoneID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid milestone ID"})
+		return
+	}
+
+	var req struct {
+		TaskID uint `json:"task_id" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.db.AssignTaskToMilestone(req.TaskID, uint(milestoneID)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Task assigned to milestone"})
+}
+
+func (h *MilestoneHandler) RemoveTaskFromMilestone(c *gin.Context) {
+	taskID, err := strconv.ParseUint(c.Param("taskId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid task ID"})
+		return
+	}
+
+	if err := h.db.RemoveTaskFromMilestone(uint(taskID)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Task removed from milestone"})
+}
@@ -0,0 +1,195 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/headless-pm/headless-project-management/internal/database"
+	"github.com/headless-pm/headless-project-management/internal/models"
+)
+
+type NotificationHandler struct {
+	db *database.Database
+}
+
+func NewNotificationHandler(db *database.Database) *NotificationHandler {
+	return &NotificationHandler{db: db}
+}
+
+// currentUserID resolves whose inbox a request is for. API tokens act as their
+// user; the admin token has no user and must name one with ?user_id=.
+func currentUserID(c *gin.Context) (uint, bool) {
+	if userID, exists := c.Get("user_id"); exists {
+		if id, ok := userID.(uint); ok {
+			return id, true
+		}
+	}
+
+	if isAdminRequest(c) {
+		if id, err := strconv.ParseUint(c.Query("user_id"), 10, 32); err == nil {
+			return uint(id), true
+		}
+	}
+
+	return 0, false
+}
+
+// isAdminRequest reports whether the request was made with an admin token
+func isAdminRequest(c *gin.Context) bool {
+	isAdmin, _ := c.Get("is_admin")
+	return isAdmin == true
+}
+
+// canActFor reports whether the caller may change what belongs to a user:
+// their own things, or anyone's for admins
+func canActFor(c *gin.Context, ownerID uint) bool {
+	if isAdminRequest(c) {
+		return true
+	}
+	userID, ok := currentUserID(c)
+	return ok && userID == ownerID
+}
+
+func (h *NotificationHandler) ListNotifications(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "user_id is required for admin tokens"})
+		return
+	}
+
+	unreadOnly := c.Query("unread") == "true"
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+
+	notifications, err := h.db.ListNotifications(userID, unreadOnly, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	unread, _ := h.db.CountUnreadNotifications(userID)
+	c.JSON(http.StatusOK, gin.H{
+		"notifications": notifications,
+		"unread_count":  unread,
+	})
+}
+
+func (h *NotificationHandler) GetUnreadCount(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "user_id is required for admin tokens"})
+		return
+	}
+
+	unread, err := h.db.CountUnreadNotifications(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"unread_count": unread})
+}
+
+// MarkRead marks the given notifications as read, or all of them when no IDs are sent
+func (h *NotificationHandler) MarkRead(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "user_id is required for admin tokens"})
+		return
+	}
+
+	var req struct {
+		NotificationIDs []uint `json:"notification_ids"`
+	}
+	// Body is optional
+	_ = c.ShouldBindJSON(&req)
+
+	updated, err := h.db.MarkNotificationsRead(userID, req.NotificationIDs)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"updated": updated})
+}
+
+func (h *NotificationHandler) MarkUnread(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "user_id is required for admin tokens"})
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid notification ID"})
+		return
+	}
+
+	if err := h.db.MarkNotificationUnread(userID, uint(id)); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Notification marked as unread"})
+}
+
+func (h *NotificationHandler) DeleteNotification(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "user_id is required for admin tokens"})
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid notification ID"})
+		return
+	}
+
+	if err := h.db.DeleteNotification(userID, uint(id)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+func (h *NotificationHandler) GetPreferences(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "user_id is required for admin tokens"})
+		return
+	}
+
+	prefs, err := h.db.GetNotificationPreferences(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, prefs)
+}
+
+// UpdatePreferences takes a map of notification type to enabled, e.g. {"due_soon": false}
+func (h *NotificationHandler) UpdatePreferences(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "user_id is required for admin tokens"})
+		return
+	}
+
+	var req map[models.NotificationType]bool
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.db.UpdateNotificationPreferences(userID, req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	prefs, _ := h.db.GetNotificationPreferences(userID)
+	c.JSON(http.StatusOK, prefs)
+}
@@ -0,0 +1,207 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/headless-pm/headless-project-management/internal/database"
+	"github.com/headless-pm/headless-project-management/internal/models"
+	"github.com/headless-pm/headless-project-management/internal/service"
+)
+
+type PromptHandler struct {
+	db      *database.Database
+	prompts *service.PromptService
+}
+
+func NewPromptHandler(db *database.Database) *PromptHandler {
+	return &PromptHandler{db: db, prompts: service.NewPromptService(db)}
+}
+
+type PromptTemplateRequest struct {
+	Name        string                   `json:"name"`
+	Description *string                  `json:"description"`
+	Arguments   *[]models.PromptArgument `json:"arguments"`
+	Template    string                   `json:"template"`
+}
+
+// ListPrompts lists the built-in prompts and the team templates, as MCP prompts/list does
+func (h *PromptHandler) ListPrompts(c *gin.Context) {
+	prompts, err := h.prompts.WithContext(c.Request.Context()).ListPrompts()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch prompts"})
+		return
+	}
+
+	c.JSON(http.StatusOK, prompts)
+}
+
+// RenderPrompt renders a prompt with the arguments in the request body, as MCP prompts/get does
+func (h *PromptHandler) RenderPrompt(c *gin.Context) {
+	var args map[string]string
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&args); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	prompt, err := h.prompts.WithContext(c.Request.Context()).GetPrompt(c.Param("name"), args)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, service.ErrPromptNotFound) {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, prompt)
+}
+
+func (h *PromptHandler) ListPromptTemplates(c *gin.Context) {
+	templates, err := h.db.ListPromptTemplates()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch prompt templates"})
+		return
+	}
+
+	c.JSON(http.StatusOK, templates)
+}
+
+func (h *PromptHandler) CreatePromptTemplate(c *gin.Context) {
+	var req PromptTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := service.CheckPromptName(req.Name); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := service.ValidatePromptTemplate(req.Template); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	template := models.PromptTemplate{
+		Name:     req.Name,
+		Template: req.Template,
+	}
+	if req.Description != nil {
+		template.Description = *req.Description
+	}
+	if req.Arguments != nil {
+		template.Arguments = *req.Arguments
+	}
+	if userID, ok := currentUserID(c); ok {
+		template.CreatedBy = &userID
+	}
+
+	if err := requestDB(c, h.db).CreatePromptTemplate(&template); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, template)
+}
+
+func (h *PromptHandler) GetPromptTemplate(c *gin.Context) {
+	template, ok := h.templateFromParam(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, template)
+}
+
+// UpdatePromptTemplate updates the fields given in the request
+func (h *PromptHandler) UpdatePromptTemplate(c *gin.Context) {
+	template, ok := h.ownedTemplateFromParam(c)
+	if !ok {
+		return
+	}
+
+	var req PromptTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if req.Name != "" {
+		if err := service.CheckPromptName(req.Name); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		template.Name = req.Name
+	}
+	if req.Description != nil {
+		template.Description = *req.Description
+	}
+	if req.Arguments != nil {
+		template.Arguments = *req.Arguments
+	}
+	if req.Template != "" {
+		if err := service.ValidatePromptTemplate(req.Template); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		template.Template = req.Template
+	}
+
+	if err := requestDB(c, h.db).UpdatePromptTemplate(template); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, template)
+}
+
+func (h *PromptHandler) DeletePromptTemplate(c *gin.Context) {
+	template, ok := h.ownedTemplateFromParam(c)
+	if !ok {
+		return
+	}
+
+	if err := requestDB(c, h.db).DeletePromptTemplate(template.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete prompt template"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Prompt template deleted successfully"})
+}
+
+// ownedTemplateFromParam loads the template of the request for a change, which
+// only its creator or an admin may make
+func (h *PromptHandler) ownedTemplateFromParam(c *gin.Context) (*models.PromptTemplate, bool) {
+	template, ok := h.templateFromParam(c)
+	if !ok {
+		return nil, false
+	}
+	var ownerID uint
+	if template.CreatedBy != nil {
+		ownerID = *template.CreatedBy
+	}
+	if !canActFor(c, ownerID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the creator of a prompt template or an admin can change it"})
+		return nil, false
+	}
+	return template, true
+}
+
+func (h *PromptHandler) templateFromParam(c *gin.Context) (*models.PromptTemplate, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid prompt template ID"})
+		return nil, false
+	}
+
+	template, err := h.db.GetPromptTemplate(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Prompt template not found"})
+		return nil, false
+	}
+	return template, true
+}
@@ -5,19 +5,28 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/headless-pm/headless-project-management/internal/auth"
 	"github.com/headless-pm/headless-project-management/internal/database"
 	"github.com/headless-pm/headless-project-management/internal/models"
 	"github.com/headless-pm/headless-project-management/internal/service"
-	"github.com/headless-pm/headless-project-management/pkg/auth"
+	pkgauth "github.com/headless-pm/headless-project-management/pkg/auth"
 )
 
 func SetupExtendedRouter(router *gin.Engine, db *database.Database, vectorService *service.VectorService) {
-	jwtManager := auth.NewJWTManager("your-secret-key-change-this", 24*time.Hour)
+	jwtManager := pkgauth.NewJWTManager("your-secret-key-change-this", 24*time.Hour)
 
 	authHandler := NewAuthHandler(db, jwtManager)
 	epicHandler := NewEpicHandler(db)
+	sprintHandler := NewSprintHandler(db)
+	milestoneHandler := NewMilestoneHandler(db)
+	worklogHandler := NewWorklogHandler(db)
+	watcherHandler := NewWatcherHandler(db)
+	commentHandler := NewCommentHandler(db)
 	extendedHandler := NewExtendedHandler(db)
 
+	// Endpoints added alongside the legacy ones below act for the token's user
+	requireAuth := auth.AuthMiddleware(db)
+
 	api := router.Group("/api")
 	{
 		// Authentication endpoints
@@ -49,11 +58,81 @@ func SetupExtendedRouter(router *gin.Engine, db *database.Database, vectorServic
 			epics.GET("/project/:projectId", epicHandler.GetProjectEpics)
 		}
 
+		// Sprint endpoints
+		sprints := api.Group("/sprints", requireAuth)
+		{
+			sprints.POST("", sprintHandler.CreateSprint)
+			sprints.GET("", sprintHandler.ListSprints)
+			sprints.GET("/:id", sprintHandler.GetSprint)
+			sprints.PUT("/:id", sprintHandler.UpdateSprint)
+			sprints.DELETE("/:id", sprintHandler.DeleteSprint)
+			sprints.POST("/:id/tasks", sprintHandler.AddTaskToSprint)
+			sprints.DELETE("/tasks/:taskId", sprintHandler.RemoveTaskFromSprint)
+			sprints.POST("/:id/start", sprintHandler.StartSprint)
+			sprints.POST("/:id/complete", sprintHandler.CompleteSprint)
+			sprints.GET("/project/:projectId", sprintHandler.GetProjectSprints)
+		}
+
+		// Milestone endpoints
+		milestones := api.Group("/milestones", requireAuth)
+		{
+			milestones.POST("", milestoneHandler.CreateMilestone)
+			milestones.GET("", milestoneHandler.ListMilestones)
+			milestones.GET("/:id", milestoneHandler.GetMilestone)
+			milestones.PUT("/:id", milestoneHandler.UpdateMilestone)
+			milestones.DELETE("/:id", milestoneHandler.DeleteMilestone)
+			milestones.POST("/:id/tasks", milestoneHandler.AssignTaskToMilestone)
+			milestones.DELETE("/tasks/:taskId", milestoneHandler.RemoveTaskFromMilestone)
+			milestones.GET("/project/:projectId", milestoneHandler.GetProjectMilestones)
+		}
+
+		// Worklog endpoints
+		worklogs := api.Group("/worklogs", requireAuth)
+		{
+			worklogs.POST("", worklogHandler.CreateWorklog)
+			worklogs.GET("", worklogHandler.ListWorklogs)
+			worklogs.GET("/:id", worklogHandler.GetWorklog)
+			worklogs.PUT("/:id", worklogHandler.UpdateWorklog)
+			worklogs.DELETE("/:id", worklogHandler.DeleteWorklog)
+		}
+
+		// Timer endpoints
+		timers := api.Group("/timers", requireAuth)
+		{
+			timers.POST("/start", worklogHandler.StartTimer)
+			timers.POST("/stop", worklogHandler.StopTimer)
+			timers.GET("/running", worklogHandler.GetRunningTimer)
+		}
+
+		// Timesheet reports (add ?format=csv for CSV export)
+		timesheets := api.Group("/timesheets", requireAuth)
+		{
+			timesheets.GET("", worklogHandler.GetTimesheet)
+			timesheets.GET("/user/:userId", worklogHandler.GetTimesheet)
+			timesheets.GET("/project/:projectId", worklogHandler.GetTimesheet)
+		}
+
 		// Task dependency endpoints
 		taskExtras := api.Group("/tasks")
 		{
 			taskExtras.POST("/:id/dependencies", extendedHandler.AddTaskDependency)
 			taskExtras.GET("/:id/dependencies", extendedHandler.GetTaskDependencies)
+			taskExtras.GET("/:id/mentions", requireAuth, extendedHandler.GetTaskMentions)
+		}
+
+		// Task watchers
+		watchers := api.Group("/tasks/:id/watchers", requireAuth)
+		{
+			watchers.POST("", watcherHandler.WatchTask)
+			watchers.GET("", watcherHandler.GetTaskWatchers)
+			watchers.DELETE("/:userId", watcherHandler.UnwatchTask)
+		}
+
+		// User watch list and mentions
+		users := api.Group("/users", requireAuth)
+		{
+			users.GET("/:id/watched-tasks", watcherHandler.GetWatchedTasks)
+			users.GET("/:id/mentions", extendedHandler.GetUserMentions)
 		}
 
 		// Label endpoints
@@ -141,34 +220,18 @@ func SetupExtendedRouter(router *gin.Engine, db *database.Database, vectorServic
 		}
 
 		// Comment endpoints
-		comments := api.Group("/comments")
+		comments := api.Group("/comments", requireAuth)
 		{
-			comments.POST("", func(c *gin.Context) {
-				var comment models.Comment
-				if err := c.ShouldBindJSON(&comment); err != nil {
-					c.JSON(400, gin.H{"error": err.Error()})
-					return
-				}
-				if err := db.AddComment(&comment); err != nil {
-					c.JSON(500, gin.H{"error": "Failed to add comment"})
-					return
-				}
-				c.JSON(201, comment)
-			})
-			comments.GET("/task/:taskId", func(c *gin.Context) {
-				taskIDStr := c.Param("taskId")
-				taskID, err := strconv.ParseUint(taskIDStr, 10, 32)
-				if err != nil {
-					c.JSON(400, gin.H{"error": "Invalid task ID"})
-					return
-				}
-				var comments []models.Comment
-				if err := db.Where("task_id = ?", taskID).Order("created_at DESC").Find(&comments).Error; err != nil {
-					c.JSON(500, gin.H{"error": "Failed to get comments"})
-					return
-				}
-				c.JSON(200, comments)
-			})
+			comments.POST("", commentHandler.CreateComment)
+			comments.GET("/task/:taskId", commentHandler.ListTaskComments)
+			comments.GET("/:id", commentHandler.GetComment)
+			comments.PUT("/:id", commentHandler.UpdateComment)
+			comments.DELETE("/:id", commentHandler.DeleteComment)
+			comments.POST("/:id/replies", commentHandler.ReplyToComment)
+			comments.GET("/:id/history", commentHandler.GetCommentHistory)
+			comments.POST("/:id/reactions", commentHandler.AddReaction)
+			comments.DELETE("/:id/reactions", commentHandler.RemoveReaction)
+			comments.POST("/:id/attachments", commentHandler.AttachToComment)
 		}
 
 		// Attachment endpoints
@@ -0,0 +1,261 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/headless-pm/headless-project-management/internal/auth"
+	"github.com/headless-pm/headless-project-management/internal/database"
+	"github.com/headless-pm/headless-project-management/internal/models"
+	"gorm.io/gorm/logger"
+)
+
+// newTestRouter returns the extended API routes on a fresh database. Requests
+// authenticate with the admin token "admin-secret".
+func newTestRouter(t *testing.T) (*database.Database, *gin.Engine) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	t.Setenv("ADMIN_API_TOKEN", "admin-secret")
+
+	db, err := database.NewDatabaseWithLogger(t.TempDir(), logger.Discard)
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	router := gin.New()
+	SetupExtendedRouter(router, db, nil)
+	return db, router
+}
+
+// createUserToken adds a user with a read and write API token and returns the token
+func createUserToken(t *testing.T, db *database.Database, username string) string {
+	t.Helper()
+	user := models.User{Username: username, Email: username + "@example.com"}
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatal(err)
+	}
+	token := "token-" + username
+	if err := db.Create(&models.APIToken{UserID: user.ID, Name: username, Token: auth.HashToken(token), Scope: "read,write"}).Error; err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+func serve(router http.Handler, method, path, token, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestExtendedRoutesRequireAuthentication(t *testing.T) {
+	_, router := newTestRouter(t)
+
+	routes := []struct{ method, path string }{
+		{http.MethodGet, "/api/sprints"},
+		{http.MethodPost, "/api/sprints"},
+		{http.MethodPost, "/api/sprints/1/start"},
+		{http.MethodGet, "/api/milestones"},
+		{http.MethodDelete, "/api/milestones/1"},
+		{http.MethodPost, "/api/worklogs"},
+		{http.MethodDelete, "/api/worklogs/1"},
+		{http.MethodPost, "/api/timers/start"},
+		{http.MethodPost, "/api/timers/stop"},
+		{http.MethodGet, "/api/timesheets"},
+		{http.MethodPost, "/api/tasks/1/watchers"},
+		{http.MethodDelete, "/api/tasks/1/watchers/1"},
+		{http.MethodGet, "/api/users/1/watched-tasks"},
+		{http.MethodGet, "/api/users/1/mentions"},
+		{http.MethodGet, "/api/tasks/1/mentions"},
+		{http.MethodPost, "/api/comments"},
+		{http.MethodPut, "/api/comments/1"},
+	}
+	for _, route := range routes {
+		if w := serve(router, route.method, route.path, "", `{}`); w.Code != http.StatusUnauthorized {
+			t.Errorf("%s %s without a token: %d, want 401", route.method, route.path, w.Code)
+		}
+	}
+
+	if w := serve(router, http.MethodGet, "/api/sprints", "admin-secret", ""); w.Code != http.StatusOK {
+		t.Errorf("GET /api/sprints with a token: %d %s", w.Code, w.Body)
+	}
+}
+
+func TestUpdateSprintKeepsStatus(t *testing.T) {
+	db, router := newTestRouter(t)
+	db.Create(&models.Project{Name: "Sprints"})
+	serve(router, http.MethodPost, "/api/sprints", "admin-secret", `{"project_id":1,"name":"First"}`)
+
+	if w := serve(router, http.MethodPut, "/api/sprints/1", "admin-secret", `{"status":"active"}`); w.Code != http.StatusBadRequest {
+		t.Errorf("activating a sprint through an update: %d, want 400", w.Code)
+	}
+	w := serve(router, http.MethodPut, "/api/sprints/1", "admin-secret", `{"name":"Renamed"}`)
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"status":"planned"`) {
+		t.Errorf("renaming a sprint: %d %s", w.Code, w.Body)
+	}
+}
+
+func TestSprintAndMilestoneValidation(t *testing.T) {
+	db, router := newTestRouter(t)
+	db.Create(&models.Project{Name: "Sprints"})
+	db.Create(&models.Project{Name: "Other"})
+
+	creates := []struct {
+		path, body string
+		want       int
+	}{
+		{"/api/sprints", `{"project_id":1,"name":"Active","status":"active"}`, http.StatusBadRequest},
+		{"/api/sprints", `{"project_id":1,"name":"Backwards","start_date":"2026-03-10T00:00:00Z","end_date":"2026-03-01T00:00:00Z"}`, http.StatusBadRequest},
+		{"/api/sprints", `{"project_id":9,"name":"Orphan"}`, http.StatusNotFound},
+		{"/api/sprints", `{"project_id":1,"name":"First","start_date":"2026-03-01T00:00:00Z","end_date":"2026-03-14T00:00:00Z"}`, http.StatusCreated},
+		{"/api/milestones", `{"project_id":1,"name":"Launch","status":"someday"}`, http.StatusBadRequest},
+		{"/api/milestones", `{"project_id":9,"name":"Orphan"}`, http.StatusNotFound},
+		{"/api/milestones", `{"project_id":1,"name":"Launch"}`, http.StatusCreated},
+	}
+	for _, create := range creates {
+		if w := serve(router, http.MethodPost, create.path, "admin-secret", create.body); w.Code != create.want {
+			t.Errorf("POST %s %s: %d %s, want %d", create.path, create.body, w.Code, w.Body, create.want)
+		}
+	}
+
+	if w := serve(router, http.MethodPut, "/api/sprints/1", "admin-secret", `{"end_date":"2026-02-01T00:00:00Z"}`); w.Code != http.StatusBadRequest {
+		t.Errorf("ending a sprint before its start: %d, want 400", w.Code)
+	}
+	if w := serve(router, http.MethodPut, "/api/sprints/1", "admin-secret", `{"project_id":2,"goal":"Ship"}`); w.Code != http.StatusOK {
+		t.Errorf("updating a sprint: %d %s", w.Code, w.Body)
+	}
+	if w := serve(router, http.MethodPut, "/api/milestones/1", "admin-secret", `{"project_id":2,"status":"completed"}`); w.Code != http.StatusOK {
+		t.Errorf("updating a milestone: %d %s", w.Code, w.Body)
+	}
+
+	var sprint models.Sprint
+	var milestone models.Milestone
+	db.First(&sprint, 1)
+	db.First(&milestone, 1)
+	if sprint.ProjectID != 1 || sprint.Goal != "Ship" || sprint.Name != "First" {
+		t.Errorf("sprint after update: %+v", sprint)
+	}
+	if milestone.ProjectID != 1 || milestone.Status != models.MilestoneStatusCompleted || milestone.Name != "Launch" {
+		t.Errorf("milestone after update: %+v", milestone)
+	}
+}
+
+func TestTimersActForTheCaller(t *testing.T) {
+	db, router := newTestRouter(t)
+	alice := createUserToken(t, db, "alice")
+	bob := createUserToken(t, db, "bob")
+	db.Create(&models.Project{Name: "Billing"})
+	db.Create(&models.Task{ProjectID: 1, Title: "Invoice"})
+
+	if w := serve(router, http.MethodPost, "/api/timers/start", alice, `{"task_id":1,"user_id":2}`); w.Code != http.StatusCreated || !strings.Contains(w.Body.String(), `"user_id":1`) {
+		t.Fatalf("alice starting a timer: %d %s", w.Code, w.Body)
+	}
+	if w := serve(router, http.MethodPost, "/api/timers/stop", bob, ""); w.Code != http.StatusBadRequest {
+		t.Errorf("bob stopping a timer: %d, want 400 as he has none", w.Code)
+	}
+	if w := serve(router, http.MethodPost, "/api/timers/stop", alice, ""); w.Code != http.StatusOK {
+		t.Fatalf("alice stopping her timer: %d %s", w.Code, w.Body)
+	}
+
+	if w := serve(router, http.MethodDelete, "/api/worklogs/1", bob, ""); w.Code != http.StatusForbidden {
+		t.Errorf("bob deleting alice's worklog: %d, want 403", w.Code)
+	}
+	if w := serve(router, http.MethodDelete, "/api/worklogs/1", alice, ""); w.Code != http.StatusNoContent {
+		t.Errorf("alice deleting her worklog: %d %s", w.Code, w.Body)
+	}
+}
+
+func TestWorklogsAreReadByTheirOwner(t *testing.T) {
+	db, router := newTestRouter(t)
+	alice := createUserToken(t, db, "alice")
+	bob := createUserToken(t, db, "bob")
+	db.Create(&models.Project{Name: "Billing"})
+	db.Create(&models.Task{ProjectID: 1, Title: "Invoice"})
+	if w := serve(router, http.MethodPost, "/api/worklogs", alice, `{"task_id":1,"duration_minutes":90}`); w.Code != http.StatusCreated {
+		t.Fatalf("alice logging time: %d %s", w.Code, w.Body)
+	}
+
+	for _, path := range []string{"/api/worklogs/1", "/api/worklogs?user_id=1", "/api/timesheets/user/1", "/api/timesheets?user_id=1&format=csv"} {
+		if w := serve(router, http.MethodGet, path, bob, ""); w.Code != http.StatusForbidden {
+			t.Errorf("bob reading GET %s: %d, want 403", path, w.Code)
+		}
+		if w := serve(router, http.MethodGet, path, alice, ""); w.Code != http.StatusOK {
+			t.Errorf("alice reading GET %s: %d %s", path, w.Code, w.Body)
+		}
+	}
+
+	if w := serve(router, http.MethodGet, "/api/worklogs?task_id=1", bob, ""); w.Code != http.StatusOK || strings.TrimSpace(w.Body.String()) != "[]" {
+		t.Errorf("bob listing the task's worklogs: %d %s", w.Code, w.Body)
+	}
+	if w := serve(router, http.MethodGet, "/api/timesheets/project/1", bob, ""); w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"total_hours":0`) {
+		t.Errorf("bob's project timesheet: %d %s", w.Code, w.Body)
+	}
+	if w := serve(router, http.MethodGet, "/api/timesheets/project/1", "admin-secret", ""); w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"total_hours":1.5`) {
+		t.Errorf("admin's project timesheet: %d %s", w.Code, w.Body)
+	}
+}
+
+func TestWatchListsArePrivate(t *testing.T) {
+	db, router := newTestRouter(t)
+	alice := createUserToken(t, db, "alice")
+	bob := createUserToken(t, db, "bob")
+
+	if w := serve(router, http.MethodGet, "/api/users/1/watched-tasks", bob, ""); w.Code != http.StatusForbidden {
+		t.Errorf("bob reading alice's watch list: %d, want 403", w.Code)
+	}
+	for _, token := range []string{alice, "admin-secret"} {
+		if w := serve(router, http.MethodGet, "/api/users/1/watched-tasks", token, ""); w.Code != http.StatusOK {
+			t.Errorf("reading alice's watch list: %d %s", w.Code, w.Body)
+		}
+	}
+}
+
+func TestUserMentionsArePrivate(t *testing.T) {
+	db, router := newTestRouter(t)
+	alice := createUserToken(t, db, "alice")
+	bob := createUserToken(t, db, "bob")
+
+	if w := serve(router, http.MethodGet, "/api/users/1/mentions", bob, ""); w.Code != http.StatusForbidden {
+		t.Errorf("bob reading alice's mentions: %d, want 403", w.Code)
+	}
+	for _, token := range []string{alice, "admin-secret"} {
+		if w := serve(router, http.MethodGet, "/api/users/1/mentions", token, ""); w.Code != http.StatusOK {
+			t.Errorf("reading alice's mentions: %d %s", w.Code, w.Body)
+		}
+	}
+}
+
+func TestCommentsBelongToTheirAuthor(t *testing.T) {
+	db, router := newTestRouter(t)
+	alice := createUserToken(t, db, "alice")
+	bob := createUserToken(t, db, "bob")
+	db.Create(&models.Project{Name: "Billing"})
+	db.Create(&models.Task{ProjectID: 1, Title: "Invoice"})
+
+	w := serve(router, http.MethodPost, "/api/comments", alice, `{"task_id":1,"content":"Sent","author":"bob"}`)
+	if w.Code != http.StatusCreated || !strings.Contains(w.Body.String(), `"author":"alice"`) {
+		t.Fatalf("alice commenting as bob: %d %s", w.Code, w.Body)
+	}
+	if w := serve(router, http.MethodPost, "/api/comments", alice, `{"task_id":1,"content":"Sent","author_id":2}`); w.Code != http.StatusBadRequest {
+		t.Errorf("alice commenting with bob's ID: %d, want 400", w.Code)
+	}
+
+	if w := serve(router, http.MethodPut, "/api/comments/1", bob, `{"content":"Not sent"}`); w.Code != http.StatusForbidden {
+		t.Errorf("bob editing alice's comment: %d, want 403", w.Code)
+	}
+	if w := serve(router, http.MethodDelete, "/api/comments/1", bob, ""); w.Code != http.StatusForbidden {
+		t.Errorf("bob deleting alice's comment: %d, want 403", w.Code)
+	}
+	if w := serve(router, http.MethodPut, "/api/comments/1", alice, `{"content":"Sent twice"}`); w.Code != http.StatusOK {
+		t.Errorf("alice editing her comment: %d %s", w.Code, w.Body)
+	}
+	if w := serve(router, http.MethodDelete, "/api/comments/1", "admin-secret", ""); w.Code != http.StatusNoContent {
+		t.Errorf("admin deleting a comment: %d %s", w.Code, w.Body)
+	}
+}
@@ -0,0 +1,282 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/headless-pm/headless-project-management/internal/database"
+	"github.com/headless-pm/headless-project-management/internal/models"
+)
+
+type SprintHandler struct {
+	db *database.Database
+}
+
+func NewSprintHandler(db *database.Database) *SprintHandler {
+	return &SprintHandler{db: db}
+}
+
+func (h *SprintHandler) CreateSprint(c *gin.Context) {
+	var sprint models.Sprint
+	if err := c.ShouldBindJSON(&sprint); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.db.CreateSprint(&sprint); err != nil {
+		c.JSON(planningErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, sprint)
+}
+
+// planningErrorStatus maps the errors of saving sprints and milestones to HTTP statuses
+func planningErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, database.ErrInvalidSprint), errors.Is(err, database.ErrInvalidMilestone):
+		return http.StatusBadRequest
+	case errors.Is(err, database.ErrProjectNotFound):
+		return http.StatusNotFound
+	}
+	return http.StatusInternalServerError
+}
+
+func (h *SprintHandler) GetSprint(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid sprint ID"})
+		return
+	}
+
+	sprint, err := h.db.GetSprint(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Sprint not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, sprint)
+}
+
+func (h *SprintHandler) ListSprints(c *gin.Context) {
+	var projectID *uint
+	var status *models.SprintStatus
+
+	if pid := c.Query("project_id"); pid != "" {
+		id, err := strconv.ParseUint(pid, 10, 32)
+		if err == nil {
+			uid := uint(id)
+			projectID = &uid
+		}
+	}
+
+	if s := c.Query("status"); s != "" {
+		sprintStatus := models.SprintStatus(s)
+		status = &sprintStatus
+	}
+
+	sprints, err := h.db.ListSprints(projectID, status)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, sprints)
+}
+
+func (h *SprintHandler) UpdateSprint(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid sprint ID"})
+		return
+	}
+
+	var sprint models.Sprint
+	if err := h.db.First(&sprint, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Sprint not found"})
+		return
+	}
+
+	// Only the details are edited here; a sprint stays in its project
+	var req struct {
+		Name      *string              `json:"name"`
+		Goal      *string              `json:"goal"`
+		Status    *models.SprintStatus `json:"status"`
+		StartDate *time.Time           `json:"start_date"`
+		EndDate   *time.Time           `json:"end_date"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// The status moves only through the start and complete endpoints
+	if req.Status != nil && *req.Status != sprint.Status {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Use the start and complete endpoints to change the sprint status"})
+		return
+	}
+
+	if req.Name != nil {
+		if *req.Name == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Sprint name cannot be empty"})
+			return
+		}
+		sprint.Name = *req.Name
+	}
+	if req.Goal != nil {
+		sprint.Goal = *req.Goal
+	}
+	if req.StartDate != nil {
+		sprint.StartDate = req.StartDate
+	}
+	if req.EndDate != nil {
+		sprint.EndDate = req.EndDate
+	}
+
+	if err := h.db.UpdateSprint(&sprint); err != nil {
+		c.JSON(planningErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	updated, err := h.db.GetSprint(uint(id))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, updated)
+}
+
+func (h *SprintHandler) DeleteSprint(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid sprint ID"})
+		return
+	}
+
+	if err := h.db.DeleteSprint(uint(id)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+func (h *SprintHandler) GetProjectSprints(c *gin.Context) {
+	projectID, err := strconv.ParseUint(c.Param("projectId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
+		return
+	}
+
+	pid := uint(projectID)
+	sprints, err := h.db.ListSprints(&pid, nil)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, sprints)
+}
+
+func (h *SprintHandler) AddTaskToSprint(c *gin.Context) {
+	sprintID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid sprint ID"})
+		return
+	}
+
+	var req struct {
+		TaskID  uint   `json:"task_id"`
+		TaskIDs []uint `json:"task_ids"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	taskIDs := req.TaskIDs
+	if req.TaskID > 0 {
+		taskIDs = append(taskIDs, req.TaskID)
+	}
+	if len(taskIDs) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "task_id or task_ids is required"})
+		return
+	}
+
+	for _, taskID := range taskIDs {
+		if err := requestDB(c, h.db).AddTaskToSprint(taskID, uint(sprintID)); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "task_id": taskID})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":  "Tasks added to sprint",
+		"task_ids": taskIDs,
+	})
+}
+
+func (h *SprintHandler) RemoveTaskFromSprint(c *gin.Context) {
+	taskID, err := strconv.ParseUint(c.Param("taskId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid task ID"})
+		return
+	}
+
+	if err := requestDB(c, h.db).RemoveTaskFromSprint(uint(taskID)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Task removed from sprint"})
+}
+
+func (h *SprintHandler) StartSprint(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid sprint ID"})
+		return
+	}
+
+	carried, err := requestDB(c, h.db).StartSprint(uint(id))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	sprint, _ := h.db.GetSprint(uint(id))
+	c.JSON(http.StatusOK, gin.H{
+		"sprint":        sprint,
+		"carried_tasks": carried,
+	})
+}
+
+func (h *SprintHandler) CompleteSprint(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid sprint ID"})
+		return
+	}
+
+	var req struct {
+		NextSprintID *uint `json:"next_sprint_id"`
+	}
+	// Body is optional
+	_ = c.ShouldBindJSON(&req)
+
+	carried, err := requestDB(c, h.db).CompleteSprint(uint(id), req.NextSprintID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	sprint, _ := h.db.GetSprint(uint(id))
+	c.JSON(http.StatusOK, gin.H{
+		"sprint":        sprint,
+		"carried_tasks": carried,
+	})
+}
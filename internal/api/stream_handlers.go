@@ -0,0 +1,219 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/headless-pm/headless-project-management/internal/service"
+	"golang.org/x/net/websocket"
+)
+
+// streamHeartbeat keeps idle connections open through proxies
+const streamHeartbeat = 25 * time.Second
+
+// streamResetEvent tells a resuming client that changes were missed and it
+// should reload its state
+const streamResetEvent = "stream.reset"
+
+type StreamHandler struct {
+	feed *service.ChangeFeed
+}
+
+func NewStreamHandler(feed *service.ChangeFeed) *StreamHandler {
+	return &StreamHandler{feed: feed}
+}
+
+// Stream pushes changes as Server-Sent Events. Filters: ?project_id=, ?types=
+// (task, comment, epic, dependency), ?task_id= and ?epic_id=. Clients resume
+// with the Last-Event-ID header (or ?last_event_id=).
+func (h *StreamHandler) Stream(c *gin.Context) {
+	filter, ok := parseChangeFilter(c)
+	if !ok {
+		return
+	}
+	h.serveSSE(c, filter, false)
+}
+
+// ProjectStream is the change stream of one project for the web board. The board
+// is served without a token, so it only learns what changed and reloads through
+// the regular pages.
+func (h *StreamHandler) ProjectStream(c *gin.Context) {
+	projectID, err := strconv.ParseUint(c.Param("projectId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
+		return
+	}
+
+	filter, ok := parseChangeFilter(c)
+	if !ok {
+		return
+	}
+	filter.ProjectID = uint(projectID)
+	h.serveSSE(c, filter, true)
+}
+
+// StreamWebSocket pushes changes as JSON messages over a WebSocket. It takes the
+// same filters as Stream; clients resume with ?last_event_id=.
+func (h *StreamHandler) StreamWebSocket(c *gin.Context) {
+	filter, ok := parseChangeFilter(c)
+	if !ok {
+		return
+	}
+	lastEventID := lastEventIDFromRequest(c)
+
+	server := websocket.Server{Handshake: checkStreamOrigin, Handler: func(ws *websocket.Conn) {
+		defer ws.Close()
+
+		sub, replay, complete := h.feed.Subscribe(filter, lastEventID)
+		defer sub.Close()
+
+		if !complete {
+			if err := websocket.JSON.Send(ws, service.ChangeEvent{Type: streamResetEvent, Timestamp: time.Now().UTC()}); err != nil {
+				return
+			}
+		}
+		for _, change := range replay {
+			if err := websocket.JSON.Send(ws, change); err != nil {
+				return
+			}
+		}
+
+		// The client only sends to close the connection
+		closed := make(chan struct{})
+		go func() {
+			defer close(closed)
+			var discard string
+			for websocket.Message.Receive(ws, &discard) == nil {
+			}
+		}()
+
+		for {
+			select {
+			case change, ok := <-sub.Events():
+				if !ok {
+					return
+				}
+				if err := websocket.JSON.Send(ws, change); err != nil {
+					return
+				}
+			case <-closed:
+				return
+			}
+		}
+	}}
+	server.ServeHTTP(c.Writer, c.Request)
+}
+
+// checkStreamOrigin rejects WebSocket connections opened by pages of other sites.
+// Clients that are not browsers send no Origin and are let through.
+func checkStreamOrigin(config *websocket.Config, r *http.Request) error {
+	origin, err := websocket.Origin(config, r)
+	if err != nil {
+		return err
+	}
+	if origin != nil && !strings.EqualFold(origin.Host, r.Host) {
+		return fmt.Errorf("origin %s not allowed", origin)
+	}
+	config.Origin = origin
+	return nil
+}
+
+// serveSSE streams the changes matching filter. With signalOnly the changes are
+// sent without their content.
+func (h *StreamHandler) serveSSE(c *gin.Context, filter service.ChangeFilter, signalOnly bool) {
+	sub, replay, complete := h.feed.Subscribe(filter, lastEventIDFromRequest(c))
+	defer sub.Close()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	w := c.Writer
+	fmt.Fprint(w, "retry: 3000\n\n")
+	if !complete {
+		fmt.Fprintf(w, "event: %s\ndata: {}\n\n", streamResetEvent)
+	}
+	for _, change := range replay {
+		if signalOnly {
+			change = change.Signal()
+		}
+		if err := writeChangeEvent(w, change); err != nil {
+			return
+		}
+	}
+	w.Flush()
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case change, ok := <-sub.Events():
+			if !ok {
+				// Too slow to keep up; the client reconnects and resumes from its last ID
+				return
+			}
+			if signalOnly {
+				change = change.Signal()
+			}
+			if err := writeChangeEvent(w, change); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+				return
+			}
+		case <-c.Request.Context().Done():
+			return
+		}
+		w.Flush()
+	}
+}
+
+func writeChangeEvent(w gin.ResponseWriter, change service.ChangeEvent) error {
+	data, err := json.Marshal(change)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", change.ID, change.Type, data)
+	return err
+}
+
+func parseChangeFilter(c *gin.Context) (service.ChangeFilter, bool) {
+	filter := service.ChangeFilter{EntityTypes: service.ParseEntityTypes(c.Query("types"))}
+
+	for param, target := range map[string]*uint{
+		"project_id": &filter.ProjectID,
+		"task_id":    &filter.TaskID,
+		"epic_id":    &filter.EpicID,
+	} {
+		value := c.Query(param)
+		if value == "" {
+			continue
+		}
+		id, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid %s", param)})
+			return filter, false
+		}
+		*target = uint(id)
+	}
+
+	return filter, true
+}
+
+func lastEventIDFromRequest(c *gin.Context) uint64 {
+	value := c.GetHeader("Last-Event-ID")
+	if value == "" {
+		value = c.Query("last_event_id")
+	}
+	id, _ := strconv.ParseUint(value, 10, 64)
+	return id
+}
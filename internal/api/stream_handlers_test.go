@@ -0,0 +1,114 @@
+package api
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/headless-pm/headless-project-management/internal/events"
+	"github.com/headless-pm/headless-project-management/internal/models"
+	"github.com/headless-pm/headless-project-management/internal/service"
+	"golang.org/x/net/websocket"
+)
+
+// newStreamServer serves the change streams of a fresh feed that already holds
+// one change; it returns the ID before that change for resuming
+func newStreamServer(t *testing.T) (*httptest.Server, uint64) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	feed := service.NewChangeFeed(10)
+	start := &service.ChangeEvent{Type: "marker"}
+	feed.Publish(start)
+	feed.Publish(&service.ChangeEvent{
+		Type:       events.NameTaskUpdated,
+		EntityType: "task",
+		EntityID:   7,
+		ProjectID:  1,
+		Changes:    []events.FieldChange{{Field: "title", Old: "Draft", New: "Secret plans"}},
+		Actor:      &events.Actor{Name: "alice"},
+		Data:       &models.Task{ID: 7, ProjectID: 1, Title: "Secret plans"},
+	})
+
+	handler := NewStreamHandler(feed)
+	router := gin.New()
+	router.GET("/projects/:projectId/stream", handler.ProjectStream)
+	router.GET("/api/stream", handler.Stream)
+	router.GET("/api/stream/ws", handler.StreamWebSocket)
+
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+	return server, start.ID
+}
+
+// readSSEChange resumes a stream after lastEventID and returns the first change
+func readSSEChange(t *testing.T, url string, lastEventID uint64) map[string]interface{} {
+	t.Helper()
+	req, _ := http.NewRequest(http.MethodGet, url, nil)
+	req.Header.Set("Last-Event-ID", strconv.FormatUint(lastEventID, 10))
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("GET %s: %v", url, err)
+	}
+	defer resp.Body.Close()
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		if data, ok := strings.CutPrefix(scanner.Text(), "data: "); ok {
+			var change map[string]interface{}
+			if err := json.Unmarshal([]byte(data), &change); err != nil {
+				t.Fatalf("invalid change %s: %v", data, err)
+			}
+			return change
+		}
+	}
+	t.Fatalf("stream %s ended without a change", url)
+	return nil
+}
+
+func TestStreamResume(t *testing.T) {
+	server, lastEventID := newStreamServer(t)
+
+	change := readSSEChange(t, server.URL+"/api/stream?project_id=1", lastEventID)
+	if change["entity_id"] != float64(7) || change["data"] == nil || change["changes"] == nil {
+		t.Errorf("api stream change = %v, want the full change", change)
+	}
+
+	board := readSSEChange(t, server.URL+"/projects/1/stream", lastEventID)
+	if board["entity_id"] != float64(7) || board["type"] != events.NameTaskUpdated {
+		t.Errorf("board change = %v, want task 7 updated", board)
+	}
+	for _, field := range []string{"data", "changes", "actor"} {
+		if _, ok := board[field]; ok {
+			t.Errorf("board stream sent %s to an unauthenticated client: %v", field, board[field])
+		}
+	}
+}
+
+func TestStreamWebSocketOrigin(t *testing.T) {
+	server, lastEventID := newStreamServer(t)
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/api/stream/ws?last_event_id=" + strconv.FormatUint(lastEventID, 10)
+
+	if ws, err := websocket.Dial(url, "", "http://evil.example.com"); err == nil {
+		ws.Close()
+		t.Error("accepted a WebSocket from another site")
+	}
+
+	ws, err := websocket.Dial(url, "", server.URL)
+	if err != nil {
+		t.Fatalf("same-origin WebSocket rejected: %v", err)
+	}
+	defer ws.Close()
+	var change service.ChangeEvent
+	if err := websocket.JSON.Receive(ws, &change); err != nil {
+		t.Fatalf("receive: %v", err)
+	}
+	if change.EntityID != 7 {
+		t.Errorf("replayed change = %+v, want task 7", change)
+	}
+}
@@ -12,6 +12,8 @@ import (
 	"github.com/headless-pm/headless-project-management/internal/auth"
 	"github.com/headless-pm/headless-project-management/internal/database"
 	"github.com/headless-pm/headless-project-management/internal/models"
+	pkgauth "github.com/headless-pm/headless-project-management/pkg/auth"
+	"gorm.io/gorm"
 )
 
 type TokenHandler struct {
@@ -29,6 +31,7 @@ type CreateAPITokenRequest struct {
 	Description string  `json:"description"`
 	Scopes      string  `json:"scopes"`
 	ExpiresIn   int     `json:"expires_in_days"` // Number of days until expiration
+	UserID      uint    `json:"user_id"`         // User the token acts as, e.g. an agent; defaults to the caller
 }
 
 type APITokenResponse struct {
@@ -52,28 +55,15 @@ func (h *TokenHandler) CreateAPIToken(c *gin.Context) {
 		return
 	}
 
-	// Generate the token
-	token := generateSecureToken(32)
-
-	// Create token record
-	apiToken := &models.APIToken{
-		Name:      req.Name,
-		Token:     auth.HashToken(token),
-		Scope:     req.Scopes,
-		UserID:    c.GetUint("user_id"),
-	}
-
-	// Set expiration if specified
-	if req.ExpiresIn > 0 {
-		expiresAt := time.Now().AddDate(0, 0, req.ExpiresIn)
-		apiToken.ExpiresAt = &expiresAt
-	}
-
-	// Default scopes if not specified
-	if apiToken.Scope == "" {
-		apiToken.Scope = "read,write"
+	userID := req.UserID
+	if userID == 0 {
+		userID = c.GetUint("user_id")
+	} else if _, err := h.db.GetUserByID(userID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "User not found"})
+		return
 	}
 
+	apiToken, token := newAPIToken(userID, req.Name, req.Scopes, req.ExpiresIn)
 	if err := h.db.Create(apiToken).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create token"})
 		return
@@ -93,6 +83,105 @@ func (h *TokenHandler) CreateAPIToken(c *gin.Context) {
 	})
 }
 
+// newAPIToken generates a token acting as userID. Only its hash is stored; the
+// token itself is returned to be shown once.
+func newAPIToken(userID uint, name, scopes string, expiresInDays int) (*models.APIToken, string) {
+	token := generateSecureToken(32)
+	apiToken := &models.APIToken{
+		Name:   name,
+		Token:  auth.HashToken(token),
+		Scope:  scopes,
+		UserID: userID,
+	}
+
+	// Set expiration if specified
+	if expiresInDays > 0 {
+		expiresAt := time.Now().AddDate(0, 0, expiresInDays)
+		apiToken.ExpiresAt = &expiresAt
+	}
+
+	// Default scopes if not specified
+	if apiToken.Scope == "" {
+		apiToken.Scope = "read,write"
+	}
+	return apiToken, token
+}
+
+type CreateAgentRequest struct {
+	Username  string `json:"username" binding:"required"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+	Client    string `json:"client"` // Model or MCP client the agent runs as
+	Scopes    string `json:"scopes"`
+	ExpiresIn int    `json:"expires_in_days"`
+}
+
+// CreateAgent registers an agent, a user that acts only through API tokens,
+// together with its first token (admin only)
+func (h *TokenHandler) CreateAgent(c *gin.Context) {
+	var req CreateAgentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Agents cannot log in: their password is a random secret nobody knows
+	password, err := pkgauth.HashPassword(generateSecureToken(32))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create agent"})
+		return
+	}
+	agent := &models.User{
+		Username:  req.Username,
+		Email:     req.Username + "@agents.invalid",
+		Password:  password,
+		FirstName: req.FirstName,
+		LastName:  req.LastName,
+		Role:      models.UserRoleMember,
+		Type:      models.UserTypeAgent,
+		Client:    req.Client,
+		IsActive:  true,
+	}
+	apiToken, token := newAPIToken(0, req.Username, req.Scopes, req.ExpiresIn)
+
+	err = h.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(agent).Error; err != nil {
+			return err
+		}
+		apiToken.UserID = agent.ID
+		return tx.Create(apiToken).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Failed to create agent: %v", err)})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"agent": agent,
+		"token": APITokenResponse{
+			ID:        apiToken.ID,
+			Name:      apiToken.Name,
+			Token:     token,
+			Scopes:    apiToken.Scope,
+			CreatedBy: fmt.Sprintf("%d", apiToken.UserID),
+			ExpiresAt: apiToken.ExpiresAt,
+			IsActive:  true,
+			CreatedAt: apiToken.CreatedAt,
+		},
+	})
+}
+
+// ListAgents lists the agent users (admin only)
+func (h *TokenHandler) ListAgents(c *gin.Context) {
+	var agents []models.User
+	if err := h.db.Where("type = ?", models.UserTypeAgent).Order("username").Find(&agents).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch agents"})
+		return
+	}
+
+	c.JSON(http.StatusOK, agents)
+}
+
 // ListAPITokens lists all API tokens (admin only)
 func (h *TokenHandler) ListAPITokens(c *gin.Context) {
 	var tokens []models.APIToken
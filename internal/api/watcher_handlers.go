@@ -0,0 +1,116 @@
+package api
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/headless-pm/headless-project-management/internal/database"
+)
+
+type WatcherHandler struct {
+	db *database.Database
+}
+
+func NewWatcherHandler(db *database.Database) *WatcherHandler {
+	return &WatcherHandler{db: db}
+}
+
+func (h *WatcherHandler) WatchTask(c *gin.Context) {
+	taskID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid task ID"})
+		return
+	}
+
+	var req struct {
+		UserID uint `json:"user_id"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil && err != io.EOF {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Callers watch tasks themselves; only admins can subscribe other users
+	if req.UserID == 0 {
+		userID, ok := currentUserID(c)
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "user_id is required for admin tokens"})
+			return
+		}
+		req.UserID = userID
+	} else if !canActFor(c, req.UserID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only admins can add other users as watchers"})
+		return
+	}
+
+	if err := h.db.AddWatcher(uint(taskID), req.UserID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Task watched"})
+}
+
+func (h *WatcherHandler) UnwatchTask(c *gin.Context) {
+	taskID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid task ID"})
+		return
+	}
+
+	userID, err := strconv.ParseUint(c.Param("userId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+	if !canActFor(c, uint(userID)) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only admins can remove other users as watchers"})
+		return
+	}
+
+	if err := h.db.RemoveWatcher(uint(taskID), uint(userID)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Task unwatched"})
+}
+
+func (h *WatcherHandler) GetTaskWatchers(c *gin.Context) {
+	taskID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid task ID"})
+		return
+	}
+
+	watchers, err := h.db.GetTaskWatchers(uint(taskID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, watchers)
+}
+
+// GetWatchedTasks lists the tasks a user watches; only the user and admins can see them
+func (h *WatcherHandler) GetWatchedTasks(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+	if !canActFor(c, uint(userID)) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You can only view your own watched tasks"})
+		return
+	}
+
+	tasks, err := h.db.GetWatchedTasks(uint(userID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, tasks)
+}
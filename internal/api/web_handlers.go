@@ -9,6 +9,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/gomarkdown/markdown"
+	"github.com/gomarkdown/markdown/ast"
 	"github.com/gomarkdown/markdown/html"
 	"github.com/gomarkdown/markdown/parser"
 	"github.com/headless-pm/headless-project-management/internal/database"
@@ -24,6 +25,7 @@ func RenderMarkdown(md string) template.HTML {
 	// Create markdown parser with extensions
 	extensions := parser.CommonExtensions | parser.AutoHeadingIDs | parser.NoEmptyLineBeforeBlock
 	p := parser.NewWithExtensions(extensions)
+	p.RegisterInline('@', parseMention)
 	doc := p.Parse([]byte(md))
 
 	// Create HTML renderer with options
@@ -35,6 +37,30 @@ func RenderMarkdown(md string) template.HTML {
 	return template.HTML(htmlContent)
 }
 
+// parseMention highlights an @username mention. It is not linked: a user's
+// mentions page is private to that user, and the pages rendering it are public.
+func parseMention(p *parser.Parser, data []byte, offset int) (int, ast.Node) {
+	// Only match at the start of a word, so e-mail addresses are left alone
+	if offset > 0 {
+		prev := data[offset-1]
+		if prev == '_' || prev == '@' || prev == '.' || (prev >= '0' && prev <= '9') ||
+			(prev >= 'a' && prev <= 'z') || (prev >= 'A' && prev <= 'Z') {
+			return 0, nil
+		}
+	}
+
+	match := models.MentionPattern.FindSubmatchIndex(data[offset:])
+	if match == nil || match[0] != 0 {
+		return 0, nil
+	}
+	username := string(data[offset+match[2] : offset+match[3]])
+
+	// MentionPattern only matches letters, digits, '_', '.' and '-', so the
+	// username needs no escaping
+	span := &ast.HTMLSpan{Leaf: ast.Leaf{Literal: []byte(`<span class="mention">@` + username + `</span>`)}}
+	return match[3], span
+}
+
 type TaskPageData struct {
 	Tasks         []models.Task
 	TasksByStatus map[string][]models.Task
@@ -260,10 +286,41 @@ func (h *WebHandler) ProjectBoardPage(c *gin.Context) {
 		Group("users.id").
 		Find(&assigneeUsers)
 
+	// Get sprints for this project for the sprint board selector
+	sprints, _ := h.db.ListSprints(&project.ID, nil)
+
 	// Get selected filters from query
 	selectedLabelIDs := c.QueryArray("labels")
 	selectedAssigneeIDs := c.QueryArray("assignees")
 
+	// Sprint board: the sprint comes from the path or the query ("active" and "backlog" are accepted)
+	selectedSprint := c.Param("sprintId")
+	if selectedSprint == "" {
+		selectedSprint = c.Query("sprint")
+	}
+	var currentSprint *models.Sprint
+	if selectedSprint == "active" {
+		if active, err := h.db.GetActiveSprint(project.ID); err == nil {
+			currentSprint = active
+			selectedSprint = strconv.FormatUint(uint64(active.ID), 10)
+		} else {
+			selectedSprint = ""
+		}
+	} else if selectedSprint != "" && selectedSprint != "backlog" {
+		for i := range sprints {
+			if strconv.FormatUint(uint64(sprints[i].ID), 10) == selectedSprint {
+				currentSprint = &sprints[i]
+				break
+			}
+		}
+		if currentSprint == nil {
+			c.HTML(http.StatusNotFound, "error.html", gin.H{
+				"Error": "Sprint not found",
+			})
+			return
+		}
+	}
+
 	// Get tasks for this project with filters
 	filters := TaskFilters{
 		Status:   c.Query("status"),
@@ -295,6 +352,13 @@ func (h *WebHandler) ProjectBoardPage(c *gin.Context) {
 		query = query.Where("assignee_id IN ?", selectedAssigneeIDs)
 	}
 
+	// Filter by sprint if selected
+	if currentSprint != nil {
+		query = query.Where("sprint_id = ?", currentSprint.ID)
+	} else if selectedSprint == "backlog" {
+		query = query.Where("sprint_id IS NULL")
+	}
+
 	// Get tasks
 	var tasks []models.Task
 	if err := query.Order("created_at DESC").Find(&tasks).Error; err != nil {
@@ -309,6 +373,12 @@ func (h *WebHandler) ProjectBoardPage(c *gin.Context) {
 	var filteredTasks []models.Task
 	var archivedCount int
 	for _, task := range tasks {
+		// A sprint board shows the whole sprint, so nothing is archived there
+		if currentSprint != nil {
+			filteredTasks = append(filteredTasks, task)
+			continue
+		}
+
 		// Archive done tasks only if they have a completed_at timestamp older than 2 days
 		// Keep all non-done tasks and done tasks that are recent or have no completion timestamp
 		if task.Status != models.TaskStatusDone {
@@ -430,6 +500,9 @@ func (h *WebHandler) ProjectBoardPage(c *gin.Context) {
 		"DependencyCounts": taskDependencyCounts,
 		"ArchivedCount": archivedCount,
 		"ProjectID":     projectID,
+		"Sprints":       sprints,
+		"SelectedSprint": selectedSprint,
+		"CurrentSprint": currentSprint,
 	})
 }
 
@@ -483,7 +556,7 @@ func (h *WebHandler) TaskDetailPage(c *gin.Context) {
 	var task models.Task
 	if err := h.db.DB.Preload("Project").
 		Preload("Comments").
-		Preload("Attachments").
+		Preload("Attachments", "comment_id IS NULL").
 		Preload("Labels").
 		Preload("Subtasks").
 		Preload("AssigneeUser").
@@ -527,25 +600,16 @@ func (h *WebHandler) TaskDetailPage(c *gin.Context) {
 		renderedDescription = RenderMarkdown(task.Description)
 	}
 
-	// Render markdown for each comment
-	type RenderedComment struct {
-		models.Comment
-		RenderedContent template.HTML
-	}
-	var renderedComments []RenderedComment
-	for _, comment := range task.Comments {
-		renderedComments = append(renderedComments, RenderedComment{
-			Comment:         comment,
-			RenderedContent: RenderMarkdown(comment.Content),
-		})
-	}
+	// Render markdown for each comment, nesting replies under their parent
+	comments, _ := h.db.ListComments(task.ID, true)
+	renderedComments := renderComments(comments)
 
 	// Render task detail template
 	c.HTML(http.StatusOK, "task_detail.html", gin.H{
 		"Task":                task,
 		"ProjectID":           projectID,
 		"RenderedDescription": renderedDescription,  // Markdown-rendered description
-		"RenderedComments":    renderedComments,     // Comments with rendered markdown
+		"RenderedComments":    renderedComments,     // Comment threads with rendered markdown
 		"DependsOnTasks":      dependsOnTasks,       // Tasks this task depends on
 		"DependentTasks":      dependentTasks,       // Tasks that depend on this task
 		"Activities":          activities,           // Activity timeline
@@ -764,7 +828,105 @@ func (h *WebHandler) EpicDetailPage(c *gin.Context) {
 	})
 }
 
+// userFromParam resolves the :userId path parameter, which can be a user ID or a username
+func (h *WebHandler) userFromParam(c *gin.Context) (*models.User, error) {
+	param := c.Param("userId")
+	if id, err := strconv.ParseUint(param, 10, 32); err == nil {
+		return h.db.GetUserByID(uint(id))
+	}
+	return h.db.GetUserByUsername(param)
+}
+
+func (h *WebHandler) NotificationsPage(c *gin.Context) {
+	user, err := h.userFromParam(c)
+	if err != nil {
+		c.HTML(http.StatusNotFound, "error.html", gin.H{
+			"Error": "User not found",
+		})
+		return
+	}
+
+	if !canActFor(c, user.ID) {
+		c.HTML(http.StatusForbidden, "error.html", gin.H{
+			"Error": "You can only view your own notifications",
+		})
+		return
+	}
+
+	unreadOnly := c.Query("unread") == "true"
+	notifications, err := h.db.ListNotifications(user.ID, unreadOnly, 100)
+	if err != nil {
+		c.HTML(http.StatusInternalServerError, "error.html", gin.H{
+			"Error": "Failed to load notifications",
+		})
+		return
+	}
+
+	unreadCount, _ := h.db.CountUnreadNotifications(user.ID)
+	watchedTasks, _ := h.db.GetWatchedTasks(user.ID)
+
+	c.HTML(http.StatusOK, "notifications.html", gin.H{
+		"User":          user,
+		"Notifications": notifications,
+		"UnreadCount":   unreadCount,
+		"UnreadOnly":    unreadOnly,
+		"WatchedTasks":  watchedTasks,
+	})
+}
+
+// MentionsPage lists the tasks that mention a user; @mention links point here
+func (h *WebHandler) MentionsPage(c *gin.Context) {
+	user, err := h.userFromParam(c)
+	if err != nil {
+		c.HTML(http.StatusNotFound, "error.html", gin.H{
+			"Error": "User not found",
+		})
+		return
+	}
+
+	if !canActFor(c, user.ID) {
+		c.HTML(http.StatusForbidden, "error.html", gin.H{
+			"Error": "You can only view your own mentions",
+		})
+		return
+	}
+
+	tasks, err := h.db.GetTasksMentioningUser(user.ID)
+	if err != nil {
+		c.HTML(http.StatusInternalServerError, "error.html", gin.H{
+			"Error": "Failed to load mentions",
+		})
+		return
+	}
+
+	c.HTML(http.StatusOK, "mentions.html", gin.H{
+		"User":  user,
+		"Tasks": tasks,
+	})
+}
+
 func parseUint(s string) uint {
 	val, _ := strconv.ParseUint(s, 10, 32)
 	return uint(val)
+}
+
+// RenderedComment is a comment with its markdown rendered and its replies rendered in turn
+type RenderedComment struct {
+	models.Comment
+	RenderedContent template.HTML
+	ReactionSummary []models.ReactionSummary
+	RenderedReplies []RenderedComment
+}
+
+func renderComments(comments []models.Comment) []RenderedComment {
+	var rendered []RenderedComment
+	for _, comment := range comments {
+		rendered = append(rendered, RenderedComment{
+			Comment:         comment,
+			RenderedContent: RenderMarkdown(comment.Content),
+			ReactionSummary: models.SummarizeReactions(comment.Reactions),
+			RenderedReplies: renderComments(comment.Replies),
+		})
+	}
+	return rendered
 }
\ No newline at end of file
@@ -0,0 +1,50 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/headless-pm/headless-project-management/internal/auth"
+)
+
+func TestInboxPagesArePrivate(t *testing.T) {
+	db, router := newTestRouter(t)
+	alice := createUserToken(t, db, "alice")
+	bob := createUserToken(t, db, "bob")
+
+	router.LoadHTMLGlob("../../templates/*")
+	web := NewWebHandler(db)
+	router.GET("/users/:userId/notifications", auth.AuthMiddleware(db), web.NotificationsPage)
+	router.GET("/users/:userId/mentions", auth.AuthMiddleware(db), web.MentionsPage)
+
+	tests := []struct {
+		name  string
+		token string
+		path  string
+		want  int
+	}{
+		{"anonymous", "", "/users/alice/notifications", http.StatusUnauthorized},
+		{"owner", alice, "/users/alice/notifications", http.StatusOK},
+		{"another user", bob, "/users/alice/notifications", http.StatusForbidden},
+		{"admin", "admin-secret", "/users/alice/notifications", http.StatusOK},
+		{"anonymous", "", "/users/alice/mentions", http.StatusUnauthorized},
+		{"owner", alice, "/users/alice/mentions", http.StatusOK},
+		{"another user", bob, "/users/alice/mentions", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		if w := serve(router, http.MethodGet, tt.path, tt.token, ""); w.Code != tt.want {
+			t.Errorf("%s: GET %s = %d, want %d", tt.name, tt.path, w.Code, tt.want)
+		}
+	}
+}
+
+func TestRenderMarkdownHighlightsMentions(t *testing.T) {
+	html := string(RenderMarkdown("Ask @alice, not mail@example.com"))
+	if !strings.Contains(html, `<span class="mention">@alice</span>`) {
+		t.Errorf("mention is not highlighted: %s", html)
+	}
+	if strings.Contains(html, "/mentions") || strings.Contains(html, "@example.com</span>") {
+		t.Errorf("rendered %s", html)
+	}
+}
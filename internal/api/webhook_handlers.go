@@ -0,0 +1,296 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/headless-pm/headless-project-management/internal/database"
+	"github.com/headless-pm/headless-project-management/internal/models"
+	"github.com/headless-pm/headless-project-management/internal/service"
+)
+
+type WebhookHandler struct {
+	db         *database.Database
+	dispatcher *service.WebhookDispatcher
+}
+
+// NewWebhookHandler creates the webhook handler. Pings and redeliveries are sent
+// right away through the dispatcher; without one they wait in the outbox.
+func NewWebhookHandler(db *database.Database, dispatcher *service.WebhookDispatcher) *WebhookHandler {
+	return &WebhookHandler{db: db, dispatcher: dispatcher}
+}
+
+type WebhookRequest struct {
+	Name      string   `json:"name"`
+	URL       string   `json:"url"`
+	ProjectID *uint    `json:"project_id"`
+	Events    []string `json:"events"`
+	Secret    string   `json:"secret"`
+	Active    *bool    `json:"active"`
+}
+
+// WebhookResponse includes the signing secret, which is only returned when it is set
+type WebhookResponse struct {
+	models.Webhook
+	Secret string `json:"secret,omitempty"`
+}
+
+// ListWebhookEvents lists the events webhooks can subscribe to
+func (h *WebhookHandler) ListWebhookEvents(c *gin.Context) {
+	c.JSON(http.StatusOK, models.GetWebhookEvents())
+}
+
+// CreateWebhook creates a webhook subscription (admin only). A signing secret is
+// generated unless one is given.
+func (h *WebhookHandler) CreateWebhook(c *gin.Context) {
+	var req WebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if req.ProjectID != nil {
+		if _, err := h.db.GetProject(*req.ProjectID); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+			return
+		}
+	}
+
+	webhook := models.Webhook{
+		Name:      req.Name,
+		URL:       req.URL,
+		ProjectID: req.ProjectID,
+		Events:    strings.Join(req.Events, ","),
+		Secret:    req.Secret,
+		Active:    true,
+	}
+	if err := h.db.CreateWebhook(&webhook); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if req.Active != nil && !*req.Active {
+		webhook.Active = false
+		if err := h.db.UpdateWebhook(&webhook); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update webhook"})
+			return
+		}
+	}
+
+	c.JSON(http.StatusCreated, WebhookResponse{Webhook: webhook, Secret: webhook.Secret})
+}
+
+// ListWebhooks lists webhooks, optionally only those of ?project_id=
+func (h *WebhookHandler) ListWebhooks(c *gin.Context) {
+	var projectID *uint
+	if value := c.Query("project_id"); value != "" {
+		id, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
+			return
+		}
+		pid := uint(id)
+		projectID = &pid
+	}
+
+	webhooks, err := h.db.ListWebhooks(projectID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch webhooks"})
+		return
+	}
+
+	c.JSON(http.StatusOK, webhooks)
+}
+
+func (h *WebhookHandler) GetWebhook(c *gin.Context) {
+	webhook, ok := h.webhookFromParam(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, webhook)
+}
+
+// UpdateWebhook updates the fields given in the request
+func (h *WebhookHandler) UpdateWebhook(c *gin.Context) {
+	webhook, ok := h.webhookFromParam(c)
+	if !ok {
+		return
+	}
+
+	var req WebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if req.Name != "" {
+		webhook.Name = req.Name
+	}
+	if req.URL != "" {
+		webhook.URL = req.URL
+	}
+	if req.ProjectID != nil {
+		if *req.ProjectID == 0 {
+			webhook.ProjectID = nil
+		} else if _, err := h.db.GetProject(*req.ProjectID); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+			return
+		} else {
+			webhook.ProjectID = req.ProjectID
+		}
+		webhook.Project = nil
+	}
+	if req.Events != nil {
+		webhook.Events = strings.Join(req.Events, ",")
+	}
+	if req.Secret != "" {
+		webhook.Secret = req.Secret
+	}
+	if req.Active != nil {
+		webhook.Active = *req.Active
+	}
+
+	if err := h.db.UpdateWebhook(webhook); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	response := WebhookResponse{Webhook: *webhook}
+	if req.Secret != "" {
+		response.Secret = webhook.Secret
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	webhook, ok := h.webhookFromParam(c)
+	if !ok {
+		return
+	}
+
+	if err := h.db.DeleteWebhook(webhook.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete webhook"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Webhook deleted successfully"})
+}
+
+// PingWebhook sends a ping event to the webhook and returns the delivery
+func (h *WebhookHandler) PingWebhook(c *gin.Context) {
+	webhook, ok := h.webhookFromParam(c)
+	if !ok {
+		return
+	}
+
+	delivery, err := h.db.PingWebhook(webhook.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to queue ping"})
+		return
+	}
+
+	h.deliverNow(c, delivery)
+}
+
+// ListDeliveries returns the delivery log of a webhook, filtered by ?status= and ?limit=
+func (h *WebhookHandler) ListDeliveries(c *gin.Context) {
+	webhook, ok := h.webhookFromParam(c)
+	if !ok {
+		return
+	}
+
+	var status *models.WebhookDeliveryStatus
+	if value := c.Query("status"); value != "" {
+		s := models.WebhookDeliveryStatus(value)
+		status = &s
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+
+	deliveries, err := h.db.ListWebhookDeliveries(webhook.ID, status, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch deliveries"})
+		return
+	}
+
+	c.JSON(http.StatusOK, deliveries)
+}
+
+func (h *WebhookHandler) GetDelivery(c *gin.Context) {
+	delivery, ok := h.deliveryFromParam(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, delivery)
+}
+
+// RedeliverDelivery sends the payload of an earlier delivery again as a new delivery
+func (h *WebhookHandler) RedeliverDelivery(c *gin.Context) {
+	original, ok := h.deliveryFromParam(c)
+	if !ok {
+		return
+	}
+
+	delivery, err := h.db.RedeliverWebhookDelivery(original.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to queue redelivery"})
+		return
+	}
+
+	h.deliverNow(c, delivery)
+}
+
+// deliverNow sends a queued delivery immediately when there is a dispatcher;
+// otherwise the delivery stays in the outbox
+func (h *WebhookHandler) deliverNow(c *gin.Context, delivery *models.WebhookDelivery) {
+	if h.dispatcher == nil {
+		c.JSON(http.StatusAccepted, delivery)
+		return
+	}
+
+	sent, err := h.dispatcher.Deliver(delivery.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, sent)
+}
+
+func (h *WebhookHandler) webhookFromParam(c *gin.Context) (*models.Webhook, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid webhook ID"})
+		return nil, false
+	}
+
+	webhook, err := h.db.GetWebhook(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Webhook not found"})
+		return nil, false
+	}
+	return webhook, true
+}
+
+func (h *WebhookHandler) deliveryFromParam(c *gin.Context) (*models.WebhookDelivery, bool) {
+	webhook, ok := h.webhookFromParam(c)
+	if !ok {
+		return nil, false
+	}
+
+	id, err := strconv.ParseUint(c.Param("deliveryId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid delivery ID"})
+		return nil, false
+	}
+
+	delivery, err := h.db.GetWebhookDelivery(uint(id))
+	if err != nil || delivery.WebhookID != webhook.ID {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Delivery not found"})
+		return nil, false
+	}
+	return delivery, true
+}
@@ -0,0 +1,39 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/headless-pm/headless-project-management/internal/models"
+	"github.com/headless-pm/headless-project-management/internal/service"
+)
+
+func TestPingUsesTheInjectedDispatcher(t *testing.T) {
+	db, _ := newTestRouter(t)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer receiver.Close()
+	if err := db.CreateWebhook(&models.Webhook{Name: "CI", URL: receiver.URL, Active: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	ping := func(handler *WebhookHandler) *httptest.ResponseRecorder {
+		router := gin.New()
+		router.POST("/webhooks/:id/ping", handler.PingWebhook)
+		return serve(router, http.MethodPost, "/webhooks/1/ping", "", "")
+	}
+
+	w := ping(NewWebhookHandler(db, service.NewWebhookDispatcher(db, time.Hour)))
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"status":"succeeded"`) {
+		t.Errorf("ping with a dispatcher: %d %s", w.Code, w.Body)
+	}
+	w = ping(NewWebhookHandler(db, nil))
+	if w.Code != http.StatusAccepted || !strings.Contains(w.Body.String(), `"status":"pending"`) {
+		t.Errorf("ping without a dispatcher: %d %s", w.Code, w.Body)
+	}
+}
@@ -0,0 +1,326 @@
+package api
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/headless-pm/headless-project-management/internal/database"
+	"github.com/headless-pm/headless-project-management/internal/models"
+)
+
+type WorklogHandler struct {
+	db *database.Database
+}
+
+func NewWorklogHandler(db *database.Database) *WorklogHandler {
+	return &WorklogHandler{db: db}
+}
+
+// CreateWorklog logs time of the caller. Admin tokens name the user with ?user_id=.
+func (h *WorklogHandler) CreateWorklog(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "user_id is required for admin tokens"})
+		return
+	}
+
+	var worklog models.Worklog
+	if err := c.ShouldBindJSON(&worklog); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	worklog.UserID = userID
+
+	// Worklogs logged without a start time ended now
+	if worklog.StartedAt.IsZero() && worklog.EndedAt == nil && worklog.DurationMinutes > 0 {
+		worklog.StartedAt = time.Now().Add(-time.Duration(worklog.DurationMinutes) * time.Minute)
+	}
+
+	if err := h.db.CreateWorklog(&worklog); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, worklog)
+}
+
+func (h *WorklogHandler) GetWorklog(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid worklog ID"})
+		return
+	}
+
+	worklog, err := h.db.GetWorklog(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Worklog not found"})
+		return
+	}
+	if !canActFor(c, worklog.UserID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You can only view your own worklogs"})
+		return
+	}
+
+	c.JSON(http.StatusOK, worklog)
+}
+
+// ListWorklogs lists worklogs, filtered by ?task_id= and ?user_id=. Users only
+// see their own; admins see everyone's unless they name a user.
+func (h *WorklogHandler) ListWorklogs(c *gin.Context) {
+	var taskID *uint
+
+	if tid := c.Query("task_id"); tid != "" {
+		id, err := strconv.ParseUint(tid, 10, 32)
+		if err == nil {
+			uid := uint(id)
+			taskID = &uid
+		}
+	}
+
+	userID, ok := worklogUser(c, c.Query("user_id"))
+	if !ok {
+		return
+	}
+
+	worklogs, err := h.db.ListWorklogs(taskID, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, worklogs)
+}
+
+func (h *WorklogHandler) UpdateWorklog(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid worklog ID"})
+		return
+	}
+
+	worklog, err := h.db.GetWorklog(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Worklog not found"})
+		return
+	}
+
+	if !canActFor(c, worklog.UserID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the owner of a worklog or an admin can change it"})
+		return
+	}
+
+	ownerID := worklog.UserID
+	if err := c.ShouldBindJSON(worklog); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	worklog.ID = uint(id)
+	worklog.UserID = ownerID
+	worklog.Task = nil
+	worklog.User = nil
+	if err := h.db.UpdateWorklog(worklog); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, worklog)
+}
+
+func (h *WorklogHandler) DeleteWorklog(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid worklog ID"})
+		return
+	}
+
+	worklog, err := h.db.GetWorklog(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Worklog not found"})
+		return
+	}
+	if !canActFor(c, worklog.UserID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the owner of a worklog or an admin can delete it"})
+		return
+	}
+
+	if err := h.db.DeleteWorklog(uint(id)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// StartTimer starts the caller's timer on a task
+func (h *WorklogHandler) StartTimer(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "user_id is required for admin tokens"})
+		return
+	}
+
+	var req struct {
+		TaskID uint   `json:"task_id" binding:"required"`
+		Note   string `json:"note"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	worklog, err := h.db.StartTimer(req.TaskID, userID, req.Note)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, worklog)
+}
+
+// StopTimer stops the caller's running timer
+func (h *WorklogHandler) StopTimer(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "user_id is required for admin tokens"})
+		return
+	}
+
+	var req struct {
+		Note string `json:"note"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil && err != io.EOF {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	worklog, err := h.db.StopTimer(userID, req.Note)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, worklog)
+}
+
+// GetRunningTimer returns the caller's running timer
+func (h *WorklogHandler) GetRunningTimer(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "user_id is required for admin tokens"})
+		return
+	}
+
+	worklog, err := h.db.GetRunningTimer(userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No running timer"})
+		return
+	}
+
+	c.JSON(http.StatusOK, worklog)
+}
+
+// worklogUser returns the user whose worklogs the caller reads: the one named by
+// userParam, or the caller when none is named. Only admins read other users'
+// worklogs, and everyone's when they name no user, for which nil is returned.
+// When ok is false the request was already answered with an error.
+func worklogUser(c *gin.Context, userParam string) (userID *uint, ok bool) {
+	if userParam == "" {
+		if isAdminRequest(c) {
+			return nil, true
+		}
+		id, found := currentUserID(c)
+		if !found {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "user_id is required"})
+			return nil, false
+		}
+		return &id, true
+	}
+
+	id, err := strconv.ParseUint(userParam, 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return nil, false
+	}
+	uid := uint(id)
+	if !canActFor(c, uid) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You can only view your own worklogs"})
+		return nil, false
+	}
+	return &uid, true
+}
+
+// GetTimesheet reports logged time. The user and project come from the path
+// (/timesheets/user/:userId, /timesheets/project/:projectId) or the query string.
+// Users only get their own time; admins get everyone's unless they name a user.
+// Use format=csv to download the report for billing.
+func (h *WorklogHandler) GetTimesheet(c *gin.Context) {
+	var filter database.TimesheetFilter
+
+	userParam := c.Param("userId")
+	if userParam == "" {
+		userParam = c.Query("user_id")
+	}
+	userID, ok := worklogUser(c, userParam)
+	if !ok {
+		return
+	}
+	filter.UserID = userID
+
+	projectParam := c.Param("projectId")
+	if projectParam == "" {
+		projectParam = c.Query("project_id")
+	}
+	if projectParam != "" {
+		id, err := strconv.ParseUint(projectParam, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
+			return
+		}
+		pid := uint(id)
+		filter.ProjectID = &pid
+	}
+
+	if from := c.Query("from"); from != "" {
+		date, err := time.Parse("2006-01-02", from)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid from date, expected YYYY-MM-DD"})
+			return
+		}
+		filter.From = &date
+	}
+
+	if to := c.Query("to"); to != "" {
+		date, err := time.Parse("2006-01-02", to)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid to date, expected YYYY-MM-DD"})
+			return
+		}
+		// The end date is inclusive
+		end := date.AddDate(0, 0, 1)
+		filter.To = &end
+	}
+
+	timesheet, err := h.db.GetTimesheet(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if c.Query("format") == "csv" {
+		filename := fmt.Sprintf("timesheet-%s.csv", time.Now().Format("20060102"))
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+		c.Header("Content-Type", "text/csv")
+		c.Status(http.StatusOK)
+		if err := timesheet.WriteCSV(c.Writer); err != nil {
+			c.Error(err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, timesheet)
+}
@@ -56,7 +56,7 @@ func AuthMiddleware(db *database.Database, requiredScopes ...string) gin.Handler
 		// Look up token in database
 		var apiToken models.APIToken
 		tokenHash := HashToken(token)
-		err := db.Where("token_hash = ?", tokenHash).First(&apiToken).Error
+		err := db.Where("token = ?", tokenHash).First(&apiToken).Error
 		if err != nil {
 			c.JSON(http.StatusUnauthorized, gin.H{
 				"error": "Invalid or expired token",
@@ -97,6 +97,13 @@ func AuthMiddleware(db *database.Database, requiredScopes ...string) gin.Handler
 		c.Set("scopes", apiToken.Scope)
 		c.Set("is_admin", strings.Contains(apiToken.Scope, "admin"))
 
+		// Writes made for the request are attributed to the token's user
+		var user models.User
+		if err := db.First(&user, apiToken.UserID).Error; err == nil {
+			ctx := database.WithPrincipal(c.Request.Context(), database.PrincipalForUser(&user, ""))
+			c.Request = c.Request.WithContext(ctx)
+		}
+
 		c.Next()
 	}
 }
@@ -142,7 +149,7 @@ func OptionalAuth(db *database.Database) gin.HandlerFunc {
 			// Try database token
 			var apiToken models.APIToken
 			tokenHash := HashToken(token)
-			err := db.Where("token_hash = ?", tokenHash).First(&apiToken).Error
+			err := db.Where("token = ?", tokenHash).First(&apiToken).Error
 			if err == nil && (apiToken.ExpiresAt == nil || apiToken.ExpiresAt.After(time.Now())) {
 				c.Set("authenticated", true)
 				c.Set("token_id", apiToken.ID)
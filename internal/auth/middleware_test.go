@@ -0,0 +1,76 @@
+package auth_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/headless-pm/headless-project-management/internal/api"
+	"github.com/headless-pm/headless-project-management/internal/auth"
+	"github.com/headless-pm/headless-project-management/internal/database"
+	"github.com/headless-pm/headless-project-management/internal/models"
+	"gorm.io/gorm/logger"
+)
+
+// A token created through the admin API must authenticate afterwards.
+func TestCreatedAPITokenAuthenticates(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Setenv("ADMIN_API_TOKEN", "admin-secret")
+
+	db, err := database.NewDatabaseWithLogger(t.TempDir(), logger.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	user := models.User{Username: "alice", Email: "alice@example.com"}
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	router := gin.New()
+	router.POST("/admin/tokens", auth.AuthMiddleware(db), auth.AdminOnly(), api.NewTokenHandler(db).CreateAPIToken)
+	whoami := func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"user_id": c.GetUint("user_id")})
+	}
+	router.GET("/whoami", auth.AuthMiddleware(db), whoami)
+	router.GET("/optional", auth.OptionalAuth(db), whoami)
+
+	serve := func(method, path, token, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := serve(http.MethodPost, "/admin/tokens", "admin-secret", `{"name":"ci","scopes":"read,write","user_id":1}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("creating token: %d %s", w.Code, w.Body)
+	}
+	var created api.APITokenResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &created); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, path := range []string{"/whoami", "/optional"} {
+		w = serve(http.MethodGet, path, created.Token, "")
+		var body struct {
+			UserID uint `json:"user_id"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Fatal(err)
+		}
+		if w.Code != http.StatusOK || body.UserID != user.ID {
+			t.Errorf("%s with created token: %d %s, want user %d", path, w.Code, w.Body, user.ID)
+		}
+	}
+
+	if w = serve(http.MethodGet, "/whoami", created.Token+"x", ""); w.Code != http.StatusUnauthorized {
+		t.Errorf("wrong token: got %d, want 401", w.Code)
+	}
+}
@@ -0,0 +1,243 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/headless-pm/headless-project-management/internal/events"
+	"github.com/headless-pm/headless-project-management/internal/models"
+	"gorm.io/gorm"
+)
+
+// maxReactionLength bounds reactions to a single emoji or a short :shortcode:
+const maxReactionLength = 32
+
+// orderReactions keeps reactions in the order they were left
+func orderReactions(db *gorm.DB) *gorm.DB {
+	return db.Order("created_at ASC, id ASC")
+}
+
+// resolveCommentAuthor links a comment to its author. Requests made for a user
+// always comment as that user. Without one, e.g. local tools, an author ID wins
+// over the free-text author name; a name that matches a username is linked to that user.
+func (db *Database) resolveCommentAuthor(comment *models.Comment) error {
+	if principal := db.principal(); principal != nil {
+		if comment.AuthorID != nil && *comment.AuthorID != 0 && !sameUint(comment.AuthorID, principal.UserID) {
+			return fmt.Errorf("comments are posted as the authenticated user")
+		}
+		comment.AuthorID = principal.UserID
+		comment.Author = principal.Name
+		return nil
+	}
+
+	if comment.AuthorID != nil && *comment.AuthorID != 0 {
+		user, err := db.GetUserByID(*comment.AuthorID)
+		if err != nil {
+			return fmt.Errorf("author not found")
+		}
+		comment.Author = user.Username
+		return nil
+	}
+
+	comment.AuthorID = nil
+	comment.Author = strings.TrimSpace(comment.Author)
+	if comment.Author == "" {
+		return fmt.Errorf("comment author is required")
+	}
+	if user, err := db.GetUserByUsername(comment.Author); err == nil {
+		comment.AuthorID = &user.ID
+	}
+	return nil
+}
+
+// EditComment replaces the content of a comment. The previous content is kept as
+// a revision and the comment is marked as edited. Requests made for a user are
+// edited by that user; otherwise editorID names the editor and defaults to the author.
+func (db *Database) EditComment(commentID uint, content string, editorID *uint) error {
+	comment, err := db.GetComment(commentID)
+	if err != nil {
+		return err
+	}
+	if comment.Content == content {
+		return nil
+	}
+
+	revision := models.CommentRevision{
+		CommentID:  comment.ID,
+		Content:    comment.Content,
+		EditedByID: comment.AuthorID,
+		EditedBy:   comment.Author,
+	}
+	if principal := db.principal(); principal != nil {
+		if editorID != nil && *editorID != 0 && !sameUint(editorID, principal.UserID) {
+			return fmt.Errorf("comments are edited as the authenticated user")
+		}
+		editorID = principal.UserID
+	}
+	if editorID != nil && *editorID != 0 {
+		editor, err := db.GetUserByID(*editorID)
+		if err != nil {
+			return fmt.Errorf("editor not found")
+		}
+		revision.EditedByID = &editor.ID
+		revision.EditedBy = editor.Username
+	}
+
+	now := time.Now()
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&revision).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.Comment{}).Where("id = ?", commentID).
+			Updates(map[string]interface{}{"content": content, "edited_at": now}).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	actor := events.Actor{ID: revision.EditedByID, Name: revision.EditedBy}
+	comment.Content = content
+	comment.EditedAt = &now
+	db.publish(events.CommentUpdated{
+		Comment:         comment,
+		PreviousContent: revision.Content,
+		ProjectID:       db.taskProjectID(comment.TaskID),
+		Actor:           actor,
+	})
+
+	// Pick up mentions added while editing
+	db.syncMentions(comment.TaskID, &comment.ID, content, actor)
+
+	return nil
+}
+
+// GetCommentWithDetails loads a comment with its author, reactions, attachments and edit history
+func (db *Database) GetCommentWithDetails(commentID uint) (*models.Comment, error) {
+	var comment models.Comment
+	err := db.Preload("AuthorUser").
+		Preload("Reactions", orderReactions).
+		Preload("Reactions.User").
+		Preload("Attachments").
+		Preload("Revisions", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at ASC")
+		}).
+		First(&comment, commentID).Error
+	return &comment, err
+}
+
+// ListComments returns the comments of a task, newest first. When threaded is set
+// only top-level comments are returned, each carrying its replies oldest first.
+func (db *Database) ListComments(taskID uint, threaded bool) ([]models.Comment, error) {
+	var comments []models.Comment
+	if err := db.Where("task_id = ?", taskID).
+		Preload("AuthorUser").
+		Preload("Reactions", orderReactions).
+		Preload("Reactions.User").
+		Preload("Attachments").
+		Order("created_at DESC, id DESC").
+		Find(&comments).Error; err != nil {
+		return nil, err
+	}
+
+	if !threaded {
+		return comments, nil
+	}
+
+	replies := make(map[uint][]models.Comment)
+	var roots []models.Comment
+	for i := len(comments) - 1; i >= 0; i-- {
+		if parentID := comments[i].ParentID; parentID != nil {
+			replies[*parentID] = append(replies[*parentID], comments[i])
+		}
+	}
+	var attachReplies func(comment *models.Comment)
+	attachReplies = func(comment *models.Comment) {
+		comment.Replies = replies[comment.ID]
+		for i := range comment.Replies {
+			attachReplies(&comment.Replies[i])
+		}
+	}
+	for _, comment := range comments {
+		if comment.ParentID == nil {
+			attachReplies(&comment)
+			roots = append(roots, comment)
+		}
+	}
+	return roots, nil
+}
+
+// GetCommentHistory returns the previous versions of a comment, oldest first
+func (db *Database) GetCommentHistory(commentID uint) ([]models.CommentRevision, error) {
+	if _, err := db.GetComment(commentID); err != nil {
+		return nil, err
+	}
+
+	var revisions []models.CommentRevision
+	err := db.Where("comment_id = ?", commentID).Order("created_at ASC, id ASC").Find(&revisions).Error
+	return revisions, err
+}
+
+// AddReaction records a user's emoji reaction on a comment. Reacting twice with
+// the same emoji is a no-op.
+func (db *Database) AddReaction(commentID, userID uint, emoji string) (*models.CommentReaction, error) {
+	emoji = strings.TrimSpace(emoji)
+	if emoji == "" || utf8.RuneCountInString(emoji) > maxReactionLength || strings.ContainsAny(emoji, " \t\n") {
+		return nil, fmt.Errorf("invalid reaction: %q", emoji)
+	}
+	if _, err := db.GetComment(commentID); err != nil {
+		return nil, fmt.Errorf("comment not found")
+	}
+	if _, err := db.GetUserByID(userID); err != nil {
+		return nil, fmt.Errorf("user not found")
+	}
+
+	reaction := models.CommentReaction{CommentID: commentID, UserID: userID, Emoji: emoji}
+	if err := db.Where(reaction).FirstOrCreate(&reaction).Error; err != nil {
+		return nil, err
+	}
+	return &reaction, nil
+}
+
+// RemoveReaction removes a user's emoji reaction from a comment
+func (db *Database) RemoveReaction(commentID, userID uint, emoji string) error {
+	return db.Where("comment_id = ? AND user_id = ? AND emoji = ?", commentID, userID, strings.TrimSpace(emoji)).
+		Delete(&models.CommentReaction{}).Error
+}
+
+// AttachToComment links attachments already uploaded to a task to one of its comments
+func (db *Database) AttachToComment(commentID uint, attachmentIDs []uint) error {
+	comment, err := db.GetComment(commentID)
+	if err != nil {
+		return fmt.Errorf("comment not found")
+	}
+	return db.Transaction(func(tx *gorm.DB) error {
+		return linkCommentAttachments(tx, comment, attachmentIDs)
+	})
+}
+
+// linkCommentAttachments points attachments at a comment. Every ID must be listed
+// once and belong to the comment's task.
+func linkCommentAttachments(tx *gorm.DB, comment *models.Comment, attachmentIDs []uint) error {
+	seen := make(map[uint]bool, len(attachmentIDs))
+	for _, id := range attachmentIDs {
+		if seen[id] {
+			return fmt.Errorf("attachment %d is listed more than once", id)
+		}
+		seen[id] = true
+	}
+
+	var count int64
+	if err := tx.Model(&models.Attachment{}).
+		Where("id IN ? AND task_id = ?", attachmentIDs, comment.TaskID).
+		Count(&count).Error; err != nil {
+		return err
+	}
+	if int(count) != len(attachmentIDs) {
+		return fmt.Errorf("attachments must belong to task %d", comment.TaskID)
+	}
+
+	return tx.Model(&models.Attachment{}).Where("id IN ?", attachmentIDs).
+		Update("comment_id", comment.ID).Error
+}
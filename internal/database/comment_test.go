@@ -0,0 +1,146 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/headless-pm/headless-project-management/internal/models"
+)
+
+func TestCommentAttachments(t *testing.T) {
+	db := newTestDatabase(t)
+	alice := createUser(t, db, "alice")
+	project := createProject(t, db, "Apollo")
+	task := createTask(t, db, project.ID, "Fuel")
+	other := createTask(t, db, project.ID, "Oxidiser")
+
+	own := &models.Attachment{TaskID: task.ID, Filename: "plan.pdf", Path: "plan.pdf"}
+	foreign := &models.Attachment{TaskID: other.ID, Filename: "notes.txt", Path: "notes.txt"}
+	for _, attachment := range []*models.Attachment{own, foreign} {
+		if err := db.AddAttachment(attachment); err != nil {
+			t.Fatalf("failed to add attachment: %v", err)
+		}
+	}
+
+	countComments := func() int64 {
+		var count int64
+		db.Model(&models.Comment{}).Where("task_id = ?", task.ID).Count(&count)
+		return count
+	}
+
+	for name, ids := range map[string][]uint{
+		"duplicate":    {own.ID, own.ID},
+		"other task":   {own.ID, foreign.ID},
+		"missing file": {own.ID + foreign.ID + 1},
+	} {
+		comment := &models.Comment{TaskID: task.ID, Content: "See attached"}
+		if err := actingAs(db, alice).AddCommentWithAttachments(comment, ids); err == nil {
+			t.Errorf("%s: accepted attachments %v", name, ids)
+		}
+	}
+	if n := countComments(); n != 0 {
+		t.Fatalf("rejected comments were saved: %d", n)
+	}
+
+	comment := &models.Comment{TaskID: task.ID, Content: "See attached"}
+	if err := actingAs(db, alice).AddCommentWithAttachments(comment, []uint{own.ID}); err != nil {
+		t.Fatalf("AddCommentWithAttachments: %v", err)
+	}
+	detailed, err := db.GetCommentWithDetails(comment.ID)
+	if err != nil {
+		t.Fatalf("GetCommentWithDetails: %v", err)
+	}
+	if len(detailed.Attachments) != 1 || detailed.Attachments[0].ID != own.ID {
+		t.Errorf("attachments = %+v, want %d", detailed.Attachments, own.ID)
+	}
+}
+
+func TestCommentThreadsAndHistory(t *testing.T) {
+	db := newTestDatabase(t)
+	alice := createUser(t, db, "alice")
+	bob := createUser(t, db, "bob")
+	project := createProject(t, db, "Apollo")
+	task := createTask(t, db, project.ID, "Fuel")
+	other := createTask(t, db, project.ID, "Oxidiser")
+
+	root := &models.Comment{TaskID: task.ID, Content: "Which tank?"}
+	if err := actingAs(db, alice).AddComment(root); err != nil {
+		t.Fatalf("AddComment: %v", err)
+	}
+	reply := &models.Comment{ParentID: &root.ID, Content: "The left one"}
+	if err := actingAs(db, bob).AddComment(reply); err != nil {
+		t.Fatalf("reply: %v", err)
+	}
+	if reply.TaskID != task.ID {
+		t.Errorf("reply task = %d, want %d", reply.TaskID, task.ID)
+	}
+	if err := actingAs(db, bob).AddComment(&models.Comment{TaskID: other.ID, ParentID: &root.ID, Content: "Wrong"}); err == nil {
+		t.Error("accepted a reply on another task")
+	}
+
+	threads, err := db.ListComments(task.ID, true)
+	if err != nil {
+		t.Fatalf("ListComments: %v", err)
+	}
+	if len(threads) != 1 || len(threads[0].Replies) != 1 || threads[0].Replies[0].ID != reply.ID {
+		t.Errorf("threads = %+v, want the reply under its parent", threads)
+	}
+
+	if _, err := db.AddReaction(root.ID, bob.ID, ":+1:"); err != nil {
+		t.Fatalf("AddReaction: %v", err)
+	}
+	if _, err := db.AddReaction(root.ID, bob.ID, ":+1:"); err != nil {
+		t.Fatalf("repeated AddReaction: %v", err)
+	}
+	if _, err := db.AddReaction(root.ID, bob.ID, "two words"); err == nil {
+		t.Error("accepted a reaction with spaces")
+	}
+	detailed, _ := db.GetCommentWithDetails(root.ID)
+	if len(detailed.Reactions) != 1 {
+		t.Errorf("reactions = %d, want 1", len(detailed.Reactions))
+	}
+
+	if err := actingAs(db, alice).EditComment(root.ID, "Which tank first?", nil); err != nil {
+		t.Fatalf("EditComment: %v", err)
+	}
+	history, err := db.GetCommentHistory(root.ID)
+	if err != nil {
+		t.Fatalf("GetCommentHistory: %v", err)
+	}
+	if len(history) != 1 || history[0].Content != "Which tank?" || history[0].EditedBy != "alice" {
+		t.Errorf("history = %+v, want the original content edited by alice", history)
+	}
+}
+
+func TestCommentsActAsThePrincipal(t *testing.T) {
+	db := newTestDatabase(t)
+	alice := createUser(t, db, "alice")
+	bob := createUser(t, db, "bob")
+	project := createProject(t, db, "Apollo")
+	task := createTask(t, db, project.ID, "Fuel")
+
+	comment := &models.Comment{TaskID: task.ID, Content: "Fuelled", Author: "bob"}
+	if err := actingAs(db, alice).AddComment(comment); err != nil {
+		t.Fatalf("AddComment: %v", err)
+	}
+	if comment.Author != "alice" || comment.AuthorID == nil || *comment.AuthorID != alice.ID {
+		t.Errorf("comment by %s (%v), want alice", comment.Author, comment.AuthorID)
+	}
+	if err := actingAs(db, alice).AddComment(&models.Comment{TaskID: task.ID, Content: "Forged", AuthorID: &bob.ID}); err == nil {
+		t.Error("commented with another user's ID")
+	}
+	if err := actingAs(db, alice).EditComment(comment.ID, "Fuelled up", &bob.ID); err == nil {
+		t.Error("edited a comment as another user")
+	}
+
+	// Without a principal, local tools name the author
+	local := &models.Comment{TaskID: task.ID, Content: "Checked", Author: "bob"}
+	if err := db.AddComment(local); err != nil {
+		t.Fatalf("AddComment: %v", err)
+	}
+	if local.AuthorID == nil || *local.AuthorID != bob.ID {
+		t.Errorf("local comment author = %v, want bob", local.AuthorID)
+	}
+	if err := db.AddComment(&models.Comment{TaskID: task.ID, Content: "Anonymous"}); err == nil {
+		t.Error("added a comment without an author")
+	}
+}
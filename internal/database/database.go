@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 	"time"
 
+	"github.com/headless-pm/headless-project-management/internal/events"
 	"github.com/headless-pm/headless-project-management/internal/models"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
@@ -15,10 +16,16 @@ import (
 
 type Database struct {
 	*gorm.DB
-	embeddingCallback func(entityType string, entityID uint)
+	events *events.Bus
 }
 
 func NewDatabase(dataDir string) (*Database, error) {
+	return NewDatabaseWithLogger(dataDir, logger.Default.LogMode(logger.Info))
+}
+
+// NewDatabaseWithLogger opens the database with a custom SQL logger, e.g. one
+// that keeps stdout free for the stdio MCP transport
+func NewDatabaseWithLogger(dataDir string, sqlLogger logger.Interface) (*Database, error) {
 	dbPath := filepath.Join(dataDir, "db", "projects.db")
 
 	// Ensure the db directory exists
@@ -28,7 +35,7 @@ func NewDatabase(dataDir string) (*Database, error) {
 	}
 
 	db, err := gorm.Open(sqlite.Open(dbPath), &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Info),
+		Logger: sqlLogger,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
@@ -40,11 +47,23 @@ func NewDatabase(dataDir string) (*Database, error) {
 		&models.User{},
 		&models.Project{},
 		&models.Epic{},
+		&models.Sprint{},
+		&models.Milestone{},
 		&models.Task{},
 		&models.Label{},
 		&models.Comment{},
+		&models.CommentReaction{},
+		&models.CommentRevision{},
 		&models.Attachment{},
 		&models.Activity{},
+		&models.Worklog{},
+		&models.TaskLease{},
+		&models.Notification{},
+		&models.NotificationPreference{},
+		&models.Mention{},
+		&models.Webhook{},
+		&models.WebhookDelivery{},
+		&models.PromptTemplate{},
 
 		// Auth entities
 		&models.Session{},
@@ -96,6 +115,11 @@ func NewDatabase(dataDir string) (*Database, error) {
 		}
 	}
 
+	// A user can only have one running timer, even when starts race
+	if err := createRunningTimerIndex(db); err != nil {
+		log.Printf("Warning: failed to create running timer index: %v", err)
+	}
+
 	// Initialize vector extension for SQLite
 	sqlDB, err := db.DB()
 	if err != nil {
@@ -105,22 +129,36 @@ func NewDatabase(dataDir string) (*Database, error) {
 	// Try to initialize vector extension (will fail gracefully if not available)
 	_ = InitializeVectorExtension(sqlDB)
 
-	return &Database{DB: db}, nil
+	return &Database{DB: db, events: events.NewBus()}, nil
 }
 
-func (db *Database) SetEmbeddingCallback(callback func(entityType string, entityID uint)) {
-	db.embeddingCallback = callback
+// Events returns the bus that domain events are published to after each write
+func (db *Database) Events() *events.Bus {
+	return db.events
+}
+
+func (db *Database) publish(event events.Event) {
+	db.events.Publish(event)
+}
+
+// taskProjectID returns the project of a task, or 0 if the task does not exist
+func (db *Database) taskProjectID(taskID uint) uint {
+	var task models.Task
+	if err := db.Select("id", "project_id").First(&task, taskID).Error; err != nil {
+		return 0
+	}
+	return task.ProjectID
 }
 
 func (db *Database) CreateProject(project *models.Project) error {
+	if principal := db.principal(); principal != nil && project.OwnerID == 0 {
+		project.OwnerID = *principal.UserID
+	}
 	if err := db.Create(project).Error; err != nil {
 		return err
 	}
 
-	// Queue embedding generation for the new project
-	if db.embeddingCallback != nil {
-		db.embeddingCallback("project", project.ID)
-	}
+	db.publish(events.ProjectCreated{Project: project})
 
 	return nil
 }
@@ -149,10 +187,7 @@ func (db *Database) UpdateProject(project *models.Project) error {
 		return err
 	}
 
-	// Queue embedding generation for the updated project
-	if db.embeddingCallback != nil {
-		db.embeddingCallback("project", project.ID)
-	}
+	db.publish(events.ProjectUpdated{Project: project})
 
 	return nil
 }
@@ -179,6 +214,17 @@ func (db *Database) DeleteProject(id uint) error {
 		return err
 	}
 
+	// Delete reactions and edit history of comments in this project
+	projectComments := "comment_id IN (SELECT id FROM comments WHERE task_id IN (SELECT id FROM tasks WHERE project_id = ?))"
+	if err := tx.Where(projectComments, id).Delete(&models.CommentReaction{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Where(projectComments, id).Delete(&models.CommentRevision{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
 	// Delete all comments for tasks in this project
 	if err := tx.Where("task_id IN (SELECT id FROM tasks WHERE project_id = ?)", id).
 		Delete(&models.Comment{}).Error; err != nil {
@@ -193,6 +239,33 @@ func (db *Database) DeleteProject(id uint) error {
 		return err
 	}
 
+	// Delete all worklogs for tasks in this project
+	if err := tx.Where("task_id IN (SELECT id FROM tasks WHERE project_id = ?)", id).
+		Delete(&models.Worklog{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// Delete the leases of claimed tasks in this project
+	if err := tx.Where("task_id IN (SELECT id FROM tasks WHERE project_id = ?)", id).
+		Delete(&models.TaskLease{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// Delete all notifications about this project
+	if err := tx.Where("project_id = ?", id).Delete(&models.Notification{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// Delete all mentions in tasks of this project
+	if err := tx.Where("task_id IN (SELECT id FROM tasks WHERE project_id = ?)", id).
+		Delete(&models.Mention{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
 	// Delete all tasks (including subtasks) for this project
 	if err := tx.Where("project_id = ?", id).Delete(&models.Task{}).Error; err != nil {
 		tx.Rollback()
@@ -205,6 +278,18 @@ func (db *Database) DeleteProject(id uint) error {
 		return err
 	}
 
+	// Delete all sprints for this project
+	if err := tx.Where("project_id = ?", id).Delete(&models.Sprint{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// Delete all milestones for this project
+	if err := tx.Where("project_id = ?", id).Delete(&models.Milestone{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
 	// Delete all labels for this project
 	if err := tx.Where("project_id = ?", id).Delete(&models.Label{}).Error; err != nil {
 		tx.Rollback()
@@ -217,6 +302,17 @@ func (db *Database) DeleteProject(id uint) error {
 		return err
 	}
 
+	// Delete the webhooks of this project and their delivery logs
+	if err := tx.Where("webhook_id IN (SELECT id FROM webhooks WHERE project_id = ?)", id).
+		Delete(&models.WebhookDelivery{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Where("project_id = ?", id).Delete(&models.Webhook{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
 	// Finally, delete the project itself
 	if err := tx.Delete(&models.Project{}, id).Error; err != nil {
 		tx.Rollback()
@@ -224,7 +320,13 @@ func (db *Database) DeleteProject(id uint) error {
 	}
 
 	// Commit the transaction
-	return tx.Commit().Error
+	if err := tx.Commit().Error; err != nil {
+		return err
+	}
+
+	db.publish(events.ProjectDeleted{ProjectID: id})
+
+	return nil
 }
 
 func (db *Database) CreateTask(task *models.Task) error {
@@ -238,6 +340,9 @@ func (db *Database) CreateTask(task *models.Task) error {
 		return fmt.Errorf("invalid task priority: %s", task.Priority)
 	}
 
+	if principal := db.principal(); principal != nil && task.CreatedBy == 0 {
+		task.CreatedBy = *principal.UserID
+	}
 	if err := db.Create(task).Error; err != nil {
 		return err
 	}
@@ -256,10 +361,13 @@ func (db *Database) CreateTask(task *models.Task) error {
 	}
 	_ = db.LogTaskCreated(task.ID, userID, userName)
 
-	// Queue embedding generation for the new task
-	if db.embeddingCallback != nil {
-		db.embeddingCallback("task", task.ID)
-	}
+	// Creators and assignees watch the task
+	db.autoWatch(task.ID, userID)
+	db.autoWatch(task.ID, task.AssigneeID)
+
+	actor := events.Actor{ID: userID, Name: userName}
+	db.publish(events.TaskCreated{Task: task, Actor: actor})
+	db.syncMentions(task.ID, nil, task.Description, actor)
 
 	return nil
 }
@@ -308,8 +416,15 @@ func (db *Database) UpdateTask(task *models.Task) error {
 			task.Priority, models.GetValidTaskPriorities())
 	}
 
+	// Changes are made by the caller, whoever updated the task before
+	if principal := db.principal(); principal != nil {
+		task.UpdatedBy = principal.UserID
+	}
+
 	// Get the old task to check for status changes
 	var oldTask models.Task
+	var unblockedTaskIDs []uint
+	var removedDependencies []models.TaskDependency
 	if err := db.First(&oldTask, task.ID).Error; err == nil {
 		// If task is being marked as done
 		if oldTask.Status != models.TaskStatusDone && task.Status == models.TaskStatusDone {
@@ -342,6 +457,15 @@ func (db *Database) UpdateTask(task *models.Task) error {
 					// Remove the dependency
 					if err := db.Delete(&dep).Error; err != nil {
 						log.Printf("Warning: Failed to remove dependency %d: %v", dep.ID, err)
+						continue
+					}
+					removedDependencies = append(removedDependencies, dep)
+
+					// Remember tasks that have no dependencies left
+					var remaining int64
+					db.Model(&models.TaskDependency{}).Where("task_id = ?", dep.TaskID).Count(&remaining)
+					if remaining == 0 {
+						unblockedTaskIDs = append(unblockedTaskIDs, dep.TaskID)
 					}
 				}
 			}
@@ -375,15 +499,42 @@ func (db *Database) UpdateTask(task *models.Task) error {
 		return err
 	}
 
-	// Queue embedding generation for the updated task
-	if db.embeddingCallback != nil {
-		db.embeddingCallback("task", task.ID)
+	// Assignees watch the task
+	db.autoWatch(task.ID, task.AssigneeID)
+
+	if oldTask.ID != 0 {
+		actor := events.Actor{ID: task.UpdatedBy, Name: "System"}
+		if task.UpdatedBy != nil && *task.UpdatedBy > 0 {
+			if user, err := db.GetUserByID(*task.UpdatedBy); err == nil {
+				actor.Name = user.Username
+			}
+		}
+		if !sameUint(oldTask.AssigneeID, task.AssigneeID) {
+			_ = db.LogTaskAssigned(task.ID, actor.ID, actor.Name, db.userName(oldTask.AssigneeID), db.userName(task.AssigneeID))
+		}
+		if oldTask.Priority != task.Priority {
+			_ = db.LogTaskPriorityChanged(task.ID, actor.ID, actor.Name, string(oldTask.Priority), string(task.Priority))
+		}
+		db.publish(events.TaskUpdated{Old: &oldTask, Task: task, Diff: events.DiffTasks(&oldTask, task), Actor: actor})
+		for _, dep := range removedDependencies {
+			db.publish(events.DependencyRemoved{Dependency: dep, ProjectID: db.taskProjectID(dep.TaskID)})
+		}
+		for _, unblockedID := range unblockedTaskIDs {
+			db.publish(events.TaskUnblocked{TaskID: unblockedID, Completed: task, Actor: actor})
+		}
+		if task.Description != oldTask.Description {
+			db.syncMentions(task.ID, nil, task.Description, actor)
+		}
 	}
 
 	return nil
 }
 
 func (db *Database) DeleteTask(id uint) error {
+	// Keep the task for the TaskDeleted event
+	var deleted models.Task
+	found := db.First(&deleted, id).Error == nil
+
 	// Start a transaction to ensure all deletions happen atomically
 	tx := db.Begin()
 	if tx.Error != nil {
@@ -402,6 +553,17 @@ func (db *Database) DeleteTask(id uint) error {
 		return err
 	}
 
+	// Delete reactions and edit history of this task's comments
+	taskComments := "comment_id IN (SELECT id FROM comments WHERE task_id = ?)"
+	if err := tx.Where(taskComments, id).Delete(&models.CommentReaction{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Where(taskComments, id).Delete(&models.CommentRevision{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
 	// Delete all comments for this task
 	if err := tx.Where("task_id = ?", id).Delete(&models.Comment{}).Error; err != nil {
 		tx.Rollback()
@@ -414,6 +576,30 @@ func (db *Database) DeleteTask(id uint) error {
 		return err
 	}
 
+	// Delete all worklogs for this task
+	if err := tx.Where("task_id = ?", id).Delete(&models.Worklog{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// Delete the lease of this task if it is claimed
+	if err := tx.Where("task_id = ?", id).Delete(&models.TaskLease{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// Delete all notifications about this task
+	if err := tx.Where("task_id = ?", id).Delete(&models.Notification{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// Delete all mentions in this task and its comments
+	if err := tx.Where("task_id = ?", id).Delete(&models.Mention{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
 	// Remove all task_labels associations for this task
 	if err := tx.Exec("DELETE FROM task_labels WHERE task_id = ?", id).Error; err != nil {
 		tx.Rollback()
@@ -448,15 +634,69 @@ func (db *Database) DeleteTask(id uint) error {
 	}
 
 	// Commit the transaction
-	return tx.Commit().Error
+	if err := tx.Commit().Error; err != nil {
+		return err
+	}
+
+	if found {
+		db.publish(events.TaskDeleted{Task: deleted})
+	}
+
+	return nil
 }
 
 func (db *Database) AddComment(comment *models.Comment) error {
-	return db.Create(comment).Error
+	return db.AddCommentWithAttachments(comment, nil)
 }
 
+// AddCommentWithAttachments adds a comment and links attachments already uploaded
+// to its task. The comment is only saved when every attachment can be linked.
+func (db *Database) AddCommentWithAttachments(comment *models.Comment, attachmentIDs []uint) error {
+	if err := db.resolveCommentAuthor(comment); err != nil {
+		return err
+	}
+
+	// Replies stay on the task of the comment they answer
+	if comment.ParentID != nil {
+		parent, err := db.GetComment(*comment.ParentID)
+		if err != nil {
+			return fmt.Errorf("parent comment not found")
+		}
+		if comment.TaskID == 0 {
+			comment.TaskID = parent.TaskID
+		}
+		if parent.TaskID != comment.TaskID {
+			return fmt.Errorf("parent comment belongs to another task")
+		}
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(comment).Error; err != nil {
+			return err
+		}
+		if len(attachmentIDs) == 0 {
+			return nil
+		}
+		return linkCommentAttachments(tx, comment, attachmentIDs)
+	})
+	if err != nil {
+		return err
+	}
+
+	// Commenters watch the task
+	db.autoWatch(comment.TaskID, comment.AuthorID)
+
+	actor := events.Actor{ID: comment.AuthorID, Name: comment.Author}
+	db.publish(events.CommentAdded{Comment: comment, ProjectID: db.taskProjectID(comment.TaskID), Actor: actor})
+	db.syncMentions(comment.TaskID, &comment.ID, comment.Content, actor)
+
+	return nil
+}
+
+// UpdateComment replaces the content of a comment, keeping the previous content
+// in its edit history
 func (db *Database) UpdateComment(commentID uint, content string) error {
-	return db.Model(&models.Comment{}).Where("id = ?", commentID).Update("content", content).Error
+	return db.EditComment(commentID, content, nil)
 }
 
 func (db *Database) GetComment(commentID uint) (*models.Comment, error) {
@@ -465,8 +705,47 @@ func (db *Database) GetComment(commentID uint) (*models.Comment, error) {
 	return &comment, err
 }
 
+// DeleteComment deletes a comment together with its replies. Attachments of the
+// deleted comments stay on the task.
 func (db *Database) DeleteComment(commentID uint) error {
-	return db.Delete(&models.Comment{}, commentID).Error
+	comment, err := db.GetComment(commentID)
+	if err != nil {
+		return err
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		ids := []uint{commentID}
+		for parents := ids; len(parents) > 0; {
+			var replies []uint
+			if err := tx.Model(&models.Comment{}).Where("parent_id IN ?", parents).Pluck("id", &replies).Error; err != nil {
+				return err
+			}
+			ids = append(ids, replies...)
+			parents = replies
+		}
+
+		if err := tx.Where("comment_id IN ?", ids).Delete(&models.Mention{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("comment_id IN ?", ids).Delete(&models.CommentReaction{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("comment_id IN ?", ids).Delete(&models.CommentRevision{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.Attachment{}).Where("comment_id IN ?", ids).
+			Update("comment_id", nil).Error; err != nil {
+			return err
+		}
+		return tx.Where("id IN ?", ids).Delete(&models.Comment{}).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	db.publish(events.CommentDeleted{Comment: comment, ProjectID: db.taskProjectID(comment.TaskID)})
+
+	return nil
 }
 
 func (db *Database) AddAttachment(attachment *models.Attachment) error {
@@ -527,18 +806,7 @@ func (db *Database) GetOrCreateLabel(projectID uint, name string, color string)
 	if err != nil {
 		// Create label if it doesn't exist
 		if color == "" {
-			// Generate a dark color if not provided
-			colors := []string{
-				"#DC2626", "#059669", "#2563EB", "#7C3AED",
-				"#EA580C", "#0891B2", "#4F46E5", "#BE123C",
-				"#15803D", "#B91C1C", "#0E7490", "#6B21A8",
-				"#C2410C", "#1E40AF", "#86198F", "#166534",
-			}
-			hash := 0
-			for _, c := range name {
-				hash = hash*31 + int(c)
-			}
-			color = colors[hash%len(colors)]
+			color = labelColor(name)
 		}
 
 		label = models.Label{
@@ -553,6 +821,21 @@ func (db *Database) GetOrCreateLabel(projectID uint, name string, color string)
 	return &label, nil
 }
 
+// labelColor picks a dark color for a label from its name
+func labelColor(name string) string {
+	colors := []string{
+		"#DC2626", "#059669", "#2563EB", "#7C3AED",
+		"#EA580C", "#0891B2", "#4F46E5", "#BE123C",
+		"#15803D", "#B91C1C", "#0E7490", "#6B21A8",
+		"#C2410C", "#1E40AF", "#86198F", "#166534",
+	}
+	hash := 0
+	for _, c := range name {
+		hash = hash*31 + int(c)
+	}
+	return colors[hash%len(colors)]
+}
+
 func (db *Database) GetLabelsByProject(projectID uint) ([]models.Label, error) {
 	var labels []models.Label
 	err := db.Where("project_id = ?", projectID).Find(&labels).Error
@@ -592,7 +875,13 @@ func (db *Database) AssignLabelsToTask(taskID uint, projectID uint, labelNames [
 
 // Epic CRUD methods
 func (db *Database) CreateEpic(epic *models.Epic) error {
-	return db.Create(epic).Error
+	if err := db.Create(epic).Error; err != nil {
+		return err
+	}
+
+	db.publish(events.EpicCreated{Epic: epic})
+
+	return nil
 }
 
 func (db *Database) GetEpic(id uint) (*models.Epic, error) {
@@ -620,7 +909,16 @@ func (db *Database) ListEpics(projectID *uint, status *models.EpicStatus) ([]mod
 }
 
 func (db *Database) UpdateEpic(epic *models.Epic) error {
-	return db.Save(epic).Error
+	var oldEpic models.Epic
+	_ = db.First(&oldEpic, epic.ID).Error
+
+	if err := db.Save(epic).Error; err != nil {
+		return err
+	}
+
+	db.publish(events.EpicUpdated{Old: &oldEpic, Epic: epic})
+
+	return nil
 }
 
 func (db *Database) DeleteEpic(id uint) error {
@@ -629,6 +927,10 @@ func (db *Database) DeleteEpic(id uint) error {
 
 // DeleteEpicWithOptions deletes an epic with option to cascade delete tasks
 func (db *Database) DeleteEpicWithOptions(id uint, cascadeTasks bool) error {
+	// Keep the epic for the EpicDeleted event
+	var epic models.Epic
+	found := db.First(&epic, id).Error == nil
+
 	// Start a transaction to ensure all deletions happen atomically
 	tx := db.Begin()
 	if tx.Error != nil {
@@ -671,7 +973,15 @@ func (db *Database) DeleteEpicWithOptions(id uint, cascadeTasks bool) error {
 	}
 
 	// Commit the transaction
-	return tx.Commit().Error
+	if err := tx.Commit().Error; err != nil {
+		return err
+	}
+
+	if found {
+		db.publish(events.EpicDeleted{EpicID: id, ProjectID: epic.ProjectID})
+	}
+
+	return nil
 }
 
 func (db *Database) GetEpicsByProject(projectID uint) ([]models.Epic, error) {
@@ -743,7 +1053,13 @@ func (db *Database) CreateTaskDependency(dependency *models.TaskDependency) erro
 	}
 
 	// Create the dependency
-	return db.Create(dependency).Error
+	if err := db.Create(dependency).Error; err != nil {
+		return err
+	}
+
+	db.publish(events.DependencyAdded{Dependency: *dependency, ProjectID: db.taskProjectID(dependency.TaskID)})
+
+	return nil
 }
 
 func (db *Database) GetTaskDependencies(taskID uint) ([]models.TaskDependency, error) {
@@ -772,12 +1088,32 @@ func (db *Database) GetAllTaskDependencies(taskID uint) ([]models.TaskDependency
 }
 
 func (db *Database) DeleteTaskDependency(id uint) error {
-	return db.Delete(&models.TaskDependency{}, id).Error
+	var dependency models.TaskDependency
+	if err := db.First(&dependency, id).Error; err != nil {
+		return err
+	}
+	return db.deleteTaskDependency(dependency)
 }
 
 func (db *Database) DeleteTaskDependencyByTaskIDs(taskID, dependsOnID uint) error {
-	return db.Where("task_id = ? AND depends_on_id = ?", taskID, dependsOnID).
-		Delete(&models.TaskDependency{}).Error
+	var dependency models.TaskDependency
+	if err := db.Where("task_id = ? AND depends_on_id = ?", taskID, dependsOnID).First(&dependency).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil
+		}
+		return err
+	}
+	return db.deleteTaskDependency(dependency)
+}
+
+func (db *Database) deleteTaskDependency(dependency models.TaskDependency) error {
+	if err := db.Delete(&models.TaskDependency{}, dependency.ID).Error; err != nil {
+		return err
+	}
+
+	db.publish(events.DependencyRemoved{Dependency: dependency, ProjectID: db.taskProjectID(dependency.TaskID)})
+
+	return nil
 }
 
 // CheckCircularDependency checks if adding a dependency from taskID to dependsOnID would create a cycle
@@ -936,7 +1272,7 @@ func (db *Database) GetProjectDependencyGraph(projectID uint) (map[string]interf
 
 // RemoveTaskDependency removes a specific task dependency by ID
 func (db *Database) RemoveTaskDependency(dependencyID uint) error {
-	return db.Delete(&models.TaskDependency{}, dependencyID).Error
+	return db.DeleteTaskDependency(dependencyID)
 }
 
 // User management functions
@@ -1029,6 +1365,50 @@ func (db *Database) DeleteUser(id uint) error {
 		return err
 	}
 
+	// Discard running timers; finished worklogs are kept for timesheets
+	if err := tx.Where("user_id = ? AND ended_at IS NULL", id).Delete(&models.Worklog{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// Drop the user's leases; their tasks are unassigned above
+	if err := tx.Where("user_id = ?", id).Delete(&models.TaskLease{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// Delete user notifications and notification preferences
+	if err := tx.Where("user_id = ?", id).Delete(&models.Notification{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Where("user_id = ?", id).Delete(&models.NotificationPreference{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// Delete mentions of the user
+	if err := tx.Where("user_id = ?", id).Delete(&models.Mention{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// Unlink authored comments (the author name is kept) and drop reactions
+	if err := tx.Model(&models.Comment{}).Where("author_id = ?", id).
+		Update("author_id", nil).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Model(&models.CommentRevision{}).Where("edited_by_id = ?", id).
+		Update("edited_by_id", nil).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Where("user_id = ?", id).Delete(&models.CommentReaction{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
 	// Delete user sessions
 	if err := tx.Where("user_id = ?", id).Delete(&models.Session{}).Error; err != nil {
 		tx.Rollback()
@@ -1056,7 +1436,21 @@ func (db *Database) DeleteUser(id uint) error {
 	return tx.Commit().Error
 }
 // Activity logging functions
+
+// LogActivity records a change to a task. Changes without a user are
+// attributed to the principal of the database's context, if any, and changes
+// by the principal note the client it acted through.
 func (db *Database) LogActivity(taskID uint, userID *uint, userName, action, fieldName, oldValue, newValue, description string) error {
+	client := ""
+	if principal := db.principal(); principal != nil {
+		if userID == nil {
+			userID, userName = principal.UserID, principal.Name
+		}
+		if *userID == *principal.UserID {
+			client = principal.Client
+		}
+	}
+
 	activity := &models.Activity{
 		TaskID:      taskID,
 		UserID:      userID,
@@ -1066,6 +1460,7 @@ func (db *Database) LogActivity(taskID uint, userID *uint, userName, action, fie
 		OldValue:    oldValue,
 		NewValue:    newValue,
 		Description: description,
+		Client:      client,
 	}
 	return db.Create(activity).Error
 }
@@ -1081,7 +1476,9 @@ func (db *Database) LogTaskStatusChanged(taskID uint, userID *uint, userName str
 
 func (db *Database) LogTaskAssigned(taskID uint, userID *uint, userName string, oldAssignee, newAssignee string) error {
 	description := fmt.Sprintf("Task assigned to %s", newAssignee)
-	if oldAssignee != "" {
+	if newAssignee == "" {
+		description = fmt.Sprintf("Task unassigned from %s", oldAssignee)
+	} else if oldAssignee != "" {
 		description = fmt.Sprintf("Task reassigned from %s to %s", oldAssignee, newAssignee)
 	}
 	return db.LogActivity(taskID, userID, userName, "assigned", "assignee", oldAssignee, newAssignee, description)
@@ -1097,6 +1494,24 @@ func (db *Database) LogTaskUpdated(taskID uint, userID *uint, userName string, f
 	return db.LogActivity(taskID, userID, userName, "updated", fieldName, oldValue, newValue, description)
 }
 
+// userName returns the username of a user for the activity log, or "" for none
+func (db *Database) userName(userID *uint) string {
+	if userID == nil || *userID == 0 {
+		return ""
+	}
+	if user, err := db.GetUserByID(*userID); err == nil {
+		return user.Username
+	}
+	return fmt.Sprintf("user %d", *userID)
+}
+
+func sameUint(a, b *uint) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
 func (db *Database) GetTaskActivities(taskID uint) ([]models.Activity, error) {
 	var activities []models.Activity
 	err := db.Preload("User").
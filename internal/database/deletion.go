@@ -0,0 +1,201 @@
+package database
+
+import (
+	"fmt"
+
+	"github.com/headless-pm/headless-project-management/internal/models"
+)
+
+// DeletionImpact describes what deleting a project, task, epic or user removes
+// and changes, as the corresponding Delete function would do it
+type DeletionImpact struct {
+	Entity  string `json:"entity"`
+	ID      uint   `json:"id"`
+	Name    string `json:"name"`
+	Cascade bool   `json:"cascade,omitempty"`
+	// Deletes counts the records removed by kind, e.g. "tasks" or "comments"
+	Deletes map[string]int64 `json:"deletes"`
+	// Changes counts the records kept but modified, e.g. "tasks_unassigned"
+	Changes map[string]int64 `json:"changes"`
+	// OrphanedDependencies link deleted tasks with tasks that are kept; they
+	// are removed, so the kept tasks lose a blocker or a dependent
+	OrphanedDependencies []models.TaskDependency `json:"orphaned_dependencies"`
+	// ReassignedProjects are owned by a deleted user and pass to the system user
+	ReassignedProjects []uint `json:"reassigned_projects,omitempty"`
+}
+
+func newDeletionImpact(entity string, id uint, name string) *DeletionImpact {
+	return &DeletionImpact{
+		Entity:               entity,
+		ID:                   id,
+		Name:                 name,
+		Deletes:              make(map[string]int64),
+		Changes:              make(map[string]int64),
+		OrphanedDependencies: []models.TaskDependency{},
+	}
+}
+
+// ProjectDeletionImpact reports what DeleteProject would remove
+func (db *Database) ProjectDeletionImpact(id uint) (*DeletionImpact, error) {
+	var project models.Project
+	if err := db.First(&project, id).Error; err != nil {
+		return nil, fmt.Errorf("project not found: %w", err)
+	}
+
+	impact := newDeletionImpact("project", project.ID, project.Name)
+	taskIDs, err := db.taskIDs("SELECT id FROM tasks WHERE project_id = ?", id)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.addTaskImpact(impact, taskIDs); err != nil {
+		return nil, err
+	}
+
+	impact.Deletes["projects"] = 1
+	impact.Deletes["epics"] = db.countWhere(&models.Epic{}, "project_id = ?", id)
+	impact.Deletes["sprints"] = db.countWhere(&models.Sprint{}, "project_id = ?", id)
+	impact.Deletes["milestones"] = db.countWhere(&models.Milestone{}, "project_id = ?", id)
+	impact.Deletes["labels"] = db.countWhere(&models.Label{}, "project_id = ?", id)
+	impact.Deletes["webhooks"] = db.countWhere(&models.Webhook{}, "project_id = ?", id)
+	impact.Deletes["memberships"] = db.countTable("project_members", "project_id = ?", id)
+	return impact, nil
+}
+
+// TaskDeletionImpact reports what DeleteTask would remove, including subtasks
+func (db *Database) TaskDeletionImpact(id uint) (*DeletionImpact, error) {
+	var task models.Task
+	if err := db.First(&task, id).Error; err != nil {
+		return nil, fmt.Errorf("task not found: %w", err)
+	}
+
+	impact := newDeletionImpact("task", task.ID, task.Title)
+	taskIDs, err := db.taskIDs("SELECT id FROM tasks WHERE id = ?", id)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.addTaskImpact(impact, taskIDs); err != nil {
+		return nil, err
+	}
+	return impact, nil
+}
+
+// EpicDeletionImpact reports what DeleteEpicWithOptions would remove. Without
+// cascade the tasks of the epic are kept and only unlinked.
+func (db *Database) EpicDeletionImpact(id uint, cascadeTasks bool) (*DeletionImpact, error) {
+	var epic models.Epic
+	if err := db.First(&epic, id).Error; err != nil {
+		return nil, fmt.Errorf("epic not found: %w", err)
+	}
+
+	impact := newDeletionImpact("epic", epic.ID, epic.Name)
+	impact.Cascade = cascadeTasks
+	impact.Deletes["epics"] = 1
+
+	if !cascadeTasks {
+		impact.Changes["tasks_unlinked"] = db.countWhere(&models.Task{}, "epic_id = ?", id)
+		return impact, nil
+	}
+
+	taskIDs, err := db.taskIDs("SELECT id FROM tasks WHERE epic_id = ?", id)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.addTaskImpact(impact, taskIDs); err != nil {
+		return nil, err
+	}
+	return impact, nil
+}
+
+// UserDeletionImpact reports what DeleteUser would remove and which records
+// would lose their reference to the user
+func (db *Database) UserDeletionImpact(id uint) (*DeletionImpact, error) {
+	var user models.User
+	if err := db.First(&user, id).Error; err != nil {
+		return nil, fmt.Errorf("user not found: %w", err)
+	}
+
+	impact := newDeletionImpact("user", user.ID, user.Username)
+
+	impact.Changes["tasks_unassigned"] = db.countWhere(&models.Task{}, "assignee_id = ?", id)
+	impact.Changes["tasks_creator_cleared"] = db.countWhere(&models.Task{}, "created_by = ?", id)
+	impact.Changes["tasks_updater_cleared"] = db.countWhere(&models.Task{}, "updated_by = ?", id)
+	impact.Changes["comments_unlinked"] = db.countWhere(&models.Comment{}, "author_id = ?", id)
+	impact.Changes["comment_revisions_unlinked"] = db.countWhere(&models.CommentRevision{}, "edited_by_id = ?", id)
+
+	var projectIDs []uint
+	if err := db.Model(&models.Project{}).Where("owner_id = ?", id).Order("id").Pluck("id", &projectIDs).Error; err != nil {
+		return nil, err
+	}
+	impact.Changes["projects_reassigned"] = int64(len(projectIDs))
+	impact.ReassignedProjects = projectIDs
+
+	impact.Deletes["users"] = 1
+	impact.Deletes["running_timers"] = db.countWhere(&models.Worklog{}, "user_id = ? AND ended_at IS NULL", id)
+	impact.Deletes["leases"] = db.countWhere(&models.TaskLease{}, "user_id = ?", id)
+	impact.Deletes["notifications"] = db.countWhere(&models.Notification{}, "user_id = ?", id)
+	impact.Deletes["mentions"] = db.countWhere(&models.Mention{}, "user_id = ?", id)
+	impact.Deletes["comment_reactions"] = db.countWhere(&models.CommentReaction{}, "user_id = ?", id)
+	impact.Deletes["watches"] = db.countTable("task_watchers", "user_id = ?", id)
+	impact.Deletes["memberships"] = db.countTable("project_members", "user_id = ?", id)
+	impact.Deletes["api_tokens"] = db.countWhere(&models.APIToken{}, "user_id = ?", id)
+	impact.Deletes["sessions"] = db.countWhere(&models.Session{}, "user_id = ?", id)
+	impact.Deletes["refresh_tokens"] = db.countWhere(&models.RefreshToken{}, "user_id = ?", id)
+	return impact, nil
+}
+
+// taskIDs returns the tasks selected by rootQuery together with all their
+// subtasks, which are deleted with them
+func (db *Database) taskIDs(rootQuery string, args ...interface{}) ([]uint, error) {
+	var ids []uint
+	err := db.Raw(`
+		WITH RECURSIVE subtree(id) AS (
+			`+rootQuery+`
+			UNION
+			SELECT tasks.id FROM tasks INNER JOIN subtree ON tasks.parent_id = subtree.id
+		)
+		SELECT id FROM subtree ORDER BY id
+	`, args...).Scan(&ids).Error
+	return ids, err
+}
+
+// addTaskImpact adds the records deleted with a set of tasks
+func (db *Database) addTaskImpact(impact *DeletionImpact, taskIDs []uint) error {
+	impact.Deletes["tasks"] = int64(len(taskIDs))
+	if len(taskIDs) == 0 {
+		return nil
+	}
+
+	impact.Deletes["comments"] = db.countWhere(&models.Comment{}, "task_id IN ?", taskIDs)
+	impact.Deletes["attachments"] = db.countWhere(&models.Attachment{}, "task_id IN ?", taskIDs)
+	impact.Deletes["worklogs"] = db.countWhere(&models.Worklog{}, "task_id IN ?", taskIDs)
+	impact.Deletes["leases"] = db.countWhere(&models.TaskLease{}, "task_id IN ?", taskIDs)
+
+	var dependencies []models.TaskDependency
+	if err := db.Where("task_id IN ? OR depends_on_id IN ?", taskIDs, taskIDs).Order("id").Find(&dependencies).Error; err != nil {
+		return err
+	}
+	impact.Deletes["dependencies"] = int64(len(dependencies))
+
+	deleted := make(map[uint]bool, len(taskIDs))
+	for _, id := range taskIDs {
+		deleted[id] = true
+	}
+	for _, dependency := range dependencies {
+		if !deleted[dependency.TaskID] || !deleted[dependency.DependsOnID] {
+			impact.OrphanedDependencies = append(impact.OrphanedDependencies, dependency)
+		}
+	}
+	return nil
+}
+
+func (db *Database) countWhere(model interface{}, query string, args ...interface{}) int64 {
+	var count int64
+	db.Model(model).Where(query, args...).Count(&count)
+	return count
+}
+
+func (db *Database) countTable(table, query string, args ...interface{}) int64 {
+	var count int64
+	db.Table(table).Where(query, args...).Count(&count)
+	return count
+}
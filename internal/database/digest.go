@@ -0,0 +1,377 @@
+package database
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/headless-pm/headless-project-management/internal/models"
+	"gorm.io/gorm"
+)
+
+// DigestFilter narrows an activity digest to a period and, when set, to a user,
+// a project or a type of user such as agents
+type DigestFilter struct {
+	From      time.Time
+	To        time.Time
+	UserID    *uint
+	ProjectID *uint
+	UserType  models.UserType
+}
+
+// DigestTask is a task in a section of a digest
+type DigestTask struct {
+	ID          uint              `json:"id"`
+	Title       string            `json:"title"`
+	ProjectID   uint              `json:"project_id"`
+	ProjectName string            `json:"project_name"`
+	Status      models.TaskStatus `json:"status"`
+	At          *time.Time        `json:"at,omitempty"`         // Last time it happened in the period; unset for blocked tasks
+	Comments    int               `json:"comments,omitempty"`   // Comments written in the period
+	BlockedBy   []uint            `json:"blocked_by,omitempty"` // Unfinished tasks it depends on
+}
+
+// DigestCounts counts the tasks in each section of a digest
+type DigestCounts struct {
+	Completed int `json:"completed"`
+	Started   int `json:"started"`
+	Blocked   int `json:"blocked"`
+	Created   int `json:"created"`
+	Commented int `json:"commented"`
+}
+
+// UserDigest is what one user did in the period. Blocked lists the open tasks
+// assigned to them that wait on unfinished dependencies now.
+type UserDigest struct {
+	UserID    uint            `json:"user_id"`
+	UserName  string          `json:"user_name"`
+	Type      models.UserType `json:"type"`
+	Clients   []string        `json:"clients,omitempty"` // MCP clients their changes were made with
+	Completed []DigestTask    `json:"completed"`
+	Started   []DigestTask    `json:"started"`
+	Blocked   []DigestTask    `json:"blocked"`
+	Created   []DigestTask    `json:"created"`
+	Commented []DigestTask    `json:"commented"`
+}
+
+// Digest summarises the activity of a period per user, for standups and
+// status reports
+type Digest struct {
+	From        time.Time       `json:"from"`
+	To          time.Time       `json:"to"`
+	UserID      *uint           `json:"user_id,omitempty"`
+	ProjectID   *uint           `json:"project_id,omitempty"`
+	ProjectName string          `json:"project_name,omitempty"`
+	UserType    models.UserType `json:"user_type,omitempty"`
+	Users       []UserDigest    `json:"users"`
+	Totals      DigestCounts    `json:"totals"`
+}
+
+// unmetDependency matches the dependencies d whose predecessor p does not let
+// the task start yet, as CanStartTask decides
+const unmetDependency = `((d.type = 'start_to_start' AND p.status = 'todo') OR
+	(d.type <> 'start_to_start' AND p.status <> 'done'))`
+
+// GetActivityDigest summarises the activity log and comments of a period: per
+// user the tasks they completed, started, created and commented on, and the
+// tasks assigned to them that are blocked
+func (db *Database) GetActivityDigest(filter DigestFilter) (*Digest, error) {
+	if !filter.To.After(filter.From) {
+		return nil, fmt.Errorf("the end of the period must be after its start")
+	}
+
+	digest := &Digest{
+		From:      filter.From,
+		To:        filter.To,
+		UserID:    filter.UserID,
+		ProjectID: filter.ProjectID,
+		UserType:  filter.UserType,
+	}
+	if filter.UserID != nil {
+		if _, err := db.GetUserByID(*filter.UserID); err != nil {
+			return nil, fmt.Errorf("user not found: %w", err)
+		}
+	}
+	if filter.ProjectID != nil {
+		var project models.Project
+		if err := db.First(&project, *filter.ProjectID).Error; err != nil {
+			return nil, fmt.Errorf("project not found: %w", err)
+		}
+		digest.ProjectName = project.Name
+	}
+
+	builder := newDigestBuilder()
+
+	var activities []models.Activity
+	err := digestScope(db.Preload("User").Preload("Task.Project").
+		Joins("JOIN tasks ON tasks.id = activities.task_id AND tasks.deleted_at IS NULL").
+		Where("activities.user_id IS NOT NULL AND activities.created_at >= ? AND activities.created_at < ?", filter.From, filter.To),
+		filter, "activities.user_id", "tasks.project_id").
+		Order("activities.created_at").
+		Find(&activities).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, activity := range activities {
+		user := builder.user(*activity.UserID, activity.UserName, activity.User)
+		if activity.Client != "" {
+			user.clients[activity.Client] = true
+		}
+		switch {
+		case activity.Action == "created":
+			user.add("created", activity.Task, activity.CreatedAt)
+		case activity.Action == "status_changed" && activity.NewValue == string(models.TaskStatusDone):
+			user.add("completed", activity.Task, activity.CreatedAt)
+		case activity.Action == "status_changed" && activity.NewValue == string(models.TaskStatusInProgress):
+			user.add("started", activity.Task, activity.CreatedAt)
+		}
+	}
+
+	var comments []models.Comment
+	err = digestScope(db.Preload("AuthorUser").Preload("Task.Project").
+		Joins("JOIN tasks ON tasks.id = comments.task_id AND tasks.deleted_at IS NULL").
+		Where("comments.author_id IS NOT NULL AND comments.created_at >= ? AND comments.created_at < ?", filter.From, filter.To),
+		filter, "comments.author_id", "tasks.project_id").
+		Order("comments.created_at").
+		Find(&comments).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, comment := range comments {
+		user := builder.user(*comment.AuthorID, comment.Author, comment.AuthorUser)
+		user.add("commented", comment.Task, comment.CreatedAt).Comments++
+	}
+
+	if err := db.addBlockedTasks(builder, filter); err != nil {
+		return nil, err
+	}
+
+	digest.Users = builder.build()
+	for _, user := range digest.Users {
+		digest.Totals.Completed += len(user.Completed)
+		digest.Totals.Started += len(user.Started)
+		digest.Totals.Blocked += len(user.Blocked)
+		digest.Totals.Created += len(user.Created)
+		digest.Totals.Commented += len(user.Commented)
+	}
+	return digest, nil
+}
+
+// addBlockedTasks adds the open tasks assigned to users in the digest's scope
+// that wait on unfinished dependencies
+func (db *Database) addBlockedTasks(builder *digestBuilder, filter DigestFilter) error {
+	var tasks []models.Task
+	err := digestScope(db.Preload("AssigneeUser").Preload("Project").
+		Where("tasks.assignee_id IS NOT NULL AND tasks.deleted_at IS NULL AND tasks.status IN ?",
+			[]models.TaskStatus{models.TaskStatusTodo, models.TaskStatusInProgress, models.TaskStatusReview}).
+		Where(`EXISTS (SELECT 1 FROM task_dependencies d JOIN tasks p ON p.id = d.depends_on_id
+			WHERE d.task_id = tasks.id AND `+unmetDependency+`)`),
+		filter, "tasks.assignee_id", "tasks.project_id").
+		Order("tasks.id").
+		Find(&tasks).Error
+	if err != nil || len(tasks) == 0 {
+		return err
+	}
+
+	ids := make([]uint, len(tasks))
+	for i, task := range tasks {
+		ids[i] = task.ID
+	}
+	var unmet []struct {
+		TaskID      uint
+		DependsOnID uint
+	}
+	err = db.Table("task_dependencies d").
+		Select("d.task_id, d.depends_on_id").
+		Joins("JOIN tasks p ON p.id = d.depends_on_id").
+		Where("d.task_id IN ? AND "+unmetDependency, ids).
+		Order("d.depends_on_id").
+		Scan(&unmet).Error
+	if err != nil {
+		return err
+	}
+	blockedBy := make(map[uint][]uint)
+	for _, dependency := range unmet {
+		blockedBy[dependency.TaskID] = append(blockedBy[dependency.TaskID], dependency.DependsOnID)
+	}
+
+	for i := range tasks {
+		task := &tasks[i]
+		user := builder.user(*task.AssigneeID, task.Assignee, task.AssigneeUser)
+		user.add("blocked", task, time.Time{}).BlockedBy = blockedBy[task.ID]
+	}
+	return nil
+}
+
+// digestScope restricts a digest query to the filter's user, project and type of user
+func digestScope(query *gorm.DB, filter DigestFilter, userColumn, projectColumn string) *gorm.DB {
+	if filter.UserID != nil {
+		query = query.Where(userColumn+" = ?", *filter.UserID)
+	}
+	if filter.ProjectID != nil {
+		query = query.Where(projectColumn+" = ?", *filter.ProjectID)
+	}
+	if filter.UserType != "" {
+		query = query.Where(userColumn+" IN (SELECT id FROM users WHERE type = ?)", filter.UserType)
+	}
+	return query
+}
+
+// digestBuilder collects the sections of each user, mentioning a task once
+// per section
+type digestBuilder struct {
+	users map[uint]*userDigestBuilder
+}
+
+type userDigestBuilder struct {
+	digest   UserDigest
+	clients  map[string]bool
+	sections map[string]map[uint]*DigestTask
+}
+
+func newDigestBuilder() *digestBuilder {
+	return &digestBuilder{users: make(map[uint]*userDigestBuilder)}
+}
+
+// user returns the builder of a user; name is the fallback for deleted users
+func (b *digestBuilder) user(id uint, name string, user *models.User) *userDigestBuilder {
+	if builder, ok := b.users[id]; ok {
+		return builder
+	}
+	builder := &userDigestBuilder{
+		digest:   UserDigest{UserID: id, UserName: name, Type: models.UserTypeHuman},
+		clients:  make(map[string]bool),
+		sections: make(map[string]map[uint]*DigestTask),
+	}
+	if user != nil {
+		builder.digest.UserName = user.Username
+		if user.Type != "" {
+			builder.digest.Type = user.Type
+		}
+	}
+	b.users[id] = builder
+	return builder
+}
+
+// add mentions a task in a section, at the latest time it happened
+func (b *userDigestBuilder) add(section string, task *models.Task, at time.Time) *DigestTask {
+	if b.sections[section] == nil {
+		b.sections[section] = make(map[uint]*DigestTask)
+	}
+	entry, ok := b.sections[section][task.ID]
+	if !ok {
+		entry = &DigestTask{ID: task.ID, Title: task.Title, ProjectID: task.ProjectID, Status: task.Status}
+		if task.Project != nil {
+			entry.ProjectName = task.Project.Name
+		}
+		b.sections[section][task.ID] = entry
+	}
+	if !at.IsZero() {
+		entry.At = &at
+	}
+	return entry
+}
+
+// build returns the users ordered by name, with each section in the order the
+// tasks were last touched
+func (b *digestBuilder) build() []UserDigest {
+	users := make([]UserDigest, 0, len(b.users))
+	for _, builder := range b.users {
+		digest := builder.digest
+		for client := range builder.clients {
+			digest.Clients = append(digest.Clients, client)
+		}
+		sort.Strings(digest.Clients)
+		digest.Completed = builder.section("completed")
+		digest.Started = builder.section("started")
+		digest.Blocked = builder.section("blocked")
+		digest.Created = builder.section("created")
+		digest.Commented = builder.section("commented")
+		users = append(users, digest)
+	}
+	sort.Slice(users, func(i, j int) bool {
+		if users[i].UserName != users[j].UserName {
+			return users[i].UserName < users[j].UserName
+		}
+		return users[i].UserID < users[j].UserID
+	})
+	return users
+}
+
+func (b *userDigestBuilder) section(name string) []DigestTask {
+	tasks := make([]DigestTask, 0, len(b.sections[name]))
+	for _, task := range b.sections[name] {
+		tasks = append(tasks, *task)
+	}
+	sort.Slice(tasks, func(i, j int) bool {
+		if tasks[i].At != nil && tasks[j].At != nil && !tasks[i].At.Equal(*tasks[j].At) {
+			return tasks[i].At.Before(*tasks[j].At)
+		}
+		return tasks[i].ID < tasks[j].ID
+	})
+	return tasks
+}
+
+// WriteMarkdown writes the digest as Markdown, ready to post as a standup or
+// status report
+func (d *Digest) WriteMarkdown(w io.Writer) error {
+	var b strings.Builder
+
+	b.WriteString("# Activity digest")
+	if d.ProjectName != "" {
+		fmt.Fprintf(&b, ": %s", d.ProjectName)
+	}
+	fmt.Fprintf(&b, "\n\n%s to %s\n", d.From.Format("2006-01-02 15:04"), d.To.Format("2006-01-02 15:04"))
+	fmt.Fprintf(&b, "\n%d completed, %d started, %d blocked, %d created, %d commented on\n",
+		d.Totals.Completed, d.Totals.Started, d.Totals.Blocked, d.Totals.Created, d.Totals.Commented)
+
+	if len(d.Users) == 0 {
+		b.WriteString("\nNo activity in this period.\n")
+	}
+	for _, user := range d.Users {
+		fmt.Fprintf(&b, "\n## %s", user.UserName)
+		if user.Type == models.UserTypeAgent {
+			b.WriteString(" (agent")
+			if len(user.Clients) > 0 {
+				fmt.Fprintf(&b, " via %s", strings.Join(user.Clients, ", "))
+			}
+			b.WriteString(")")
+		}
+		b.WriteString("\n")
+
+		writeDigestSection(&b, "Completed", user.Completed)
+		writeDigestSection(&b, "Started", user.Started)
+		writeDigestSection(&b, "Blocked", user.Blocked)
+		writeDigestSection(&b, "Created", user.Created)
+		writeDigestSection(&b, "Commented on", user.Commented)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeDigestSection(b *strings.Builder, title string, tasks []DigestTask) {
+	if len(tasks) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "\n### %s\n\n", title)
+	for _, task := range tasks {
+		fmt.Fprintf(b, "- #%d %s", task.ID, task.Title)
+		if task.ProjectName != "" {
+			fmt.Fprintf(b, " (%s)", task.ProjectName)
+		}
+		if len(task.BlockedBy) > 0 {
+			waiting := make([]string, len(task.BlockedBy))
+			for i, id := range task.BlockedBy {
+				waiting[i] = fmt.Sprintf("#%d", id)
+			}
+			fmt.Fprintf(b, ", waiting on %s", strings.Join(waiting, ", "))
+		}
+		if task.Comments > 1 {
+			fmt.Fprintf(b, ", %d comments", task.Comments)
+		}
+		b.WriteString("\n")
+	}
+}
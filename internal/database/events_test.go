@@ -0,0 +1,46 @@
+package database
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/headless-pm/headless-project-management/internal/events"
+	"github.com/headless-pm/headless-project-management/internal/models"
+)
+
+func TestEventsFollowCommittedWrites(t *testing.T) {
+	db := newTestDatabase(t)
+	project := createProject(t, db, "Apollo")
+	fuel := createTask(t, db, project.ID, "Fuel")
+	launch := createTask(t, db, project.ID, "Launch")
+	if err := db.CreateTaskDependency(&models.TaskDependency{TaskID: launch.ID, DependsOnID: fuel.ID}); err != nil {
+		t.Fatalf("CreateTaskDependency: %v", err)
+	}
+
+	var published []string
+	db.Events().SubscribeAll(func(event events.Event) {
+		published = append(published, event.Name())
+
+		// Subscribers see the write that triggered the event
+		if e, ok := event.(events.TaskUpdated); ok {
+			stored, err := db.GetTask(e.Task.ID)
+			if err != nil || stored.Status != e.Task.Status {
+				t.Errorf("task.updated published before the task was saved: %v", err)
+			}
+		}
+	})
+
+	task, err := db.GetTask(fuel.ID)
+	if err != nil {
+		t.Fatalf("GetTask: %v", err)
+	}
+	task.Status = models.TaskStatusDone
+	if err := db.UpdateTask(task); err != nil {
+		t.Fatalf("UpdateTask: %v", err)
+	}
+
+	want := []string{events.NameTaskUpdated, events.NameDependencyRemoved, events.NameTaskUnblocked}
+	if !reflect.DeepEqual(published, want) {
+		t.Errorf("published %v, want %v", published, want)
+	}
+}
@@ -0,0 +1,56 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"github.com/headless-pm/headless-project-management/internal/models"
+	"gorm.io/gorm/logger"
+)
+
+// Helpers shared by the tests of the database
+
+// newTestDatabase opens a fresh database in a temporary directory
+func newTestDatabase(t *testing.T) *Database {
+	t.Helper()
+	db, err := NewDatabaseWithLogger(t.TempDir(), logger.Discard)
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	return db
+}
+
+// createUser adds a user with the given name
+func createUser(t *testing.T, db *Database, username string) *models.User {
+	t.Helper()
+	user := &models.User{Username: username, Email: username + "@example.com"}
+	if err := db.Create(user).Error; err != nil {
+		t.Fatalf("failed to create user %s: %v", username, err)
+	}
+	return user
+}
+
+// createProject adds a project with the given name
+func createProject(t *testing.T, db *Database, name string) *models.Project {
+	t.Helper()
+	project := &models.Project{Name: name}
+	if err := db.CreateProject(project); err != nil {
+		t.Fatalf("failed to create project %s: %v", name, err)
+	}
+	return project
+}
+
+// createTask adds a task to a project
+func createTask(t *testing.T, db *Database, projectID uint, title string) *models.Task {
+	t.Helper()
+	task := &models.Task{ProjectID: projectID, Title: title}
+	if err := db.CreateTask(task); err != nil {
+		t.Fatalf("failed to create task %s: %v", title, err)
+	}
+	return task
+}
+
+// actingAs returns the database bound to a request of user
+func actingAs(db *Database, user *models.User) *Database {
+	return db.WithContext(WithPrincipal(context.Background(), PrincipalForUser(user, "")))
+}
@@ -0,0 +1,302 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/headless-pm/headless-project-management/internal/events"
+	"github.com/headless-pm/headless-project-management/internal/models"
+	"gorm.io/gorm"
+)
+
+const (
+	// DefaultLeaseDuration is how long a claim lasts without a heartbeat
+	DefaultLeaseDuration = 10 * time.Minute
+	// MaxLeaseDuration caps the lease a claim or heartbeat can ask for
+	MaxLeaseDuration = 24 * time.Hour
+)
+
+var (
+	// ErrNoTaskAvailable is returned when no task of the project can be claimed
+	ErrNoTaskAvailable = errors.New("no task available to claim")
+	// ErrLeaseNotHeld is returned for heartbeats and releases of a task the
+	// user has no lease on, e.g. because the lease expired
+	ErrLeaseNotHeld = errors.New("task is not leased to this user")
+)
+
+// ClaimRequest describes which task a user may claim. A task qualifies for
+// Labels when all of its labels are among them; without Labels every task does.
+type ClaimRequest struct {
+	ProjectID     uint
+	UserID        uint
+	Labels        []string
+	LeaseDuration time.Duration
+}
+
+// TaskClaim is a claimed task together with its lease
+type TaskClaim struct {
+	Task  *models.Task      `json:"task"`
+	Lease *models.TaskLease `json:"lease"`
+}
+
+// claimOrder ranks candidates: most urgent first, then the earliest due date,
+// then the oldest task
+const claimOrder = `CASE priority
+	WHEN 'urgent' THEN 0 WHEN 'high' THEN 1 WHEN 'medium' THEN 2 WHEN 'low' THEN 3 ELSE 2
+END, due_date IS NULL, due_date, id`
+
+// ClaimNextTask assigns the best available task of a project to the user and
+// leases it to them. Available tasks are to do, unassigned or already assigned
+// to the user, not leased, qualified for by the user's labels and not blocked
+// by dependencies. Concurrent claims never get the same task.
+func (db *Database) ClaimNextTask(req ClaimRequest) (*TaskClaim, error) {
+	duration, err := leaseDuration(req.LeaseDuration)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.First(&models.Project{}, req.ProjectID).Error; err != nil {
+		return nil, fmt.Errorf("project not found: %w", err)
+	}
+	user, err := db.GetUserByID(req.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("user not found: %w", err)
+	}
+
+	query := db.Model(&models.Task{}).
+		Where("project_id = ? AND status = ? AND deleted_at IS NULL", req.ProjectID, models.TaskStatusTodo).
+		Where("assignee_id IS NULL OR assignee_id = ?", req.UserID).
+		Where("id NOT IN (SELECT task_id FROM task_leases)")
+	if len(req.Labels) > 0 {
+		query = query.Where(`NOT EXISTS (
+			SELECT 1 FROM task_labels JOIN labels ON labels.id = task_labels.label_id
+			WHERE task_labels.task_id = tasks.id AND labels.name NOT IN ?
+		)`, req.Labels)
+	}
+
+	var candidates []uint
+	if err := query.Order(claimOrder).Pluck("id", &candidates).Error; err != nil {
+		return nil, err
+	}
+
+	for _, id := range candidates {
+		canStart, err := db.CanStartTask(id)
+		if err != nil {
+			return nil, err
+		}
+		if !canStart {
+			continue
+		}
+
+		claim, err := db.claimTask(id, user, duration)
+		if err != nil {
+			return nil, err
+		}
+		if claim != nil {
+			return claim, nil
+		}
+		// Another claim took the task first, try the next one
+	}
+
+	return nil, ErrNoTaskAvailable
+}
+
+// claimTask assigns and leases a task if it is still available, and returns
+// nil without an error if it is not
+func (db *Database) claimTask(taskID uint, user *models.User, duration time.Duration) (*TaskClaim, error) {
+	var old models.Task
+	if err := db.First(&old, taskID).Error; err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	lease := &models.TaskLease{
+		TaskID:      taskID,
+		UserID:      user.ID,
+		ClaimedAt:   now,
+		HeartbeatAt: now,
+		ExpiresAt:   now.Add(duration),
+	}
+	claimed := false
+	err := db.Transaction(func(tx *gorm.DB) error {
+		startDate := old.StartDate
+		if startDate == nil {
+			startDate = &now
+		}
+		result := tx.Model(&models.Task{}).
+			Where("id = ? AND status = ?", taskID, models.TaskStatusTodo).
+			Where("assignee_id IS NULL OR assignee_id = ?", user.ID).
+			Where("id NOT IN (SELECT task_id FROM task_leases)").
+			Updates(map[string]interface{}{
+				"assignee_id": user.ID,
+				"assignee":    user.Username,
+				"status":      models.TaskStatusInProgress,
+				"start_date":  startDate,
+				"updated_by":  user.ID,
+			})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		claimed = true
+		return tx.Create(lease).Error
+	})
+	if err != nil || !claimed {
+		return nil, err
+	}
+
+	task, err := db.GetTask(taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	actor := events.Actor{ID: &user.ID, Name: user.Username}
+	_ = db.LogActivity(taskID, &user.ID, user.Username, "claimed", "assignee", old.Assignee, user.Username,
+		fmt.Sprintf("Task claimed by %s, leased until %s", user.Username, lease.ExpiresAt.Format(time.RFC3339)))
+	_ = db.LogTaskStatusChanged(taskID, &user.ID, user.Username, string(old.Status), string(task.Status))
+	db.autoWatch(taskID, &user.ID)
+	db.publish(events.TaskUpdated{Old: &old, Task: task, Diff: events.DiffTasks(&old, task), Actor: actor})
+
+	return &TaskClaim{Task: task, Lease: lease}, nil
+}
+
+// HeartbeatTaskLease extends the user's lease on a task by duration from now
+func (db *Database) HeartbeatTaskLease(taskID, userID uint, duration time.Duration) (*models.TaskLease, error) {
+	duration, err := leaseDuration(duration)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	result := db.Model(&models.TaskLease{}).
+		Where("task_id = ? AND user_id = ? AND expires_at > ?", taskID, userID, now).
+		Updates(map[string]interface{}{"heartbeat_at": now, "expires_at": now.Add(duration)})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrLeaseNotHeld
+	}
+
+	var lease models.TaskLease
+	if err := db.Where("task_id = ?", taskID).First(&lease).Error; err != nil {
+		return nil, err
+	}
+	return &lease, nil
+}
+
+// ReleaseTaskLease gives up the user's lease on a task. A task still in
+// progress goes back to the pool, unassigned and to do.
+func (db *Database) ReleaseTaskLease(taskID, userID uint) (*models.Task, error) {
+	var lease models.TaskLease
+	if err := db.Where("task_id = ? AND user_id = ?", taskID, userID).First(&lease).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrLeaseNotHeld
+		}
+		return nil, err
+	}
+
+	user, err := db.GetUserByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("user not found: %w", err)
+	}
+	actor := events.Actor{ID: &user.ID, Name: user.Username}
+	if err := db.endLease(lease, nil, actor, "released",
+		fmt.Sprintf("Released by %s, returned to the pool", user.Username)); err != nil {
+		return nil, err
+	}
+	return db.GetTask(taskID)
+}
+
+// ExpireTaskLeases ends the leases that expired before now, returning their
+// tasks to the pool, and drops the leases of tasks that were finished. It
+// returns how many leases expired.
+func (db *Database) ExpireTaskLeases(now time.Time) (int, error) {
+	if err := db.Where("task_id IN (SELECT id FROM tasks WHERE status IN ?)",
+		[]models.TaskStatus{models.TaskStatusDone, models.TaskStatusCancelled}).
+		Delete(&models.TaskLease{}).Error; err != nil {
+		return 0, err
+	}
+
+	var leases []models.TaskLease
+	if err := db.Preload("User").Where("expires_at <= ?", now).Order("expires_at").Find(&leases).Error; err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	for _, lease := range leases {
+		holder := fmt.Sprintf("user %d", lease.UserID)
+		if lease.User != nil {
+			holder = lease.User.Username
+		}
+		description := fmt.Sprintf("Lease of %s expired without a heartbeat, returned to the pool", holder)
+		if err := db.endLease(lease, &now, events.Actor{Name: "System"}, "lease_expired", description); err != nil {
+			if errors.Is(err, ErrLeaseNotHeld) {
+				// Renewed or released meanwhile
+				continue
+			}
+			return expired, err
+		}
+		expired++
+		db.publish(events.TaskLeaseExpired{Lease: lease})
+	}
+	return expired, nil
+}
+
+// endLease deletes a lease, if expiredBy is set only when it is still expired
+// by then, and returns the task to the pool if it is still in progress with
+// the lease holder. The handoff is logged as action on the task.
+func (db *Database) endLease(lease models.TaskLease, expiredBy *time.Time, actor events.Actor, action, description string) error {
+	var old models.Task
+	if err := db.First(&old, lease.TaskID).Error; err != nil {
+		return err
+	}
+
+	returned := false
+	err := db.Transaction(func(tx *gorm.DB) error {
+		query := tx.Where("id = ?", lease.ID)
+		if expiredBy != nil {
+			query = query.Where("expires_at <= ?", *expiredBy)
+		}
+		deleted := query.Delete(&models.TaskLease{})
+		if deleted.Error != nil {
+			return deleted.Error
+		}
+		if deleted.RowsAffected == 0 {
+			return ErrLeaseNotHeld
+		}
+
+		result := tx.Model(&models.Task{}).
+			Where("id = ? AND status = ? AND assignee_id = ?", lease.TaskID, models.TaskStatusInProgress, lease.UserID).
+			Updates(map[string]interface{}{
+				"assignee_id": nil,
+				"assignee":    "",
+				"status":      models.TaskStatusTodo,
+				"updated_by":  actor.ID,
+			})
+		returned = result.RowsAffected > 0
+		return result.Error
+	})
+	if err != nil || !returned {
+		return err
+	}
+
+	task, err := db.GetTask(lease.TaskID)
+	if err != nil {
+		return err
+	}
+	_ = db.LogActivity(lease.TaskID, actor.ID, actor.Name, action, "assignee", old.Assignee, "", description)
+	_ = db.LogTaskStatusChanged(lease.TaskID, actor.ID, actor.Name, string(old.Status), string(task.Status))
+	db.publish(events.TaskUpdated{Old: &old, Task: task, Diff: events.DiffTasks(&old, task), Actor: actor})
+	return nil
+}
+
+// leaseDuration applies the default to an unset duration and checks the bounds
+func leaseDuration(duration time.Duration) (time.Duration, error) {
+	if duration == 0 {
+		return DefaultLeaseDuration, nil
+	}
+	if duration < time.Second || duration > MaxLeaseDuration {
+		return 0, fmt.Errorf("lease duration must be between 1s and %s", MaxLeaseDuration)
+	}
+	return duration, nil
+}
@@ -0,0 +1,94 @@
+package database
+
+import (
+	"github.com/headless-pm/headless-project-management/internal/events"
+	"github.com/headless-pm/headless-project-management/internal/models"
+)
+
+// GetTaskMentions returns the mentions recorded for a task and its comments
+func (db *Database) GetTaskMentions(taskID uint) ([]models.Mention, error) {
+	var mentions []models.Mention
+	err := db.Preload("User").Where("task_id = ?", taskID).Order("created_at ASC").Find(&mentions).Error
+	return mentions, err
+}
+
+// GetTasksMentioningUser returns the tasks whose description or comments mention a user,
+// most recently mentioned first
+func (db *Database) GetTasksMentioningUser(userID uint) ([]models.Task, error) {
+	var tasks []models.Task
+	err := db.Where("id IN (?)", db.Model(&models.Mention{}).Select("task_id").Where("user_id = ?", userID)).
+		Preload("Project").
+		Order("(SELECT MAX(created_at) FROM mentions WHERE mentions.task_id = tasks.id) DESC").
+		Find(&tasks).Error
+	return tasks, err
+}
+
+// syncMentions records the users mentioned in a task description (commentID nil)
+// or in a comment. Mentions that were removed from the text are dropped, and
+// users mentioned for the first time are announced with a UsersMentioned event.
+func (db *Database) syncMentions(taskID uint, commentID *uint, text string, actor events.Actor) {
+	source := models.MentionSourceDescription
+	query := db.Where("task_id = ? AND comment_id IS NULL", taskID)
+	if commentID != nil {
+		source = models.MentionSourceComment
+		query = db.Where("comment_id = ?", *commentID)
+	}
+
+	var existing []models.Mention
+	if err := query.Find(&existing).Error; err != nil {
+		return
+	}
+	alreadyMentioned := make(map[uint]bool)
+	for _, mention := range existing {
+		alreadyMentioned[mention.UserID] = true
+	}
+
+	var users []models.User
+	if usernames := models.ExtractMentions(text); len(usernames) > 0 {
+		if err := db.Where("username IN ?", usernames).Find(&users).Error; err != nil {
+			return
+		}
+	}
+
+	mentioned := make(map[uint]bool)
+	var newlyMentioned []uint
+	for _, user := range users {
+		mentioned[user.ID] = true
+		if alreadyMentioned[user.ID] {
+			continue
+		}
+		mention := &models.Mention{
+			UserID:    user.ID,
+			TaskID:    taskID,
+			CommentID: commentID,
+			Source:    source,
+		}
+		if err := db.Create(mention).Error; err == nil {
+			newlyMentioned = append(newlyMentioned, user.ID)
+		}
+	}
+
+	for _, mention := range existing {
+		if !mentioned[mention.UserID] {
+			db.Delete(&models.Mention{}, mention.ID)
+		}
+	}
+
+	if len(newlyMentioned) == 0 {
+		return
+	}
+
+	var task models.Task
+	if err := db.First(&task, taskID).Error; err != nil {
+		return
+	}
+
+	db.publish(events.UsersMentioned{
+		TaskID:    task.ID,
+		ProjectID: task.ProjectID,
+		TaskTitle: task.Title,
+		CommentID: commentID,
+		UserIDs:   newlyMentioned,
+		Actor:     actor,
+	})
+}
@@ -0,0 +1,51 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/headless-pm/headless-project-management/internal/events"
+	"github.com/headless-pm/headless-project-management/internal/models"
+)
+
+func TestMentions(t *testing.T) {
+	db := newTestDatabase(t)
+	alice := createUser(t, db, "alice")
+	bob := createUser(t, db, "bob")
+	project := createProject(t, db, "Mentions")
+
+	var announced [][]uint
+	db.Events().Subscribe(func(event events.Event) {
+		if mentioned, ok := event.(events.UsersMentioned); ok {
+			announced = append(announced, mentioned.UserIDs)
+		}
+	}, events.NameUsersMentioned)
+
+	task := &models.Task{ProjectID: project.ID, Title: "Review", Description: "@alice and @nobody please look"}
+	if err := actingAs(db, bob).CreateTask(task); err != nil {
+		t.Fatal(err)
+	}
+	mentions, _ := db.GetTaskMentions(task.ID)
+	if len(mentions) != 1 || mentions[0].UserID != alice.ID {
+		t.Fatalf("mentions are %v, want alice only", mentions)
+	}
+
+	// Editing the description keeps existing mentions and only announces new ones
+	task.Description = "@alice @bob please look"
+	if err := db.UpdateTask(task); err != nil {
+		t.Fatal(err)
+	}
+	task.Description = "@bob only"
+	if err := db.UpdateTask(task); err != nil {
+		t.Fatal(err)
+	}
+
+	if tasks, _ := db.GetTasksMentioningUser(alice.ID); len(tasks) != 0 {
+		t.Errorf("alice is still mentioned by %d tasks after the mention was removed", len(tasks))
+	}
+	if tasks, _ := db.GetTasksMentioningUser(bob.ID); len(tasks) != 1 {
+		t.Errorf("bob is mentioned by %d tasks, want 1", len(tasks))
+	}
+	if len(announced) != 2 || announced[0][0] != alice.ID || announced[1][0] != bob.ID {
+		t.Errorf("announced mentions %v, want alice then bob", announced)
+	}
+}
@@ -0,0 +1,116 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/headless-pm/headless-project-management/internal/models"
+	"gorm.io/gorm/clause"
+)
+
+// ErrInvalidMilestone is wrapped by the errors of milestones that cannot be saved
+var ErrInvalidMilestone = errors.New("invalid milestone")
+
+// Milestone CRUD methods
+func (db *Database) CreateMilestone(milestone *models.Milestone) error {
+	if milestone.Status != "" && !models.IsValidMilestoneStatus(string(milestone.Status)) {
+		return fmt.Errorf("%w: unknown status %s", ErrInvalidMilestone, milestone.Status)
+	}
+	if err := db.requireProject(milestone.ProjectID); err != nil {
+		return err
+	}
+	return db.Create(milestone).Error
+}
+
+func (db *Database) GetMilestone(id uint) (*models.Milestone, error) {
+	var milestone models.Milestone
+	err := db.Preload("Project").Preload("Tasks.AssigneeUser").Preload("Tasks.Labels").First(&milestone, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &milestone, nil
+}
+
+func (db *Database) ListMilestones(projectID *uint, status *models.MilestoneStatus) ([]models.Milestone, error) {
+	var milestones []models.Milestone
+	query := db.DB
+
+	if projectID != nil {
+		query = query.Where("project_id = ?", *projectID)
+	}
+	if status != nil {
+		query = query.Where("status = ?", *status)
+	}
+
+	err := query.Preload("Tasks").Order("due_date IS NULL, due_date ASC, id ASC").Find(&milestones).Error
+	return milestones, err
+}
+
+// UpdateMilestone saves the details and status of a milestone; its project stays the same
+func (db *Database) UpdateMilestone(milestone *models.Milestone) error {
+	if milestone.Status != "" && !models.IsValidMilestoneStatus(string(milestone.Status)) {
+		return fmt.Errorf("%w: unknown status %s", ErrInvalidMilestone, milestone.Status)
+	}
+
+	// Track completion time the same way tasks do
+	if milestone.Status == models.MilestoneStatusCompleted && milestone.CompletedAt == nil {
+		now := time.Now()
+		milestone.CompletedAt = &now
+	} else if milestone.Status != models.MilestoneStatusCompleted {
+		milestone.CompletedAt = nil
+	}
+
+	return db.Omit("project_id", clause.Associations).Save(milestone).Error
+}
+
+// DeleteMilestone deletes a milestone and detaches its tasks
+func (db *Database) DeleteMilestone(id uint) error {
+	tx := db.Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	if err := tx.Model(&models.Task{}).Where("milestone_id = ?", id).
+		Update("milestone_id", nil).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Delete(&models.Milestone{}, id).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// AssignTaskToMilestone links a task to a milestone of the same project
+func (db *Database) AssignTaskToMilestone(taskID uint, milestoneID uint) error {
+	var task models.Task
+	if err := db.First(&task, taskID).Error; err != nil {
+		return err
+	}
+
+	if _, err := db.milestoneForProject(task.ProjectID, milestoneID); err != nil {
+		return fmt.Errorf("cannot assign task %d to milestone %d: %w", taskID, milestoneID, err)
+	}
+
+	return db.Model(&task).Update("milestone_id", milestoneID).Error
+}
+
+// milestoneForProject returns a milestone that tasks of the project can be assigned to
+func (db *Database) milestoneForProject(projectID, milestoneID uint) (*models.Milestone, error) {
+	var milestone models.Milestone
+	if err := db.First(&milestone, milestoneID).Error; err != nil {
+		return nil, err
+	}
+	if milestone.ProjectID != projectID {
+		return nil, fmt.Errorf("the milestone belongs to another project")
+	}
+	return &milestone, nil
+}
+
+func (db *Database) RemoveTaskFromMilestone(taskID uint) error {
+	return db.Model(&models.Task{}).Where("id = ?", taskID).Update("milestone_id", nil).Error
+}
@@ -0,0 +1,265 @@
+package database
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/headless-pm/headless-project-management/internal/events"
+	"github.com/headless-pm/headless-project-management/internal/models"
+	"gorm.io/gorm/clause"
+)
+
+// ListNotifications returns a user's notifications, newest first
+func (db *Database) ListNotifications(userID uint, unreadOnly bool, limit int) ([]models.Notification, error) {
+	var notifications []models.Notification
+	query := db.Where("user_id = ?", userID)
+
+	if unreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	err := query.Order("created_at DESC, id DESC").Find(&notifications).Error
+	return notifications, err
+}
+
+// CountUnreadNotifications returns how many unread notifications a user has
+func (db *Database) CountUnreadNotifications(userID uint) (int64, error) {
+	var count int64
+	err := db.Model(&models.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Count(&count).Error
+	return count, err
+}
+
+// MarkNotificationsRead marks a user's notifications as read. An empty list
+// marks all of them. It returns the number of notifications changed.
+func (db *Database) MarkNotificationsRead(userID uint, notificationIDs []uint) (int64, error) {
+	query := db.Model(&models.Notification{}).Where("user_id = ? AND read_at IS NULL", userID)
+	if len(notificationIDs) > 0 {
+		query = query.Where("id IN ?", notificationIDs)
+	}
+
+	result := query.Update("read_at", time.Now())
+	return result.RowsAffected, result.Error
+}
+
+// MarkNotificationUnread puts a notification back into a user's unread list
+func (db *Database) MarkNotificationUnread(userID uint, notificationID uint) error {
+	result := db.Model(&models.Notification{}).
+		Where("id = ? AND user_id = ?", notificationID, userID).
+		Update("read_at", nil)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("notification %d not found", notificationID)
+	}
+	return nil
+}
+
+// DeleteNotification removes a notification from a user's inbox
+func (db *Database) DeleteNotification(userID uint, notificationID uint) error {
+	return db.Where("id = ? AND user_id = ?", notificationID, userID).
+		Delete(&models.Notification{}).Error
+}
+
+// GetNotificationPreferences returns whether each notification type is enabled for a user
+func (db *Database) GetNotificationPreferences(userID uint) (map[models.NotificationType]bool, error) {
+	var prefs []models.NotificationPreference
+	if err := db.Where("user_id = ?", userID).Find(&prefs).Error; err != nil {
+		return nil, err
+	}
+
+	result := make(map[models.NotificationType]bool)
+	for _, t := range models.GetNotificationTypes() {
+		result[t] = true
+	}
+	for _, pref := range prefs {
+		result[pref.Type] = pref.Enabled
+	}
+	return result, nil
+}
+
+// UpdateNotificationPreferences enables or disables notification types for a user.
+// Types missing from the map are left unchanged.
+func (db *Database) UpdateNotificationPreferences(userID uint, prefs map[models.NotificationType]bool) error {
+	for notificationType := range prefs {
+		if !models.IsValidNotificationType(string(notificationType)) {
+			return fmt.Errorf("invalid notification type: %s. Valid values: %v",
+				notificationType, models.GetNotificationTypes())
+		}
+	}
+
+	tx := db.Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	for notificationType, enabled := range prefs {
+		pref := models.NotificationPreference{
+			UserID:  userID,
+			Type:    notificationType,
+			Enabled: enabled,
+		}
+		if err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}, {Name: "type"}},
+			DoUpdates: clause.AssignmentColumns([]string{"enabled"}),
+		}).Create(&pref).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit().Error
+}
+
+// NotifyDueSoonTasks notifies assignees of open tasks due within the given window.
+// A task is only announced once per assignee. It returns the number of notifications sent.
+func (db *Database) NotifyDueSoonTasks(window time.Duration) (int, error) {
+	now := time.Now()
+	var tasks []models.Task
+	if err := db.Where("assignee_id IS NOT NULL AND due_date IS NOT NULL").
+		Where("due_date > ? AND due_date <= ?", now, now.Add(window)).
+		Where("status NOT IN ?", []models.TaskStatus{models.TaskStatusDone, models.TaskStatusCancelled}).
+		Find(&tasks).Error; err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, task := range tasks {
+		var existing int64
+		db.Model(&models.Notification{}).
+			Where("user_id = ? AND task_id = ? AND type = ?", *task.AssigneeID, task.ID, models.NotificationTypeDueSoon).
+			Count(&existing)
+		if existing > 0 {
+			continue
+		}
+
+		sent += db.notifyUsers([]uint{*task.AssigneeID}, nil, models.Notification{
+			Type:      models.NotificationTypeDueSoon,
+			TaskID:    &task.ID,
+			ProjectID: &task.ProjectID,
+			ActorName: "System",
+			Title:     fmt.Sprintf("Task #%d is due soon", task.ID),
+			Message:   fmt.Sprintf("%s is due %s", task.Title, task.DueDate.Format("Jan 2 15:04")),
+		})
+	}
+
+	return sent, nil
+}
+
+// NotifyTaskChanges notifies users about an updated task: the new assignee,
+// and the watchers when the status changed
+func (db *Database) NotifyTaskChanges(oldTask, task *models.Task, actorName string) {
+	if task.AssigneeID != nil && (oldTask.AssigneeID == nil || *oldTask.AssigneeID != *task.AssigneeID) {
+		db.NotifyAssigned(task, task.UpdatedBy, actorName)
+	}
+
+	if task.Status != "" && oldTask.Status != task.Status {
+		watcherIDs, err := db.GetTaskWatcherIDs(task.ID)
+		if err != nil {
+			return
+		}
+		db.notifyUsers(watcherIDs, task.UpdatedBy, models.Notification{
+			Type:      models.NotificationTypeStatusChanged,
+			TaskID:    &task.ID,
+			ProjectID: &task.ProjectID,
+			ActorName: actorName,
+			Title:     fmt.Sprintf("Task #%d moved to %s", task.ID, task.Status),
+			Message:   fmt.Sprintf("%s changed the status of %s from %s to %s", actorName, task.Title, oldTask.Status, task.Status),
+		})
+	}
+}
+
+// NotifyAssigned notifies the assignee of a task, unless they assigned it to themselves
+func (db *Database) NotifyAssigned(task *models.Task, actorID *uint, actorName string) {
+	if task.AssigneeID == nil || *task.AssigneeID == 0 {
+		return
+	}
+	db.notifyUsers([]uint{*task.AssigneeID}, actorID, models.Notification{
+		Type:      models.NotificationTypeAssigned,
+		TaskID:    &task.ID,
+		ProjectID: &task.ProjectID,
+		ActorName: actorName,
+		Title:     fmt.Sprintf("You were assigned task #%d", task.ID),
+		Message:   fmt.Sprintf("%s assigned you %s", actorName, task.Title),
+	})
+}
+
+// NotifyDependencyUnblocked tells the assignee and watchers of a task that its last dependency is done
+func (db *Database) NotifyDependencyUnblocked(taskID uint, completed *models.Task, actorName string) {
+	var task models.Task
+	if err := db.First(&task, taskID).Error; err != nil {
+		return
+	}
+
+	userIDs, err := db.GetTaskWatcherIDs(task.ID)
+	if err != nil {
+		return
+	}
+	if task.AssigneeID != nil {
+		userIDs = append(userIDs, *task.AssigneeID)
+	}
+
+	db.notifyUsers(userIDs, nil, models.Notification{
+		Type:      models.NotificationTypeDependencyUnblocked,
+		TaskID:    &task.ID,
+		ProjectID: &task.ProjectID,
+		ActorName: actorName,
+		Title:     fmt.Sprintf("Task #%d is unblocked", task.ID),
+		Message:   fmt.Sprintf("%s can start now that #%d %s is done", task.Title, completed.ID, completed.Title),
+	})
+}
+
+// NotifyMentioned notifies users mentioned for the first time in the description
+// of a task (commentID nil) or in one of its comments
+func (db *Database) NotifyMentioned(userIDs []uint, taskID, projectID uint, taskTitle string, commentID *uint, actorID *uint, actorName string) {
+	where := "the description of"
+	if commentID != nil {
+		where = "a comment on"
+	}
+	db.notifyUsers(userIDs, actorID, models.Notification{
+		Type:      models.NotificationTypeMentioned,
+		TaskID:    &taskID,
+		ProjectID: &projectID,
+		ActorName: actorName,
+		Title:     fmt.Sprintf("%s mentioned you on task #%d", actorName, taskID),
+		Message:   fmt.Sprintf("%s mentioned you in %s %s", actorName, where, taskTitle),
+	})
+}
+
+// notifyUsers sends a copy of a notification to each user, skipping the actor
+// who caused it and users who turned the notification type off. It returns the
+// number of notifications created.
+func (db *Database) notifyUsers(userIDs []uint, actorID *uint, template models.Notification) int {
+	seen := make(map[uint]bool)
+	sent := 0
+
+	for _, userID := range userIDs {
+		if userID == 0 || seen[userID] || (actorID != nil && *actorID == userID) {
+			continue
+		}
+		seen[userID] = true
+
+		var pref models.NotificationPreference
+		if err := db.Where("user_id = ? AND type = ?", userID, template.Type).First(&pref).Error; err == nil && !pref.Enabled {
+			continue
+		}
+
+		notification := template
+		notification.ID = 0
+		notification.UserID = userID
+		if err := db.Create(&notification).Error; err != nil {
+			log.Printf("Warning: Failed to create notification for user %d: %v", userID, err)
+			continue
+		}
+		db.publish(events.NotificationSent{Notification: notification})
+		sent++
+	}
+
+	return sent
+}
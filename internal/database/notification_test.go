@@ -0,0 +1,69 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/headless-pm/headless-project-management/internal/models"
+)
+
+func TestNotificationInbox(t *testing.T) {
+	db := newTestDatabase(t)
+	alice := createUser(t, db, "alice")
+	bob := createUser(t, db, "bob")
+	project := createProject(t, db, "Inbox")
+	task := createTask(t, db, project.ID, "Ship it")
+
+	t.Run("assignees are notified unless they assigned themselves", func(t *testing.T) {
+		task.AssigneeID = &bob.ID
+		db.NotifyAssigned(task, &alice.ID, "alice")
+		db.NotifyAssigned(task, &bob.ID, "bob")
+
+		notifications, _ := db.ListNotifications(bob.ID, false, 0)
+		if len(notifications) != 1 || notifications[0].Type != models.NotificationTypeAssigned {
+			t.Fatalf("bob has %v, want one assignment", notifications)
+		}
+	})
+
+	t.Run("disabled types are not delivered", func(t *testing.T) {
+		if err := db.UpdateNotificationPreferences(bob.ID, map[models.NotificationType]bool{models.NotificationTypeAssigned: false}); err != nil {
+			t.Fatal(err)
+		}
+		db.NotifyAssigned(task, &alice.ID, "alice")
+		if unread, _ := db.CountUnreadNotifications(bob.ID); unread != 1 {
+			t.Errorf("bob has %d unread notifications, want still 1", unread)
+		}
+		if err := db.UpdateNotificationPreferences(bob.ID, map[models.NotificationType]bool{"carrier_pigeon": true}); err == nil {
+			t.Error("accepted an unknown notification type")
+		}
+	})
+
+	t.Run("inboxes only change for their owner", func(t *testing.T) {
+		notifications, _ := db.ListNotifications(bob.ID, false, 0)
+		id := notifications[0].ID
+
+		if changed, _ := db.MarkNotificationsRead(alice.ID, []uint{id}); changed != 0 {
+			t.Errorf("alice marked %d of bob's notifications read", changed)
+		}
+		if changed, _ := db.MarkNotificationsRead(bob.ID, nil); changed != 1 {
+			t.Errorf("marking all read changed %d notifications, want 1", changed)
+		}
+		if err := db.MarkNotificationUnread(alice.ID, id); err == nil {
+			t.Error("alice marked bob's notification unread")
+		}
+		if unread, _ := db.ListNotifications(bob.ID, true, 0); len(unread) != 0 {
+			t.Errorf("bob has %d unread notifications, want 0", len(unread))
+		}
+	})
+
+	t.Run("due dates are announced once", func(t *testing.T) {
+		due := time.Now().Add(time.Hour)
+		db.Model(task).Updates(map[string]interface{}{"assignee_id": alice.ID, "due_date": due})
+
+		for i, want := range []int{1, 0} {
+			if sent, err := db.NotifyDueSoonTasks(24 * time.Hour); err != nil || sent != want {
+				t.Errorf("run %d sent %d due-soon notifications (%v), want %d", i+1, sent, err, want)
+			}
+		}
+	})
+}
@@ -0,0 +1,461 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/headless-pm/headless-project-management/internal/events"
+	"github.com/headless-pm/headless-project-management/internal/models"
+	"gorm.io/gorm"
+)
+
+// Plan is a project plan to create in one go: epics with their tasks, tasks
+// outside epics, subtasks, labels and dependencies. Items are named by local
+// refs that dependencies point to and that ImportPlan maps to the created IDs.
+type Plan struct {
+	ProjectID uint       `json:"project_id"`
+	Epics     []PlanEpic `json:"epics,omitempty"`
+	Tasks     []PlanTask `json:"tasks,omitempty"`
+}
+
+type PlanEpic struct {
+	Ref         string     `json:"ref,omitempty"`
+	Name        string     `json:"name"`
+	Description string     `json:"description,omitempty"`
+	Status      string     `json:"status,omitempty"`
+	Tasks       []PlanTask `json:"tasks,omitempty"`
+}
+
+type PlanTask struct {
+	Ref            string     `json:"ref,omitempty"`
+	Title          string     `json:"title"`
+	Description    string     `json:"description,omitempty"`
+	Status         string     `json:"status,omitempty"`
+	Priority       string     `json:"priority,omitempty"`
+	AssigneeID     uint       `json:"assignee_id,omitempty"`
+	StoryPoints    *int       `json:"story_points,omitempty"`
+	EstimatedHours *float64   `json:"estimated_hours,omitempty"`
+	DueDate        string     `json:"due_date,omitempty"` // YYYY-MM-DD
+	Labels         []string   `json:"labels,omitempty"`
+	DependsOn      []string   `json:"depends_on,omitempty"` // Refs of tasks that must finish first
+	Subtasks       []PlanTask `json:"subtasks,omitempty"`
+}
+
+// PlanCounts counts what a plan creates
+type PlanCounts struct {
+	Epics        int `json:"epics"`
+	Tasks        int `json:"tasks"`
+	Dependencies int `json:"dependencies"`
+	Labels       int `json:"labels"` // Labels the project does not have yet
+}
+
+// PlanResult reports an imported or validated plan. Refs maps the local refs
+// to the IDs of the created epics and tasks; it is empty for dry runs.
+type PlanResult struct {
+	DryRun    bool            `json:"dry_run"`
+	ProjectID uint            `json:"project_id"`
+	Refs      map[string]uint `json:"refs"`
+	Created   PlanCounts      `json:"created"`
+}
+
+// PlanIssue is one problem of a plan; Path locates it, e.g. "epics[0].tasks[1].depends_on[0]"
+type PlanIssue struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+// PlanError reports the problems that keep a plan from being imported
+type PlanError struct {
+	Issues []PlanIssue `json:"issues"`
+}
+
+func (e *PlanError) Error() string {
+	messages := make([]string, len(e.Issues))
+	for i, issue := range e.Issues {
+		messages[i] = issue.Path + ": " + issue.Message
+	}
+	return "invalid plan: " + strings.Join(messages, "; ")
+}
+
+// plannedTask is a task of a plan flattened with its position in the document
+type plannedTask struct {
+	path   string
+	task   *PlanTask
+	epic   int // Index into Plan.Epics, or -1
+	parent int // Index into the flattened tasks, or -1
+	dueAt  *time.Time
+}
+
+// planGraph is a validated plan ready to be created
+type planGraph struct {
+	plan      *Plan
+	tasks     []plannedTask
+	taskRefs  map[string]int // Ref to index into tasks
+	newLabels map[string]bool
+}
+
+// ImportPlan validates a plan and, unless dryRun is set, creates it in a
+// single transaction: either all of it is created or nothing is. Invalid
+// plans return a *PlanError listing every problem.
+func (db *Database) ImportPlan(plan *Plan, dryRun bool) (*PlanResult, error) {
+	graph, err := db.validatePlan(plan)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &PlanResult{
+		DryRun:    dryRun,
+		ProjectID: plan.ProjectID,
+		Refs:      make(map[string]uint),
+		Created: PlanCounts{
+			Epics:  len(plan.Epics),
+			Tasks:  len(graph.tasks),
+			Labels: len(graph.newLabels),
+		},
+	}
+	for _, planned := range graph.tasks {
+		result.Created.Dependencies += len(planned.task.DependsOn)
+	}
+	if dryRun {
+		return result, nil
+	}
+
+	epics := make([]*models.Epic, len(plan.Epics))
+	tasks := make([]*models.Task, len(graph.tasks))
+	var dependencies []models.TaskDependency
+
+	// Every epic, task and dependency created is a step; cancelling the
+	// context rolls the whole plan back
+	steps := result.Created.Epics + result.Created.Tasks + result.Created.Dependencies
+	done := 0
+	err = db.Transaction(func(tx *gorm.DB) error {
+		for i, planEpic := range plan.Epics {
+			epic := &models.Epic{
+				ProjectID:   plan.ProjectID,
+				Name:        planEpic.Name,
+				Description: planEpic.Description,
+				Status:      models.EpicStatus(planEpic.Status),
+			}
+			if err := tx.Create(epic).Error; err != nil {
+				return fmt.Errorf("creating epic %q: %w", planEpic.Name, err)
+			}
+			epics[i] = epic
+			done++
+			if err := db.step(done, steps, "Created epic "+epic.Name); err != nil {
+				return err
+			}
+		}
+
+		labels := make(map[string]*models.Label)
+		// Parents come before their subtasks in graph.tasks
+		for i, planned := range graph.tasks {
+			task := &models.Task{
+				ProjectID:      plan.ProjectID,
+				Title:          planned.task.Title,
+				Description:    planned.task.Description,
+				Status:         models.TaskStatus(planned.task.Status),
+				Priority:       models.TaskPriority(planned.task.Priority),
+				StoryPoints:    planned.task.StoryPoints,
+				EstimatedHours: planned.task.EstimatedHours,
+				DueDate:        planned.dueAt,
+			}
+			if principal := db.principal(); principal != nil {
+				task.CreatedBy = *principal.UserID
+			}
+			if task.Status == "" {
+				task.Status = models.TaskStatusTodo
+			}
+			if task.Priority == "" {
+				task.Priority = models.TaskPriorityMedium
+			}
+			if planned.task.AssigneeID > 0 {
+				assigneeID := planned.task.AssigneeID
+				task.AssigneeID = &assigneeID
+			}
+			if planned.epic >= 0 {
+				task.EpicID = &epics[planned.epic].ID
+			}
+			if planned.parent >= 0 {
+				task.ParentID = &tasks[planned.parent].ID
+				task.EpicID = tasks[planned.parent].EpicID
+			}
+			if task.Status == models.TaskStatusDone {
+				now := time.Now()
+				task.CompletedAt = &now
+			}
+			if err := tx.Create(task).Error; err != nil {
+				return fmt.Errorf("creating task %q: %w", task.Title, err)
+			}
+			tasks[i] = task
+
+			labelled := make(map[string]bool)
+			for _, name := range planned.task.Labels {
+				if labelled[name] {
+					continue
+				}
+				labelled[name] = true
+				label, ok := labels[name]
+				if !ok {
+					label = &models.Label{}
+					err := tx.Where("project_id = ? AND name = ?", plan.ProjectID, name).First(label).Error
+					if err == gorm.ErrRecordNotFound {
+						label = &models.Label{ProjectID: plan.ProjectID, Name: name, Color: labelColor(name)}
+						err = tx.Create(label).Error
+					}
+					if err != nil {
+						return fmt.Errorf("creating label %q: %w", name, err)
+					}
+					labels[name] = label
+				}
+				if err := tx.Model(task).Association("Labels").Append(label); err != nil {
+					return fmt.Errorf("labelling task %q: %w", task.Title, err)
+				}
+			}
+			done++
+			if err := db.step(done, steps, "Created task "+task.Title); err != nil {
+				return err
+			}
+		}
+
+		for i, planned := range graph.tasks {
+			for _, ref := range planned.task.DependsOn {
+				dependency := models.TaskDependency{
+					TaskID:      tasks[i].ID,
+					DependsOnID: tasks[graph.taskRefs[ref]].ID,
+					Type:        "finish_to_start",
+				}
+				if err := tx.Create(&dependency).Error; err != nil {
+					return fmt.Errorf("creating dependency on %q: %w", ref, err)
+				}
+				dependencies = append(dependencies, dependency)
+				done++
+				if err := db.step(done, steps, "Added dependency on "+ref); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i, planEpic := range plan.Epics {
+		if planEpic.Ref != "" {
+			result.Refs[planEpic.Ref] = epics[i].ID
+		}
+		db.publish(events.EpicCreated{Epic: epics[i]})
+	}
+	actor := db.actor()
+	for i, planned := range graph.tasks {
+		task := tasks[i]
+		if planned.task.Ref != "" {
+			result.Refs[planned.task.Ref] = task.ID
+		}
+		_ = db.LogTaskCreated(task.ID, actor.ID, actor.Name)
+		db.autoWatch(task.ID, actor.ID)
+		db.autoWatch(task.ID, task.AssigneeID)
+		db.publish(events.TaskCreated{Task: task, Actor: actor})
+		db.syncMentions(task.ID, nil, task.Description, actor)
+	}
+	for _, dependency := range dependencies {
+		db.publish(events.DependencyAdded{Dependency: dependency, ProjectID: plan.ProjectID})
+	}
+
+	return result, nil
+}
+
+// validatePlan checks a plan against the database and flattens its tasks
+func (db *Database) validatePlan(plan *Plan) (*planGraph, error) {
+	var issues []PlanIssue
+	fail := func(path, format string, args ...interface{}) {
+		issues = append(issues, PlanIssue{Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if plan.ProjectID == 0 {
+		fail("project_id", "is required")
+	} else if err := db.Select("id").First(&models.Project{}, plan.ProjectID).Error; err != nil {
+		fail("project_id", "project %d not found", plan.ProjectID)
+	}
+	if len(plan.Epics) == 0 && len(plan.Tasks) == 0 {
+		fail("", "the plan has no epics or tasks")
+	}
+
+	graph := &planGraph{plan: plan, taskRefs: make(map[string]int), newLabels: make(map[string]bool)}
+	refPaths := make(map[string]string)
+	claimRef := func(ref, path string) {
+		if ref == "" {
+			return
+		}
+		if first, taken := refPaths[ref]; taken {
+			fail(path+".ref", "ref %q is already used by %s", ref, first)
+			return
+		}
+		refPaths[ref] = path
+	}
+
+	var addTasks func(tasks []PlanTask, path string, epic, parent int)
+	addTasks = func(tasks []PlanTask, path string, epic, parent int) {
+		for i := range tasks {
+			task := &tasks[i]
+			taskPath := fmt.Sprintf("%s[%d]", path, i)
+			claimRef(task.Ref, taskPath)
+			if task.Ref != "" && refPaths[task.Ref] == taskPath {
+				graph.taskRefs[task.Ref] = len(graph.tasks)
+			}
+
+			planned := plannedTask{path: taskPath, task: task, epic: epic, parent: parent}
+			if strings.TrimSpace(task.Title) == "" {
+				fail(taskPath+".title", "is required")
+			}
+			if task.Status != "" && !models.IsValidTaskStatus(task.Status) {
+				fail(taskPath+".status", "must be one of: %s", strings.Join(models.GetValidTaskStatuses(), ", "))
+			}
+			if task.Priority != "" && !models.IsValidTaskPriority(task.Priority) {
+				fail(taskPath+".priority", "must be one of: %s", strings.Join(models.GetValidTaskPriorities(), ", "))
+			}
+			if task.DueDate != "" {
+				dueAt, err := time.Parse("2006-01-02", task.DueDate)
+				if err != nil {
+					fail(taskPath+".due_date", "must be a date in the form YYYY-MM-DD")
+				} else {
+					planned.dueAt = &dueAt
+				}
+			}
+			for j, name := range task.Labels {
+				if strings.TrimSpace(name) == "" {
+					fail(fmt.Sprintf("%s.labels[%d]", taskPath, j), "must not be empty")
+				}
+			}
+
+			index := len(graph.tasks)
+			graph.tasks = append(graph.tasks, planned)
+			addTasks(task.Subtasks, taskPath+".subtasks", -1, index)
+		}
+	}
+
+	for i := range plan.Epics {
+		epic := &plan.Epics[i]
+		epicPath := fmt.Sprintf("epics[%d]", i)
+		claimRef(epic.Ref, epicPath)
+		if strings.TrimSpace(epic.Name) == "" {
+			fail(epicPath+".name", "is required")
+		}
+		switch models.EpicStatus(epic.Status) {
+		case "", models.EpicStatusPlanned, models.EpicStatusActive, models.EpicStatusCompleted, models.EpicStatusCancelled:
+		default:
+			fail(epicPath+".status", "must be one of: planned, active, completed, cancelled")
+		}
+		addTasks(epic.Tasks, epicPath+".tasks", i, -1)
+	}
+	addTasks(plan.Tasks, "tasks", -1, -1)
+
+	// Dependencies point to task refs
+	assignees := make(map[uint]string)
+	for _, planned := range graph.tasks {
+		seen := make(map[string]bool)
+		for j, ref := range planned.task.DependsOn {
+			path := fmt.Sprintf("%s.depends_on[%d]", planned.path, j)
+			target, ok := graph.taskRefs[ref]
+			switch {
+			case !ok && refPaths[ref] != "":
+				fail(path, "%q is an epic; tasks can only depend on tasks", ref)
+			case !ok:
+				fail(path, "unknown ref %q", ref)
+			case graph.tasks[target].task == planned.task:
+				fail(path, "a task cannot depend on itself")
+			case seen[ref]:
+				fail(path, "duplicate dependency on %q", ref)
+			}
+			seen[ref] = true
+		}
+		if planned.task.AssigneeID > 0 {
+			if _, listed := assignees[planned.task.AssigneeID]; !listed {
+				assignees[planned.task.AssigneeID] = planned.path + ".assignee_id"
+			}
+		}
+	}
+	for id, path := range assignees {
+		if err := db.Select("id").First(&models.User{}, id).Error; err != nil {
+			fail(path, "user %d not found", id)
+		}
+	}
+	for _, cycle := range graph.dependencyCycles() {
+		fail(graph.tasks[cycle[0]].path+".depends_on", "dependency cycle: %s", graph.describeCycle(cycle))
+	}
+
+	if len(issues) > 0 {
+		return nil, &PlanError{Issues: issues}
+	}
+
+	// Labels the project does not have yet are created with the plan
+	var existing []string
+	db.Model(&models.Label{}).Where("project_id = ?", plan.ProjectID).Pluck("name", &existing)
+	known := make(map[string]bool, len(existing))
+	for _, name := range existing {
+		known[name] = true
+	}
+	for _, planned := range graph.tasks {
+		for _, name := range planned.task.Labels {
+			if !known[name] {
+				graph.newLabels[name] = true
+			}
+		}
+	}
+	return graph, nil
+}
+
+// dependencyCycles finds the cycles among the plan's dependencies, each as
+// the task indexes along it
+func (g *planGraph) dependencyCycles() [][]int {
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+	state := make([]int, len(g.tasks))
+	var stack []int
+	var cycles [][]int
+
+	var visit func(int)
+	visit = func(i int) {
+		state[i] = visiting
+		stack = append(stack, i)
+		for _, ref := range g.tasks[i].task.DependsOn {
+			next, ok := g.taskRefs[ref]
+			if !ok || next == i {
+				continue
+			}
+			switch state[next] {
+			case unvisited:
+				visit(next)
+			case visiting:
+				for start := len(stack) - 1; start >= 0; start-- {
+					if stack[start] == next {
+						cycles = append(cycles, append([]int(nil), stack[start:]...))
+						break
+					}
+				}
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[i] = done
+	}
+
+	for i := range g.tasks {
+		if state[i] == unvisited {
+			visit(i)
+		}
+	}
+	return cycles
+}
+
+func (g *planGraph) describeCycle(cycle []int) string {
+	names := make([]string, 0, len(cycle)+1)
+	for _, i := range cycle {
+		names = append(names, g.tasks[i].task.Ref)
+	}
+	names = append(names, g.tasks[cycle[0]].task.Ref)
+	return strings.Join(names, " -> ")
+}
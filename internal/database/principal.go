@@ -0,0 +1,75 @@
+package database
+
+import (
+	"context"
+
+	"github.com/headless-pm/headless-project-management/internal/events"
+	"github.com/headless-pm/headless-project-management/internal/models"
+)
+
+// Principal is the authenticated user behind a request. Writes made through a
+// database bound to a context carrying a principal are attributed to it.
+type Principal struct {
+	UserID *uint
+	Name   string
+	Type   models.UserType
+	// Client names the MCP client or model acting for the user, if known
+	Client string
+}
+
+type principalContextKey struct{}
+
+// WithPrincipal returns a context whose writes are attributed to principal
+func WithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalContextKey{}, principal)
+}
+
+// PrincipalFromContext returns the principal of a context, or nil
+func PrincipalFromContext(ctx context.Context) *Principal {
+	principal, _ := ctx.Value(principalContextKey{}).(*Principal)
+	return principal
+}
+
+// PrincipalForUser describes a user acting through client. Agents without a
+// client of the session fall back to the client they were registered with.
+func PrincipalForUser(user *models.User, client string) *Principal {
+	if client == "" {
+		client = user.Client
+	}
+	id := user.ID
+	return &Principal{UserID: &id, Name: user.Username, Type: user.Type, Client: client}
+}
+
+// WithContext returns a database whose queries run with ctx, so they stop when
+// it is cancelled, and whose writes are attributed to the principal of ctx
+func (db *Database) WithContext(ctx context.Context) *Database {
+	return &Database{DB: db.DB.WithContext(ctx), events: db.events}
+}
+
+// principal returns the principal of the context the database is bound to, if
+// it is a registered user
+func (db *Database) principal() *Principal {
+	if db.Statement == nil || db.Statement.Context == nil {
+		return nil
+	}
+	principal := PrincipalFromContext(db.Statement.Context)
+	if principal == nil || principal.UserID == nil {
+		return nil
+	}
+	return principal
+}
+
+// actor is who changes made through the database are published as
+func (db *Database) actor() events.Actor {
+	if principal := db.principal(); principal != nil {
+		return events.Actor{ID: principal.UserID, Name: principal.Name}
+	}
+	return events.Actor{Name: "System"}
+}
+
+// RecordAgentClient sets the client of an agent that has none yet
+func (db *Database) RecordAgentClient(userID uint, client string) error {
+	return db.Model(&models.User{}).
+		Where("id = ? AND type = ? AND (client IS NULL OR client = '')", userID, models.UserTypeAgent).
+		Update("client", client).Error
+}
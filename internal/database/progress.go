@@ -0,0 +1,28 @@
+package database
+
+import "context"
+
+// ProgressFunc is told how far a long-running operation got: done of total
+// steps, the last one described by message
+type ProgressFunc func(done, total int, message string)
+
+type progressContextKey struct{}
+
+// WithProgress returns a context whose long-running operations report their
+// progress to progress
+func WithProgress(ctx context.Context, progress ProgressFunc) context.Context {
+	return context.WithValue(ctx, progressContextKey{}, progress)
+}
+
+// step reports that done of total steps of an operation finished and returns
+// the error of the bound context once it is cancelled, so the operation stops
+func (db *Database) step(done, total int, message string) error {
+	if db.Statement == nil || db.Statement.Context == nil {
+		return nil
+	}
+	ctx := db.Statement.Context
+	if progress, ok := ctx.Value(progressContextKey{}).(ProgressFunc); ok {
+		progress(done, total, message)
+	}
+	return ctx.Err()
+}
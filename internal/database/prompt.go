@@ -0,0 +1,93 @@
+package database
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/headless-pm/headless-project-management/internal/events"
+	"github.com/headless-pm/headless-project-management/internal/models"
+)
+
+// promptNamePattern keeps prompt names usable as MCP identifiers
+var promptNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// Prompt template CRUD methods
+func (db *Database) CreatePromptTemplate(template *models.PromptTemplate) error {
+	if err := validatePromptTemplate(template); err != nil {
+		return err
+	}
+	if principal := db.principal(); principal != nil && template.CreatedBy == nil {
+		template.CreatedBy = principal.UserID
+	}
+	if err := db.Create(template).Error; err != nil {
+		return err
+	}
+	db.publish(events.PromptTemplatesChanged{})
+	return nil
+}
+
+func (db *Database) GetPromptTemplate(id uint) (*models.PromptTemplate, error) {
+	var template models.PromptTemplate
+	err := db.First(&template, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
+
+func (db *Database) GetPromptTemplateByName(name string) (*models.PromptTemplate, error) {
+	var template models.PromptTemplate
+	err := db.Where("name = ?", name).First(&template).Error
+	if err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
+
+func (db *Database) ListPromptTemplates() ([]models.PromptTemplate, error) {
+	var templates []models.PromptTemplate
+	err := db.Order("name ASC").Find(&templates).Error
+	return templates, err
+}
+
+func (db *Database) UpdatePromptTemplate(template *models.PromptTemplate) error {
+	if err := validatePromptTemplate(template); err != nil {
+		return err
+	}
+	if err := db.Save(template).Error; err != nil {
+		return err
+	}
+	db.publish(events.PromptTemplatesChanged{})
+	return nil
+}
+
+func (db *Database) DeletePromptTemplate(id uint) error {
+	if err := db.Delete(&models.PromptTemplate{}, id).Error; err != nil {
+		return err
+	}
+	db.publish(events.PromptTemplatesChanged{})
+	return nil
+}
+
+func validatePromptTemplate(template *models.PromptTemplate) error {
+	template.Name = strings.TrimSpace(template.Name)
+	if !promptNamePattern.MatchString(template.Name) {
+		return fmt.Errorf("invalid prompt name %q: use lowercase letters, digits, '_' and '-'", template.Name)
+	}
+	if strings.TrimSpace(template.Template) == "" {
+		return fmt.Errorf("prompt template is required")
+	}
+
+	seen := make(map[string]bool)
+	for _, argument := range template.Arguments {
+		if argument.Name == "" {
+			return fmt.Errorf("prompt arguments need a name")
+		}
+		if seen[argument.Name] {
+			return fmt.Errorf("duplicate prompt argument: %s", argument.Name)
+		}
+		seen[argument.Name] = true
+	}
+	return nil
+}
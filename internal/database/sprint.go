@@ -0,0 +1,369 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/headless-pm/headless-project-management/internal/events"
+	"github.com/headless-pm/headless-project-management/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	// ErrInvalidSprint is wrapped by the errors of sprints that cannot be saved
+	ErrInvalidSprint = errors.New("invalid sprint")
+	// ErrProjectNotFound is returned for sprints and milestones of a project that does not exist
+	ErrProjectNotFound = errors.New("project not found")
+)
+
+// Sprint CRUD methods
+
+// CreateSprint adds a planned sprint. Sprints become active and completed only
+// through StartSprint and CompleteSprint.
+func (db *Database) CreateSprint(sprint *models.Sprint) error {
+	if sprint.Status != "" && sprint.Status != models.SprintStatusPlanned {
+		return fmt.Errorf("%w: new sprints must be planned (got %s)", ErrInvalidSprint, sprint.Status)
+	}
+	if err := checkSprintDates(sprint); err != nil {
+		return err
+	}
+	if err := db.requireProject(sprint.ProjectID); err != nil {
+		return err
+	}
+	sprint.CompletedAt = nil
+	return db.Create(sprint).Error
+}
+
+func checkSprintDates(sprint *models.Sprint) error {
+	if sprint.StartDate != nil && sprint.EndDate != nil && sprint.EndDate.Before(*sprint.StartDate) {
+		return fmt.Errorf("%w: end date %s is before start date %s", ErrInvalidSprint,
+			sprint.EndDate.Format("2006-01-02"), sprint.StartDate.Format("2006-01-02"))
+	}
+	return nil
+}
+
+// requireProject returns ErrProjectNotFound unless the project exists
+func (db *Database) requireProject(projectID uint) error {
+	var count int64
+	if err := db.Model(&models.Project{}).Where("id = ?", projectID).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return fmt.Errorf("%w: %d", ErrProjectNotFound, projectID)
+	}
+	return nil
+}
+
+func (db *Database) GetSprint(id uint) (*models.Sprint, error) {
+	var sprint models.Sprint
+	err := db.Preload("Project").Preload("Tasks.AssigneeUser").Preload("Tasks.Labels").First(&sprint, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &sprint, nil
+}
+
+func (db *Database) ListSprints(projectID *uint, status *models.SprintStatus) ([]models.Sprint, error) {
+	var sprints []models.Sprint
+	query := db.DB
+
+	if projectID != nil {
+		query = query.Where("project_id = ?", *projectID)
+	}
+	if status != nil {
+		query = query.Where("status = ?", *status)
+	}
+
+	err := query.Preload("Tasks").Order("start_date IS NULL, start_date ASC, id ASC").Find(&sprints).Error
+	return sprints, err
+}
+
+// UpdateSprint saves the details of a sprint. Its project stays the same, and
+// its status and completion time are left alone: StartSprint and CompleteSprint
+// are the only ways to change them.
+func (db *Database) UpdateSprint(sprint *models.Sprint) error {
+	if err := checkSprintDates(sprint); err != nil {
+		return err
+	}
+	return db.Omit("project_id", "status", "completed_at", clause.Associations).Save(sprint).Error
+}
+
+// DeleteSprint deletes a sprint and moves its tasks back to the backlog
+func (db *Database) DeleteSprint(id uint) error {
+	tx := db.Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	if err := tx.Model(&models.Task{}).Where("sprint_id = ?", id).
+		Update("sprint_id", nil).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Delete(&models.Sprint{}, id).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// AddTaskToSprint moves a task into a sprint of the same project
+func (db *Database) AddTaskToSprint(taskID uint, sprintID uint) error {
+	var task models.Task
+	if err := db.First(&task, taskID).Error; err != nil {
+		return err
+	}
+
+	sprint, err := db.sprintForProject(task.ProjectID, sprintID)
+	if err != nil {
+		return fmt.Errorf("cannot add task %d to sprint %d: %w", taskID, sprintID, err)
+	}
+
+	oldSprint := sprintLabel(task.SprintID)
+	if err := db.Model(&task).Update("sprint_id", sprintID).Error; err != nil {
+		return err
+	}
+
+	actor := db.actor()
+	_ = db.LogActivity(taskID, actor.ID, actor.Name, "sprint_changed", "sprint",
+		oldSprint, sprintLabel(&sprintID),
+		fmt.Sprintf("Task added to sprint %s", sprint.Name))
+
+	return nil
+}
+
+// CheckTaskPlacement reports whether a task of a project can go into the sprint
+// and milestone, so that new tasks are checked before they are created. Zero
+// IDs are not checked.
+func (db *Database) CheckTaskPlacement(projectID, sprintID, milestoneID uint) error {
+	if sprintID > 0 {
+		if _, err := db.sprintForProject(projectID, sprintID); err != nil {
+			return fmt.Errorf("invalid sprint %d: %w", sprintID, err)
+		}
+	}
+	if milestoneID > 0 {
+		if _, err := db.milestoneForProject(projectID, milestoneID); err != nil {
+			return fmt.Errorf("invalid milestone %d: %w", milestoneID, err)
+		}
+	}
+	return nil
+}
+
+// sprintForProject returns a sprint that tasks of the project can be added to
+func (db *Database) sprintForProject(projectID, sprintID uint) (*models.Sprint, error) {
+	var sprint models.Sprint
+	if err := db.First(&sprint, sprintID).Error; err != nil {
+		return nil, err
+	}
+	if sprint.ProjectID != projectID {
+		return nil, fmt.Errorf("the sprint belongs to another project")
+	}
+	if sprint.Status == models.SprintStatusCompleted {
+		return nil, fmt.Errorf("the sprint is completed")
+	}
+	return &sprint, nil
+}
+
+// RemoveTaskFromSprint moves a task back to the backlog
+func (db *Database) RemoveTaskFromSprint(taskID uint) error {
+	var task models.Task
+	if err := db.First(&task, taskID).Error; err != nil {
+		return err
+	}
+
+	oldSprintID := task.SprintID
+	if err := db.Model(&task).Update("sprint_id", nil).Error; err != nil {
+		return err
+	}
+
+	if oldSprintID != nil {
+		actor := db.actor()
+		_ = db.LogActivity(taskID, actor.ID, actor.Name, "sprint_changed", "sprint",
+			sprintLabel(oldSprintID), "", "Task moved back to the backlog")
+	}
+
+	return nil
+}
+
+// StartSprint activates a planned sprint. Unfinished tasks left behind in the
+// most recently completed sprint of the project are carried over into it.
+// It returns the number of tasks carried over.
+func (db *Database) StartSprint(id uint) (int, error) {
+	var sprint models.Sprint
+	if err := db.First(&sprint, id).Error; err != nil {
+		return 0, err
+	}
+
+	if sprint.Status != models.SprintStatusPlanned {
+		return 0, fmt.Errorf("only planned sprints can be started (sprint is %s)", sprint.Status)
+	}
+
+	carried := 0
+	err := db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		updates := map[string]interface{}{"status": models.SprintStatusActive}
+		if sprint.StartDate == nil {
+			updates["start_date"] = now
+		}
+
+		// Only one sprint can be active per project. The check is part of the
+		// update so that concurrent starts cannot both pass it.
+		result := tx.Model(&sprint).
+			Where("status = ?", models.SprintStatusPlanned).
+			Where("NOT EXISTS (SELECT 1 FROM sprints active WHERE active.project_id = ? AND active.status = ? AND active.id <> ?)",
+				sprint.ProjectID, models.SprintStatusActive, sprint.ID).
+			Updates(updates)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("project already has an active sprint")
+		}
+
+		var previous models.Sprint
+		err := tx.Where("project_id = ? AND status = ?", sprint.ProjectID, models.SprintStatusCompleted).
+			Order("completed_at DESC").First(&previous).Error
+		if err == gorm.ErrRecordNotFound {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		n, err := carryOverSprintTasks(tx, previous.ID, sprint.ID, db.actor())
+		carried = n
+		return err
+	})
+	if err != nil {
+		return carried, err
+	}
+
+	sprint.Status = models.SprintStatusActive
+	db.publish(events.SprintStarted{Sprint: &sprint, CarriedTasks: carried})
+
+	return carried, nil
+}
+
+// CompleteSprint closes an active sprint and carries its unfinished tasks over
+// to the next sprint. When nextSprintID is nil the next planned sprint of the
+// project is used; if there is none, the tasks stay behind until a sprint is
+// started. It returns the number of tasks carried over.
+func (db *Database) CompleteSprint(id uint, nextSprintID *uint) (int, error) {
+	var sprint models.Sprint
+	if err := db.First(&sprint, id).Error; err != nil {
+		return 0, err
+	}
+
+	if sprint.Status != models.SprintStatusActive {
+		return 0, fmt.Errorf("only active sprints can be completed (sprint is %s)", sprint.Status)
+	}
+
+	var next *models.Sprint
+	if nextSprintID != nil {
+		var target models.Sprint
+		if err := db.First(&target, *nextSprintID).Error; err != nil {
+			return 0, fmt.Errorf("next sprint not found: %w", err)
+		}
+		if target.ProjectID != sprint.ProjectID || target.ID == sprint.ID {
+			return 0, fmt.Errorf("next sprint must be another sprint of the same project")
+		}
+		if target.Status == models.SprintStatusCompleted {
+			return 0, fmt.Errorf("cannot carry tasks over to a completed sprint")
+		}
+		next = &target
+	} else {
+		var target models.Sprint
+		err := db.Where("project_id = ? AND status = ? AND id <> ?", sprint.ProjectID, models.SprintStatusPlanned, sprint.ID).
+			Order("start_date IS NULL, start_date ASC, id ASC").First(&target).Error
+		if err == nil {
+			next = &target
+		} else if err != gorm.ErrRecordNotFound {
+			return 0, err
+		}
+	}
+
+	carried := 0
+	err := db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		if err := tx.Model(&sprint).Updates(map[string]interface{}{
+			"status":       models.SprintStatusCompleted,
+			"completed_at": now,
+		}).Error; err != nil {
+			return err
+		}
+
+		if next == nil {
+			return nil
+		}
+
+		n, err := carryOverSprintTasks(tx, sprint.ID, next.ID, db.actor())
+		carried = n
+		return err
+	})
+	if err != nil {
+		return carried, err
+	}
+
+	sprint.Status = models.SprintStatusCompleted
+	completed := events.SprintCompleted{Sprint: &sprint, CarriedTasks: carried}
+	if next != nil {
+		completed.NextSprintID = &next.ID
+	}
+	db.publish(completed)
+
+	return carried, nil
+}
+
+// GetActiveSprint returns the active sprint of a project, if any
+func (db *Database) GetActiveSprint(projectID uint) (*models.Sprint, error) {
+	var sprint models.Sprint
+	err := db.Where("project_id = ? AND status = ?", projectID, models.SprintStatusActive).First(&sprint).Error
+	if err != nil {
+		return nil, err
+	}
+	return &sprint, nil
+}
+
+// carryOverSprintTasks moves every unfinished task from one sprint to another,
+// crediting the moves to actor
+func carryOverSprintTasks(tx *gorm.DB, fromSprintID, toSprintID uint, actor events.Actor) (int, error) {
+	var tasks []models.Task
+	if err := tx.Where("sprint_id = ? AND status NOT IN ?", fromSprintID,
+		[]models.TaskStatus{models.TaskStatusDone, models.TaskStatusCancelled}).
+		Find(&tasks).Error; err != nil {
+		return 0, err
+	}
+
+	for _, task := range tasks {
+		if err := tx.Model(&models.Task{}).Where("id = ?", task.ID).
+			Update("sprint_id", toSprintID).Error; err != nil {
+			return 0, err
+		}
+
+		activity := &models.Activity{
+			TaskID:      task.ID,
+			UserID:      actor.ID,
+			UserName:    actor.Name,
+			Action:      "sprint_changed",
+			FieldName:   "sprint",
+			OldValue:    sprintLabel(&fromSprintID),
+			NewValue:    sprintLabel(&toSprintID),
+			Description: fmt.Sprintf("Carried over from sprint #%d to sprint #%d", fromSprintID, toSprintID),
+		}
+		if err := tx.Create(activity).Error; err != nil {
+			return 0, err
+		}
+	}
+
+	return len(tasks), nil
+}
+
+func sprintLabel(sprintID *uint) string {
+	if sprintID == nil {
+		return ""
+	}
+	return fmt.Sprintf("Sprint #%d", *sprintID)
+}
@@ -0,0 +1,139 @@
+package database
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/headless-pm/headless-project-management/internal/models"
+)
+
+func TestSprintLifecycle(t *testing.T) {
+	db := newTestDatabase(t)
+	alice := createUser(t, db, "alice")
+	project := createProject(t, db, "Sprints")
+	other := createProject(t, db, "Other")
+
+	newSprint := func(t *testing.T, projectID uint, name string) *models.Sprint {
+		t.Helper()
+		sprint := &models.Sprint{ProjectID: projectID, Name: name}
+		if err := db.CreateSprint(sprint); err != nil {
+			t.Fatalf("failed to create sprint %s: %v", name, err)
+		}
+		return sprint
+	}
+	first := newSprint(t, project.ID, "First")
+	second := newSprint(t, project.ID, "Second")
+	foreign := newSprint(t, other.ID, "Foreign")
+
+	done := createTask(t, db, project.ID, "Done")
+	open := createTask(t, db, project.ID, "Open")
+	as := actingAs(db, alice)
+	for _, task := range []*models.Task{done, open} {
+		if err := as.AddTaskToSprint(task.ID, first.ID); err != nil {
+			t.Fatalf("failed to add task to sprint: %v", err)
+		}
+	}
+
+	t.Run("sprints are created planned", func(t *testing.T) {
+		if err := db.CreateSprint(&models.Sprint{ProjectID: project.ID, Name: "Active", Status: models.SprintStatusActive}); !errors.Is(err, ErrInvalidSprint) {
+			t.Errorf("created an active sprint: %v", err)
+		}
+		if err := db.CreateSprint(&models.Sprint{ProjectID: 99, Name: "Orphan"}); !errors.Is(err, ErrProjectNotFound) {
+			t.Errorf("created a sprint of a missing project: %v", err)
+		}
+	})
+
+	t.Run("sprints end after they start and stay in their project", func(t *testing.T) {
+		start, end := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+		if err := db.CreateSprint(&models.Sprint{ProjectID: project.ID, Name: "Backwards", StartDate: &start, EndDate: &end}); !errors.Is(err, ErrInvalidSprint) {
+			t.Errorf("created a sprint ending before it starts: %v", err)
+		}
+
+		moved := *second
+		moved.ProjectID = other.ID
+		if err := db.UpdateSprint(&moved); err != nil {
+			t.Fatal(err)
+		}
+		if saved, _ := db.GetSprint(second.ID); saved.ProjectID != project.ID {
+			t.Errorf("update moved the sprint to project %d", saved.ProjectID)
+		}
+	})
+
+	t.Run("tasks only join open sprints of their project", func(t *testing.T) {
+		if err := db.AddTaskToSprint(open.ID, foreign.ID); err == nil {
+			t.Error("added a task to another project's sprint")
+		}
+		if err := db.CheckTaskPlacement(project.ID, foreign.ID, 0); err == nil {
+			t.Error("placement check accepted another project's sprint")
+		}
+	})
+
+	t.Run("only one sprint is active per project", func(t *testing.T) {
+		if _, err := db.StartSprint(first.ID); err != nil {
+			t.Fatalf("failed to start sprint: %v", err)
+		}
+		if _, err := db.StartSprint(second.ID); err == nil {
+			t.Error("started a second active sprint")
+		}
+		if _, err := db.StartSprint(foreign.ID); err != nil {
+			t.Errorf("sprints of other projects are independent: %v", err)
+		}
+	})
+
+	t.Run("updates do not change the status", func(t *testing.T) {
+		update := *second
+		update.Name = "Second, renamed"
+		update.Status = models.SprintStatusCompleted
+		if err := db.UpdateSprint(&update); err != nil {
+			t.Fatalf("failed to update sprint: %v", err)
+		}
+		stored, _ := db.GetSprint(second.ID)
+		if stored.Name != "Second, renamed" || stored.Status != models.SprintStatusPlanned {
+			t.Errorf("sprint is %q (%s), want the new name and still planned", stored.Name, stored.Status)
+		}
+	})
+
+	t.Run("completing carries unfinished tasks over", func(t *testing.T) {
+		finished, _ := db.GetTask(done.ID)
+		finished.Status = models.TaskStatusDone
+		if err := db.UpdateTask(finished); err != nil {
+			t.Fatal(err)
+		}
+
+		carried, err := as.CompleteSprint(first.ID, nil)
+		if err != nil || carried != 1 {
+			t.Fatalf("carried %d tasks (%v), want 1", carried, err)
+		}
+
+		moved, _ := db.GetTask(open.ID)
+		if moved.SprintID == nil || *moved.SprintID != second.ID {
+			t.Errorf("open task is in sprint %v, want %d", moved.SprintID, second.ID)
+		}
+		stayed, _ := db.GetTask(done.ID)
+		if stayed.SprintID == nil || *stayed.SprintID != first.ID {
+			t.Errorf("done task is in sprint %v, want %d", stayed.SprintID, first.ID)
+		}
+
+		if err := db.AddTaskToSprint(open.ID, first.ID); err == nil {
+			t.Error("added a task to a completed sprint")
+		}
+	})
+
+	t.Run("sprint moves are credited to the caller", func(t *testing.T) {
+		activities, _ := db.GetTaskActivities(open.ID)
+		moves := 0
+		for _, activity := range activities {
+			if activity.Action != "sprint_changed" {
+				continue
+			}
+			moves++
+			if activity.UserName != "alice" || activity.UserID == nil || *activity.UserID != alice.ID {
+				t.Errorf("sprint move %q credited to %s", activity.Description, activity.UserName)
+			}
+		}
+		if moves != 2 {
+			t.Errorf("logged %d sprint moves, want 2", moves)
+		}
+	})
+}
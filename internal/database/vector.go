@@ -89,6 +89,33 @@ func (db *Database) StoreEmbedding(entityType string, entityID uint, vector []fl
 	return tx.Commit().Error
 }
 
+// DeleteEmbedding removes the stored embedding of a deleted entity
+func (db *Database) DeleteEmbedding(entityType string, entityID uint) error {
+	var query string
+	switch entityType {
+	case "project":
+		query = `DELETE FROM project_vectors WHERE project_id = ?`
+	case "task":
+		query = `DELETE FROM task_vectors WHERE task_id = ?`
+	case "document":
+		query = `DELETE FROM document_vectors WHERE document_id = ?`
+	default:
+		return fmt.Errorf("unsupported entity type: %s", entityType)
+	}
+
+	tx := db.Begin()
+	defer tx.Rollback()
+
+	if err := tx.Exec(query, entityID).Error; err != nil {
+		return err
+	}
+	if err := tx.Table("embeddings").Where("entity_type = ? AND entity_id = ?", entityType, entityID).Delete(nil).Error; err != nil {
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
 // SearchSimilar performs vector similarity search
 func (db *Database) SearchSimilar(entityType string, queryVector []float32, limit int) ([]map[string]interface{}, error) {
 	var query string
@@ -0,0 +1,61 @@
+package database
+
+import (
+	"fmt"
+
+	"github.com/headless-pm/headless-project-management/internal/models"
+)
+
+// AddWatcher subscribes a user to a task. Watching a task twice is a no-op.
+func (db *Database) AddWatcher(taskID uint, userID uint) error {
+	if err := db.First(&models.Task{}, taskID).Error; err != nil {
+		return fmt.Errorf("task not found: %w", err)
+	}
+	if err := db.First(&models.User{}, userID).Error; err != nil {
+		return fmt.Errorf("user not found: %w", err)
+	}
+
+	return db.Exec("INSERT OR IGNORE INTO task_watchers (task_id, user_id) VALUES (?, ?)", taskID, userID).Error
+}
+
+// RemoveWatcher unsubscribes a user from a task
+func (db *Database) RemoveWatcher(taskID uint, userID uint) error {
+	return db.Exec("DELETE FROM task_watchers WHERE task_id = ? AND user_id = ?", taskID, userID).Error
+}
+
+// GetTaskWatchers returns the users watching a task
+func (db *Database) GetTaskWatchers(taskID uint) ([]models.User, error) {
+	var users []models.User
+	err := db.Joins("JOIN task_watchers ON task_watchers.user_id = users.id").
+		Where("task_watchers.task_id = ?", taskID).
+		Order("users.username ASC").
+		Find(&users).Error
+	return users, err
+}
+
+// GetTaskWatcherIDs returns the IDs of the users watching a task, for fanning out notifications
+func (db *Database) GetTaskWatcherIDs(taskID uint) ([]uint, error) {
+	var userIDs []uint
+	err := db.Table("task_watchers").Where("task_id = ?", taskID).Pluck("user_id", &userIDs).Error
+	return userIDs, err
+}
+
+// GetWatchedTasks returns the tasks a user is watching, most recently updated first
+func (db *Database) GetWatchedTasks(userID uint) ([]models.Task, error) {
+	var tasks []models.Task
+	err := db.Joins("JOIN task_watchers ON task_watchers.task_id = tasks.id").
+		Where("task_watchers.user_id = ?", userID).
+		Preload("Project").
+		Order("tasks.updated_at DESC").
+		Find(&tasks).Error
+	return tasks, err
+}
+
+// autoWatch adds a user as a watcher of a task when it is involved in it
+// (creator, assignee or commenter). Unknown users are ignored.
+func (db *Database) autoWatch(taskID uint, userID *uint) {
+	if userID == nil || *userID == 0 {
+		return
+	}
+	_ = db.AddWatcher(taskID, *userID)
+}
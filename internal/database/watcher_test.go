@@ -0,0 +1,39 @@
+package database
+
+import "testing"
+
+func TestTaskWatchers(t *testing.T) {
+	db := newTestDatabase(t)
+	alice := createUser(t, db, "alice")
+	bob := createUser(t, db, "bob")
+	project := createProject(t, db, "Watching")
+	task := createTask(t, actingAs(db, alice), project.ID, "Watched")
+
+	ids, _ := db.GetTaskWatcherIDs(task.ID)
+	if len(ids) != 1 || ids[0] != alice.ID {
+		t.Fatalf("watchers after creation are %v, want the creator", ids)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := db.AddWatcher(task.ID, bob.ID); err != nil {
+			t.Fatalf("failed to watch task: %v", err)
+		}
+	}
+	watchers, _ := db.GetTaskWatchers(task.ID)
+	if len(watchers) != 2 {
+		t.Errorf("task has %d watchers, want 2", len(watchers))
+	}
+	if err := db.AddWatcher(task.ID, 99); err == nil {
+		t.Error("an unknown user watched the task")
+	}
+
+	if err := db.RemoveWatcher(task.ID, alice.ID); err != nil {
+		t.Fatal(err)
+	}
+	if watched, _ := db.GetWatchedTasks(alice.ID); len(watched) != 0 {
+		t.Errorf("alice still watches %d tasks", len(watched))
+	}
+	if watched, _ := db.GetWatchedTasks(bob.ID); len(watched) != 1 {
+		t.Errorf("bob watches %d tasks, want 1", len(watched))
+	}
+}
@@ -0,0 +1,251 @@
+package database
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/headless-pm/headless-project-management/internal/models"
+	"gorm.io/gorm"
+)
+
+// Webhook CRUD methods
+func (db *Database) CreateWebhook(webhook *models.Webhook) error {
+	if err := validateWebhook(webhook); err != nil {
+		return err
+	}
+	if webhook.Secret == "" {
+		secret, err := generateWebhookSecret()
+		if err != nil {
+			return err
+		}
+		webhook.Secret = secret
+	}
+	return db.Create(webhook).Error
+}
+
+func (db *Database) GetWebhook(id uint) (*models.Webhook, error) {
+	var webhook models.Webhook
+	err := db.Preload("Project").First(&webhook, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+// ListWebhooks lists webhooks; with a project only the webhooks scoped to it are returned
+func (db *Database) ListWebhooks(projectID *uint) ([]models.Webhook, error) {
+	var webhooks []models.Webhook
+	query := db.DB
+	if projectID != nil {
+		query = query.Where("project_id = ?", *projectID)
+	}
+	err := query.Preload("Project").Order("id ASC").Find(&webhooks).Error
+	return webhooks, err
+}
+
+func (db *Database) UpdateWebhook(webhook *models.Webhook) error {
+	if err := validateWebhook(webhook); err != nil {
+		return err
+	}
+	return db.Omit("Project").Save(webhook).Error
+}
+
+// DeleteWebhook deletes a webhook and its delivery log
+func (db *Database) DeleteWebhook(id uint) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("webhook_id = ?", id).Delete(&models.WebhookDelivery{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Webhook{}, id).Error
+	})
+}
+
+// PingWebhook queues a ping delivery to test a webhook
+func (db *Database) PingWebhook(id uint) (*models.WebhookDelivery, error) {
+	webhook, err := db.GetWebhook(id)
+	if err != nil {
+		return nil, err
+	}
+	return db.queueWebhookDelivery(webhook, models.WebhookEventPing, webhook.ProjectID, map[string]interface{}{
+		"webhook_id": webhook.ID,
+		"events":     webhook.EventList(),
+	})
+}
+
+// ListWebhookDeliveries returns the delivery log of a webhook, newest first
+func (db *Database) ListWebhookDeliveries(webhookID uint, status *models.WebhookDeliveryStatus, limit int) ([]models.WebhookDelivery, error) {
+	var deliveries []models.WebhookDelivery
+	query := db.Where("webhook_id = ?", webhookID)
+	if status != nil {
+		query = query.Where("status = ?", *status)
+	}
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	err := query.Order("created_at DESC, id DESC").Find(&deliveries).Error
+	return deliveries, err
+}
+
+func (db *Database) GetWebhookDelivery(id uint) (*models.WebhookDelivery, error) {
+	var delivery models.WebhookDelivery
+	err := db.First(&delivery, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+// RedeliverWebhookDelivery queues a new delivery with the payload of an earlier one
+func (db *Database) RedeliverWebhookDelivery(id uint) (*models.WebhookDelivery, error) {
+	original, err := db.GetWebhookDelivery(id)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	delivery := &models.WebhookDelivery{
+		WebhookID:     original.WebhookID,
+		Event:         original.Event,
+		Payload:       original.Payload,
+		Status:        models.WebhookDeliveryPending,
+		NextAttemptAt: &now,
+		RedeliveryOf:  &original.ID,
+	}
+	if err := db.Create(delivery).Error; err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
+
+// ClaimWebhookDelivery marks a pending delivery as in flight so it is sent only once.
+// It returns nil when the delivery was already claimed or finished.
+func (db *Database) ClaimWebhookDelivery(id uint) (*models.WebhookDelivery, error) {
+	result := db.Model(&models.WebhookDelivery{}).
+		Where("id = ? AND status = ?", id, models.WebhookDeliveryPending).
+		Update("status", models.WebhookDeliveryDelivering)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+
+	var delivery models.WebhookDelivery
+	if err := db.Preload("Webhook").First(&delivery, id).Error; err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+// ListDueWebhookDeliveries returns the IDs of pending deliveries whose next attempt is due
+func (db *Database) ListDueWebhookDeliveries(limit int) ([]uint, error) {
+	var ids []uint
+	err := db.Model(&models.WebhookDelivery{}).
+		Where("status = ? AND (next_attempt_at IS NULL OR next_attempt_at <= ?)", models.WebhookDeliveryPending, time.Now()).
+		Order("next_attempt_at ASC, id ASC").
+		Limit(limit).
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+// SaveWebhookDelivery records the outcome of a delivery attempt
+func (db *Database) SaveWebhookDelivery(delivery *models.WebhookDelivery) error {
+	return db.Omit("Webhook").Save(delivery).Error
+}
+
+// ResetInterruptedWebhookDeliveries puts deliveries left in flight by a previous
+// run back in the outbox
+func (db *Database) ResetInterruptedWebhookDeliveries() (int64, error) {
+	result := db.Model(&models.WebhookDelivery{}).
+		Where("status = ?", models.WebhookDeliveryDelivering).
+		Update("status", models.WebhookDeliveryPending)
+	return result.RowsAffected, result.Error
+}
+
+// QueueWebhookEvent queues a delivery of an event for every active webhook of
+// the project, and every global webhook, that subscribes to it. It runs after
+// the change was committed, in its own write: if the server stops in between,
+// the change is kept but its deliveries are never queued.
+func (db *Database) QueueWebhookEvent(event models.WebhookEvent, projectID *uint, data interface{}) {
+	query := db.Where("active = ?", true)
+	if projectID != nil {
+		query = query.Where("project_id IS NULL OR project_id = ?", *projectID)
+	} else {
+		query = query.Where("project_id IS NULL")
+	}
+
+	var webhooks []models.Webhook
+	if err := query.Find(&webhooks).Error; err != nil {
+		log.Printf("Warning: failed to load webhooks for %s: %v", event, err)
+		return
+	}
+
+	for i := range webhooks {
+		if !webhooks[i].Subscribes(event) {
+			continue
+		}
+		if _, err := db.queueWebhookDelivery(&webhooks[i], event, projectID, data); err != nil {
+			log.Printf("Warning: failed to queue %s for webhook %d: %v", event, webhooks[i].ID, err)
+		}
+	}
+}
+
+func (db *Database) queueWebhookDelivery(webhook *models.Webhook, event models.WebhookEvent, projectID *uint, data interface{}) (*models.WebhookDelivery, error) {
+	payload, err := json.Marshal(models.WebhookPayload{
+		Event:     event,
+		ProjectID: projectID,
+		Timestamp: time.Now().UTC(),
+		Data:      data,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	delivery := &models.WebhookDelivery{
+		WebhookID:     webhook.ID,
+		Event:         event,
+		Payload:       string(payload),
+		Status:        models.WebhookDeliveryPending,
+		NextAttemptAt: &now,
+	}
+	if err := db.Create(delivery).Error; err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
+
+func validateWebhook(webhook *models.Webhook) error {
+	webhook.Name = strings.TrimSpace(webhook.Name)
+	if webhook.Name == "" {
+		return fmt.Errorf("webhook name is required")
+	}
+
+	parsed, err := url.Parse(webhook.URL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("invalid webhook URL: %s", webhook.URL)
+	}
+
+	events := webhook.EventList()
+	for _, event := range events {
+		if event != "*" && !models.IsValidWebhookEvent(event) {
+			return fmt.Errorf("invalid webhook event: %s", event)
+		}
+	}
+	webhook.Events = strings.Join(events, ",")
+	return nil
+}
+
+func generateWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,59 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/headless-pm/headless-project-management/internal/models"
+)
+
+func TestQueueWebhookEvent(t *testing.T) {
+	db := newTestDatabase(t)
+	apollo := createProject(t, db, "Apollo")
+	gemini := createProject(t, db, "Gemini")
+
+	global := &models.Webhook{Name: "All", URL: "https://example.com/all", Active: true}
+	scoped := &models.Webhook{Name: "Apollo tasks", URL: "https://example.com/apollo", ProjectID: &apollo.ID, Events: "task.created", Active: true}
+	comments := &models.Webhook{Name: "Comments", URL: "https://example.com/comments", Events: "comment.added", Active: true}
+	for _, webhook := range []*models.Webhook{global, scoped, comments} {
+		if err := db.CreateWebhook(webhook); err != nil {
+			t.Fatalf("CreateWebhook %s: %v", webhook.Name, err)
+		}
+	}
+	if global.Secret == "" {
+		t.Error("webhook was created without a signing secret")
+	}
+	if err := db.CreateWebhook(&models.Webhook{Name: "Bad", URL: "ftp://example.com"}); err == nil {
+		t.Error("accepted a non-HTTP webhook URL")
+	}
+	if err := db.CreateWebhook(&models.Webhook{Name: "Bad", URL: "https://example.com", Events: "task.exploded"}); err == nil {
+		t.Error("accepted an unknown webhook event")
+	}
+
+	db.QueueWebhookEvent(models.WebhookEventTaskCreated, &apollo.ID, map[string]interface{}{"id": 1})
+	db.QueueWebhookEvent(models.WebhookEventTaskCreated, &gemini.ID, map[string]interface{}{"id": 2})
+
+	for webhook, want := range map[*models.Webhook]int{global: 2, scoped: 1, comments: 0} {
+		deliveries, err := db.ListWebhookDeliveries(webhook.ID, nil, 0)
+		if err != nil {
+			t.Fatalf("ListWebhookDeliveries: %v", err)
+		}
+		if len(deliveries) != want {
+			t.Errorf("%s got %d deliveries, want %d", webhook.Name, len(deliveries), want)
+		}
+	}
+
+	due, err := db.ListDueWebhookDeliveries(10)
+	if err != nil || len(due) != 3 {
+		t.Fatalf("due deliveries = %v (%v), want 3", due, err)
+	}
+	claimed, err := db.ClaimWebhookDelivery(due[0])
+	if err != nil || claimed == nil || claimed.Webhook == nil {
+		t.Fatalf("ClaimWebhookDelivery = %+v (%v), want the delivery with its webhook", claimed, err)
+	}
+	if again, _ := db.ClaimWebhookDelivery(due[0]); again != nil {
+		t.Error("claimed a delivery twice")
+	}
+	if n, _ := db.ResetInterruptedWebhookDeliveries(); n != 1 {
+		t.Errorf("requeued %d interrupted deliveries, want 1", n)
+	}
+}
@@ -0,0 +1,392 @@
+package database
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/headless-pm/headless-project-management/internal/models"
+	"gorm.io/gorm"
+)
+
+// TimesheetFilter narrows a timesheet report. Nil fields are not filtered on.
+type TimesheetFilter struct {
+	UserID    *uint
+	ProjectID *uint
+	From      *time.Time
+	To        *time.Time
+}
+
+// TimesheetEntry is a single finished worklog flattened for reporting
+type TimesheetEntry struct {
+	WorklogID   uint      `json:"worklog_id"`
+	Date        string    `json:"date"`
+	UserID      uint      `json:"user_id"`
+	UserName    string    `json:"user_name"`
+	ProjectID   uint      `json:"project_id"`
+	ProjectName string    `json:"project_name"`
+	TaskID      uint      `json:"task_id"`
+	TaskTitle   string    `json:"task_title"`
+	StartedAt   time.Time `json:"started_at"`
+	Minutes     int       `json:"minutes"`
+	Hours       float64   `json:"hours"`
+	Note        string    `json:"note"`
+}
+
+// Timesheet is the result of a timesheet report
+type Timesheet struct {
+	From       *time.Time         `json:"from,omitempty"`
+	To         *time.Time         `json:"to,omitempty"`
+	Entries    []TimesheetEntry   `json:"entries"`
+	TotalHours float64            `json:"total_hours"`
+	ByUser     map[string]float64 `json:"by_user"`
+	ByProject  map[string]float64 `json:"by_project"`
+}
+
+// CreateWorklog records time spent on a task and refreshes the task's actual hours.
+// Either DurationMinutes or EndedAt must be set; the other one is derived.
+func (db *Database) CreateWorklog(worklog *models.Worklog) error {
+	if worklog.StartedAt.IsZero() {
+		worklog.StartedAt = time.Now()
+	}
+	if err := normalizeWorklog(worklog); err != nil {
+		return err
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&models.Task{}, worklog.TaskID).Error; err != nil {
+			return fmt.Errorf("task not found: %w", err)
+		}
+		if err := tx.First(&models.User{}, worklog.UserID).Error; err != nil {
+			return fmt.Errorf("user not found: %w", err)
+		}
+		if err := tx.Create(worklog).Error; err != nil {
+			return err
+		}
+		return rollupActualHours(tx, worklog.TaskID)
+	})
+}
+
+func (db *Database) GetWorklog(id uint) (*models.Worklog, error) {
+	var worklog models.Worklog
+	err := db.Preload("User").Preload("Task").First(&worklog, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &worklog, nil
+}
+
+func (db *Database) ListWorklogs(taskID *uint, userID *uint) ([]models.Worklog, error) {
+	var worklogs []models.Worklog
+	query := db.DB
+
+	if taskID != nil {
+		query = query.Where("task_id = ?", *taskID)
+	}
+	if userID != nil {
+		query = query.Where("user_id = ?", *userID)
+	}
+
+	err := query.Preload("User").Order("started_at DESC").Find(&worklogs).Error
+	return worklogs, err
+}
+
+// UpdateWorklog saves changes to a finished worklog and refreshes the task's actual hours
+func (db *Database) UpdateWorklog(worklog *models.Worklog) error {
+	var existing models.Worklog
+	if err := db.First(&existing, worklog.ID).Error; err != nil {
+		return err
+	}
+	if existing.EndedAt == nil {
+		return fmt.Errorf("cannot edit a running timer, stop it first")
+	}
+
+	// Recompute the end from the duration unless only the end was changed
+	endChanged := worklog.EndedAt != nil && !worklog.EndedAt.Equal(*existing.EndedAt)
+	if endChanged && worklog.DurationMinutes == existing.DurationMinutes {
+		worklog.DurationMinutes = 0
+	} else {
+		worklog.EndedAt = nil
+	}
+	if err := normalizeWorklog(worklog); err != nil {
+		return err
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(worklog).Error; err != nil {
+			return err
+		}
+		if err := rollupActualHours(tx, worklog.TaskID); err != nil {
+			return err
+		}
+		if existing.TaskID != worklog.TaskID {
+			return rollupActualHours(tx, existing.TaskID)
+		}
+		return nil
+	})
+}
+
+// DeleteWorklog removes a worklog and refreshes the task's actual hours
+func (db *Database) DeleteWorklog(id uint) error {
+	var worklog models.Worklog
+	if err := db.First(&worklog, id).Error; err != nil {
+		return err
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&models.Worklog{}, id).Error; err != nil {
+			return err
+		}
+		return rollupActualHours(tx, worklog.TaskID)
+	})
+}
+
+// createRunningTimerIndex makes the database reject a second running timer of
+// a user, which the check in StartTimer alone cannot do for concurrent starts
+func createRunningTimerIndex(db *gorm.DB) error {
+	return db.Exec("CREATE UNIQUE INDEX IF NOT EXISTS idx_worklogs_running_timer ON worklogs(user_id) WHERE ended_at IS NULL").Error
+}
+
+// StartTimer starts a running worklog for a user on a task. A user can only
+// have one running timer at a time.
+func (db *Database) StartTimer(taskID uint, userID uint, note string) (*models.Worklog, error) {
+	alreadyRunning := fmt.Errorf("user %d already has a running timer, stop it first", userID)
+	if _, err := db.GetRunningTimer(userID); err == nil {
+		return nil, alreadyRunning
+	} else if err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	if err := db.First(&models.Task{}, taskID).Error; err != nil {
+		return nil, fmt.Errorf("task not found: %w", err)
+	}
+	if err := db.First(&models.User{}, userID).Error; err != nil {
+		return nil, fmt.Errorf("user not found: %w", err)
+	}
+
+	worklog := &models.Worklog{
+		TaskID:    taskID,
+		UserID:    userID,
+		StartedAt: time.Now(),
+		Note:      note,
+	}
+	if err := db.Create(worklog).Error; err != nil {
+		// Another start won the race for the running timer index
+		if strings.Contains(err.Error(), "UNIQUE constraint failed") {
+			return nil, alreadyRunning
+		}
+		return nil, err
+	}
+	return worklog, nil
+}
+
+// StopTimer stops the running timer of a user, turning it into a finished
+// worklog. A non-empty note replaces the one given when the timer started.
+func (db *Database) StopTimer(userID uint, note string) (*models.Worklog, error) {
+	worklog, err := db.GetRunningTimer(userID)
+	if err == gorm.ErrRecordNotFound {
+		return nil, fmt.Errorf("user %d has no running timer", userID)
+	} else if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	worklog.EndedAt = &now
+	worklog.DurationMinutes = 0
+	if note != "" {
+		worklog.Note = note
+	}
+	if err := normalizeWorklog(worklog); err != nil {
+		return nil, err
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(worklog).Error; err != nil {
+			return err
+		}
+		return rollupActualHours(tx, worklog.TaskID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return worklog, nil
+}
+
+// GetRunningTimer returns the running timer of a user, if any
+func (db *Database) GetRunningTimer(userID uint) (*models.Worklog, error) {
+	var worklog models.Worklog
+	err := db.Preload("Task").Where("user_id = ? AND ended_at IS NULL", userID).First(&worklog).Error
+	if err != nil {
+		return nil, err
+	}
+	return &worklog, nil
+}
+
+// GetTimesheet reports finished worklogs matching the filter, with totals per user and project
+func (db *Database) GetTimesheet(filter TimesheetFilter) (*Timesheet, error) {
+	query := db.Preload("User").Preload("Task.Project").
+		Where("worklogs.ended_at IS NOT NULL")
+
+	if filter.UserID != nil {
+		query = query.Where("worklogs.user_id = ?", *filter.UserID)
+	}
+	if filter.ProjectID != nil {
+		query = query.Where("worklogs.task_id IN (SELECT id FROM tasks WHERE project_id = ?)", *filter.ProjectID)
+	}
+	if filter.From != nil {
+		query = query.Where("worklogs.started_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("worklogs.started_at < ?", *filter.To)
+	}
+
+	var worklogs []models.Worklog
+	if err := query.Order("worklogs.started_at ASC").Find(&worklogs).Error; err != nil {
+		return nil, err
+	}
+
+	timesheet := &Timesheet{
+		From:      filter.From,
+		To:        filter.To,
+		Entries:   make([]TimesheetEntry, 0, len(worklogs)),
+		ByUser:    make(map[string]float64),
+		ByProject: make(map[string]float64),
+	}
+
+	totalMinutes := 0
+	userMinutes := make(map[string]int)
+	projectMinutes := make(map[string]int)
+	for _, worklog := range worklogs {
+		entry := TimesheetEntry{
+			WorklogID: worklog.ID,
+			Date:      worklog.StartedAt.Format("2006-01-02"),
+			UserID:    worklog.UserID,
+			TaskID:    worklog.TaskID,
+			StartedAt: worklog.StartedAt,
+			Minutes:   worklog.DurationMinutes,
+			Hours:     minutesToHours(worklog.DurationMinutes),
+			Note:      worklog.Note,
+		}
+		if worklog.User != nil {
+			entry.UserName = worklog.User.Username
+		}
+		if worklog.Task != nil {
+			entry.TaskTitle = worklog.Task.Title
+			entry.ProjectID = worklog.Task.ProjectID
+			if worklog.Task.Project != nil {
+				entry.ProjectName = worklog.Task.Project.Name
+			}
+		}
+
+		timesheet.Entries = append(timesheet.Entries, entry)
+		totalMinutes += entry.Minutes
+		userMinutes[entry.UserName] += entry.Minutes
+		projectMinutes[entry.ProjectName] += entry.Minutes
+	}
+
+	timesheet.TotalHours = minutesToHours(totalMinutes)
+	for name, minutes := range userMinutes {
+		timesheet.ByUser[name] = minutesToHours(minutes)
+	}
+	for name, minutes := range projectMinutes {
+		timesheet.ByProject[name] = minutesToHours(minutes)
+	}
+
+	return timesheet, nil
+}
+
+// WriteCSV writes the timesheet entries as CSV, one row per worklog
+func (t *Timesheet) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"date", "user", "project", "task_id", "task", "started_at", "minutes", "hours", "note"}); err != nil {
+		return err
+	}
+
+	for _, entry := range t.Entries {
+		record := []string{
+			entry.Date,
+			csvText(entry.UserName),
+			csvText(entry.ProjectName),
+			strconv.FormatUint(uint64(entry.TaskID), 10),
+			csvText(entry.TaskTitle),
+			entry.StartedAt.Format(time.RFC3339),
+			strconv.Itoa(entry.Minutes),
+			strconv.FormatFloat(entry.Hours, 'f', 2, 64),
+			csvText(entry.Note),
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// csvText keeps spreadsheets from running user text as a formula: text starting
+// with =, +, -, @, a tab or a carriage return is prefixed with a quote
+func csvText(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+// normalizeWorklog validates a finished worklog and fills in whichever of
+// EndedAt and DurationMinutes is missing
+func normalizeWorklog(worklog *models.Worklog) error {
+	if worklog.TaskID == 0 {
+		return fmt.Errorf("task_id is required")
+	}
+	if worklog.UserID == 0 {
+		return fmt.Errorf("user_id is required")
+	}
+
+	switch {
+	case worklog.DurationMinutes > 0:
+		end := worklog.StartedAt.Add(time.Duration(worklog.DurationMinutes) * time.Minute)
+		worklog.EndedAt = &end
+	case worklog.EndedAt != nil:
+		if worklog.EndedAt.Before(worklog.StartedAt) {
+			return fmt.Errorf("ended_at must be after started_at")
+		}
+		// Round to the nearest minute, but never log less than one minute
+		minutes := int(math.Round(worklog.EndedAt.Sub(worklog.StartedAt).Minutes()))
+		if minutes < 1 {
+			minutes = 1
+		}
+		worklog.DurationMinutes = minutes
+	default:
+		return fmt.Errorf("duration_minutes or ended_at is required")
+	}
+
+	return nil
+}
+
+// rollupActualHours recomputes a task's actual hours from its finished worklogs
+func rollupActualHours(tx *gorm.DB, taskID uint) error {
+	var totalMinutes int64
+	if err := tx.Model(&models.Worklog{}).
+		Where("task_id = ? AND ended_at IS NOT NULL", taskID).
+		Select("COALESCE(SUM(duration_minutes), 0)").
+		Scan(&totalMinutes).Error; err != nil {
+		return err
+	}
+
+	var actualHours *float64
+	if totalMinutes > 0 {
+		hours := minutesToHours(int(totalMinutes))
+		actualHours = &hours
+	}
+
+	return tx.Model(&models.Task{}).Where("id = ?", taskID).
+		UpdateColumn("actual_hours", actualHours).Error
+}
+
+func minutesToHours(minutes int) float64 {
+	return math.Round(float64(minutes)/60*100) / 100
+}
@@ -0,0 +1,118 @@
+package database
+
+import (
+	"encoding/csv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/headless-pm/headless-project-management/internal/models"
+)
+
+func TestTimers(t *testing.T) {
+	db := newTestDatabase(t)
+	alice := createUser(t, db, "alice")
+	project := createProject(t, db, "Timers")
+	task := createTask(t, db, project.ID, "Track me")
+
+	t.Run("a user has one running timer, even when starts race", func(t *testing.T) {
+		var wg sync.WaitGroup
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, _ = db.StartTimer(task.ID, alice.ID, "")
+			}()
+		}
+		wg.Wait()
+
+		var running int64
+		db.Model(&models.Worklog{}).Where("user_id = ? AND ended_at IS NULL", alice.ID).Count(&running)
+		if running != 1 {
+			t.Fatalf("alice has %d running timers, want 1", running)
+		}
+
+		if _, err := db.StartTimer(task.ID, alice.ID, ""); err == nil || !strings.Contains(err.Error(), "already has a running timer") {
+			t.Errorf("starting a second timer: %v", err)
+		}
+		second := &models.Worklog{TaskID: task.ID, UserID: alice.ID, StartedAt: time.Now()}
+		if err := db.Create(second).Error; err == nil {
+			t.Error("the database accepted a second running timer")
+		}
+	})
+
+	t.Run("stopping a timer rolls up the task's hours", func(t *testing.T) {
+		running, err := db.GetRunningTimer(alice.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		db.Model(running).Update("started_at", time.Now().Add(-90*time.Minute))
+
+		worklog, err := db.StopTimer(alice.ID, "done")
+		if err != nil {
+			t.Fatalf("failed to stop timer: %v", err)
+		}
+		if worklog.DurationMinutes != 90 || worklog.Note != "done" {
+			t.Errorf("stopped worklog is %d minutes with note %q", worklog.DurationMinutes, worklog.Note)
+		}
+
+		tracked, _ := db.GetTask(task.ID)
+		if tracked.ActualHours == nil || *tracked.ActualHours != 1.5 {
+			t.Errorf("actual hours = %v, want 1.5", tracked.ActualHours)
+		}
+		if _, err := db.StartTimer(task.ID, alice.ID, ""); err != nil {
+			t.Errorf("could not start a new timer after stopping: %v", err)
+		}
+	})
+
+	t.Run("timesheets only report finished worklogs", func(t *testing.T) {
+		timesheet, err := db.GetTimesheet(TimesheetFilter{ProjectID: &project.ID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(timesheet.Entries) != 1 || timesheet.TotalHours != 1.5 || timesheet.ByUser["alice"] != 1.5 {
+			t.Errorf("timesheet has %d entries and %v hours (%v)", len(timesheet.Entries), timesheet.TotalHours, timesheet.ByUser)
+		}
+
+		var csv strings.Builder
+		if err := timesheet.WriteCSV(&csv); err != nil {
+			t.Fatal(err)
+		}
+		if lines := strings.Split(strings.TrimSpace(csv.String()), "\n"); len(lines) != 2 || !strings.Contains(lines[1], "Track me") {
+			t.Errorf("CSV export is %q", csv.String())
+		}
+	})
+}
+
+func TestTimesheetCSVEscapesFormulas(t *testing.T) {
+	timesheet := &Timesheet{Entries: []TimesheetEntry{{
+		Date:        "2026-03-02",
+		UserName:    "@alice",
+		ProjectName: "+Billing",
+		TaskID:      1,
+		TaskTitle:   `=HYPERLINK("https://example.com","Invoice")`,
+		Minutes:     30,
+		Hours:       0.5,
+		Note:        "-1 day",
+	}}}
+
+	var out strings.Builder
+	if err := timesheet.WriteCSV(&out); err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(strings.NewReader(out.String())).ReadAll()
+	if err != nil || len(records) != 2 {
+		t.Fatalf("CSV export is %q: %v", out.String(), err)
+	}
+	row := records[1]
+	want := map[int]string{1: "'@alice", 2: "'+Billing", 4: `'=HYPERLINK("https://example.com","Invoice")`, 8: "'-1 day"}
+	for column, value := range want {
+		if row[column] != value {
+			t.Errorf("column %s = %q, want %q", records[0][column], row[column], value)
+		}
+	}
+	if row[0] != "2026-03-02" || row[6] != "30" {
+		t.Errorf("generated columns were escaped: %q", row)
+	}
+}
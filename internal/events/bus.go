@@ -0,0 +1,72 @@
+package events
+
+import (
+	"log"
+	"runtime/debug"
+	"sync"
+)
+
+// Event is a domain event published by the database after a write has been committed
+type Event interface {
+	// Name identifies the event type, e.g. "task.created"
+	Name() string
+}
+
+// Handler receives published events
+type Handler func(Event)
+
+// Bus is an in-process publish/subscribe hub for domain events. Handlers run
+// synchronously in the publishing goroutine, in the order they subscribed; a
+// handler that needs to do slow work should hand it off (e.g. to a queue).
+type Bus struct {
+	mu       sync.RWMutex
+	handlers map[string][]Handler
+	all      []Handler
+}
+
+func NewBus() *Bus {
+	return &Bus{handlers: make(map[string][]Handler)}
+}
+
+// Subscribe registers a handler for the events with the given names
+func (b *Bus) Subscribe(handler Handler, names ...string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, name := range names {
+		b.handlers[name] = append(b.handlers[name], handler)
+	}
+}
+
+// SubscribeAll registers a handler for every event
+func (b *Bus) SubscribeAll(handler Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.all = append(b.all, handler)
+}
+
+// Publish delivers an event to its subscribers. A panicking handler is logged
+// and does not stop the other handlers.
+func (b *Bus) Publish(event Event) {
+	if b == nil || event == nil {
+		return
+	}
+
+	b.mu.RLock()
+	handlers := make([]Handler, 0, len(b.handlers[event.Name()])+len(b.all))
+	handlers = append(handlers, b.handlers[event.Name()]...)
+	handlers = append(handlers, b.all...)
+	b.mu.RUnlock()
+
+	for _, handler := range handlers {
+		dispatch(handler, event)
+	}
+}
+
+func dispatch(handler Handler, event Event) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Warning: %s event handler panicked: %v\n%s", event.Name(), r, debug.Stack())
+		}
+	}()
+	handler(event)
+}
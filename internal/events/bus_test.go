@@ -0,0 +1,38 @@
+package events
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBusPublishOrder(t *testing.T) {
+	bus := NewBus()
+	var calls []string
+	record := func(name string) Handler {
+		return func(Event) { calls = append(calls, name) }
+	}
+
+	bus.SubscribeAll(record("all"))
+	bus.Subscribe(record("first"), NameTaskCreated)
+	bus.Subscribe(func(Event) { panic("boom") }, NameTaskCreated)
+	bus.Subscribe(record("second"), NameTaskCreated, NameTaskUpdated)
+	bus.Subscribe(record("comments"), NameCommentAdded)
+
+	bus.Publish(TaskCreated{})
+	want := []string{"first", "second", "all"}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("task.created handlers ran %v, want %v", calls, want)
+	}
+
+	calls = nil
+	bus.Publish(TaskUpdated{})
+	want = []string{"second", "all"}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("task.updated handlers ran %v, want %v", calls, want)
+	}
+
+	// A nil bus or event is ignored
+	var none *Bus
+	none.Publish(TaskCreated{})
+	bus.Publish(nil)
+}
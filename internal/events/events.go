@@ -0,0 +1,267 @@
+package events
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/headless-pm/headless-project-management/internal/models"
+)
+
+// Event names
+const (
+	NameProjectCreated    = "project.created"
+	NameProjectUpdated    = "project.updated"
+	NameProjectDeleted    = "project.deleted"
+	NameTaskCreated       = "task.created"
+	NameTaskUpdated       = "task.updated"
+	NameTaskDeleted       = "task.deleted"
+	NameTaskUnblocked     = "task.unblocked"
+	NameCommentAdded      = "comment.added"
+	NameCommentUpdated    = "comment.updated"
+	NameCommentDeleted    = "comment.deleted"
+	NameUsersMentioned    = "users.mentioned"
+	NameNotificationSent  = "notification.sent"
+	NameDependencyAdded   = "dependency.added"
+	NameDependencyRemoved = "dependency.removed"
+	NameEpicCreated       = "epic.created"
+	NameEpicUpdated       = "epic.updated"
+	NameEpicDeleted       = "epic.deleted"
+	NameSprintStarted     = "sprint.started"
+	NameSprintCompleted   = "sprint.completed"
+	NamePromptsChanged    = "prompts.changed"
+	NameEmbeddingFailed   = "embedding.failed"
+	NameWebhookFailed     = "webhook.failed"
+	NameTaskLeaseExpired  = "task.lease_expired"
+)
+
+// Actor is the user who caused an event; ID is nil for system changes and
+// users that are not registered
+type Actor struct {
+	ID   *uint  `json:"id,omitempty"`
+	Name string `json:"name"`
+}
+
+type ProjectCreated struct {
+	Project *models.Project
+}
+
+type ProjectUpdated struct {
+	Project *models.Project
+}
+
+type ProjectDeleted struct {
+	ProjectID uint
+}
+
+type TaskCreated struct {
+	Task  *models.Task
+	Actor Actor
+}
+
+// TaskUpdated carries the task before and after the update and the fields that changed
+type TaskUpdated struct {
+	Old   *models.Task
+	Task  *models.Task
+	Diff  []FieldChange
+	Actor Actor
+}
+
+// Changed reports whether a field, by its JSON name, changed in the update
+func (e TaskUpdated) Changed(field string) bool {
+	for _, change := range e.Diff {
+		if change.Field == field {
+			return true
+		}
+	}
+	return false
+}
+
+type TaskDeleted struct {
+	Task models.Task
+}
+
+// TaskUnblocked is published when the last dependency of a task was completed
+type TaskUnblocked struct {
+	TaskID    uint
+	Completed *models.Task
+	Actor     Actor
+}
+
+type CommentAdded struct {
+	Comment   *models.Comment
+	ProjectID uint
+	Actor     Actor
+}
+
+type CommentUpdated struct {
+	Comment         *models.Comment
+	PreviousContent string
+	ProjectID       uint
+	Actor           Actor
+}
+
+type CommentDeleted struct {
+	Comment   *models.Comment
+	ProjectID uint
+}
+
+// UsersMentioned is published for users newly @mentioned in a task description
+// (CommentID nil) or a comment
+type UsersMentioned struct {
+	TaskID    uint
+	ProjectID uint
+	TaskTitle string
+	CommentID *uint
+	UserIDs   []uint
+	Actor     Actor
+}
+
+// NotificationSent is published for every inbox notification created
+type NotificationSent struct {
+	Notification models.Notification
+}
+
+type DependencyAdded struct {
+	Dependency models.TaskDependency
+	ProjectID  uint
+}
+
+type DependencyRemoved struct {
+	Dependency models.TaskDependency
+	ProjectID  uint
+}
+
+type EpicCreated struct {
+	Epic *models.Epic
+}
+
+type EpicUpdated struct {
+	Old  *models.Epic
+	Epic *models.Epic
+}
+
+type EpicDeleted struct {
+	EpicID    uint
+	ProjectID uint
+}
+
+type SprintStarted struct {
+	Sprint       *models.Sprint
+	CarriedTasks int
+}
+
+type SprintCompleted struct {
+	Sprint       *models.Sprint
+	CarriedTasks int
+	NextSprintID *uint
+}
+
+// PromptTemplatesChanged is published when a team prompt template is added, changed or removed
+type PromptTemplatesChanged struct{}
+
+// EmbeddingFailed is published when the search embedding of a project or task
+// could not be generated; Final is set once the worker stopped retrying
+type EmbeddingFailed struct {
+	EntityType string
+	EntityID   uint
+	Attempt    int
+	Err        string
+	Final      bool
+}
+
+// WebhookDeliveryFailed is published after a failed delivery attempt; Final is
+// set when the delivery will not be retried
+type WebhookDeliveryFailed struct {
+	Delivery *models.WebhookDelivery
+	Final    bool
+}
+
+// TaskLeaseExpired is published when a claim expired without a heartbeat and
+// its task was returned to the pool
+type TaskLeaseExpired struct {
+	Lease models.TaskLease
+}
+
+func (ProjectCreated) Name() string         { return NameProjectCreated }
+func (ProjectUpdated) Name() string         { return NameProjectUpdated }
+func (ProjectDeleted) Name() string         { return NameProjectDeleted }
+func (TaskCreated) Name() string            { return NameTaskCreated }
+func (TaskUpdated) Name() string            { return NameTaskUpdated }
+func (TaskDeleted) Name() string            { return NameTaskDeleted }
+func (TaskUnblocked) Name() string          { return NameTaskUnblocked }
+func (CommentAdded) Name() string           { return NameCommentAdded }
+func (CommentUpdated) Name() string         { return NameCommentUpdated }
+func (CommentDeleted) Name() string         { return NameCommentDeleted }
+func (UsersMentioned) Name() string         { return NameUsersMentioned }
+func (NotificationSent) Name() string       { return NameNotificationSent }
+func (DependencyAdded) Name() string        { return NameDependencyAdded }
+func (DependencyRemoved) Name() string      { return NameDependencyRemoved }
+func (EpicCreated) Name() string            { return NameEpicCreated }
+func (EpicUpdated) Name() string            { return NameEpicUpdated }
+func (EpicDeleted) Name() string            { return NameEpicDeleted }
+func (SprintStarted) Name() string          { return NameSprintStarted }
+func (SprintCompleted) Name() string        { return NameSprintCompleted }
+func (PromptTemplatesChanged) Name() string { return NamePromptsChanged }
+func (EmbeddingFailed) Name() string        { return NameEmbeddingFailed }
+func (WebhookDeliveryFailed) Name() string  { return NameWebhookFailed }
+func (TaskLeaseExpired) Name() string       { return NameTaskLeaseExpired }
+
+// FieldChange is one changed field of an update, keyed by its JSON name
+type FieldChange struct {
+	Field string      `json:"field"`
+	Old   interface{} `json:"old"`
+	New   interface{} `json:"new"`
+}
+
+// DiffTasks returns the user-editable task fields that differ between two versions
+func DiffTasks(oldTask, task *models.Task) []FieldChange {
+	var diff []FieldChange
+	add := func(field string, oldValue, newValue interface{}) {
+		if !reflect.DeepEqual(oldValue, newValue) {
+			diff = append(diff, FieldChange{Field: field, Old: oldValue, New: newValue})
+		}
+	}
+
+	add("title", oldTask.Title, task.Title)
+	add("description", oldTask.Description, task.Description)
+	add("status", oldTask.Status, task.Status)
+	add("priority", oldTask.Priority, task.Priority)
+	add("assignee_id", uintValue(oldTask.AssigneeID), uintValue(task.AssigneeID))
+	add("parent_id", uintValue(oldTask.ParentID), uintValue(task.ParentID))
+	add("epic_id", uintValue(oldTask.EpicID), uintValue(task.EpicID))
+	add("sprint_id", uintValue(oldTask.SprintID), uintValue(task.SprintID))
+	add("milestone_id", uintValue(oldTask.MilestoneID), uintValue(task.MilestoneID))
+	add("estimated_hours", floatValue(oldTask.EstimatedHours), floatValue(task.EstimatedHours))
+	add("story_points", intValue(oldTask.StoryPoints), intValue(task.StoryPoints))
+	add("due_date", timeValue(oldTask.DueDate), timeValue(task.DueDate))
+	add("start_date", timeValue(oldTask.StartDate), timeValue(task.StartDate))
+	return diff
+}
+
+func uintValue(v *uint) interface{} {
+	if v == nil {
+		return nil
+	}
+	return *v
+}
+
+func floatValue(v *float64) interface{} {
+	if v == nil {
+		return nil
+	}
+	return *v
+}
+
+func intValue(v *int) interface{} {
+	if v == nil {
+		return nil
+	}
+	return *v
+}
+
+func timeValue(v *time.Time) interface{} {
+	if v == nil {
+		return nil
+	}
+	return v.UTC().Format(time.RFC3339)
+}
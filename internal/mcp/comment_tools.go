@@ -0,0 +1,76 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+)
+
+// Comment history and reaction operations
+func (s *EnhancedMCPServer) getComment(ctx context.Context, args []byte) (*ToolResponse, error) {
+	var input struct {
+		CommentID uint `json:"comment_id"`
+	}
+	if err := UnmarshalArgs(args, &input); err != nil {
+		return ErrorResponse(err), nil
+	}
+
+	comment, err := s.db.GetCommentWithDetails(input.CommentID)
+	if err != nil {
+		return ErrorResponse(fmt.Errorf("comment not found: %w", err)), nil
+	}
+
+	return SuccessResponse(comment), nil
+}
+
+func (s *EnhancedMCPServer) getCommentHistory(ctx context.Context, args []byte) (*ToolResponse, error) {
+	var input struct {
+		CommentID uint `json:"comment_id"`
+	}
+	if err := UnmarshalArgs(args, &input); err != nil {
+		return ErrorResponse(err), nil
+	}
+
+	revisions, err := s.db.GetCommentHistory(input.CommentID)
+	if err != nil {
+		return ErrorResponse(fmt.Errorf("comment not found: %w", err)), nil
+	}
+
+	return SuccessResponse(revisions), nil
+}
+
+func (s *EnhancedMCPServer) addCommentReaction(ctx context.Context, args []byte) (*ToolResponse, error) {
+	var input struct {
+		CommentID uint   `json:"comment_id"`
+		UserID    uint   `json:"user_id"`
+		Emoji     string `json:"emoji"`
+	}
+	if err := UnmarshalArgs(args, &input); err != nil {
+		return ErrorResponse(err), nil
+	}
+
+	reaction, err := s.db.AddReaction(input.CommentID, input.UserID, input.Emoji)
+	if err != nil {
+		return ErrorResponse(err), nil
+	}
+
+	return SuccessResponse(reaction), nil
+}
+
+func (s *EnhancedMCPServer) removeCommentReaction(ctx context.Context, args []byte) (*ToolResponse, error) {
+	var input struct {
+		CommentID uint   `json:"comment_id"`
+		UserID    uint   `json:"user_id"`
+		Emoji     string `json:"emoji"`
+	}
+	if err := UnmarshalArgs(args, &input); err != nil {
+		return ErrorResponse(err), nil
+	}
+
+	if err := s.db.RemoveReaction(input.CommentID, input.UserID, input.Emoji); err != nil {
+		return ErrorResponse(err), nil
+	}
+
+	return SuccessResponse(map[string]string{
+		"message": fmt.Sprintf("Removed %s reaction of user %d from comment %d", input.Emoji, input.UserID, input.CommentID),
+	}), nil
+}
@@ -0,0 +1,443 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/headless-pm/headless-project-management/internal/models"
+	"gorm.io/gorm"
+)
+
+const (
+	// maxCompletionValues is the most values completion/complete returns, as the spec allows
+	maxCompletionValues = 100
+	// completionScanLimit bounds the rows fetched from the database per completion
+	completionScanLimit = 1000
+)
+
+// CompletionRequest is the params of completion/complete. Besides the
+// "ref/prompt" and "ref/resource" references of the spec, "ref/tool" completes
+// tool arguments.
+type CompletionRequest struct {
+	Ref struct {
+		Type string `json:"type"`
+		Name string `json:"name,omitempty"`
+		URI  string `json:"uri,omitempty"`
+	} `json:"ref"`
+	Argument struct {
+		Name  string `json:"name"`
+		Value string `json:"value"`
+	} `json:"argument"`
+	// Context holds arguments the client already resolved, e.g. the project
+	// that label completions are scoped to
+	Context struct {
+		Arguments map[string]string `json:"arguments,omitempty"`
+	} `json:"context"`
+}
+
+// Completion is the completion member of the completion/complete result
+type Completion struct {
+	Values  []string `json:"values"`
+	Total   int      `json:"total"`
+	HasMore bool     `json:"hasMore"`
+}
+
+// completionCandidate is a value that may be offered, with the texts it is
+// matched by. Entities complete to their ID or name and match both.
+type completionCandidate struct {
+	value string
+	terms []string
+}
+
+// completionSource lists the candidates matching what was typed so far
+type completionSource func(ctx context.Context, req CompletionRequest) ([]completionCandidate, error)
+
+// Complete answers completion/complete: the values for the argument being
+// typed, prefix matches first, then fuzzy ones
+func (s *EnhancedMCPServer) Complete(ctx context.Context, req CompletionRequest) (*Completion, error) {
+	source, err := s.completionSource(req)
+	if err != nil {
+		return nil, err
+	}
+
+	completion := &Completion{Values: []string{}}
+	if source == nil {
+		return completion, nil
+	}
+	candidates, err := source(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	values := rankCompletions(req.Argument.Value, candidates)
+	completion.Total = len(values)
+	if len(values) > maxCompletionValues {
+		values = values[:maxCompletionValues]
+		completion.HasMore = true
+	}
+	completion.Values = values
+	return completion, nil
+}
+
+// completionSource picks the source for the argument of a reference; nil
+// means the argument has no completions
+func (s *EnhancedMCPServer) completionSource(req CompletionRequest) (completionSource, error) {
+	argument := req.Argument.Name
+	if argument == "" {
+		return nil, fmt.Errorf("%w: argument.name", ErrMissingRequired)
+	}
+
+	switch req.Ref.Type {
+	case "ref/tool":
+		schema, ok := toolSchema(req.Ref.Name)
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown tool %s", ErrInvalidInput, req.Ref.Name)
+		}
+		properties, _ := schema["properties"].(map[string]interface{})
+		property, ok := properties[argument].(map[string]interface{})
+		if !ok {
+			return nil, nil
+		}
+		if enum := schemaEnum(property); enum != nil {
+			return staticCompletions(enum), nil
+		}
+		return s.argumentCompletions(req.Ref.Name, argument), nil
+
+	case "ref/prompt":
+		prompts, err := s.prompts.ListPrompts()
+		if err != nil {
+			return nil, err
+		}
+		for _, prompt := range prompts {
+			if prompt.Name == req.Ref.Name {
+				return s.argumentCompletions("", argument), nil
+			}
+		}
+		return nil, fmt.Errorf("%w: unknown prompt %s", ErrInvalidInput, req.Ref.Name)
+
+	case "ref/resource":
+		scheme, _, found := strings.Cut(req.Ref.URI, "://")
+		if !found {
+			return nil, fmt.Errorf("%w: ref.uri", ErrInvalidInput)
+		}
+		switch argument {
+		case "format":
+			return staticCompletions([]string{"json", "markdown"}), nil
+		case "id":
+			return s.argumentCompletions("", scheme+"_id"), nil
+		}
+		return nil, nil
+	}
+	return nil, fmt.Errorf("%w: unknown ref.type %q", ErrInvalidInput, req.Ref.Type)
+}
+
+// argumentCompletions maps argument names used across tools and prompts to
+// their source. tool is empty for prompts and resources.
+func (s *EnhancedMCPServer) argumentCompletions(tool, argument string) completionSource {
+	switch argument {
+	case "project_id":
+		return s.projectCompletions(true)
+	case "project":
+		return s.projectCompletions(false)
+	case "label_id":
+		return s.labelCompletions(true)
+	case "label", "labels":
+		return s.labelCompletions(false)
+	case "epic_id":
+		return s.epicCompletions(true)
+	case "epic":
+		return s.epicCompletions(false)
+	case "user_id", "assignee_id", "author_id":
+		return s.userCompletions(true)
+	case "user", "username", "assignee", "author":
+		return s.userCompletions(false)
+	case "priority":
+		return staticCompletions(models.GetValidTaskPriorities())
+	case "status":
+		switch {
+		case strings.Contains(tool, "project"):
+			return staticCompletions([]string{
+				string(models.ProjectStatusActive),
+				string(models.ProjectStatusArchived),
+				string(models.ProjectStatusDraft),
+			})
+		case strings.Contains(tool, "epic"):
+			return staticCompletions([]string{
+				string(models.EpicStatusPlanned),
+				string(models.EpicStatusActive),
+				string(models.EpicStatusCompleted),
+				string(models.EpicStatusCancelled),
+			})
+		}
+		return staticCompletions(models.GetValidTaskStatuses())
+	}
+	return nil
+}
+
+func staticCompletions(values []string) completionSource {
+	return func(ctx context.Context, req CompletionRequest) ([]completionCandidate, error) {
+		candidates := make([]completionCandidate, len(values))
+		for i, value := range values {
+			candidates[i] = completionCandidate{value: value, terms: []string{value}}
+		}
+		return candidates, nil
+	}
+}
+
+func schemaEnum(property map[string]interface{}) []string {
+	enum, ok := property["enum"].([]interface{})
+	if !ok {
+		return nil
+	}
+	values := make([]string, 0, len(enum))
+	for _, option := range enum {
+		values = append(values, fmt.Sprintf("%v", option))
+	}
+	return values
+}
+
+// namedRow is an entity as completions see it
+type namedRow struct {
+	ID   uint
+	Name string
+}
+
+// namedCompletions turns rows into candidates completing to their ID or name
+func namedCompletions(rows []namedRow, byID bool) []completionCandidate {
+	candidates := make([]completionCandidate, 0, len(rows))
+	for _, row := range rows {
+		id := strconv.FormatUint(uint64(row.ID), 10)
+		if byID {
+			candidates = append(candidates, completionCandidate{value: id, terms: []string{id, row.Name}})
+		} else {
+			candidates = append(candidates, completionCandidate{value: row.Name, terms: []string{row.Name, id}})
+		}
+	}
+	return candidates
+}
+
+func (s *EnhancedMCPServer) projectCompletions(byID bool) completionSource {
+	return func(ctx context.Context, req CompletionRequest) ([]completionCandidate, error) {
+		query := s.db.Model(&models.Project{}).Select("id, name")
+		query = matchNameOrID(query, "name", req.Argument.Value)
+		if access := accessFromContext(ctx); access != nil && access.projects != nil {
+			query = query.Where("id IN ?", projectIDs(access))
+		}
+
+		var rows []namedRow
+		if err := query.Limit(completionScanLimit).Scan(&rows).Error; err != nil {
+			return nil, err
+		}
+		return namedCompletions(rows, byID), nil
+	}
+}
+
+func (s *EnhancedMCPServer) labelCompletions(byID bool) completionSource {
+	return func(ctx context.Context, req CompletionRequest) ([]completionCandidate, error) {
+		query := s.db.Model(&models.Label{}).Select("id, name")
+		query = matchNameOrID(query, "name", req.Argument.Value)
+		query = s.scopeToProject(ctx, query, req)
+
+		var rows []namedRow
+		if err := query.Limit(completionScanLimit).Scan(&rows).Error; err != nil {
+			return nil, err
+		}
+		if !byID {
+			// Projects often share label names; offer each name once
+			rows = uniqueNames(rows)
+		}
+		return namedCompletions(rows, byID), nil
+	}
+}
+
+func (s *EnhancedMCPServer) epicCompletions(byID bool) completionSource {
+	return func(ctx context.Context, req CompletionRequest) ([]completionCandidate, error) {
+		query := s.db.Model(&models.Epic{}).Select("id, name")
+		query = matchNameOrID(query, "name", req.Argument.Value)
+		query = s.scopeToProject(ctx, query, req)
+
+		var rows []namedRow
+		if err := query.Limit(completionScanLimit).Scan(&rows).Error; err != nil {
+			return nil, err
+		}
+		return namedCompletions(rows, byID), nil
+	}
+}
+
+func (s *EnhancedMCPServer) userCompletions(byID bool) completionSource {
+	return func(ctx context.Context, req CompletionRequest) ([]completionCandidate, error) {
+		query := s.db.Model(&models.User{}).Select("id, username AS name")
+		query = matchNameOrID(query, "username", req.Argument.Value)
+
+		var rows []namedRow
+		if err := query.Limit(completionScanLimit).Scan(&rows).Error; err != nil {
+			return nil, err
+		}
+		return namedCompletions(rows, byID), nil
+	}
+}
+
+// scopeToProject restricts project data to the project named by the
+// project_id or project argument of the context, and to the projects the
+// caller's token may see
+func (s *EnhancedMCPServer) scopeToProject(ctx context.Context, query *gorm.DB, req CompletionRequest) *gorm.DB {
+	if access := accessFromContext(ctx); access != nil && access.projects != nil {
+		query = query.Where("project_id IN ?", projectIDs(access))
+	}
+
+	project := req.Context.Arguments["project_id"]
+	if project == "" {
+		project = req.Context.Arguments["project"]
+	}
+	if project == "" {
+		return query
+	}
+	if id, err := strconv.ParseUint(project, 10, 32); err == nil {
+		return query.Where("project_id = ?", id)
+	}
+	return query.Where("project_id IN (?)", s.db.Model(&models.Project{}).Select("id").Where("LOWER(name) = LOWER(?)", project))
+}
+
+// matchNameOrID narrows a query to rows whose ID starts with value or whose
+// name contains the characters of value in order. Ranking the matches is up
+// to rankCompletions.
+func matchNameOrID(query *gorm.DB, column, value string) *gorm.DB {
+	if value == "" {
+		return query
+	}
+	var pattern strings.Builder
+	pattern.WriteString("%")
+	for _, r := range strings.ToLower(value) {
+		if r == '%' || r == '_' || r == '\\' {
+			pattern.WriteRune('\\')
+		}
+		pattern.WriteRune(r)
+		pattern.WriteString("%")
+	}
+	return query.Where("(LOWER("+column+") LIKE ? ESCAPE '\\' OR CAST(id AS TEXT) LIKE ?)", pattern.String(), value+"%")
+}
+
+func projectIDs(access *callerAccess) []uint {
+	ids := make([]uint, 0, len(access.projects))
+	for id := range access.projects {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+func uniqueNames(rows []namedRow) []namedRow {
+	seen := make(map[string]bool, len(rows))
+	unique := rows[:0]
+	for _, row := range rows {
+		if !seen[row.Name] {
+			seen[row.Name] = true
+			unique = append(unique, row)
+		}
+	}
+	return unique
+}
+
+// Match qualities, best first
+const (
+	matchExact = iota
+	matchPrefix
+	matchWordPrefix
+	matchSubstring
+	matchFuzzy
+	noMatch
+)
+
+// rankCompletions orders the candidates matching typed by how well they
+// match: exact, prefix, start of a word, substring, then characters in order.
+// Equal matches are ordered by value.
+func rankCompletions(typed string, candidates []completionCandidate) []string {
+	type ranked struct {
+		value string
+		match int
+	}
+	typed = strings.ToLower(typed)
+
+	best := make(map[string]int)
+	for _, candidate := range candidates {
+		match := noMatch
+		for _, term := range candidate.terms {
+			if m := matchQuality(typed, strings.ToLower(term)); m < match {
+				match = m
+			}
+		}
+		if match == noMatch {
+			continue
+		}
+		if previous, seen := best[candidate.value]; !seen || match < previous {
+			best[candidate.value] = match
+		}
+	}
+
+	matches := make([]ranked, 0, len(best))
+	for value, match := range best {
+		matches = append(matches, ranked{value: value, match: match})
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].match != matches[j].match {
+			return matches[i].match < matches[j].match
+		}
+		return lessNatural(matches[i].value, matches[j].value)
+	})
+
+	values := make([]string, len(matches))
+	for i, m := range matches {
+		values[i] = m.value
+	}
+	return values
+}
+
+func matchQuality(typed, term string) int {
+	switch {
+	case typed == term:
+		return matchExact
+	case strings.HasPrefix(term, typed):
+		return matchPrefix
+	case strings.Contains(" "+strings.Map(wordSeparator, term), " "+typed):
+		return matchWordPrefix
+	case strings.Contains(term, typed):
+		return matchSubstring
+	case isSubsequence(typed, term):
+		return matchFuzzy
+	}
+	return noMatch
+}
+
+// wordSeparator maps the characters separating words in names to spaces
+func wordSeparator(r rune) rune {
+	switch r {
+	case '-', '_', '.', '/', ':':
+		return ' '
+	}
+	return r
+}
+
+func isSubsequence(typed, term string) bool {
+	remaining := []rune(typed)
+	for _, r := range term {
+		if len(remaining) == 0 {
+			break
+		}
+		if r == remaining[0] {
+			remaining = remaining[1:]
+		}
+	}
+	return len(remaining) == 0
+}
+
+// lessNatural orders numbers numerically, so ID 9 comes before ID 10
+func lessNatural(a, b string) bool {
+	x, errA := strconv.ParseUint(a, 10, 64)
+	y, errB := strconv.ParseUint(b, 10, 64)
+	if errA == nil && errB == nil {
+		return x < y
+	}
+	return strings.ToLower(a) < strings.ToLower(b)
+}
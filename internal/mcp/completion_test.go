@@ -0,0 +1,53 @@
+package mcp
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestCompletion(t *testing.T) {
+	_, handler := newTestServer(t)
+	mustCallTool(t, handler, nil, "create_project", `{"name":"Website Redesign"}`)
+	mustCallTool(t, handler, nil, "create_project", `{"name":"Mobile App"}`)
+	mustCallTool(t, handler, nil, "create_label", `{"project_id":1,"name":"frontend"}`)
+	mustCallTool(t, handler, nil, "create_label", `{"project_id":2,"name":"firmware"}`)
+	mustCallTool(t, handler, nil, "create_user", `{"username":"alice","email":"alice@example.com","password":"secret123"}`)
+
+	complete := func(t *testing.T, params string) []interface{} {
+		t.Helper()
+		exchange := post(t, handler, `{"jsonrpc":"2.0","id":1,"method":"completion/complete","params":`+params+`}`, nil)
+		completion := result(t, exchange.message)["completion"].(map[string]interface{})
+		return completion["values"].([]interface{})
+	}
+
+	tests := []struct {
+		name   string
+		params string
+		want   []interface{}
+	}{
+		{"project IDs by name prefix", `{"ref":{"type":"ref/tool","name":"list_tasks"},"argument":{"name":"project_id","value":"mob"}}`, []interface{}{"2"}},
+		{"project names fuzzily", `{"ref":{"type":"ref/prompt","name":"daily_standup"},"argument":{"name":"project","value":"wbrd"}}`, []interface{}{"Website Redesign"}},
+		{"labels scoped to a project", `{"ref":{"type":"ref/tool","name":"create_task"},"argument":{"name":"labels","value":"f"},"context":{"arguments":{"project_id":"2"}}}`, []interface{}{"firmware"}},
+		{"prefix matches before fuzzy ones", `{"ref":{"type":"ref/tool","name":"create_task"},"argument":{"name":"labels","value":"fr"}}`, []interface{}{"frontend", "firmware"}},
+		{"usernames", `{"ref":{"type":"ref/tool","name":"list_tasks"},"argument":{"name":"assignee_id","value":"ali"}}`, []interface{}{"1"}},
+		{"task statuses", `{"ref":{"type":"ref/tool","name":"update_task"},"argument":{"name":"status","value":"in"}}`, []interface{}{"in_progress"}},
+		{"task priorities", `{"ref":{"type":"ref/tool","name":"create_task"},"argument":{"name":"priority","value":"h"}}`, []interface{}{"high"}},
+		{"resource template variables", `{"ref":{"type":"ref/resource","uri":"project://{id}"},"argument":{"name":"id","value":"web"}}`, []interface{}{"1"}},
+		{"arguments without completions", `{"ref":{"type":"ref/tool","name":"create_task"},"argument":{"name":"title","value":"a"}}`, []interface{}{}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := complete(t, tc.params)
+			if fmt.Sprint(got) != fmt.Sprint(tc.want) {
+				t.Errorf("got %v, want %v", got, tc.want)
+			}
+		})
+	}
+
+	t.Run("unknown references are invalid params", func(t *testing.T) {
+		exchange := post(t, handler, `{"jsonrpc":"2.0","id":1,"method":"completion/complete","params":{"ref":{"type":"ref/tool","name":"no_such_tool"},"argument":{"name":"x","value":""}}}`, nil)
+		if code := errorCode(t, exchange.message); code != codeInvalidParams {
+			t.Errorf("got code %d, want %d", code, codeInvalidParams)
+		}
+	})
+}
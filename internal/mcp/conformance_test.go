@@ -0,0 +1,399 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// Conformance tests for the JSON-RPC 2.0 and MCP lifecycle behaviour of the
+// Streamable HTTP and stdio transports
+
+func TestConformanceLifecycle(t *testing.T) {
+	_, handler := newTestServer(t)
+
+	t.Run("initialize negotiates a supported version", func(t *testing.T) {
+		for _, version := range supportedProtocolVersions {
+			_, res := initialize(t, handler, version)
+			if res["protocolVersion"] != version {
+				t.Errorf("requested %s, got %v", version, res["protocolVersion"])
+			}
+		}
+	})
+
+	t.Run("initialize answers unknown versions with the latest", func(t *testing.T) {
+		_, res := initialize(t, handler, "1999-01-01")
+		if res["protocolVersion"] != "2025-06-18" {
+			t.Errorf("got %v, want 2025-06-18", res["protocolVersion"])
+		}
+	})
+
+	t.Run("initialize declares server info and capabilities", func(t *testing.T) {
+		_, res := initialize(t, handler, "2025-03-26")
+		if _, ok := res["serverInfo"].(map[string]interface{}); !ok {
+			t.Error("serverInfo missing")
+		}
+		capabilities, ok := res["capabilities"].(map[string]interface{})
+		if !ok {
+			t.Fatal("capabilities missing")
+		}
+		for _, capability := range []string{"tools", "resources", "prompts", "logging"} {
+			if _, ok := capabilities[capability]; !ok {
+				t.Errorf("capability %s missing", capability)
+			}
+		}
+	})
+
+	t.Run("initialized notification is accepted without a body", func(t *testing.T) {
+		sessionID, _ := initialize(t, handler, "2025-03-26")
+		exchange := post(t, handler, `{"jsonrpc":"2.0","method":"notifications/initialized"}`, map[string]string{sessionHeader: sessionID})
+		if exchange.status != http.StatusAccepted || len(exchange.body) != 0 {
+			t.Errorf("got status %d with body %q, want 202 without body", exchange.status, exchange.body)
+		}
+	})
+
+	t.Run("ping returns an empty result", func(t *testing.T) {
+		sessionID, _ := initialize(t, handler, "2025-03-26")
+		exchange := post(t, handler, `{"jsonrpc":"2.0","id":"p","method":"ping"}`, map[string]string{sessionHeader: sessionID})
+		if res := result(t, exchange.message); len(res) != 0 {
+			t.Errorf("got %v, want {}", res)
+		}
+		if exchange.message["id"] != "p" {
+			t.Errorf("got id %v, want p", exchange.message["id"])
+		}
+	})
+
+	t.Run("unknown session is not found", func(t *testing.T) {
+		exchange := post(t, handler, `{"jsonrpc":"2.0","id":1,"method":"ping"}`, map[string]string{sessionHeader: "missing"})
+		if exchange.status != http.StatusNotFound {
+			t.Errorf("got status %d, want 404", exchange.status)
+		}
+	})
+
+	t.Run("protocol version header must match the session", func(t *testing.T) {
+		sessionID, _ := initialize(t, handler, "2024-11-05")
+		exchange := post(t, handler, `{"jsonrpc":"2.0","id":1,"method":"ping"}`, map[string]string{sessionHeader: sessionID, protocolVersionHeader: "2025-03-26"})
+		if exchange.status != http.StatusBadRequest {
+			t.Errorf("mismatched version: got status %d, want 400", exchange.status)
+		}
+		exchange = post(t, handler, `{"jsonrpc":"2.0","id":1,"method":"ping"}`, map[string]string{protocolVersionHeader: "1999-01-01"})
+		if exchange.status != http.StatusBadRequest {
+			t.Errorf("unsupported version: got status %d, want 400", exchange.status)
+		}
+		exchange = post(t, handler, `{"jsonrpc":"2.0","id":1,"method":"ping"}`, map[string]string{sessionHeader: sessionID, protocolVersionHeader: "2024-11-05"})
+		if exchange.status != http.StatusOK {
+			t.Errorf("negotiated version: got status %d, want 200", exchange.status)
+		}
+	})
+
+	t.Run("session ends with DELETE", func(t *testing.T) {
+		sessionID, _ := initialize(t, handler, "2025-03-26")
+		req := httptest.NewRequest(http.MethodDelete, "/mcp", nil)
+		req.Header.Set(sessionHeader, sessionID)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != http.StatusNoContent {
+			t.Fatalf("got status %d, want 204", rec.Code)
+		}
+		exchange := post(t, handler, `{"jsonrpc":"2.0","id":1,"method":"ping"}`, map[string]string{sessionHeader: sessionID})
+		if exchange.status != http.StatusNotFound {
+			t.Errorf("after DELETE: got status %d, want 404", exchange.status)
+		}
+	})
+}
+
+func TestConformanceJSONRPC(t *testing.T) {
+	_, handler := newTestServer(t)
+
+	invalid := []struct {
+		name string
+		body string
+		code int
+		id   interface{}
+	}{
+		{"parse error", `{"jsonrpc":"2.0","method":`, codeParseError, nil},
+		{"empty body", ``, codeParseError, nil},
+		{"not an object", `42`, codeInvalidRequest, nil},
+		{"missing jsonrpc", `{"id":1,"method":"ping"}`, codeInvalidRequest, float64(1)},
+		{"wrong jsonrpc version", `{"jsonrpc":"1.0","id":1,"method":"ping"}`, codeInvalidRequest, float64(1)},
+		{"method not a string", `{"jsonrpc":"2.0","id":1,"method":1}`, codeInvalidRequest, float64(1)},
+		{"id an object", `{"jsonrpc":"2.0","id":{},"method":"ping"}`, codeInvalidRequest, nil},
+		{"params a string", `{"jsonrpc":"2.0","id":1,"method":"ping","params":"x"}`, codeInvalidRequest, float64(1)},
+		{"empty batch", `[]`, codeInvalidRequest, nil},
+	}
+	for _, tc := range invalid {
+		t.Run(tc.name, func(t *testing.T) {
+			exchange := post(t, handler, tc.body, nil)
+			if exchange.status != http.StatusBadRequest {
+				t.Errorf("got status %d, want 400", exchange.status)
+			}
+			if code := errorCode(t, exchange.message); code != tc.code {
+				t.Errorf("got code %d, want %d", code, tc.code)
+			}
+			if id, present := exchange.message["id"]; !present || id != tc.id {
+				t.Errorf("got id %v, want %v", id, tc.id)
+			}
+		})
+	}
+
+	t.Run("unknown method", func(t *testing.T) {
+		exchange := post(t, handler, `{"jsonrpc":"2.0","id":7,"method":"no/such/method"}`, nil)
+		if code := errorCode(t, exchange.message); code != codeMethodNotFound {
+			t.Errorf("got code %d, want %d", code, codeMethodNotFound)
+		}
+		if exchange.message["id"] != float64(7) {
+			t.Errorf("got id %v, want 7", exchange.message["id"])
+		}
+	})
+
+	t.Run("ids are echoed exactly", func(t *testing.T) {
+		for _, id := range []string{`"abc"`, `9007199254740993`, `0`, `-1`, `null`} {
+			exchange := post(t, handler, `{"jsonrpc":"2.0","id":`+id+`,"method":"ping"}`, nil)
+			if !bytes.Contains(exchange.body, []byte(`"id":`+id)) {
+				t.Errorf("id %s: got %s", id, exchange.body)
+			}
+		}
+	})
+
+	t.Run("notifications get no response", func(t *testing.T) {
+		for _, body := range []string{
+			`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+			`{"jsonrpc":"2.0","method":"notifications/cancelled","params":{"requestId":1}}`,
+			`{"jsonrpc":"2.0","method":"ping"}`,
+			`{"jsonrpc":"2.0","method":"no/such/method"}`,
+		} {
+			exchange := post(t, handler, body, nil)
+			if exchange.status != http.StatusAccepted || len(exchange.body) != 0 {
+				t.Errorf("%s: got status %d with body %q, want 202 without body", body, exchange.status, exchange.body)
+			}
+		}
+	})
+
+	t.Run("batch answers each request in order", func(t *testing.T) {
+		exchange := post(t, handler, `[
+			{"jsonrpc":"2.0","id":1,"method":"ping"},
+			{"jsonrpc":"2.0","method":"notifications/initialized"},
+			{"jsonrpc":"2.0","id":"two","method":"tools/list"},
+			{"jsonrpc":"2.0","id":3,"method":"no/such/method"},
+			{"foo":"bar"}
+		]`, nil)
+		if exchange.status != http.StatusOK {
+			t.Fatalf("got status %d, want 200", exchange.status)
+		}
+		if len(exchange.batch) != 4 {
+			t.Fatalf("got %d responses, want 4: %s", len(exchange.batch), exchange.body)
+		}
+		if exchange.batch[0]["id"] != float64(1) || exchange.batch[0]["result"] == nil {
+			t.Errorf("ping: %v", exchange.batch[0])
+		}
+		if exchange.batch[1]["id"] != "two" || result(t, exchange.batch[1])["tools"] == nil {
+			t.Errorf("tools/list: %v", exchange.batch[1])
+		}
+		if errorCode(t, exchange.batch[2]) != codeMethodNotFound {
+			t.Errorf("unknown method: %v", exchange.batch[2])
+		}
+		if errorCode(t, exchange.batch[3]) != codeInvalidRequest || exchange.batch[3]["id"] != nil {
+			t.Errorf("invalid request: %v", exchange.batch[3])
+		}
+	})
+
+	t.Run("batch of notifications gets no response", func(t *testing.T) {
+		exchange := post(t, handler, `[{"jsonrpc":"2.0","method":"notifications/initialized"},{"jsonrpc":"2.0","method":"ping"}]`, nil)
+		if exchange.status != http.StatusAccepted || len(exchange.body) != 0 {
+			t.Errorf("got status %d with body %q, want 202 without body", exchange.status, exchange.body)
+		}
+	})
+
+	t.Run("initialize is not allowed in a batch", func(t *testing.T) {
+		exchange := post(t, handler, `[{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26"}}]`, nil)
+		if len(exchange.batch) != 1 || errorCode(t, exchange.batch[0]) != codeInvalidRequest {
+			t.Errorf("got %s", exchange.body)
+		}
+		if exchange.header.Get(sessionHeader) != "" {
+			t.Error("a batched initialize created a session")
+		}
+	})
+}
+
+func TestConformanceTools(t *testing.T) {
+	_, handler := newTestServer(t)
+
+	t.Run("tool failures are results with isError", func(t *testing.T) {
+		exchange := post(t, handler, `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"get_task","arguments":{"task_id":12345}}}`, nil)
+		res := result(t, exchange.message)
+		if res["isError"] != true {
+			t.Errorf("got %v, want isError", res)
+		}
+		content, _ := res["content"].([]interface{})
+		if len(content) != 1 || content[0].(map[string]interface{})["type"] != "text" {
+			t.Errorf("got content %v, want one text item", res["content"])
+		}
+	})
+
+	t.Run("successful calls return text content", func(t *testing.T) {
+		exchange := post(t, handler, `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"create_project","arguments":{"name":"Conformance"}}}`, nil)
+		res := result(t, exchange.message)
+		if res["isError"] != nil {
+			t.Errorf("got isError for %v", res)
+		}
+		content := res["content"].([]interface{})[0].(map[string]interface{})
+		if !strings.Contains(content["text"].(string), "Conformance") {
+			t.Errorf("got %v", content)
+		}
+		structured, ok := res["structuredContent"].(map[string]interface{})
+		if !ok || structured["name"] != "Conformance" {
+			t.Errorf("got structuredContent %v", res["structuredContent"])
+		}
+	})
+
+	t.Run("lists are structured as items", func(t *testing.T) {
+		exchange := post(t, handler, `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"list_users"}}`, nil)
+		structured, _ := result(t, exchange.message)["structuredContent"].(map[string]interface{})
+		if _, ok := structured["items"].([]interface{}); !ok {
+			t.Errorf("got structuredContent %v", structured)
+		}
+	})
+
+	t.Run("arguments are validated against the input schema", func(t *testing.T) {
+		exchange := post(t, handler, `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"create_task","arguments":{"title":5,"labels":["a",2],"colour":"red"}}}`, nil)
+		res := result(t, exchange.message)
+		if res["isError"] != true {
+			t.Fatalf("got %v, want isError", res)
+		}
+		var content struct {
+			Fields []FieldError `json:"fields"`
+		}
+		text := res["content"].([]interface{})[0].(map[string]interface{})["text"].(string)
+		if err := json.Unmarshal([]byte(text), &content); err != nil {
+			t.Fatalf("field errors are not JSON: %s", text)
+		}
+		got := make(map[string]bool)
+		for _, field := range content.Fields {
+			got[field.Field] = true
+		}
+		for _, field := range []string{"colour", "labels[1]", "project_id", "title"} {
+			if !got[field] {
+				t.Errorf("no error for %s in %s", field, text)
+			}
+		}
+	})
+
+	t.Run("unknown tools are invalid params", func(t *testing.T) {
+		exchange := post(t, handler, `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"no_such_tool"}}`, nil)
+		if code := errorCode(t, exchange.message); code != codeInvalidParams {
+			t.Errorf("got code %d, want %d", code, codeInvalidParams)
+		}
+	})
+
+	t.Run("missing tool name is invalid params", func(t *testing.T) {
+		exchange := post(t, handler, `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{}}`, nil)
+		if code := errorCode(t, exchange.message); code != codeInvalidParams {
+			t.Errorf("got code %d, want %d", code, codeInvalidParams)
+		}
+	})
+
+	t.Run("tools have a name, schemas and annotations", func(t *testing.T) {
+		exchange := post(t, handler, `{"jsonrpc":"2.0","id":1,"method":"tools/list"}`, nil)
+		for _, item := range result(t, exchange.message)["tools"].([]interface{}) {
+			tool := item.(map[string]interface{})
+			schema, ok := tool["inputSchema"].(map[string]interface{})
+			if tool["name"] == "" || !ok || schema["type"] != "object" {
+				t.Errorf("invalid tool definition %v", tool)
+			}
+			if output, ok := tool["outputSchema"].(map[string]interface{}); !ok || output["type"] != "object" {
+				t.Errorf("%v has no object output schema", tool["name"])
+			}
+			annotations, ok := tool["annotations"].(map[string]interface{})
+			if !ok {
+				t.Errorf("%v has no annotations", tool["name"])
+				continue
+			}
+			for _, hint := range []string{"readOnlyHint", "destructiveHint", "idempotentHint", "openWorldHint"} {
+				if _, ok := annotations[hint].(bool); !ok {
+					t.Errorf("%v has no %s", tool["name"], hint)
+				}
+			}
+		}
+	})
+}
+
+func TestConformanceResources(t *testing.T) {
+	_, handler := newTestServer(t)
+
+	t.Run("read returns text contents", func(t *testing.T) {
+		exchange := post(t, handler, `{"jsonrpc":"2.0","id":1,"method":"resources/read","params":{"uri":"projects://list"}}`, nil)
+		contents := result(t, exchange.message)["contents"].([]interface{})
+		if len(contents) != 1 {
+			t.Fatalf("got %d contents, want 1", len(contents))
+		}
+		content := contents[0].(map[string]interface{})
+		if content["uri"] != "projects://list" || content["mimeType"] != "application/json" {
+			t.Errorf("got %v", content)
+		}
+		if _, ok := content["text"].(string); !ok {
+			t.Errorf("got %v, want text", content)
+		}
+	})
+
+	t.Run("unknown resources are not found", func(t *testing.T) {
+		exchange := post(t, handler, `{"jsonrpc":"2.0","id":1,"method":"resources/read","params":{"uri":"nothing://here"}}`, nil)
+		if code := errorCode(t, exchange.message); code != codeResourceNotFound {
+			t.Errorf("got code %d, want %d", code, codeResourceNotFound)
+		}
+	})
+
+	t.Run("subscriptions need a session", func(t *testing.T) {
+		exchange := post(t, handler, `{"jsonrpc":"2.0","id":1,"method":"resources/subscribe","params":{"uri":"projects://list"}}`, nil)
+		if code := errorCode(t, exchange.message); code != codeInvalidRequest {
+			t.Errorf("got code %d, want %d", code, codeInvalidRequest)
+		}
+	})
+}
+
+func TestConformanceStdio(t *testing.T) {
+	server, _ := newTestServer(t)
+
+	input := strings.Join([]string{
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05"}}`,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+		`not json`,
+		`[{"jsonrpc":"2.0","id":2,"method":"ping"},{"jsonrpc":"2.0","method":"ping"}]`,
+		`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"get_task","arguments":{"task_id":12345}}}`,
+	}, "\n")
+	var out bytes.Buffer
+	if err := server.ServeStdio(context.Background(), strings.NewReader(input), &out, nil); err != nil {
+		t.Fatalf("ServeStdio: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("got %d lines, want 4:\n%s", len(lines), out.String())
+	}
+
+	var initialized map[string]interface{}
+	_ = json.Unmarshal([]byte(lines[0]), &initialized)
+	if result(t, initialized)["protocolVersion"] != "2024-11-05" {
+		t.Errorf("initialize: %s", lines[0])
+	}
+
+	var parseError map[string]interface{}
+	_ = json.Unmarshal([]byte(lines[1]), &parseError)
+	if errorCode(t, parseError) != codeParseError {
+		t.Errorf("parse error: %s", lines[1])
+	}
+
+	var batch []map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[2]), &batch); err != nil || len(batch) != 1 || batch[0]["id"] != float64(2) {
+		t.Errorf("batch: %s", lines[2])
+	}
+
+	var toolError map[string]interface{}
+	_ = json.Unmarshal([]byte(lines[3]), &toolError)
+	if result(t, toolError)["isError"] != true {
+		t.Errorf("tool error: %s", lines[3])
+	}
+}
@@ -0,0 +1,69 @@
+package mcp
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/headless-pm/headless-project-management/internal/database"
+	"github.com/headless-pm/headless-project-management/internal/service"
+)
+
+// deletionArgs are the arguments shared by the destructive delete tools. A
+// dry run reports what would be deleted and returns the confirmation token
+// the actual deletion needs.
+type deletionArgs struct {
+	DryRun            bool   `json:"dry_run,omitempty"`
+	ConfirmationToken string `json:"confirmation_token,omitempty"`
+}
+
+// runDeletion previews or performs a deletion. result holds the fields that
+// identify the target and is extended with the status and the impact.
+func (s *EnhancedMCPServer) runDeletion(target service.DeletionTarget, args deletionArgs, result map[string]interface{}) *ToolResponse {
+	if args.DryRun {
+		plan, err := s.deletions.Preview(target)
+		if err != nil {
+			return ErrorResponse(fmt.Errorf("failed to preview %s deletion: %w", target.Entity, err))
+		}
+		result["status"] = "dry_run"
+		result["impact"] = plan.Impact
+		result["confirmation_token"] = plan.ConfirmationToken
+		result["expires_at"] = plan.ExpiresAt
+		return SuccessResponse(result)
+	}
+
+	impact, err := s.deletions.Delete(target, args.ConfirmationToken)
+	if err != nil {
+		return ErrorResponse(fmt.Errorf("failed to delete %s: %w", target.Entity, err))
+	}
+	result["status"] = "deleted"
+	result["impact"] = impact
+	return SuccessResponse(result)
+}
+
+// deletionFlow explains the two-step deletion in tool descriptions
+const deletionFlow = "Call with dry_run first to see what would be deleted, then call again with the confirmation_token it returns; tokens expire after 5 minutes"
+
+// withDeletionArgs adds the dry run and confirmation arguments to the
+// properties of a delete tool's input schema
+func withDeletionArgs(properties map[string]interface{}) map[string]interface{} {
+	properties["dry_run"] = map[string]string{"type": "boolean", "description": "Only report what would be deleted and return a confirmation token"}
+	properties["confirmation_token"] = map[string]string{"type": "string", "description": "Token from a dry run of the same deletion, required to delete"}
+	return properties
+}
+
+// deletionOutput describes the results of delete tools: the target's
+// identifying fields, the status and the impact, plus the confirmation token
+// for dry runs
+func deletionOutput(target map[string]interface{}) map[string]interface{} {
+	required := []string{"status", "impact"}
+	for name := range target {
+		required = append(required, name)
+	}
+	sort.Strings(required[2:])
+
+	target["status"] = map[string]interface{}{"type": "string", "enum": []string{"dry_run", "deleted"}}
+	target["impact"] = objectOutput(database.DeletionImpact{})
+	target["confirmation_token"] = typed("string")
+	target["expires_at"] = map[string]interface{}{"type": "string", "format": "date-time"}
+	return fieldsOutput(target, required...)
+}
@@ -0,0 +1,49 @@
+package mcp
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestDeleteConfirmation(t *testing.T) {
+	_, handler := newTestServer(t)
+	call := func(tool, arguments string) map[string]interface{} {
+		t.Helper()
+		return callTool(t, handler, nil, tool, arguments)
+	}
+	project := structured(call("create_project", `{"name":"Deletion"}`))
+	task := structured(call("create_task", fmt.Sprintf(`{"project_id":%v,"title":"Blocker"}`, project["id"])))
+	blocked := structured(call("create_task", fmt.Sprintf(`{"project_id":%v,"title":"Blocked"}`, project["id"])))
+	call("add_task_dependency", fmt.Sprintf(`{"task_id":%v,"depends_on_id":%v}`, blocked["id"], task["id"]))
+	target := fmt.Sprintf(`"task_id":%v`, task["id"])
+
+	if res := call("delete_task", `{`+target+`}`); res["isError"] != true {
+		t.Fatalf("deleted without confirmation: %v", res)
+	}
+
+	plan := structured(call("delete_task", `{`+target+`,"dry_run":true}`))
+	impact := plan["impact"].(map[string]interface{})
+	if plan["status"] != "dry_run" || impact["deletes"].(map[string]interface{})["tasks"] != float64(1) {
+		t.Fatalf("got dry run %v, want one task", plan)
+	}
+	if orphaned, _ := impact["orphaned_dependencies"].([]interface{}); len(orphaned) != 1 {
+		t.Errorf("got orphaned dependencies %v, want the one of the blocked task", impact["orphaned_dependencies"])
+	}
+	token := plan["confirmation_token"].(string)
+
+	if res := call("delete_task", `{`+target+`,"confirmation_token":"`+token+`x"}`); res["isError"] != true {
+		t.Fatalf("deleted with a forged token: %v", res)
+	}
+	call("add_comment", `{`+target+`,"content":"Changes the impact","author":"tester"}`)
+	if res := call("delete_task", `{`+target+`,"confirmation_token":"`+token+`"}`); res["isError"] != true {
+		t.Fatalf("deleted although the impact changed: %v", res)
+	}
+
+	token = structured(call("delete_task", `{`+target+`,"dry_run":true}`))["confirmation_token"].(string)
+	if deleted := call("delete_task", `{`+target+`,"confirmation_token":"`+token+`"}`); structured(deleted)["status"] != "deleted" {
+		t.Fatalf("got %v, want the task deleted", deleted)
+	}
+	if res := call("get_task", `{`+target+`}`); res["isError"] != true {
+		t.Errorf("task still exists: %v", res)
+	}
+}
@@ -0,0 +1,61 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/headless-pm/headless-project-management/internal/database"
+	"github.com/headless-pm/headless-project-management/internal/models"
+	"github.com/headless-pm/headless-project-management/internal/service"
+)
+
+// Activity digests
+func (s *EnhancedMCPServer) getActivityDigest(ctx context.Context, args []byte) (*ToolResponse, error) {
+	var input struct {
+		UserID    *uint  `json:"user_id"`
+		ProjectID *uint  `json:"project_id"`
+		UserType  string `json:"user_type"`
+		Since     string `json:"since"`
+		Until     string `json:"until"`
+		Format    string `json:"format"`
+	}
+	if err := UnmarshalArgs(args, &input); err != nil {
+		return ErrorResponse(err), nil
+	}
+	if input.Since == "" {
+		input.Since = "24h"
+	}
+
+	now := time.Now()
+	from, err := service.ParseRelativeTime(input.Since, now)
+	if err != nil {
+		return ErrorResponse(fmt.Errorf("since: %w", err)), nil
+	}
+	to, err := service.ParseRelativeTime(input.Until, now)
+	if err != nil {
+		return ErrorResponse(fmt.Errorf("until: %w", err)), nil
+	}
+
+	digest, err := s.db.GetActivityDigest(database.DigestFilter{
+		From:      from,
+		To:        to,
+		UserID:    input.UserID,
+		ProjectID: input.ProjectID,
+		UserType:  models.UserType(input.UserType),
+	})
+	if err != nil {
+		return ErrorResponse(err), nil
+	}
+
+	if input.Format == "markdown" {
+		var buf bytes.Buffer
+		if err := digest.WriteMarkdown(&buf); err != nil {
+			return ErrorResponse(err), nil
+		}
+		return SuccessResponse(buf.String()), nil
+	}
+
+	return SuccessResponse(digest), nil
+}
@@ -0,0 +1,100 @@
+package mcp
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestActivityDigest(t *testing.T) {
+	_, handler := newTestServer(t)
+	call := func(t *testing.T, headers map[string]string, tool, arguments string) map[string]interface{} {
+		t.Helper()
+		return mustCallTool(t, handler, headers, tool, arguments)
+	}
+	call(t, nil, "create_user", `{"username":"alice","email":"alice@example.com","password":"secret123"}`)
+	call(t, nil, "create_user", `{"username":"builder","email":"builder@example.com","password":"secret123","type":"agent"}`)
+
+	agent := openSession(t, handler, "2", "agent-cli")
+
+	call(t, agent, "create_project", `{"name":"Digest"}`)
+	call(t, agent, "create_task", `{"project_id":1,"title":"Spec"}`)
+	call(t, agent, "create_task", `{"project_id":1,"title":"Build","assignee_id":2}`)
+	call(t, agent, "update_task", `{"task_id":1,"status":"in_progress"}`)
+	call(t, agent, "update_task", `{"task_id":1,"status":"done"}`)
+	call(t, agent, "update_task", `{"task_id":2,"status":"in_progress"}`)
+	call(t, nil, "create_task", `{"project_id":1,"title":"Deploy","assignee_id":1}`)
+	call(t, nil, "add_task_dependency", `{"task_id":3,"depends_on_id":2}`)
+	call(t, nil, "add_task_dependency", `{"task_id":3,"depends_on_id":1}`)
+	call(t, nil, "add_comment", `{"task_id":2,"content":"Looks good","author_id":1}`)
+	call(t, nil, "add_comment", `{"task_id":2,"content":"Ship it","author_id":1}`)
+
+	digest := func(t *testing.T, arguments string) map[string]interface{} {
+		t.Helper()
+		return structured(call(t, nil, "get_activity_digest", arguments))
+	}
+	ids := func(tasks interface{}) []float64 {
+		var ids []float64
+		for _, task := range tasks.([]interface{}) {
+			ids = append(ids, task.(map[string]interface{})["id"].(float64))
+		}
+		return ids
+	}
+
+	t.Run("summarises each user", func(t *testing.T) {
+		users := digest(t, `{}`)["users"].([]interface{})
+		if len(users) != 2 {
+			t.Fatalf("got %d users, want alice and the agent", len(users))
+		}
+		alice, agent := users[0].(map[string]interface{}), users[1].(map[string]interface{})
+		if alice["user_name"] != "alice" || agent["user_name"] != "builder" || agent["type"] != "agent" {
+			t.Fatalf("users are %v and %v", alice["user_name"], agent["user_name"])
+		}
+
+		if got := fmt.Sprint(agent["clients"]); got != "[agent-cli 2.1]" {
+			t.Errorf("agent clients are %s", got)
+		}
+		for section, want := range map[string]string{"completed": "[1]", "started": "[1 2]", "created": "[1 2]", "blocked": "[]"} {
+			if got := fmt.Sprint(ids(agent[section])); got != want {
+				t.Errorf("agent %s %s, want %s", section, got, want)
+			}
+		}
+
+		commented := alice["commented"].([]interface{})
+		if len(commented) != 1 || commented[0].(map[string]interface{})["comments"] != float64(2) {
+			t.Errorf("alice commented on %v, want two comments on task 2", commented)
+		}
+		blocked := alice["blocked"].([]interface{})
+		if len(blocked) != 1 || fmt.Sprint(blocked[0].(map[string]interface{})["blocked_by"]) != "[2]" {
+			t.Errorf("alice is blocked on %v, want task 3 waiting on task 2", blocked)
+		}
+	})
+
+	t.Run("filters by user type and user", func(t *testing.T) {
+		users := digest(t, `{"user_type":"agent","project_id":1}`)["users"].([]interface{})
+		if len(users) != 1 || users[0].(map[string]interface{})["user_name"] != "builder" {
+			t.Errorf("agent digest has %v", users)
+		}
+		totals := digest(t, `{"user_id":1}`)["totals"].(map[string]interface{})
+		if totals["commented"] != float64(1) || totals["blocked"] != float64(1) || totals["completed"] != float64(0) {
+			t.Errorf("alice's totals are %v", totals)
+		}
+	})
+
+	t.Run("renders markdown", func(t *testing.T) {
+		text, _ := digest(t, `{"project_id":1,"format":"markdown"}`)["text"].(string)
+		for _, want := range []string{"# Activity digest: Digest", "## builder (agent via agent-cli 2.1)", "### Completed\n\n- #1 Spec (Digest)", "- #3 Deploy (Digest), waiting on #2", "- #2 Build (Digest), 2 comments"} {
+			if !strings.Contains(text, want) {
+				t.Errorf("markdown lacks %q:\n%s", want, text)
+			}
+		}
+	})
+
+	t.Run("rejects invalid periods", func(t *testing.T) {
+		for _, arguments := range []string{`{"since":"yesterday"}`, `{"since":"1d","until":"2d"}`} {
+			if callTool(t, handler, nil, "get_activity_digest", arguments)["isError"] != true {
+				t.Errorf("%s was accepted", arguments)
+			}
+		}
+	})
+}
@@ -17,6 +17,9 @@ var (
 	ErrInvalidInput    = errors.New("invalid input parameters")
 	ErrMissingRequired = errors.New("missing required parameters")
 
+	// Authorization errors
+	ErrPermissionDenied = errors.New("permission denied")
+
 	// Business logic errors
 	ErrDuplicateEntry = errors.New("duplicate entry already exists")
 
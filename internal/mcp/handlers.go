@@ -1,9 +1,13 @@
 package mcp
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
@@ -17,16 +21,101 @@ func (s *EnhancedMCPServer) RegisterRoutes(router *gin.RouterGroup) {
 
 	// MCP Resource endpoints
 	router.GET("/resources", s.handleListResources)
+	router.GET("/resources/templates", s.handleListResourceTemplates)
 	router.GET("/resources/get", s.handleGetResource)
 	router.POST("/resources/subscribe", s.handleSubscribeResource)
 
-	// MCP Info endpoint - handle both with and without trailing slash
-	router.GET("", s.handleMCPInfo)
-	router.GET("/", s.handleMCPInfo)
+	// MCP Info endpoint - handle both with and without trailing slash. Clients
+	// that accept text/event-stream get the notification stream of their session.
+	router.GET("", s.handleMCPGet)
+	router.GET("/", s.handleMCPGet)
 
-	// JSON-RPC endpoint for MCP protocol
+	// JSON-RPC endpoint for MCP protocol (Streamable HTTP transport)
 	router.POST("", s.handleJSONRPC)
 	router.POST("/", s.handleJSONRPC)
+	router.DELETE("", s.handleDeleteSession)
+	router.DELETE("/", s.handleDeleteSession)
+}
+
+// supportedProtocolVersions lists the MCP revisions the server speaks, newest first
+var supportedProtocolVersions = []string{"2025-06-18", "2025-03-26", "2024-11-05"}
+
+// protocolVersionHeader carries the negotiated protocol version on requests after initialize
+const protocolVersionHeader = "MCP-Protocol-Version"
+
+type contextKey string
+
+const (
+	userIDContextKey contextKey = "user_id"
+	scopesContextKey contextKey = "scopes"
+)
+
+// requestContext carries the authenticated user of a request and the scopes
+// of its token into tool and resource handlers
+func requestContext(c *gin.Context) context.Context {
+	ctx := c.Request.Context()
+	if userID, exists := c.Get("user_id"); exists {
+		if id, ok := userID.(uint); ok {
+			ctx = context.WithValue(ctx, userIDContextKey, id)
+		}
+	}
+	if scopes, exists := c.Get("scopes"); exists {
+		if scope, ok := scopes.(string); ok {
+			ctx = context.WithValue(ctx, scopesContextKey, parseScopes(scope))
+		}
+	}
+	return ctx
+}
+
+// userIDFromContext returns the user behind a request. Admin tokens have no user.
+func userIDFromContext(ctx context.Context) (uint, bool) {
+	id, ok := ctx.Value(userIDContextKey).(uint)
+	return id, ok
+}
+
+// requireUser returns the user behind a tool call that acts on the caller's own
+// data, or an error for admin tokens, which have no user
+func requireUser(ctx context.Context, tool string) (uint, error) {
+	id, ok := userIDFromContext(ctx)
+	if !ok {
+		return 0, fmt.Errorf("%s requires a user API token", tool)
+	}
+	return id, nil
+}
+
+// actingUser resolves the user a tool acts for: the caller, unless an admin
+// names another user
+func actingUser(ctx context.Context, tool string, requested uint) (uint, error) {
+	userID, ok := userIDFromContext(ctx)
+	if requested == 0 || ok && requested == userID {
+		if !ok {
+			return 0, fmt.Errorf("%s requires a user API token or a user_id", tool)
+		}
+		return userID, nil
+	}
+	if !isAdminCaller(ctx) {
+		return 0, fmt.Errorf("%w: only admins can use %s for other users", ErrPermissionDenied, tool)
+	}
+	return requested, nil
+}
+
+// isAdminCaller reports whether the caller has admin access. Requests that did
+// not come through token authentication, like the local stdio transport, do.
+func isAdminCaller(ctx context.Context) bool {
+	access := accessFromContext(ctx)
+	return access == nil || access.permissions[PermissionAdmin]
+}
+
+// requireOwnerOrAdmin fails unless the caller is the owner of what a tool
+// changes or an admin
+func requireOwnerOrAdmin(ctx context.Context, ownerID uint, what string) error {
+	if isAdminCaller(ctx) {
+		return nil
+	}
+	if userID, ok := userIDFromContext(ctx); ok && userID == ownerID {
+		return nil
+	}
+	return fmt.Errorf("%w: only its owner or an admin can change %s", ErrPermissionDenied, what)
 }
 
 // JSON-RPC 2.0 structures
@@ -51,12 +140,18 @@ type JSONRPCError struct {
 }
 
 func (s *EnhancedMCPServer) handleJSONRPC(c *gin.Context) {
-	var request JSONRPCRequest
-	if err := c.ShouldBindJSON(&request); err != nil {
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxRPCMessage))
+	if err != nil {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Request body too large"})
+		return
+	}
+
+	messages, batch, ok := decodeRPCBody(body)
+	if !ok {
 		c.JSON(http.StatusBadRequest, JSONRPCResponse{
 			JSONRPC: "2.0",
 			Error: &JSONRPCError{
-				Code:    -32700,
+				Code:    codeParseError,
 				Message: "Parse error",
 			},
 			ID: nil,
@@ -64,14 +159,156 @@ func (s *EnhancedMCPServer) handleJSONRPC(c *gin.Context) {
 		return
 	}
 
+	session, ok := s.requestSession(c)
+	if !ok {
+		return
+	}
+	if !checkProtocolVersionHeader(c, session) {
+		return
+	}
+
+	// Initialize starts a new session
+	single := !batch && len(messages) == 1 && messages[0].err == nil
+	if single && messages[0].request.Method == "initialize" && !messages[0].notification {
+		owner, userID := sessionOwner(c)
+		newSession, err := s.sessions.create(owner, userID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, JSONRPCResponse{
+				JSONRPC: "2.0",
+				Error: &JSONRPCError{
+					Code:    codeInternalError,
+					Message: err.Error(),
+				},
+				ID: messages[0].request.ID,
+			})
+			return
+		}
+		session = newSession
+		c.Header(sessionHeader, session.id)
+	}
+
+	// Clients that accept a stream get the progress of a tool while it runs,
+	// then its result as an SSE message
+	if single && !messages[0].notification && messages[0].request.Method == "tools/call" && acceptsEventStream(c) {
+		s.streamToolCall(c, session, messages[0])
+		return
+	}
+
+	responses := s.handleRPCMessages(requestContext(c), session, messages, batch)
+
+	// Bodies of notifications only are acknowledged without a response
+	if !hasRequests(messages) {
+		c.Status(http.StatusAccepted)
+		return
+	}
+
+	// A single message that is not a valid request is rejected as a whole
+	if !batch && messages[0].err != nil {
+		c.JSON(http.StatusBadRequest, responses[0])
+		return
+	}
+
+	c.JSON(http.StatusOK, rpcReply(responses, batch))
+}
+
+// streamToolCall runs a tool call and answers it with an SSE stream: progress
+// notifications as the tool reports them, then the notifications its session
+// received while the tool ran, then the result. Closing the stream cancels
+// the tool.
+func (s *EnhancedMCPServer) streamToolCall(c *gin.Context, session *mcpSession, message rpcMessage) {
+	progress := make(chan JSONRPCNotification, sessionOutboxSize)
+	ctx := withNotifier(requestContext(c), func(notification JSONRPCNotification) {
+		select {
+		case progress <- notification:
+		default:
+		}
+	})
+
+	done := make(chan JSONRPCResponse, 1)
+	go func() {
+		done <- s.handleRPCMessages(ctx, session, []rpcMessage{message}, false)[0]
+	}()
+
+	startEventStream(c)
+	c.Writer.Flush()
+	for {
+		select {
+		case notification := <-progress:
+			_ = writeSSEMessage(c.Writer, notification)
+			c.Writer.Flush()
+
+		case response := <-done:
+			for pending := true; pending; {
+				select {
+				case notification := <-progress:
+					_ = writeSSEMessage(c.Writer, notification)
+				default:
+					pending = false
+				}
+			}
+			if session != nil {
+				for pending := true; pending; {
+					select {
+					case notification := <-session.outbox:
+						_ = writeSSEMessage(c.Writer, notification)
+					default:
+						pending = false
+					}
+				}
+			}
+			_ = writeSSEMessage(c.Writer, response)
+			c.Writer.Flush()
+			return
+		}
+	}
+}
+
+// checkProtocolVersionHeader rejects requests whose MCP-Protocol-Version header
+// names a version the server does not speak, or not the one negotiated for the session
+func checkProtocolVersionHeader(c *gin.Context, session *mcpSession) bool {
+	version := c.GetHeader(protocolVersionHeader)
+	if version == "" {
+		return true
+	}
+
+	message := ""
+	if !isSupportedProtocolVersion(version) {
+		message = "Unsupported " + protocolVersionHeader + ": " + version
+	} else if negotiated := session.negotiatedVersion(); negotiated != "" && negotiated != version {
+		message = protocolVersionHeader + " " + version + " does not match the negotiated version " + negotiated
+	}
+	if message == "" {
+		return true
+	}
+
+	c.JSON(http.StatusBadRequest, JSONRPCResponse{
+		JSONRPC: "2.0",
+		Error:   invalidRequest(message),
+	})
+	return false
+}
+
+// handleRPCRequest runs a JSON-RPC request against the server. It is shared by
+// the HTTP and stdio transports; session is nil for clients without a session.
+func (s *EnhancedMCPServer) handleRPCRequest(ctx context.Context, session *mcpSession, request JSONRPCRequest) JSONRPCResponse {
 	// Handle different MCP methods
 	var result interface{}
 	var rpcErr *JSONRPCError
 
 	switch request.Method {
 	case "initialize":
+		var params struct {
+			ProtocolVersion string `json:"protocolVersion"`
+		}
+		_ = json.Unmarshal(request.Params, &params)
+
+		version := negotiateProtocolVersion(params.ProtocolVersion)
+		if session != nil {
+			session.setProtocolVersion(version)
+		}
+		s.initializeClient(ctx, session, request.Params)
 		result = gin.H{
-			"protocolVersion": "2024-11-05",
+			"protocolVersion": version,
 			"serverInfo": gin.H{
 				"name":    "Headless PM MCP Server",
 				"version": "2.0.0",
@@ -81,71 +318,53 @@ func (s *EnhancedMCPServer) handleJSONRPC(c *gin.Context) {
 					"listChanged": true,
 				},
 				"resources": gin.H{
-					"subscribe": true,
+					"subscribe":   true,
+					"listChanged": false,
+				},
+				"prompts": gin.H{
 					"listChanged": true,
 				},
+				"completions": gin.H{},
+				"logging":     gin.H{},
 			},
 		}
 
+	case "ping":
+		result = gin.H{}
+
 	case "tools/list":
-		tools := s.ListTools()
+		tools := s.ListTools(ctx)
 		result = gin.H{
 			"tools": tools,
 		}
 
 	case "tools/call":
 		var params struct {
-			Name      string                 `json:"name"`
-			Arguments map[string]interface{} `json:"arguments"`
+			Name      string          `json:"name"`
+			Arguments json.RawMessage `json:"arguments"`
 		}
-		if err := json.Unmarshal(request.Params, &params); err != nil {
+		if err := json.Unmarshal(request.Params, &params); err != nil || params.Name == "" {
 			rpcErr = &JSONRPCError{
-				Code:    -32602,
+				Code:    codeInvalidParams,
 				Message: "Invalid params",
 			}
+		} else if _, exists := s.getToolHandlers()[params.Name]; !exists {
+			rpcErr = &JSONRPCError{
+				Code:    codeInvalidParams,
+				Message: fmt.Sprintf("Unknown tool: %s", params.Name),
+			}
 		} else {
-			args, _ := json.Marshal(params.Arguments)
 			call := ToolCall{
 				Name:      params.Name,
-				Arguments: args,
+				Arguments: params.Arguments,
 			}
-			toolResult, err := s.ExecuteTool(c.Request.Context(), call)
+			// Tool failures are results with isError set, so the model sees them
+			ctx = withProgress(s.withPrincipal(ctx, session), session, request.Params)
+			toolResult, err := s.ExecuteTool(ctx, call)
 			if err != nil {
-				rpcErr = &JSONRPCError{
-					Code:    -32603,
-					Message: fmt.Sprintf("Tool execution error: %v", err),
-				}
-			} else if toolResult.IsError {
-				rpcErr = &JSONRPCError{
-					Code:    -32603,
-					Message: fmt.Sprintf("%v", toolResult.Content),
-				}
-			} else {
-				// Convert content to string
-				var contentStr string
-				switch v := toolResult.Content.(type) {
-				case string:
-					contentStr = v
-				case []byte:
-					contentStr = string(v)
-				default:
-					// Marshal to JSON if not a string
-					if bytes, err := json.Marshal(v); err == nil {
-						contentStr = string(bytes)
-					} else {
-						contentStr = fmt.Sprintf("%v", v)
-					}
-				}
-
-				result = gin.H{
-					"content": []gin.H{
-						{
-							"type": "text",
-							"text": contentStr,
-						},
-					},
-				}
+				toolResult = ErrorResponse(fmt.Errorf("tool execution error: %w", err))
 			}
+			result = toolCallResult(toolResult)
 		}
 
 	case "resources/list":
@@ -154,33 +373,163 @@ func (s *EnhancedMCPServer) handleJSONRPC(c *gin.Context) {
 			"resources": resources,
 		}
 
+	case "resources/templates/list":
+		result = gin.H{
+			"resourceTemplates": s.ListResourceTemplates(),
+		}
+
 	case "resources/read":
 		var params struct {
 			URI string `json:"uri"`
 		}
 		if err := json.Unmarshal(request.Params, &params); err != nil {
 			rpcErr = &JSONRPCError{
-				Code:    -32602,
+				Code:    codeInvalidParams,
 				Message: "Invalid params",
 			}
 		} else {
-			content, err := s.GetResource(c.Request.Context(), params.URI)
+			content, err := s.GetResource(ctx, params.URI)
 			if err != nil {
 				rpcErr = &JSONRPCError{
-					Code:    -32603,
+					Code:    resourceErrorCode(err),
 					Message: fmt.Sprintf("Resource error: %v", err),
+					Data:    gin.H{"uri": params.URI},
 				}
 			} else {
 				result = gin.H{
-					"contents": []interface{}{content},
+					"contents": []interface{}{content.textContent()},
 				}
 			}
 		}
 
+	case "resources/subscribe", "resources/unsubscribe":
+		var params struct {
+			URI string `json:"uri"`
+		}
+		if err := json.Unmarshal(request.Params, &params); err != nil || params.URI == "" {
+			rpcErr = &JSONRPCError{
+				Code:    codeInvalidParams,
+				Message: "Invalid params",
+			}
+		} else if session == nil {
+			rpcErr = &JSONRPCError{
+				Code:    codeInvalidRequest,
+				Message: "Subscriptions require a session; send the " + sessionHeader + " header returned by initialize",
+			}
+		} else if _, err := s.GetResource(ctx, params.URI); err != nil {
+			rpcErr = &JSONRPCError{
+				Code:    resourceErrorCode(err),
+				Message: fmt.Sprintf("Resource error: %v", err),
+			}
+		} else {
+			if request.Method == "resources/subscribe" {
+				session.subscribe(params.URI)
+			} else {
+				session.unsubscribe(params.URI)
+			}
+			result = gin.H{}
+		}
+
+	case "logging/setLevel":
+		var params struct {
+			Level string `json:"level"`
+		}
+		if err := json.Unmarshal(request.Params, &params); err != nil || logSeverity(params.Level) < 0 {
+			rpcErr = &JSONRPCError{
+				Code:    codeInvalidParams,
+				Message: "Invalid params",
+				Data:    gin.H{"levels": logLevels},
+			}
+		} else if session == nil {
+			rpcErr = &JSONRPCError{
+				Code:    codeInvalidRequest,
+				Message: "Logging requires a session; send the " + sessionHeader + " header returned by initialize",
+			}
+		} else {
+			session.setLogLevel(params.Level)
+			result = gin.H{}
+		}
+
+	case "prompts/list":
+		prompts, err := s.prompts.WithContext(ctx).ListPrompts()
+		if err != nil {
+			rpcErr = &JSONRPCError{
+				Code:    codeInternalError,
+				Message: fmt.Sprintf("Prompt error: %v", err),
+			}
+		} else {
+			result = gin.H{
+				"prompts": prompts,
+			}
+		}
+
+	case "prompts/get":
+		var params struct {
+			Name      string            `json:"name"`
+			Arguments map[string]string `json:"arguments"`
+		}
+		if err := json.Unmarshal(request.Params, &params); err != nil || params.Name == "" {
+			rpcErr = &JSONRPCError{
+				Code:    codeInvalidParams,
+				Message: "Invalid params",
+			}
+		} else if err := s.authorizePrompt(ctx, params.Arguments); err != nil {
+			rpcErr = &JSONRPCError{
+				Code:    codeInvalidRequest,
+				Message: fmt.Sprintf("Prompt error: %v", err),
+			}
+		} else if prompt, err := s.prompts.WithContext(ctx).GetPrompt(params.Name, params.Arguments); err != nil {
+			// Unknown prompts and bad arguments are the caller's mistake
+			rpcErr = &JSONRPCError{
+				Code:    codeInvalidParams,
+				Message: fmt.Sprintf("Prompt error: %v", err),
+			}
+		} else {
+			result = prompt
+		}
+
+	case "completion/complete":
+		var params CompletionRequest
+		if err := json.Unmarshal(request.Params, &params); err != nil {
+			rpcErr = &JSONRPCError{
+				Code:    codeInvalidParams,
+				Message: "Invalid params",
+			}
+		} else if completion, err := s.Complete(ctx, params); err != nil {
+			code := codeInternalError
+			if errors.Is(err, ErrInvalidInput) || errors.Is(err, ErrMissingRequired) {
+				code = codeInvalidParams
+			}
+			rpcErr = &JSONRPCError{
+				Code:    code,
+				Message: fmt.Sprintf("Completion error: %v", err),
+			}
+		} else {
+			result = gin.H{
+				"completion": completion,
+			}
+		}
+
+	case "notifications/initialized":
+		// Lifecycle notifications need no action
+		result = gin.H{}
+
+	case "notifications/cancelled":
+		// Stops an earlier request of the session; its context is cancelled,
+		// so the tool and its database calls give up
+		var params struct {
+			RequestID json.RawMessage `json:"requestId"`
+		}
+		if err := json.Unmarshal(request.Params, &params); err == nil && session != nil && len(params.RequestID) > 0 {
+			session.cancelRequest(string(params.RequestID))
+		}
+		result = gin.H{}
+
 	default:
 		rpcErr = &JSONRPCError{
-			Code:    -32601,
+			Code:    codeMethodNotFound,
 			Message: "Method not found",
+			Data:    gin.H{"method": request.Method},
 		}
 	}
 
@@ -196,7 +545,67 @@ func (s *EnhancedMCPServer) handleJSONRPC(c *gin.Context) {
 		response.Result = result
 	}
 
-	c.JSON(http.StatusOK, response)
+	return response
+}
+
+// toolCallResult converts a tool response to a tools/call result; content that
+// is not text is sent as JSON
+func toolCallResult(response *ToolResponse) gin.H {
+	var text string
+	switch v := response.Content.(type) {
+	case string:
+		text = v
+	case []byte:
+		text = string(v)
+	default:
+		if bytes, err := json.Marshal(v); err == nil {
+			text = string(bytes)
+		} else {
+			text = fmt.Sprintf("%v", v)
+		}
+	}
+
+	result := gin.H{
+		"content": []gin.H{
+			{
+				"type": "text",
+				"text": text,
+			},
+		},
+	}
+	if response.IsError {
+		result["isError"] = true
+	} else if structured := structuredContent(response.Content); structured != nil {
+		// Typed clients read the result here instead of parsing the text
+		result["structuredContent"] = structured
+	}
+	return result
+}
+
+// negotiateProtocolVersion answers with the client's protocol version when the
+// server supports it and with the latest supported version otherwise
+func negotiateProtocolVersion(requested string) string {
+	if isSupportedProtocolVersion(requested) {
+		return requested
+	}
+	return supportedProtocolVersions[0]
+}
+
+func isSupportedProtocolVersion(version string) bool {
+	for _, supported := range supportedProtocolVersions {
+		if supported == version {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *EnhancedMCPServer) handleMCPGet(c *gin.Context) {
+	if acceptsEventStream(c) {
+		s.handleSessionStream(c)
+		return
+	}
+	s.handleMCPInfo(c)
 }
 
 func (s *EnhancedMCPServer) handleMCPInfo(c *gin.Context) {
@@ -205,7 +614,7 @@ func (s *EnhancedMCPServer) handleMCPInfo(c *gin.Context) {
 		"version":     "2.0.0",
 		"description": "Enhanced MCP server with comprehensive project management capabilities",
 		"capabilities": gin.H{
-			"tools":     len(s.ListTools()),
+			"tools":     len(s.ListTools(requestContext(c))),
 			"resources": len(s.ListResources()),
 			"features": []string{
 				"project_management",
@@ -220,45 +629,40 @@ func (s *EnhancedMCPServer) handleMCPInfo(c *gin.Context) {
 				"webhooks",
 				"notifications",
 				"time_tracking",
+				"streamable_http",
+				"resource_subscriptions",
+				"resource_templates",
+				"prompts",
 			},
 		},
 	})
 }
 
 func (s *EnhancedMCPServer) handleListTools(c *gin.Context) {
-	tools := s.ListTools()
+	tools := s.ListTools(requestContext(c))
 	c.JSON(http.StatusOK, gin.H{
 		"tools": tools,
 	})
 }
 
 func (s *EnhancedMCPServer) handleExecuteTool(c *gin.Context) {
-	var request struct {
-		Name      string                 `json:"name"`
-		Arguments map[string]interface{} `json:"arguments"`
-	}
-
-	if err := c.ShouldBindJSON(&request); err != nil {
+	var call ToolCall
+	if err := c.ShouldBindJSON(&call); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error": "Invalid request format",
 		})
 		return
 	}
 
-	args, err := json.Marshal(request.Arguments)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "Invalid arguments",
+	ctx := s.withPrincipal(requestContext(c), nil)
+	if err := s.authorizeToolCall(ctx, call); err != nil {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": err.Error(),
 		})
 		return
 	}
 
-	call := ToolCall{
-		Name:      request.Name,
-		Arguments: args,
-	}
-
-	result, err := s.ExecuteTool(c.Request.Context(), call)
+	result, err := s.ExecuteTool(ctx, call)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": err.Error(),
@@ -267,6 +671,11 @@ func (s *EnhancedMCPServer) handleExecuteTool(c *gin.Context) {
 	}
 
 	if result.IsError {
+		// Argument errors already carry an error message next to their fields
+		if content, ok := result.Content.(map[string]interface{}); ok {
+			c.JSON(http.StatusBadRequest, content)
+			return
+		}
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error": result.Content,
 		})
@@ -285,6 +694,12 @@ func (s *EnhancedMCPServer) handleListResources(c *gin.Context) {
 	})
 }
 
+func (s *EnhancedMCPServer) handleListResourceTemplates(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"resourceTemplates": s.ListResourceTemplates(),
+	})
+}
+
 func (s *EnhancedMCPServer) handleGetResource(c *gin.Context) {
 	uri := c.Query("uri")
 	if uri == "" {
@@ -294,7 +709,7 @@ func (s *EnhancedMCPServer) handleGetResource(c *gin.Context) {
 		return
 	}
 
-	content, err := s.GetResource(c.Request.Context(), uri)
+	content, err := s.GetResource(requestContext(c), uri)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{
 			"error": err.Error(),
@@ -316,9 +731,9 @@ func (s *EnhancedMCPServer) handleSubscribeResource(c *gin.Context) {
 		return
 	}
 
-	// For HTTP-based subscriptions, we'll return the current state
-	// In a production system, this could use WebSockets or Server-Sent Events
-	content, err := s.GetResource(c.Request.Context(), req.URI)
+	// HTTP clients get the current state and the change stream to follow; the
+	// resource should be read again when a change arrives
+	content, err := s.GetResource(requestContext(c), req.URI)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{
 			"error": err.Error(),
@@ -326,9 +741,38 @@ func (s *EnhancedMCPServer) handleSubscribeResource(c *gin.Context) {
 		return
 	}
 
+	stream := resourceStream(req.URI)
 	c.JSON(http.StatusOK, gin.H{
-		"subscribed": true,
+		"subscribed": stream != "",
 		"uri":        req.URI,
 		"initial":    content,
+		"stream":     stream,
 	})
+}
+
+// resourceStream returns the change stream that announces updates of a resource
+func resourceStream(uri string) string {
+	switch {
+	case strings.HasPrefix(uri, "tasks://"):
+		return "/api/stream?types=task,dependency"
+	case strings.HasPrefix(uri, "epics://"):
+		return "/api/stream?types=epic"
+	}
+
+	entity, ok, err := parseEntityURI(uri)
+	if !ok || err != nil {
+		return ""
+	}
+	switch entity.kind {
+	case "project":
+		return fmt.Sprintf("/api/stream?project_id=%d", entity.id)
+	case "task":
+		return fmt.Sprintf("/api/stream?task_id=%d", entity.id)
+	case "epic":
+		return fmt.Sprintf("/api/stream?epic_id=%d", entity.id)
+	case "user":
+		return "/api/stream?types=task"
+	default:
+		return ""
+	}
 }
\ No newline at end of file
@@ -0,0 +1,164 @@
+package mcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/headless-pm/headless-project-management/internal/database"
+	"gorm.io/gorm/logger"
+)
+
+// Helpers shared by the tests of the MCP server
+
+// newTestServer returns an MCP server on a fresh database and its HTTP routes.
+// Requests name the token scopes with X-Test-Scopes and the user with
+// X-Test-User; without them they act as the admin token.
+func newTestServer(t *testing.T) (*EnhancedMCPServer, http.Handler) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	db, err := database.NewDatabaseWithLogger(t.TempDir(), logger.Discard)
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+
+	server := NewEnhancedMCPServer(db, nil, nil, nil)
+	router := gin.New()
+	group := router.Group("/mcp")
+	// Stands in for the token scopes and user the auth middleware sets
+	group.Use(func(c *gin.Context) {
+		if scope, ok := c.Request.Header["X-Test-Scopes"]; ok {
+			c.Set("scopes", scope[0])
+		}
+		if user := c.GetHeader("X-Test-User"); user != "" {
+			id, _ := strconv.ParseUint(user, 10, 64)
+			c.Set("user_id", uint(id))
+		}
+		c.Next()
+	})
+	server.RegisterRoutes(group)
+	return server, router
+}
+
+type rpcExchange struct {
+	status  int
+	header  http.Header
+	body    []byte
+	message map[string]interface{}
+	batch   []map[string]interface{}
+}
+
+func post(t *testing.T, handler http.Handler, body string, headers map[string]string) rpcExchange {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	exchange := rpcExchange{status: rec.Code, header: rec.Header(), body: rec.Body.Bytes()}
+	trimmed := bytes.TrimSpace(exchange.body)
+	switch {
+	case len(trimmed) == 0:
+	case trimmed[0] == '[':
+		if err := json.Unmarshal(trimmed, &exchange.batch); err != nil {
+			t.Fatalf("invalid batch response %s: %v", trimmed, err)
+		}
+	default:
+		if err := json.Unmarshal(trimmed, &exchange.message); err != nil {
+			t.Fatalf("invalid response %s: %v", trimmed, err)
+		}
+	}
+	return exchange
+}
+
+func errorCode(t *testing.T, message map[string]interface{}) int {
+	t.Helper()
+	rpcErr, ok := message["error"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("expected an error response, got %v", message)
+	}
+	return int(rpcErr["code"].(float64))
+}
+
+func result(t *testing.T, message map[string]interface{}) map[string]interface{} {
+	t.Helper()
+	if message["error"] != nil {
+		t.Fatalf("unexpected error response: %v", message["error"])
+	}
+	res, ok := message["result"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("expected a result, got %v", message)
+	}
+	return res
+}
+
+func initialize(t *testing.T, handler http.Handler, version string) (string, map[string]interface{}) {
+	t.Helper()
+	exchange := post(t, handler, `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"`+version+`","capabilities":{},"clientInfo":{"name":"test","version":"1"}}}`, nil)
+	if exchange.status != http.StatusOK {
+		t.Fatalf("initialize: status %d: %s", exchange.status, exchange.body)
+	}
+	sessionID := exchange.header.Get(sessionHeader)
+	if sessionID == "" {
+		t.Fatal("initialize did not return a session ID")
+	}
+	return sessionID, result(t, exchange.message)
+}
+
+// callTool calls a tool and returns its result, failed or not
+func callTool(t *testing.T, handler http.Handler, headers map[string]string, tool, arguments string) map[string]interface{} {
+	t.Helper()
+	exchange := post(t, handler, `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"`+tool+`","arguments":`+arguments+`}}`, headers)
+	return result(t, exchange.message)
+}
+
+// mustCallTool calls a tool and fails the test if the tool fails
+func mustCallTool(t *testing.T, handler http.Handler, headers map[string]string, tool, arguments string) map[string]interface{} {
+	t.Helper()
+	res := callTool(t, handler, headers, tool, arguments)
+	if res["isError"] == true {
+		t.Fatalf("%s failed: %v", tool, res["content"])
+	}
+	return res
+}
+
+// structured returns the structuredContent of a tool result
+func structured(res map[string]interface{}) map[string]interface{} {
+	content, _ := res["structuredContent"].(map[string]interface{})
+	return content
+}
+
+// openSession initializes a session of a user through a client and returns the
+// headers of its requests
+func openSession(t *testing.T, handler http.Handler, user, client string) map[string]string {
+	t.Helper()
+	exchange := post(t, handler, `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-06-18","capabilities":{},"clientInfo":{"name":"`+client+`","version":"2.1"}}}`,
+		map[string]string{"X-Test-User": user})
+	sessionID := exchange.header.Get(sessionHeader)
+	if sessionID == "" {
+		t.Fatalf("initialize did not return a session ID: %s", exchange.body)
+	}
+	return map[string]string{"X-Test-User": user, sessionHeader: sessionID}
+}
+
+// findSession returns the session of the server with the ID
+func findSession(t *testing.T, server *EnhancedMCPServer, id string) *mcpSession {
+	t.Helper()
+	for _, session := range server.sessions.all() {
+		if session.id == id {
+			return session
+		}
+	}
+	t.Fatalf("no session %s", id)
+	return nil
+}
@@ -0,0 +1,159 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+)
+
+// JSON-RPC 2.0 and MCP error codes
+const (
+	codeParseError       = -32700
+	codeInvalidRequest   = -32600
+	codeMethodNotFound   = -32601
+	codeInvalidParams    = -32602
+	codeInternalError    = -32603
+	codeSessionNotFound  = -32001
+	codeResourceNotFound = -32002
+)
+
+// maxRPCMessage bounds a request body or a line read from stdin
+const maxRPCMessage = 10 * 1024 * 1024
+
+// rpcMessage is one message of a request body. Notifications have no ID and
+// get no response; err is set for messages that are not valid requests.
+type rpcMessage struct {
+	request      JSONRPCRequest
+	notification bool
+	err          *JSONRPCError
+}
+
+// decodeRPCBody splits a body into its messages; batch reports whether it was
+// an array. ok is false when the body is not JSON at all.
+func decodeRPCBody(body []byte) (messages []rpcMessage, batch bool, ok bool) {
+	body = bytes.TrimSpace(body)
+	if len(body) == 0 || !json.Valid(body) {
+		return nil, false, false
+	}
+
+	if body[0] != '[' {
+		return []rpcMessage{decodeRPCMessage(body)}, false, true
+	}
+
+	var raws []json.RawMessage
+	if err := json.Unmarshal(body, &raws); err != nil {
+		return nil, false, false
+	}
+	if len(raws) == 0 {
+		// An empty batch is answered with a single error
+		return []rpcMessage{{err: invalidRequest("Empty batch")}}, false, true
+	}
+	for _, raw := range raws {
+		messages = append(messages, decodeRPCMessage(raw))
+	}
+	return messages, true, true
+}
+
+// decodeRPCMessage validates one message against the JSON-RPC 2.0 request object
+func decodeRPCMessage(raw json.RawMessage) rpcMessage {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil || fields == nil {
+		return rpcMessage{err: invalidRequest("Request must be an object")}
+	}
+
+	var message rpcMessage
+	idRaw, hasID := fields["id"]
+	message.notification = !hasID
+	if hasID {
+		// Keep numeric IDs exactly as sent
+		decoder := json.NewDecoder(bytes.NewReader(idRaw))
+		decoder.UseNumber()
+		var id interface{}
+		_ = decoder.Decode(&id)
+		switch id.(type) {
+		case nil, string, json.Number:
+			message.request.ID = id
+		default:
+			return rpcMessage{err: invalidRequest("id must be a string, number or null")}
+		}
+	}
+
+	if err := json.Unmarshal(fields["jsonrpc"], &message.request.JSONRPC); err != nil || message.request.JSONRPC != "2.0" {
+		message.err = invalidRequest(`jsonrpc must be "2.0"`)
+		return message
+	}
+	if err := json.Unmarshal(fields["method"], &message.request.Method); err != nil || message.request.Method == "" {
+		message.err = invalidRequest("method must be a non-empty string")
+		return message
+	}
+	if params, ok := fields["params"]; ok && !bytes.Equal(params, []byte("null")) {
+		if params[0] != '{' && params[0] != '[' {
+			message.err = invalidRequest("params must be an object or an array")
+			return message
+		}
+		message.request.Params = params
+	}
+	return message
+}
+
+func invalidRequest(detail string) *JSONRPCError {
+	return &JSONRPCError{
+		Code:    codeInvalidRequest,
+		Message: "Invalid Request",
+		Data:    detail,
+	}
+}
+
+// hasRequests reports whether any message expects a response
+func hasRequests(messages []rpcMessage) bool {
+	for _, message := range messages {
+		if !message.notification || message.err != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// handleRPCMessages runs the messages of a body in order and returns the
+// responses to send, one per request; notifications are run without one
+func (s *EnhancedMCPServer) handleRPCMessages(ctx context.Context, session *mcpSession, messages []rpcMessage, batch bool) []JSONRPCResponse {
+	var responses []JSONRPCResponse
+	for _, message := range messages {
+		if message.err != nil {
+			responses = append(responses, JSONRPCResponse{
+				JSONRPC: "2.0",
+				Error:   message.err,
+				ID:      message.request.ID,
+			})
+			continue
+		}
+		if batch && message.request.Method == "initialize" {
+			responses = append(responses, JSONRPCResponse{
+				JSONRPC: "2.0",
+				Error:   invalidRequest("initialize must not be part of a batch"),
+				ID:      message.request.ID,
+			})
+			continue
+		}
+
+		requestCtx, done := ctx, func() {}
+		if session != nil && !message.notification {
+			requestCtx, done = session.track(ctx, message.request.ID)
+		}
+		response := s.handleRPCRequest(requestCtx, session, message.request)
+		done()
+		if !message.notification {
+			responses = append(responses, response)
+		}
+	}
+	return responses
+}
+
+// rpcReply is the body answering a set of messages: the single response, or
+// an array for batches
+func rpcReply(responses []JSONRPCResponse, batch bool) interface{} {
+	if batch {
+		return responses
+	}
+	return responses[0]
+}
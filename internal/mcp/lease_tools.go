@@ -0,0 +1,82 @@
+package mcp
+
+import (
+	"context"
+	"time"
+
+	"github.com/headless-pm/headless-project-management/internal/database"
+)
+
+// Task claims act as the authenticated user
+func (s *EnhancedMCPServer) claimNextTask(ctx context.Context, args []byte) (*ToolResponse, error) {
+	var input struct {
+		ProjectID    uint     `json:"project_id"`
+		Labels       []string `json:"labels"`
+		LeaseSeconds int      `json:"lease_seconds"`
+	}
+	if err := UnmarshalArgs(args, &input); err != nil {
+		return ErrorResponse(err), nil
+	}
+	userID, err := requireUser(ctx, "claim_next_task")
+	if err != nil {
+		return ErrorResponse(err), nil
+	}
+
+	claim, err := s.db.ClaimNextTask(database.ClaimRequest{
+		ProjectID:     input.ProjectID,
+		UserID:        userID,
+		Labels:        input.Labels,
+		LeaseDuration: time.Duration(input.LeaseSeconds) * time.Second,
+	})
+	if err != nil {
+		return ErrorResponse(err), nil
+	}
+
+	return SuccessResponse(claim), nil
+}
+
+func (s *EnhancedMCPServer) heartbeatTask(ctx context.Context, args []byte) (*ToolResponse, error) {
+	var input struct {
+		TaskID       uint `json:"task_id"`
+		LeaseSeconds int  `json:"lease_seconds"`
+	}
+	if err := UnmarshalArgs(args, &input); err != nil {
+		return ErrorResponse(err), nil
+	}
+	userID, err := requireUser(ctx, "heartbeat_task")
+	if err != nil {
+		return ErrorResponse(err), nil
+	}
+
+	lease, err := s.db.HeartbeatTaskLease(input.TaskID, userID, time.Duration(input.LeaseSeconds)*time.Second)
+	if err != nil {
+		return ErrorResponse(err), nil
+	}
+
+	return SuccessResponse(lease), nil
+}
+
+func (s *EnhancedMCPServer) releaseTask(ctx context.Context, args []byte) (*ToolResponse, error) {
+	var input struct {
+		TaskID uint `json:"task_id"`
+	}
+	if err := UnmarshalArgs(args, &input); err != nil {
+		return ErrorResponse(err), nil
+	}
+	userID, err := requireUser(ctx, "release_task")
+	if err != nil {
+		return ErrorResponse(err), nil
+	}
+
+	task, err := s.db.ReleaseTaskLease(input.TaskID, userID)
+	if err != nil {
+		return ErrorResponse(err), nil
+	}
+
+	return SuccessResponse(task), nil
+}
+
+// claimOutput describes the result of claim_next_task
+func claimOutput() map[string]interface{} {
+	return objectOutput(database.TaskClaim{})
+}
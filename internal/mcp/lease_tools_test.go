@@ -0,0 +1,112 @@
+package mcp
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/headless-pm/headless-project-management/internal/models"
+)
+
+func TestTaskClaims(t *testing.T) {
+	server, handler := newTestServer(t)
+	alice, bob := map[string]string{"X-Test-User": "1"}, map[string]string{"X-Test-User": "2"}
+	call := func(t *testing.T, user map[string]string, tool, arguments string) map[string]interface{} {
+		t.Helper()
+		return callTool(t, handler, user, tool, arguments)
+	}
+	call(t, nil, "create_user", `{"username":"alice","email":"alice@example.com","password":"secret123"}`)
+	call(t, nil, "create_user", `{"username":"bob","email":"bob@example.com","password":"secret123"}`)
+	call(t, nil, "create_project", `{"name":"Agents"}`)
+	plan := call(t, nil, "import_plan", `{"project_id":1,"tasks":[
+		{"ref":"low","title":"Low","priority":"low"},
+		{"ref":"frontend","title":"Frontend","priority":"urgent","labels":["frontend"]},
+		{"ref":"later","title":"Due later","priority":"high","due_date":"2030-01-02"},
+		{"ref":"sooner","title":"Due sooner","priority":"high","due_date":"2030-01-01"},
+		{"ref":"blocked","title":"Blocked","priority":"urgent","depends_on":["low"]}
+	]}`)
+	refs := plan["structuredContent"].(map[string]interface{})["refs"].(map[string]interface{})
+
+	claim := func(t *testing.T, user map[string]string, arguments string) interface{} {
+		t.Helper()
+		res := call(t, user, "claim_next_task", arguments)
+		if res["isError"] == true {
+			return nil
+		}
+		task := res["structuredContent"].(map[string]interface{})["task"].(map[string]interface{})
+		return task["id"]
+	}
+
+	t.Run("claims follow priority, due date, labels and dependencies", func(t *testing.T) {
+		want := []struct {
+			user      map[string]string
+			arguments string
+			ref       string
+		}{
+			{alice, `{"project_id":1,"labels":["backend"]}`, "sooner"},
+			{bob, `{"project_id":1}`, "frontend"},
+			{alice, `{"project_id":1,"labels":["backend"]}`, "later"},
+			{bob, `{"project_id":1,"lease_seconds":60}`, "low"},
+			{alice, `{"project_id":1}`, ""},
+		}
+		for _, claimed := range want {
+			got := claim(t, claimed.user, claimed.arguments)
+			if claimed.ref == "" && got != nil || claimed.ref != "" && got != refs[claimed.ref] {
+				t.Errorf("claim %s as %s got task %v, want %s", claimed.arguments, claimed.user["X-Test-User"], got, claimed.ref)
+			}
+		}
+
+		task, _ := server.db.GetTask(uint(refs["sooner"].(float64)))
+		if task.Status != models.TaskStatusInProgress || task.AssigneeID == nil || *task.AssigneeID != 1 {
+			t.Errorf("claimed task is %s, assigned to %v", task.Status, task.AssigneeID)
+		}
+	})
+
+	t.Run("only the lease holder can heartbeat and release", func(t *testing.T) {
+		low := fmt.Sprint(refs["low"])
+		if res := call(t, alice, "heartbeat_task", `{"task_id":`+low+`}`); res["isError"] != true {
+			t.Error("alice renewed bob's lease")
+		}
+		if res := call(t, bob, "heartbeat_task", `{"task_id":`+low+`,"lease_seconds":120}`); res["isError"] == true {
+			t.Errorf("bob could not renew his lease: %v", res["content"])
+		}
+		if res := call(t, alice, "heartbeat_task", `{"task_id":`+low+`,"user_id":2}`); res["isError"] != true {
+			t.Error("alice renewed bob's lease by naming him")
+		}
+		released := call(t, alice, "release_task", `{"task_id":`+fmt.Sprint(refs["later"])+`}`)
+		if task := structured(released); task["status"] != "todo" || task["assignee_id"] != nil {
+			t.Errorf("released task is %v", task)
+		}
+	})
+
+	t.Run("expired leases return tasks to the pool", func(t *testing.T) {
+		expired, err := server.db.ExpireTaskLeases(time.Now().Add(time.Hour))
+		if err != nil || expired != 3 {
+			t.Fatalf("expired %d leases (%v), want 3", expired, err)
+		}
+
+		sooner := uint(refs["sooner"].(float64))
+		task, _ := server.db.GetTask(sooner)
+		if task.Status != models.TaskStatusTodo || task.AssigneeID != nil {
+			t.Errorf("expired task is %s, assigned to %v", task.Status, task.AssigneeID)
+		}
+		activities, _ := server.db.GetTaskActivities(sooner)
+		actions := make(map[string]bool)
+		for _, activity := range activities {
+			actions[activity.Action] = true
+		}
+		if !actions["claimed"] || !actions["lease_expired"] {
+			t.Errorf("activity log is missing the handoff: %v", actions)
+		}
+
+		if got := claim(t, bob, `{"project_id":1}`); got != refs["frontend"] {
+			t.Errorf("got task %v back, want the urgent one again", got)
+		}
+	})
+
+	t.Run("claims need a user", func(t *testing.T) {
+		if res := call(t, nil, "claim_next_task", `{"project_id":1}`); res["isError"] != true {
+			t.Errorf("admin token claimed a task: %v", res)
+		}
+	})
+}
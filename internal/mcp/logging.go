@@ -0,0 +1,168 @@
+package mcp
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/headless-pm/headless-project-management/internal/events"
+	"github.com/headless-pm/headless-project-management/internal/models"
+)
+
+// logLevels are the severities of MCP logging, least severe first
+var logLevels = []string{"debug", "info", "notice", "warning", "error", "critical", "alert", "emergency"}
+
+// webhookURLPattern matches the URLs in delivery errors, e.g. the quoted target
+// of "Post \"https://...\": dial tcp ..."; they may carry tokens and are left out
+// of log messages
+var webhookURLPattern = regexp.MustCompile(`(?i)https?://[^\s"]+`)
+
+// logSeverity ranks a level, or returns -1 for unknown levels
+func logSeverity(level string) int {
+	for i, known := range logLevels {
+		if known == level {
+			return i
+		}
+	}
+	return -1
+}
+
+// setLogLevel makes the session receive log messages of level and above
+func (session *mcpSession) setLogLevel(level string) {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	session.logLevel = level
+}
+
+// logs reports whether the session wants log messages of level; sessions get
+// none until they call logging/setLevel
+func (session *mcpSession) logs(level string) bool {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	return session.logLevel != "" && logSeverity(level) >= logSeverity(session.logLevel)
+}
+
+// concerns reports whether a log message about users is relevant to the
+// session. Sessions without a user, like those of the admin token, get all.
+func (session *mcpSession) concerns(users []*uint) bool {
+	if session.userID == nil {
+		return true
+	}
+	for _, user := range users {
+		if user != nil && *user == *session.userID {
+			return true
+		}
+	}
+	return false
+}
+
+// logging reports whether any session receives log messages, so events are
+// only looked into when someone listens
+func (m *sessionManager) logging() bool {
+	for _, session := range m.all() {
+		if session.logs(logLevels[len(logLevels)-1]) {
+			return true
+		}
+	}
+	return false
+}
+
+// logMessage sends notifications/message to the sessions that asked for level
+// and are concerned by the users the message is about
+func (m *sessionManager) logMessage(level, logger string, data map[string]interface{}, users ...*uint) {
+	for _, session := range m.all() {
+		if !session.logs(level) || !session.concerns(users) {
+			continue
+		}
+		session.notify("notifications/message", map[string]interface{}{
+			"level":  level,
+			"logger": logger,
+			"data":   data,
+		})
+	}
+}
+
+// registerLogNotifications forwards server events to the sessions they concern
+// as log messages: failed embeddings to the creator and assignee of the task,
+// failed webhook deliveries to the owner of the project and expired leases to
+// their holder
+func (s *EnhancedMCPServer) registerLogNotifications(bus *events.Bus) {
+	bus.Subscribe(func(event events.Event) {
+		if !s.sessions.logging() {
+			return
+		}
+		switch e := event.(type) {
+		case events.EmbeddingFailed:
+			s.logEmbeddingFailed(e)
+		case events.WebhookDeliveryFailed:
+			s.logWebhookDeliveryFailed(e)
+		case events.TaskLeaseExpired:
+			s.sessions.logMessage("notice", "leases", map[string]interface{}{
+				"message": fmt.Sprintf("Lease on task %d expired without a heartbeat; the task was returned to the pool", e.Lease.TaskID),
+				"task_id": e.Lease.TaskID,
+				"user_id": e.Lease.UserID,
+			}, &e.Lease.UserID)
+		}
+	}, events.NameEmbeddingFailed, events.NameWebhookFailed, events.NameTaskLeaseExpired)
+}
+
+func (s *EnhancedMCPServer) logEmbeddingFailed(e events.EmbeddingFailed) {
+	level, outcome := "warning", "retrying"
+	if e.Final {
+		level, outcome = "error", "giving up"
+	}
+
+	var users []*uint
+	switch e.EntityType {
+	case "task":
+		var task models.Task
+		if err := s.db.First(&task, e.EntityID).Error; err == nil {
+			users = append(users, &task.CreatedBy, task.AssigneeID)
+		}
+	case "project":
+		var project models.Project
+		if err := s.db.First(&project, e.EntityID).Error; err == nil {
+			users = append(users, &project.OwnerID)
+		}
+	}
+
+	s.sessions.logMessage(level, "embeddings", map[string]interface{}{
+		"message":     fmt.Sprintf("Embedding of %s %d failed on attempt %d, %s: %s", e.EntityType, e.EntityID, e.Attempt, outcome, e.Err),
+		"entity_type": e.EntityType,
+		"entity_id":   e.EntityID,
+		"attempt":     e.Attempt,
+		"error":       e.Err,
+	}, users...)
+}
+
+func (s *EnhancedMCPServer) logWebhookDeliveryFailed(e events.WebhookDeliveryFailed) {
+	delivery := e.Delivery
+	level, outcome := "warning", "retrying"
+	if e.Final {
+		level, outcome = "error", "giving up"
+	} else if delivery.NextAttemptAt != nil {
+		outcome = "retrying at " + delivery.NextAttemptAt.Format(time.RFC3339)
+	}
+
+	name := fmt.Sprintf("%d", delivery.WebhookID)
+	var users []*uint
+	if webhook := delivery.Webhook; webhook != nil {
+		name = webhook.Name
+		if webhook.ProjectID != nil {
+			var project models.Project
+			if err := s.db.First(&project, *webhook.ProjectID).Error; err == nil {
+				users = append(users, &project.OwnerID)
+			}
+		}
+	}
+
+	reason := webhookURLPattern.ReplaceAllString(delivery.Error, "<webhook url>")
+	s.sessions.logMessage(level, "webhooks", map[string]interface{}{
+		"message":     fmt.Sprintf("Webhook %s failed to deliver %s on attempt %d, %s: %s", name, delivery.Event, delivery.Attempts, outcome, reason),
+		"webhook_id":  delivery.WebhookID,
+		"delivery_id": delivery.ID,
+		"event":       delivery.Event,
+		"attempts":    delivery.Attempts,
+		"error":       reason,
+	}, users...)
+}
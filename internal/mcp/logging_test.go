@@ -0,0 +1,118 @@
+package mcp
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/headless-pm/headless-project-management/internal/events"
+	"github.com/headless-pm/headless-project-management/internal/models"
+)
+
+func TestLogging(t *testing.T) {
+	server, handler := newTestServer(t)
+	mustCallTool(t, handler, nil, "create_user", `{"username":"alice","email":"alice@example.com","password":"secret123"}`)
+	mustCallTool(t, handler, nil, "create_user", `{"username":"bob","email":"bob@example.com","password":"secret123"}`)
+	mustCallTool(t, handler, nil, "create_project", `{"name":"Logged"}`)
+	mustCallTool(t, handler, nil, "create_task", `{"project_id":1,"title":"Index me"}`)
+	mustCallTool(t, handler, map[string]string{"X-Test-User": "1"}, "claim_next_task", `{"project_id":1}`)
+
+	// Opens a session of a user and returns what its log level makes it receive
+	open := func(t *testing.T, user string) (func(level string) int, func() []map[string]interface{}) {
+		t.Helper()
+		headers := openSession(t, handler, user, "test")
+		session := findSession(t, server, headers[sessionHeader])
+
+		setLevel := func(level string) int {
+			exchange := post(t, handler, `{"jsonrpc":"2.0","id":2,"method":"logging/setLevel","params":{"level":"`+level+`"}}`, headers)
+			if exchange.message["error"] != nil {
+				return errorCode(t, exchange.message)
+			}
+			return 0
+		}
+		received := func() []map[string]interface{} {
+			var messages []map[string]interface{}
+			for pending := true; pending; {
+				select {
+				case notification := <-session.outbox:
+					if notification.Method == "notifications/message" {
+						messages = append(messages, notification.Params.(map[string]interface{}))
+					}
+				default:
+					pending = false
+				}
+			}
+			return messages
+		}
+		return setLevel, received
+	}
+	setAlice, aliceReceived := open(t, "1")
+	setBob, bobReceived := open(t, "2")
+
+	t.Run("logging/setLevel takes the syslog levels", func(t *testing.T) {
+		if code := setAlice("verbose"); code != codeInvalidParams {
+			t.Errorf("unknown level: code %d, want %d", code, codeInvalidParams)
+		}
+		exchange := post(t, handler, `{"jsonrpc":"2.0","id":2,"method":"logging/setLevel","params":{"level":"info"}}`, nil)
+		if errorCode(t, exchange.message) != codeInvalidRequest {
+			t.Errorf("setLevel without a session: %s", exchange.body)
+		}
+		if setAlice("notice") != 0 || setBob("debug") != 0 {
+			t.Fatal("setLevel failed")
+		}
+	})
+
+	t.Run("messages go to the sessions they concern", func(t *testing.T) {
+		server.db.Events().Publish(events.EmbeddingFailed{EntityType: "task", EntityID: 1, Attempt: 1, Err: "provider unavailable"})
+		if _, err := server.db.ExpireTaskLeases(time.Now().Add(time.Hour)); err != nil {
+			t.Fatal(err)
+		}
+
+		messages := aliceReceived()
+		if len(messages) != 2 || messages[0]["level"] != "warning" || messages[0]["logger"] != "embeddings" ||
+			messages[1]["level"] != "notice" || messages[1]["logger"] != "leases" {
+			t.Errorf("alice received %v", messages)
+		}
+		if messages := bobReceived(); len(messages) != 0 {
+			t.Errorf("bob received messages about alice's task: %v", messages)
+		}
+	})
+
+	t.Run("messages below the level are dropped", func(t *testing.T) {
+		// The expired lease unassigned the task
+		server.db.Model(&models.Task{}).Where("id = ?", 1).Update("assignee_id", 1)
+		setAlice("error")
+		server.db.Events().Publish(events.EmbeddingFailed{EntityType: "task", EntityID: 1, Attempt: 2, Err: "provider unavailable"})
+		server.db.Events().Publish(events.EmbeddingFailed{EntityType: "task", EntityID: 1, Attempt: 4, Err: "provider unavailable", Final: true})
+		if messages := aliceReceived(); len(messages) != 1 || messages[0]["level"] != "error" {
+			t.Errorf("alice received %v", messages)
+		}
+	})
+
+	t.Run("webhook failures leave out the webhook URL", func(t *testing.T) {
+		server.db.Model(&models.Project{}).Where("id = ?", 1).Update("owner_id", 1)
+		projectID := uint(1)
+		url := "https://hooks.example.com/T0/B0/secret-token"
+		server.db.Events().Publish(events.WebhookDeliveryFailed{
+			Delivery: &models.WebhookDelivery{
+				WebhookID: 1,
+				Webhook:   &models.Webhook{Name: "chat", URL: url, ProjectID: &projectID},
+				Event:     "task.created",
+				Attempts:  5,
+				Error:     `Post "` + url + `": dial tcp: connection refused`,
+			},
+			Final: true,
+		})
+
+		messages := aliceReceived()
+		if len(messages) != 1 || messages[0]["logger"] != "webhooks" {
+			t.Fatalf("alice received %v", messages)
+		}
+		data := messages[0]["data"].(map[string]interface{})
+		for _, field := range []string{"message", "error"} {
+			if text := data[field].(string); strings.Contains(text, "secret-token") || !strings.Contains(text, "connection refused") {
+				t.Errorf("%s = %q", field, text)
+			}
+		}
+	})
+}
@@ -0,0 +1,97 @@
+package mcp
+
+import (
+	"context"
+
+	"github.com/headless-pm/headless-project-management/internal/models"
+)
+
+// Notification operations act on the inbox of the caller
+func (s *EnhancedMCPServer) listNotifications(ctx context.Context, args []byte) (*ToolResponse, error) {
+	var input struct {
+		UnreadOnly bool `json:"unread_only"`
+		Limit      int  `json:"limit"`
+	}
+	if err := UnmarshalArgs(args, &input); err != nil {
+		return ErrorResponse(err), nil
+	}
+	userID, err := requireUser(ctx, "list_notifications")
+	if err != nil {
+		return ErrorResponse(err), nil
+	}
+
+	if input.Limit <= 0 {
+		input.Limit = 50
+	}
+
+	notifications, err := s.db.ListNotifications(userID, input.UnreadOnly, input.Limit)
+	if err != nil {
+		return ErrorResponse(err), nil
+	}
+	unreadCount, _ := s.db.CountUnreadNotifications(userID)
+
+	return SuccessResponse(map[string]interface{}{
+		"notifications": notifications,
+		"unread_count":  unreadCount,
+	}), nil
+}
+
+func (s *EnhancedMCPServer) markNotificationsRead(ctx context.Context, args []byte) (*ToolResponse, error) {
+	var input struct {
+		NotificationIDs []uint `json:"notification_ids"`
+	}
+	if err := UnmarshalArgs(args, &input); err != nil {
+		return ErrorResponse(err), nil
+	}
+	userID, err := requireUser(ctx, "mark_notifications_read")
+	if err != nil {
+		return ErrorResponse(err), nil
+	}
+
+	updated, err := s.db.MarkNotificationsRead(userID, input.NotificationIDs)
+	if err != nil {
+		return ErrorResponse(err), nil
+	}
+
+	return SuccessResponse(map[string]interface{}{
+		"updated": updated,
+	}), nil
+}
+
+func (s *EnhancedMCPServer) getNotificationPreferences(ctx context.Context, args []byte) (*ToolResponse, error) {
+	userID, err := requireUser(ctx, "get_notification_preferences")
+	if err != nil {
+		return ErrorResponse(err), nil
+	}
+
+	prefs, err := s.db.GetNotificationPreferences(userID)
+	if err != nil {
+		return ErrorResponse(err), nil
+	}
+
+	return SuccessResponse(prefs), nil
+}
+
+func (s *EnhancedMCPServer) updateNotificationPreferences(ctx context.Context, args []byte) (*ToolResponse, error) {
+	var input struct {
+		Preferences map[models.NotificationType]bool `json:"preferences"`
+	}
+	if err := UnmarshalArgs(args, &input); err != nil {
+		return ErrorResponse(err), nil
+	}
+	userID, err := requireUser(ctx, "update_notification_preferences")
+	if err != nil {
+		return ErrorResponse(err), nil
+	}
+
+	if err := s.db.UpdateNotificationPreferences(userID, input.Preferences); err != nil {
+		return ErrorResponse(err), nil
+	}
+
+	prefs, err := s.db.GetNotificationPreferences(userID)
+	if err != nil {
+		return ErrorResponse(err), nil
+	}
+
+	return SuccessResponse(prefs), nil
+}
@@ -0,0 +1,55 @@
+package mcp
+
+import (
+	"testing"
+
+	"github.com/headless-pm/headless-project-management/internal/models"
+)
+
+func TestNotificationTools(t *testing.T) {
+	server, handler := newTestServer(t)
+	mustCallTool(t, handler, nil, "create_user", `{"username":"alice","email":"alice@example.com","password":"secret123"}`)
+	mustCallTool(t, handler, nil, "create_user", `{"username":"bob","email":"bob@example.com","password":"secret123"}`)
+	for _, notification := range []models.Notification{
+		{UserID: 1, Type: models.NotificationTypeAssigned, Title: "For alice"},
+		{UserID: 2, Type: models.NotificationTypeAssigned, Title: "For bob"},
+	} {
+		if err := server.db.Create(&notification).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	alice := map[string]string{"X-Test-User": "1"}
+
+	t.Run("tools act on the caller's inbox", func(t *testing.T) {
+		listed := structured(mustCallTool(t, handler, alice, "list_notifications", `{}`))
+		items := listed["notifications"].([]interface{})
+		if len(items) != 1 || items[0].(map[string]interface{})["title"] != "For alice" {
+			t.Errorf("alice listed %v", items)
+		}
+
+		read := structured(mustCallTool(t, handler, alice, "mark_notifications_read", `{}`))
+		if read["updated"] != float64(1) {
+			t.Errorf("alice marked %v notifications read, want her own", read["updated"])
+		}
+		if unread, _ := server.db.CountUnreadNotifications(2); unread != 1 {
+			t.Errorf("bob has %d unread notifications, want 1", unread)
+		}
+
+		mustCallTool(t, handler, alice, "update_notification_preferences", `{"preferences":{"due_soon":false}}`)
+		if prefs, _ := server.db.GetNotificationPreferences(2); !prefs[models.NotificationTypeDueSoon] {
+			t.Error("alice changed bob's preferences")
+		}
+	})
+
+	t.Run("other users cannot be named", func(t *testing.T) {
+		if res := callTool(t, handler, alice, "list_notifications", `{"user_id":2}`); res["isError"] != true {
+			t.Errorf("alice listed bob's notifications: %v", res)
+		}
+	})
+
+	t.Run("admin tokens have no inbox", func(t *testing.T) {
+		if res := callTool(t, handler, nil, "get_notification_preferences", `{}`); res["isError"] != true {
+			t.Errorf("got preferences without a user: %v", res)
+		}
+	})
+}
@@ -0,0 +1,269 @@
+package mcp
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/headless-pm/headless-project-management/internal/database"
+	"github.com/headless-pm/headless-project-management/internal/models"
+)
+
+// Annotation presets for tool definitions. Every tool works on this server's
+// own data, so only the webhook tools that call out are open world.
+
+// readOnlyHints is for tools that only look at data
+func readOnlyHints() *ToolAnnotations {
+	return &ToolAnnotations{ReadOnlyHint: true, IdempotentHint: true}
+}
+
+// additiveHints is for tools that add data; calling them twice adds twice
+func additiveHints() *ToolAnnotations {
+	return &ToolAnnotations{}
+}
+
+// setHints is for tools that add data only once, however often they are called
+func setHints() *ToolAnnotations {
+	return &ToolAnnotations{IdempotentHint: true}
+}
+
+// updateHints is for tools that overwrite existing values
+func updateHints() *ToolAnnotations {
+	return &ToolAnnotations{DestructiveHint: true, IdempotentHint: true}
+}
+
+// deleteHints is for tools that remove data
+func deleteHints() *ToolAnnotations {
+	return &ToolAnnotations{DestructiveHint: true, IdempotentHint: true}
+}
+
+// externalHints is for tools that send requests to other systems
+func externalHints() *ToolAnnotations {
+	return &ToolAnnotations{OpenWorldHint: true}
+}
+
+// Output schemas describe the structuredContent of a tool result. Results
+// that are JSON objects are the structured content as they are; lists are
+// wrapped as {"items": [...]} and text as {"text": "..."}.
+
+// objectOutput describes a result that is a value of v's type
+func objectOutput(v interface{}) map[string]interface{} {
+	return schemaOf(reflect.TypeOf(v), 0)
+}
+
+// listOutput describes a result that is a list of values of v's type
+func listOutput(v interface{}) map[string]interface{} {
+	return fieldsOutput(map[string]interface{}{
+		"items": arrayOf(objectOutput(v)),
+	}, "items")
+}
+
+// fieldsOutput describes a result object with the given properties
+func fieldsOutput(properties map[string]interface{}, required ...string) map[string]interface{} {
+	schema := map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// messageOutput describes the {"message": "..."} results of delete tools
+func messageOutput() map[string]interface{} {
+	return fieldsOutput(map[string]interface{}{"message": typed("string")}, "message")
+}
+
+// statusOutput describes results reporting a status and the IDs involved
+func statusOutput(ids ...string) map[string]interface{} {
+	properties := map[string]interface{}{"status": typed("string")}
+	for _, id := range ids {
+		properties[id] = typed("integer")
+	}
+	return fieldsOutput(properties, "status")
+}
+
+// successOutput describes {"success": true, ...} results with the IDs involved
+func successOutput(ids ...string) map[string]interface{} {
+	properties := map[string]interface{}{"success": typed("boolean")}
+	for _, id := range ids {
+		properties[id] = typed("integer")
+	}
+	return fieldsOutput(properties, "success")
+}
+
+// sprintTransitionOutput describes the results of starting and completing sprints
+func sprintTransitionOutput() map[string]interface{} {
+	return fieldsOutput(map[string]interface{}{
+		"sprint":        objectOutput(models.Sprint{}),
+		"carried_tasks": typed("integer"),
+	}, "sprint", "carried_tasks")
+}
+
+// preferencesOutput describes notification preferences, a flag per notification type
+func preferencesOutput() map[string]interface{} {
+	schema := typed("object")
+	schema["additionalProperties"] = typed("boolean")
+	return schema
+}
+
+// timesheetOutput describes timesheet reports; CSV reports come as text
+func timesheetOutput() map[string]interface{} {
+	schema := objectOutput(database.Timesheet{})
+	delete(schema, "required")
+	schema["properties"].(map[string]interface{})["text"] = typed("string")
+	return schema
+}
+
+// digestOutput describes activity digests; Markdown digests come as text
+func digestOutput() map[string]interface{} {
+	schema := objectOutput(database.Digest{})
+	delete(schema, "required")
+	schema["properties"].(map[string]interface{})["text"] = typed("string")
+	return schema
+}
+
+func typed(typeName string) map[string]interface{} {
+	return map[string]interface{}{"type": typeName}
+}
+
+func arrayOf(items map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{"type": "array", "items": items}
+}
+
+var (
+	timeType      = reflect.TypeOf(time.Time{})
+	rawJSONType   = reflect.TypeOf(json.RawMessage{})
+	marshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+)
+
+// schemaOf describes the JSON encoding of a Go type. Structs nested in the
+// described one, mostly preloaded relations, are left as open objects to keep
+// tool lists small.
+func schemaOf(t reflect.Type, depth int) map[string]interface{} {
+	switch {
+	case t == timeType:
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	case t == rawJSONType:
+		return map[string]interface{}{}
+	case t.Implements(marshalerType):
+		return map[string]interface{}{}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		return nullable(schemaOf(t.Elem(), depth))
+	case reflect.Interface:
+		return map[string]interface{}{}
+	case reflect.String:
+		return typed("string")
+	case reflect.Bool:
+		return typed("boolean")
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return typed("integer")
+	case reflect.Float32, reflect.Float64:
+		return typed("number")
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return nullable(typed("string"))
+		}
+		return nullable(arrayOf(schemaOf(t.Elem(), depth+1)))
+	case reflect.Array:
+		return arrayOf(schemaOf(t.Elem(), depth+1))
+	case reflect.Map:
+		schema := typed("object")
+		schema["additionalProperties"] = schemaOf(t.Elem(), depth+1)
+		return nullable(schema)
+	case reflect.Struct:
+		if depth > 0 {
+			return typed("object")
+		}
+		return structSchema(t, depth)
+	}
+	return map[string]interface{}{}
+}
+
+func structSchema(t reflect.Type, depth int) map[string]interface{} {
+	properties := make(map[string]interface{})
+	var required []string
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+
+		// Embedded structs without a name have their fields promoted
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			embedded := structSchema(field.Type, depth)
+			for key, value := range embedded["properties"].(map[string]interface{}) {
+				properties[key] = value
+			}
+			if names, ok := embedded["required"].([]string); ok {
+				required = append(required, names...)
+			}
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+		properties[name] = schemaOf(field.Type, depth+1)
+		if !strings.Contains(options, "omitempty") {
+			required = append(required, name)
+		}
+	}
+
+	return fieldsOutput(properties, required...)
+}
+
+// nullable widens a schema to also accept null, as nil pointers, slices and
+// maps encode to it
+func nullable(schema map[string]interface{}) map[string]interface{} {
+	typeName, ok := schema["type"].(string)
+	if !ok {
+		return schema
+	}
+	widened := make(map[string]interface{}, len(schema))
+	for key, value := range schema {
+		widened[key] = value
+	}
+	widened["type"] = []string{typeName, "null"}
+	return widened
+}
+
+// structuredContent converts tool result content to the structuredContent of
+// tools/call, matching the shapes the output schemas describe
+func structuredContent(content interface{}) map[string]interface{} {
+	if text, ok := content.(string); ok {
+		return map[string]interface{}{"text": text}
+	}
+
+	data, err := json.Marshal(content)
+	if err != nil {
+		return nil
+	}
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return v
+	case []interface{}:
+		return map[string]interface{}{"items": v}
+	case nil:
+		// Empty lists come back from the database as nil slices
+		return map[string]interface{}{"items": []interface{}{}}
+	}
+	return map[string]interface{}{"value": value}
+}
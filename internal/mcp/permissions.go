@@ -0,0 +1,407 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/headless-pm/headless-project-management/internal/models"
+)
+
+// ToolPermission is the access a tool needs from the token calling it
+type ToolPermission string
+
+const (
+	// PermissionRead tools only look at data
+	PermissionRead ToolPermission = "read"
+	// PermissionWrite tools change project data
+	PermissionWrite ToolPermission = "write"
+	// PermissionAdmin tools manage users, projects and integrations
+	PermissionAdmin ToolPermission = "admin"
+)
+
+// toolAccess declares what a tool needs. projectArgs name the arguments
+// identifying the projects a call touches; tools with any are project-scoped
+// and can be used by tokens restricted to some projects.
+type toolAccess struct {
+	permission  ToolPermission
+	projectArgs []string
+}
+
+func readAccess(projectArgs ...string) toolAccess {
+	return toolAccess{permission: PermissionRead, projectArgs: projectArgs}
+}
+
+func writeAccess(projectArgs ...string) toolAccess {
+	return toolAccess{permission: PermissionWrite, projectArgs: projectArgs}
+}
+
+func adminAccess(projectArgs ...string) toolAccess {
+	return toolAccess{permission: PermissionAdmin, projectArgs: projectArgs}
+}
+
+// toolAccessRules declares the access every tool needs; tools missing here
+// can only be used by admins
+var toolAccessRules = map[string]toolAccess{
+	// Project Management
+	"create_project": writeAccess(),
+	"get_project":    readAccess("project_id"),
+	"update_project": writeAccess("project_id"),
+	"delete_project": adminAccess("project_id"),
+	"list_projects":  readAccess(),
+
+	// Task Management
+	"create_task": writeAccess("project_id", "epic_id", "sprint_id", "milestone_id", "parent_id"),
+	"get_task":    readAccess("task_id"),
+	"update_task": writeAccess("task_id", "epic_id", "sprint_id", "milestone_id", "parent_id"),
+	"delete_task": writeAccess("task_id"),
+	"list_tasks":  readAccess("project_id"),
+
+	// Epic Management
+	"create_epic": writeAccess("project_id"),
+	"get_epic":    readAccess("epic_id"),
+	"update_epic": writeAccess("epic_id"),
+	"delete_epic": writeAccess("epic_id"),
+	"list_epics":  readAccess("project_id"),
+
+	// Sprints
+	"create_sprint":           writeAccess("project_id"),
+	"get_sprint":              readAccess("sprint_id"),
+	"update_sprint":           writeAccess("sprint_id"),
+	"delete_sprint":           writeAccess("sprint_id"),
+	"list_sprints":            readAccess("project_id"),
+	"add_task_to_sprint":      writeAccess("sprint_id", "task_id", "task_ids"),
+	"remove_task_from_sprint": writeAccess("task_id"),
+	"start_sprint":            writeAccess("sprint_id"),
+	"complete_sprint":         writeAccess("sprint_id", "next_sprint_id"),
+
+	// Milestones
+	"create_milestone":         writeAccess("project_id"),
+	"get_milestone":            readAccess("milestone_id"),
+	"update_milestone":         writeAccess("milestone_id"),
+	"delete_milestone":         writeAccess("milestone_id"),
+	"list_milestones":          readAccess("project_id"),
+	"assign_task_to_milestone": writeAccess("milestone_id", "task_id"),
+
+	// Labels
+	"create_label": writeAccess("project_id"),
+	"assign_label": writeAccess("task_id", "label_id"),
+	"list_labels":  readAccess("project_id"),
+	"update_label": writeAccess("label_id"),
+	"delete_label": writeAccess("label_id"),
+
+	// Assignees
+	"assign_task":    writeAccess("task_id"),
+	"list_assignees": readAccess("project_id"),
+
+	// Users
+	"create_user": adminAccess(),
+	"list_users":  readAccess(),
+	"get_user":    readAccess(),
+	"update_user": adminAccess(),
+	"delete_user": adminAccess(),
+
+	// Comments
+	"add_comment":             writeAccess("task_id"),
+	"update_comment":          writeAccess("comment_id"),
+	"delete_comment":          writeAccess("comment_id"),
+	"list_comments":           readAccess("task_id"),
+	"get_comment":             readAccess("comment_id"),
+	"get_comment_history":     readAccess("comment_id"),
+	"add_comment_reaction":    writeAccess("comment_id"),
+	"remove_comment_reaction": writeAccess("comment_id"),
+
+	// Watchers and mentions
+	"watch_task":         writeAccess("task_id"),
+	"unwatch_task":       writeAccess("task_id"),
+	"list_task_watchers": readAccess("task_id"),
+	"list_watched_tasks": readAccess(),
+	"list_mentions":      readAccess(),
+
+	// Notifications
+	"list_notifications":              readAccess(),
+	"mark_notifications_read":         writeAccess(),
+	"get_notification_preferences":    readAccess(),
+	"update_notification_preferences": writeAccess(),
+
+	// Webhooks
+	"create_webhook":          adminAccess(),
+	"list_webhooks":           adminAccess(),
+	"update_webhook":          adminAccess(),
+	"delete_webhook":          adminAccess(),
+	"ping_webhook":            adminAccess(),
+	"list_webhook_deliveries": adminAccess(),
+	"redeliver_webhook":       adminAccess(),
+
+	// Prompt Templates
+	"create_prompt_template": writeAccess(),
+	"update_prompt_template": writeAccess(),
+	"delete_prompt_template": writeAccess(),
+
+	// Time Tracking
+	"log_work":          writeAccess("task_id"),
+	"list_worklogs":     readAccess("task_id"),
+	"delete_worklog":    writeAccess("worklog_id"),
+	"start_timer":       writeAccess("task_id"),
+	"stop_timer":        writeAccess(),
+	"get_running_timer": readAccess(),
+	"get_timesheet":     readAccess("project_id"),
+
+	// Activity Digests
+	"get_activity_digest": readAccess("project_id"),
+
+	// Plan Import
+	"import_plan": writeAccess("project_id"),
+
+	// Task Claims
+	"claim_next_task": writeAccess("project_id"),
+	"heartbeat_task":  writeAccess("task_id"),
+	"release_task":    writeAccess("task_id"),
+
+	// Task Dependencies
+	"add_task_dependency":          writeAccess("task_id", "depends_on_id"),
+	"remove_task_dependency":       writeAccess("dependency_id"),
+	"list_task_dependencies":       readAccess("task_id"),
+	"get_task_dependency_chain":    readAccess("task_id"),
+	"get_task_dependent_chain":     readAccess("task_id"),
+	"can_start_task":               readAccess("task_id"),
+	"get_project_dependency_graph": readAccess("project_id"),
+}
+
+func accessRule(tool string) toolAccess {
+	if rule, ok := toolAccessRules[tool]; ok {
+		return rule
+	}
+	return adminAccess()
+}
+
+// callerAccess is what the token behind a request may do
+type callerAccess struct {
+	permissions map[ToolPermission]bool
+	// projects restricts project-scoped tools to these projects; nil allows every project
+	projects map[uint]bool
+}
+
+// parseScopes reads the scope of an API token: a comma or space separated list
+// of "read", "write", "admin" and "project:<id>" entries. "*" and "admin" grant
+// everything, "write" implies "read". Scopes naming no permission get the
+// "read,write" default of token creation.
+func parseScopes(scope string) *callerAccess {
+	access := &callerAccess{permissions: make(map[ToolPermission]bool)}
+	for _, entry := range strings.FieldsFunc(scope, func(r rune) bool { return r == ',' || r == ' ' }) {
+		switch {
+		case entry == "*" || entry == string(PermissionAdmin):
+			access.permissions[PermissionAdmin] = true
+			access.permissions[PermissionWrite] = true
+			access.permissions[PermissionRead] = true
+		case entry == string(PermissionWrite):
+			access.permissions[PermissionWrite] = true
+			access.permissions[PermissionRead] = true
+		case entry == string(PermissionRead):
+			access.permissions[PermissionRead] = true
+		case strings.HasPrefix(entry, "project:"):
+			if id, err := strconv.ParseUint(strings.TrimPrefix(entry, "project:"), 10, 32); err == nil {
+				if access.projects == nil {
+					access.projects = make(map[uint]bool)
+				}
+				access.projects[uint(id)] = true
+			}
+		}
+	}
+	if len(access.permissions) == 0 {
+		access.permissions[PermissionWrite] = true
+		access.permissions[PermissionRead] = true
+	}
+	return access
+}
+
+// accessFromContext returns the access of the caller. Requests that did not
+// come through token authentication, like the local stdio transport, have
+// full access and get nil.
+func accessFromContext(ctx context.Context) *callerAccess {
+	access, _ := ctx.Value(scopesContextKey).(*callerAccess)
+	return access
+}
+
+// canUseTool reports whether the caller may use a tool at all, before its
+// arguments are known
+func canUseTool(access *callerAccess, tool string) bool {
+	if access == nil {
+		return true
+	}
+	rule := accessRule(tool)
+	if !access.permissions[rule.permission] {
+		return false
+	}
+	// Tokens restricted to projects only get tools that work on a project
+	return access.projects == nil || len(rule.projectArgs) > 0
+}
+
+// authorizeToolCall enforces the access rules of a tool for one call,
+// including the projects it touches
+func (s *EnhancedMCPServer) authorizeToolCall(ctx context.Context, call ToolCall) error {
+	access := accessFromContext(ctx)
+	if access == nil {
+		return nil
+	}
+
+	rule := accessRule(call.Name)
+	if !canUseTool(access, call.Name) {
+		if access.projects != nil && access.permissions[rule.permission] {
+			return fmt.Errorf("%w: %s does not work on a single project and this token is restricted to projects", ErrPermissionDenied, call.Name)
+		}
+		return fmt.Errorf("%w: %s requires %s access", ErrPermissionDenied, call.Name, rule.permission)
+	}
+	if access.projects == nil {
+		return nil
+	}
+
+	var args map[string]json.RawMessage
+	_ = json.Unmarshal(call.Arguments, &args)
+
+	checked := 0
+	for _, arg := range rule.projectArgs {
+		var ids []uint
+		var id uint
+		if err := json.Unmarshal(args[arg], &id); err == nil && id != 0 {
+			ids = append(ids, id)
+		} else {
+			_ = json.Unmarshal(args[arg], &ids)
+		}
+
+		for _, id := range ids {
+			projectID, ok := s.projectOfArgument(arg, id)
+			if !ok {
+				return fmt.Errorf("%w: %s %d not found", ErrPermissionDenied, arg, id)
+			}
+			if !access.projects[projectID] {
+				return fmt.Errorf("%w: this token has no access to project %d", ErrPermissionDenied, projectID)
+			}
+			checked++
+		}
+	}
+	if checked == 0 {
+		return fmt.Errorf("%w: this token is restricted to projects; pass %s", ErrPermissionDenied, rule.projectArgs[0])
+	}
+	return nil
+}
+
+// authorizeResource enforces project-restricted tokens on a resource: entity
+// resources must belong to one of the token's projects, and resources spanning
+// projects are not available to them
+func (s *EnhancedMCPServer) authorizeResource(ctx context.Context, uri string) error {
+	access := accessFromContext(ctx)
+	if access == nil {
+		return nil
+	}
+	if !access.permissions[PermissionRead] {
+		return fmt.Errorf("%w: resources require read access", ErrPermissionDenied)
+	}
+	if access.projects == nil {
+		return nil
+	}
+
+	entity, ok, err := parseEntityURI(uri)
+	if err != nil {
+		return err
+	}
+	if !ok || entity.kind == "user" {
+		return fmt.Errorf("%w: %s spans projects and this token is restricted to projects", ErrPermissionDenied, uri)
+	}
+
+	projectID, found := s.projectOfArgument(entity.kind+"_id", entity.id)
+	if !found {
+		return fmt.Errorf("resource not found: %s", uri)
+	}
+	if !access.projects[projectID] {
+		return fmt.Errorf("%w: this token has no access to project %d", ErrPermissionDenied, projectID)
+	}
+	return nil
+}
+
+// authorizePrompt enforces project-restricted tokens on a prompt: the project
+// and epic it is rendered for must belong to the token's projects
+func (s *EnhancedMCPServer) authorizePrompt(ctx context.Context, args map[string]string) error {
+	access := accessFromContext(ctx)
+	if access == nil || access.projects == nil {
+		return nil
+	}
+
+	var projectIDs []uint
+	if ref := args["project"]; ref != "" {
+		project, err := s.prompts.WithContext(ctx).ResolveProject(ref)
+		if err != nil {
+			return err
+		}
+		projectIDs = append(projectIDs, project.ID)
+	}
+	if ref := args["epic_id"]; ref != "" {
+		id, err := strconv.ParseUint(ref, 10, 32)
+		if err != nil {
+			return fmt.Errorf("invalid epic ID %q", ref)
+		}
+		projectID, ok := s.projectOfArgument("epic_id", uint(id))
+		if !ok {
+			return fmt.Errorf("epic %s not found", ref)
+		}
+		projectIDs = append(projectIDs, projectID)
+	}
+
+	if len(projectIDs) == 0 {
+		return fmt.Errorf("%w: this token is restricted to projects; pass project", ErrPermissionDenied)
+	}
+	for _, projectID := range projectIDs {
+		if !access.projects[projectID] {
+			return fmt.Errorf("%w: this token has no access to project %d", ErrPermissionDenied, projectID)
+		}
+	}
+	return nil
+}
+
+// projectOfArgument resolves the project of the entity an ID argument names
+func (s *EnhancedMCPServer) projectOfArgument(arg string, id uint) (uint, bool) {
+	var model interface{}
+	switch arg {
+	case "project_id":
+		model = &models.Project{}
+		var ids []uint
+		s.db.Model(model).Where("id = ?", id).Pluck("id", &ids)
+		return id, len(ids) > 0
+	case "task_id", "task_ids", "depends_on_id", "parent_id":
+		model = &models.Task{}
+	case "epic_id":
+		model = &models.Epic{}
+	case "sprint_id", "next_sprint_id":
+		model = &models.Sprint{}
+	case "milestone_id":
+		model = &models.Milestone{}
+	case "label_id":
+		model = &models.Label{}
+	case "comment_id":
+		return s.projectOfTaskChild(&models.Comment{}, id)
+	case "dependency_id":
+		return s.projectOfTaskChild(&models.TaskDependency{}, id)
+	case "worklog_id":
+		return s.projectOfTaskChild(&models.Worklog{}, id)
+	default:
+		return 0, false
+	}
+
+	var projectIDs []uint
+	s.db.Model(model).Where("id = ?", id).Pluck("project_id", &projectIDs)
+	if len(projectIDs) == 0 {
+		return 0, false
+	}
+	return projectIDs[0], true
+}
+
+func (s *EnhancedMCPServer) projectOfTaskChild(model interface{}, id uint) (uint, bool) {
+	var taskIDs []uint
+	s.db.Model(model).Where("id = ?", id).Pluck("task_id", &taskIDs)
+	if len(taskIDs) == 0 {
+		return 0, false
+	}
+	return s.projectOfArgument("task_id", taskIDs[0])
+}
@@ -0,0 +1,115 @@
+package mcp
+
+import "testing"
+
+func TestToolPermissions(t *testing.T) {
+	_, handler := newTestServer(t)
+
+	listTools := func(scope string) map[string]bool {
+		exchange := post(t, handler, `{"jsonrpc":"2.0","id":1,"method":"tools/list"}`, map[string]string{"X-Test-Scopes": scope})
+		names := make(map[string]bool)
+		for _, item := range result(t, exchange.message)["tools"].([]interface{}) {
+			names[item.(map[string]interface{})["name"].(string)] = true
+		}
+		return names
+	}
+	callAs := func(scope, name, args string) map[string]interface{} {
+		return callTool(t, handler, map[string]string{"X-Test-Scopes": scope}, name, args)
+	}
+
+	t.Run("every tool declares its access", func(t *testing.T) {
+		for _, tool := range toolDefinitions() {
+			if _, ok := toolAccessRules[tool.Name]; !ok {
+				t.Errorf("%s has no access rule", tool.Name)
+			}
+		}
+	})
+
+	t.Run("tool lists follow the token scope", func(t *testing.T) {
+		readOnly, readWrite, admin := listTools("read"), listTools("read,write"), listTools("admin")
+		if !readOnly["list_tasks"] || readOnly["create_task"] || readOnly["delete_project"] {
+			t.Error("read-only tokens must only see read tools")
+		}
+		if !readWrite["create_task"] || readWrite["delete_project"] || readWrite["create_user"] || readWrite["create_webhook"] {
+			t.Error("read-write tokens must not see admin tools")
+		}
+		if !admin["delete_project"] || !admin["delete_user"] || len(admin) != len(toolDefinitions()) {
+			t.Error("admin tokens must see every tool")
+		}
+	})
+
+	t.Run("calls are checked against the token scope", func(t *testing.T) {
+		if res := callAs("read", "create_project", `{"name":"Denied"}`); res["isError"] != true {
+			t.Errorf("read-only token created a project: %v", res)
+		}
+		if res := callAs("write", "create_user", `{"username":"u","email":"u@example.com","password":"secret123"}`); res["isError"] != true {
+			t.Errorf("write token created a user: %v", res)
+		}
+		if res := callAs("write", "create_project", `{"name":"Allowed"}`); res["isError"] != nil {
+			t.Errorf("write token could not create a project: %v", res)
+		}
+	})
+
+	t.Run("project scopes restrict calls to their projects", func(t *testing.T) {
+		callAs("admin", "create_project", `{"name":"Other"}`)
+		scope := "read,write,project:1"
+		if tools := listTools(scope); tools["list_projects"] || !tools["create_task"] {
+			t.Error("project-scoped tokens must only see project-scoped tools")
+		}
+		if res := callAs(scope, "create_task", `{"project_id":1,"title":"Mine"}`); res["isError"] != nil {
+			t.Errorf("could not create a task in an allowed project: %v", res)
+		}
+		if res := callAs(scope, "create_task", `{"project_id":2,"title":"Theirs"}`); res["isError"] != true {
+			t.Errorf("created a task in another project: %v", res)
+		}
+		if res := callAs(scope, "get_task", `{"task_id":1}`); res["isError"] != nil {
+			t.Errorf("could not read a task of an allowed project: %v", res)
+		}
+		if res := callAs(scope, "list_tasks", `{}`); res["isError"] != true {
+			t.Errorf("listed tasks without naming a project: %v", res)
+		}
+	})
+
+	t.Run("tasks cannot be linked into other projects", func(t *testing.T) {
+		scope := "read,write,project:1"
+		callAs("admin", "create_epic", `{"project_id":2,"name":"Theirs"}`)
+		if res := callAs(scope, "update_task", `{"task_id":1,"epic_id":1}`); res["isError"] != true {
+			t.Errorf("moved a task into another project's epic: %v", res)
+		}
+		if res := callAs(scope, "create_task", `{"project_id":1,"title":"Sneaky","epic_id":1}`); res["isError"] != true {
+			t.Errorf("created a task in another project's epic: %v", res)
+		}
+	})
+
+	t.Run("project scopes restrict resources and prompts", func(t *testing.T) {
+		scope := map[string]string{"X-Test-Scopes": "read,project:1"}
+		callAs("admin", "create_task", `{"project_id":2,"title":"Secret"}`)
+
+		if _, code := readResource(t, handler, scope, "task://1"); code != 0 {
+			t.Errorf("could not read a task of an allowed project: code %d", code)
+		}
+		for _, uri := range []string{"task://2", "project://2/board", "epic://1", "projects://list", "user://1/assigned"} {
+			if _, code := readResource(t, handler, scope, uri); code != codeInvalidRequest {
+				t.Errorf("%s: got code %d, want %d", uri, code, codeInvalidRequest)
+			}
+		}
+
+		getPrompt := func(args string) int {
+			exchange := post(t, handler, `{"jsonrpc":"2.0","id":1,"method":"prompts/get","params":{"name":"epic_breakdown","arguments":`+args+`}}`, scope)
+			if _, failed := exchange.message["error"]; !failed {
+				return 0
+			}
+			return errorCode(t, exchange.message)
+		}
+		callAs("admin", "create_epic", `{"project_id":1,"name":"Ours"}`)
+		if code := getPrompt(`{"project":"1","epic_id":"2"}`); code != 0 {
+			t.Errorf("could not render a prompt for an allowed project: code %d", code)
+		}
+		if code := getPrompt(`{"project":"Other","epic_id":"1"}`); code != codeInvalidRequest {
+			t.Errorf("rendered a prompt for another project: code %d", code)
+		}
+		if code := getPrompt(`{"project":"1","epic_id":"1"}`); code != codeInvalidRequest {
+			t.Errorf("rendered a prompt with another project's epic: code %d", code)
+		}
+	})
+}
@@ -0,0 +1,95 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+
+	"github.com/headless-pm/headless-project-management/internal/database"
+	"github.com/headless-pm/headless-project-management/internal/models"
+)
+
+// planSubtaskDepth is how deep subtasks are described in the import_plan
+// schema; deeper levels are only checked when the plan is validated
+const planSubtaskDepth = 3
+
+// importPlan creates a nested plan of epics, tasks, subtasks, labels and
+// dependencies in one transaction, or only validates it as a dry run
+func (s *EnhancedMCPServer) importPlan(ctx context.Context, args []byte) (*ToolResponse, error) {
+	var input struct {
+		database.Plan
+		DryRun bool `json:"dry_run,omitempty"`
+	}
+	if err := UnmarshalArgs(args, &input); err != nil {
+		return ErrorResponse(err), nil
+	}
+
+	result, err := s.db.ImportPlan(&input.Plan, input.DryRun)
+	if err != nil {
+		var planErr *database.PlanError
+		if errors.As(err, &planErr) {
+			return ArgumentErrorResponse(planArgumentError(planErr)), nil
+		}
+		return ErrorResponse(err), nil
+	}
+
+	return SuccessResponse(result), nil
+}
+
+// planArgumentError reports the problems of a plan like invalid arguments,
+// each located by its path in the plan
+func planArgumentError(err *database.PlanError) *ArgumentError {
+	fields := make([]FieldError, len(err.Issues))
+	for i, issue := range err.Issues {
+		fields[i] = FieldError{Field: issue.Path, Message: issue.Message}
+	}
+	return &ArgumentError{Tool: "import_plan", Fields: fields}
+}
+
+// planTaskSchema describes a task of a plan, with subtasks down to depth levels
+func planTaskSchema(depth int) map[string]interface{} {
+	properties := map[string]interface{}{
+		"ref":             map[string]string{"type": "string", "description": "Local reference for depends_on and the returned ID mapping"},
+		"title":           map[string]string{"type": "string"},
+		"description":     map[string]string{"type": "string"},
+		"status":          map[string]interface{}{"type": "string", "enum": models.GetValidTaskStatuses()},
+		"priority":        map[string]interface{}{"type": "string", "enum": models.GetValidTaskPriorities()},
+		"assignee_id":     map[string]string{"type": "integer"},
+		"story_points":    map[string]string{"type": "integer"},
+		"estimated_hours": map[string]string{"type": "number"},
+		"due_date":        map[string]string{"type": "string", "description": "YYYY-MM-DD"},
+		"labels":          map[string]interface{}{"type": "array", "items": map[string]string{"type": "string"}},
+		"depends_on":      map[string]interface{}{"type": "array", "items": map[string]string{"type": "string"}, "description": "Refs of tasks in this plan that must finish first"},
+	}
+	if depth > 0 {
+		properties["subtasks"] = arrayOf(planTaskSchema(depth - 1))
+	} else {
+		properties["subtasks"] = arrayOf(typed("object"))
+	}
+	return map[string]interface{}{
+		"type":                 "object",
+		"properties":           properties,
+		"required":             []string{"title"},
+		"additionalProperties": false,
+	}
+}
+
+// planEpicSchema describes an epic of a plan with its tasks
+func planEpicSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"ref":         map[string]string{"type": "string", "description": "Local reference for the returned ID mapping"},
+			"name":        map[string]string{"type": "string"},
+			"description": map[string]string{"type": "string"},
+			"status":      map[string]interface{}{"type": "string", "enum": []string{"planned", "active", "completed", "cancelled"}},
+			"tasks":       arrayOf(planTaskSchema(planSubtaskDepth)),
+		},
+		"required":             []string{"name"},
+		"additionalProperties": false,
+	}
+}
+
+// planOutput describes the result of import_plan
+func planOutput() map[string]interface{} {
+	return objectOutput(database.PlanResult{})
+}
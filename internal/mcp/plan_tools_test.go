@@ -0,0 +1,63 @@
+package mcp
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestPlanImport(t *testing.T) {
+	_, handler := newTestServer(t)
+	call := func(t *testing.T, tool, arguments string) map[string]interface{} {
+		t.Helper()
+		return callTool(t, handler, nil, tool, arguments)
+	}
+	mustCallTool(t, handler, nil, "create_project", `{"name":"Plans"}`)
+
+	plan := `"project_id":1,
+		"epics":[{"ref":"auth","name":"Authentication","tasks":[
+			{"ref":"schema","title":"User schema","labels":["backend"]},
+			{"ref":"login","title":"Login","depends_on":["schema"],"subtasks":[{"ref":"form","title":"Login form"}]}
+		]}],
+		"tasks":[{"ref":"docs","title":"Document login","depends_on":["login","form"]}]`
+
+	t.Run("dry run validates without creating", func(t *testing.T) {
+		res := call(t, "import_plan", `{`+plan+`,"dry_run":true}`)
+		created := structured(res)["created"].(map[string]interface{})
+		if created["tasks"] != float64(4) || created["dependencies"] != float64(3) || created["labels"] != float64(1) {
+			t.Errorf("got %v", created)
+		}
+		tasks := call(t, "list_tasks", `{"project_id":1}`)["structuredContent"].(map[string]interface{})["items"].([]interface{})
+		if len(tasks) != 0 {
+			t.Errorf("dry run created %d tasks", len(tasks))
+		}
+	})
+
+	t.Run("cycles and unknown refs are reported by path", func(t *testing.T) {
+		res := call(t, "import_plan", `{"project_id":1,"dry_run":true,"tasks":[
+			{"ref":"a","title":"A","depends_on":["b"]},
+			{"ref":"b","title":"B","depends_on":["a","missing"]}
+		]}`)
+		text := res["content"].([]interface{})[0].(map[string]interface{})["text"].(string)
+		if res["isError"] != true || !strings.Contains(text, `"field":"tasks[0].depends_on","message":"dependency cycle`) || !strings.Contains(text, "tasks[1].depends_on[1]") {
+			t.Errorf("got %s", text)
+		}
+	})
+
+	t.Run("import maps refs to created IDs", func(t *testing.T) {
+		res := call(t, "import_plan", `{`+plan+`}`)
+		refs := structured(res)["refs"].(map[string]interface{})
+		if len(refs) != 5 {
+			t.Fatalf("got refs %v, want the epic and four tasks", refs)
+		}
+		login := fmt.Sprint(refs["login"])
+		form := call(t, "get_task", fmt.Sprintf(`{"task_id":%v}`, refs["form"]))["structuredContent"].(map[string]interface{})
+		if fmt.Sprint(form["parent_id"]) != login || fmt.Sprint(form["epic_id"]) != fmt.Sprint(refs["auth"]) {
+			t.Errorf("subtask %v is not under login %s in epic %v", form, login, refs["auth"])
+		}
+		dependencies := call(t, "list_task_dependencies", fmt.Sprintf(`{"task_id":%v}`, refs["docs"]))
+		if items, _ := dependencies["structuredContent"].(map[string]interface{})["items"].([]interface{}); len(items) != 2 {
+			t.Errorf("got dependencies %v", dependencies["structuredContent"])
+		}
+	})
+}
@@ -0,0 +1,84 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/headless-pm/headless-project-management/internal/database"
+	"github.com/headless-pm/headless-project-management/internal/models"
+)
+
+// clientInfo identifies the MCP client in the initialize request
+type clientInfo struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// String formats the client as "name version", e.g. "claude-code 1.0.3"
+func (info clientInfo) String() string {
+	return strings.TrimSpace(info.Name + " " + info.Version)
+}
+
+// userTypeSchema describes the type argument of user tools
+func userTypeSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type":        "string",
+		"enum":        []string{string(models.UserTypeHuman), string(models.UserTypeAgent)},
+		"description": "agent for AI agents and service accounts acting through API tokens",
+	}
+}
+
+// initializeClient records the client of a session. Agents registered without
+// a client are described by the client of their first session.
+func (s *EnhancedMCPServer) initializeClient(ctx context.Context, session *mcpSession, params json.RawMessage) {
+	var request struct {
+		ClientInfo clientInfo `json:"clientInfo"`
+	}
+	_ = json.Unmarshal(params, &request)
+	client := request.ClientInfo.String()
+	if client == "" {
+		return
+	}
+
+	if session != nil {
+		session.setClient(client)
+	}
+	if userID, ok := userIDFromContext(ctx); ok && s.db != nil {
+		_ = s.db.RecordAgentClient(userID, client)
+	}
+}
+
+// withPrincipal attributes the writes of a request to its authenticated user,
+// acting through the client of the session
+func (s *EnhancedMCPServer) withPrincipal(ctx context.Context, session *mcpSession) context.Context {
+	principal := database.PrincipalFromContext(ctx)
+	if principal == nil {
+		userID, ok := userIDFromContext(ctx)
+		if !ok || s.db == nil {
+			return ctx
+		}
+		user, err := s.db.GetUserByID(userID)
+		if err != nil {
+			return ctx
+		}
+		principal = database.PrincipalForUser(user, "")
+	}
+
+	if client := session.clientName(); client != "" && client != principal.Client {
+		scoped := *principal
+		scoped.Client = client
+		principal = &scoped
+	}
+	return database.WithPrincipal(ctx, principal)
+}
+
+// withContext returns a copy of the server whose handlers and services query
+// the database with ctx, attributing their writes to its principal
+func (s *EnhancedMCPServer) withContext(ctx context.Context) *EnhancedMCPServer {
+	scoped := *s
+	scoped.db = s.db.WithContext(ctx)
+	scoped.prompts = s.prompts.WithContext(ctx)
+	scoped.deletions = s.deletions.WithContext(ctx)
+	return &scoped
+}
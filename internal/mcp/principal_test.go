@@ -0,0 +1,104 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/headless-pm/headless-project-management/internal/service"
+)
+
+func TestAgentAttribution(t *testing.T) {
+	server, handler := newTestServer(t)
+	mustCallTool(t, handler, nil, "create_user", `{"username":"planner","email":"planner@example.com","password":"secret123","type":"agent"}`)
+
+	agent := openSession(t, handler, "1", "agent-cli")
+	mustCallTool(t, handler, agent, "create_project", `{"name":"Attributed"}`)
+	mustCallTool(t, handler, agent, "create_task", `{"project_id":1,"title":"Write the spec"}`)
+	mustCallTool(t, handler, agent, "update_task", `{"task_id":1,"status":"in_progress"}`)
+
+	t.Run("writes are attributed to the agent", func(t *testing.T) {
+		project, _ := server.db.GetProject(1)
+		if project.OwnerID != 1 {
+			t.Errorf("project owner is %d, want the agent", project.OwnerID)
+		}
+		task, _ := server.db.GetTask(1)
+		if task.CreatedBy != 1 || task.UpdatedBy == nil || *task.UpdatedBy != 1 {
+			t.Errorf("task created by %v and updated by %v, want the agent", task.CreatedBy, task.UpdatedBy)
+		}
+	})
+
+	t.Run("create_project takes an owner_id", func(t *testing.T) {
+		mustCallTool(t, handler, nil, "create_user", `{"username":"owner","email":"owner@example.com","password":"secret123"}`)
+		writer := map[string]string{"X-Test-User": "1", "X-Test-Scopes": "read,write"}
+		if project := structured(mustCallTool(t, handler, writer, "create_project", `{"name":"Own","owner_id":1}`)); project["owner_id"] != float64(1) {
+			t.Errorf("project owner is %v, want the agent", project["owner_id"])
+		}
+		if res := callTool(t, handler, writer, "create_project", `{"name":"Theirs","owner_id":2}`); res["isError"] != true {
+			t.Errorf("the agent created a project for another user: %v", res)
+		}
+		if project := structured(mustCallTool(t, handler, nil, "create_project", `{"name":"Assigned","owner_id":2}`)); project["owner_id"] != float64(2) {
+			t.Errorf("project owner is %v, want the user the admin named", project["owner_id"])
+		}
+	})
+
+	t.Run("activities record the client", func(t *testing.T) {
+		activities, _ := server.db.GetTaskActivities(1)
+		if len(activities) == 0 {
+			t.Fatal("no activity was logged")
+		}
+		for _, activity := range activities {
+			if activity.UserID == nil || *activity.UserID != 1 || activity.Client != "agent-cli 2.1" {
+				t.Errorf("%s activity by %v via %q", activity.Action, activity.UserID, activity.Client)
+			}
+		}
+
+		user, _ := server.db.GetUserByID(1)
+		if !user.IsAgent() || user.Client != "agent-cli 2.1" {
+			t.Errorf("agent is %s with client %q", user.Type, user.Client)
+		}
+	})
+
+	t.Run("services run with the request context", func(t *testing.T) {
+		template := structured(mustCallTool(t, handler, agent, "create_prompt_template", `{"name":"handoff","template":"Hand over {{.project}}"}`))
+		if template["created_by"] != float64(1) {
+			t.Errorf("prompt template created by %v, want the agent", template["created_by"])
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if _, err := server.withContext(ctx).deletions.Preview(service.DeletionTarget{Entity: "task", ID: 1}); !errors.Is(err, context.Canceled) {
+			t.Errorf("deletion with a cancelled request returned %v, want context.Canceled", err)
+		}
+	})
+}
+
+func TestCommentsBelongToTheirAuthor(t *testing.T) {
+	_, handler := newTestServer(t)
+	alice := map[string]string{"X-Test-User": "1", "X-Test-Scopes": "read,write"}
+	bob := map[string]string{"X-Test-User": "2", "X-Test-Scopes": "read,write"}
+	mustCallTool(t, handler, nil, "create_user", `{"username":"alice","email":"alice@example.com","password":"secret123"}`)
+	mustCallTool(t, handler, nil, "create_user", `{"username":"bob","email":"bob@example.com","password":"secret123"}`)
+	mustCallTool(t, handler, nil, "create_project", `{"name":"Billing"}`)
+	mustCallTool(t, handler, nil, "create_task", `{"project_id":1,"title":"Invoice"}`)
+
+	comment := structured(mustCallTool(t, handler, alice, "add_comment", `{"task_id":1,"content":"Sent","author":"bob"}`))
+	if comment["author"] != "alice" {
+		t.Errorf("comment by %v, want alice", comment["author"])
+	}
+	if res := callTool(t, handler, alice, "add_comment", `{"task_id":1,"content":"Forged","author_id":2}`); res["isError"] != true {
+		t.Errorf("alice commented with bob's ID: %v", res)
+	}
+
+	if res := callTool(t, handler, bob, "update_comment", `{"comment_id":1,"content":"Not sent"}`); res["isError"] != true {
+		t.Errorf("bob edited alice's comment: %v", res)
+	}
+	if res := callTool(t, handler, bob, "delete_comment", `{"comment_id":1}`); res["isError"] != true {
+		t.Errorf("bob deleted alice's comment: %v", res)
+	}
+	if res := callTool(t, handler, alice, "update_comment", `{"comment_id":1,"content":"Sent twice","editor_id":2}`); res["isError"] != true {
+		t.Errorf("alice edited as bob: %v", res)
+	}
+	mustCallTool(t, handler, alice, "update_comment", `{"comment_id":1,"content":"Sent twice"}`)
+	mustCallTool(t, handler, nil, "delete_comment", `{"comment_id":1}`)
+}
@@ -0,0 +1,94 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/headless-pm/headless-project-management/internal/database"
+)
+
+// notifierContextKey carries where the notifications caused by a request go
+// when the transport streams them with its response
+const notifierContextKey contextKey = "notifier"
+
+// withNotifier sends the notifications caused by a request to notify instead
+// of its session
+func withNotifier(ctx context.Context, notify func(JSONRPCNotification)) context.Context {
+	return context.WithValue(ctx, notifierContextKey, notify)
+}
+
+// progressToken returns the _meta.progressToken of request params as sent, or
+// nil when the client did not ask for progress
+func progressToken(params json.RawMessage) json.RawMessage {
+	var request struct {
+		Meta struct {
+			ProgressToken json.RawMessage `json:"progressToken"`
+		} `json:"_meta"`
+	}
+	if err := json.Unmarshal(params, &request); err != nil {
+		return nil
+	}
+	token := request.Meta.ProgressToken
+	if len(token) == 0 || bytes.Equal(token, []byte("null")) {
+		return nil
+	}
+	return token
+}
+
+// progressReporter sends notifications/progress for one request
+type progressReporter struct {
+	token  json.RawMessage
+	notify func(JSONRPCNotification)
+	mu     sync.Mutex
+	last   float64
+}
+
+// report sends the progress of the request; progress that does not increase is
+// dropped, as clients expect it to
+func (r *progressReporter) report(progress, total float64, message string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if progress <= r.last {
+		return
+	}
+	r.last = progress
+
+	params := map[string]interface{}{
+		"progressToken": r.token,
+		"progress":      progress,
+	}
+	if total > 0 {
+		params["total"] = total
+	}
+	if message != "" {
+		params["message"] = message
+	}
+	r.notify(JSONRPCNotification{JSONRPC: "2.0", Method: "notifications/progress", Params: params})
+}
+
+// withProgress makes the long-running work of a request report its progress
+// when the client sent a progressToken. Progress goes to the stream of the
+// request if the transport set one, otherwise to the session.
+func withProgress(ctx context.Context, session *mcpSession, params json.RawMessage) context.Context {
+	token := progressToken(params)
+	if token == nil {
+		return ctx
+	}
+
+	notify, _ := ctx.Value(notifierContextKey).(func(JSONRPCNotification))
+	if notify == nil {
+		if session == nil {
+			return ctx
+		}
+		notify = func(notification JSONRPCNotification) {
+			session.notify(notification.Method, notification.Params)
+		}
+	}
+
+	reporter := &progressReporter{token: token, notify: notify}
+	return database.WithProgress(ctx, func(done, total int, message string) {
+		reporter.report(float64(done), float64(total), message)
+	})
+}
@@ -0,0 +1,96 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/headless-pm/headless-project-management/internal/database"
+	"github.com/headless-pm/headless-project-management/internal/models"
+)
+
+func TestProgressAndCancellation(t *testing.T) {
+	server, handler := newTestServer(t)
+	mustCallTool(t, handler, nil, "create_project", `{"name":"Long running"}`)
+
+	t.Run("tools report progress on the request stream", func(t *testing.T) {
+		body := `{"jsonrpc":"2.0","id":"plan","method":"tools/call","params":{"name":"import_plan","_meta":{"progressToken":7},"arguments":{"project_id":1,"epics":[
+			{"name":"Launch","tasks":[{"ref":"a","title":"A"},{"title":"B","depends_on":["a"]}]}
+		]}}}`
+		req := httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "application/json, text/event-stream")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		var messages []map[string]interface{}
+		for _, line := range strings.Split(rec.Body.String(), "\n") {
+			if data, ok := strings.CutPrefix(line, "data: "); ok {
+				var message map[string]interface{}
+				if err := json.Unmarshal([]byte(data), &message); err != nil {
+					t.Fatalf("invalid SSE message %s: %v", data, err)
+				}
+				messages = append(messages, message)
+			}
+		}
+		// One epic, two tasks and a dependency, then the result
+		if len(messages) != 5 {
+			t.Fatalf("got %d messages, want 4 progress notifications and the result:\n%s", len(messages), rec.Body.String())
+		}
+		for i, notification := range messages[:4] {
+			params, _ := notification["params"].(map[string]interface{})
+			if notification["method"] != "notifications/progress" || params["progressToken"] != float64(7) ||
+				params["progress"] != float64(i+1) || params["total"] != float64(4) {
+				t.Errorf("progress %d: %v", i, notification)
+			}
+		}
+		if last := messages[4]; last["id"] != "plan" || result(t, last)["isError"] == true {
+			t.Errorf("result: %v", last)
+		}
+	})
+
+	t.Run("notifications/cancelled stops the request", func(t *testing.T) {
+		sessionID, _ := initialize(t, handler, "2025-06-18")
+		session := findSession(t, server, sessionID)
+		numeric, doneNumeric := session.track(context.Background(), json.Number("9"))
+		defer doneNumeric()
+		text, doneText := session.track(context.Background(), "9")
+		defer doneText()
+
+		exchange := post(t, handler, `{"jsonrpc":"2.0","method":"notifications/cancelled","params":{"requestId":9,"reason":"user abort"}}`,
+			map[string]string{sessionHeader: sessionID})
+		if exchange.status != http.StatusAccepted {
+			t.Fatalf("cancellation: status %d", exchange.status)
+		}
+		if numeric.Err() == nil {
+			t.Error("request 9 was not cancelled")
+		}
+		if text.Err() != nil {
+			t.Error(`request "9" was cancelled too`)
+		}
+	})
+
+	t.Run("cancelled imports roll back", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		ctx = database.WithProgress(ctx, func(done, total int, message string) {
+			if done == 2 {
+				cancel()
+			}
+		})
+		plan := &database.Plan{ProjectID: 1, Tasks: []database.PlanTask{{Title: "X"}, {Title: "Y"}, {Title: "Z"}}}
+		if _, err := server.db.WithContext(ctx).ImportPlan(plan, false); !errors.Is(err, context.Canceled) {
+			t.Fatalf("import returned %v, want context.Canceled", err)
+		}
+
+		var created int64
+		server.db.Model(&models.Task{}).Where("title IN ?", []string{"X", "Y", "Z"}).Count(&created)
+		if created != 0 {
+			t.Errorf("%d tasks of the cancelled import were kept", created)
+		}
+	})
+}
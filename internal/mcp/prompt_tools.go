@@ -0,0 +1,134 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/headless-pm/headless-project-management/internal/models"
+	"github.com/headless-pm/headless-project-management/internal/service"
+)
+
+// Prompt template operations
+func (s *EnhancedMCPServer) createPromptTemplate(ctx context.Context, args []byte) (*ToolResponse, error) {
+	var input struct {
+		Name        string                  `json:"name"`
+		Description string                  `json:"description"`
+		Arguments   []models.PromptArgument `json:"arguments"`
+		Template    string                  `json:"template"`
+	}
+	if err := UnmarshalArgs(args, &input); err != nil {
+		return ErrorResponse(err), nil
+	}
+	if err := service.CheckPromptName(input.Name); err != nil {
+		return ErrorResponse(err), nil
+	}
+	if err := service.ValidatePromptTemplate(input.Template); err != nil {
+		return ErrorResponse(err), nil
+	}
+
+	template := &models.PromptTemplate{
+		Name:        input.Name,
+		Description: input.Description,
+		Arguments:   input.Arguments,
+		Template:    input.Template,
+	}
+	if err := s.db.CreatePromptTemplate(template); err != nil {
+		return ErrorResponse(err), nil
+	}
+
+	return SuccessResponse(template), nil
+}
+
+func (s *EnhancedMCPServer) updatePromptTemplate(ctx context.Context, args []byte) (*ToolResponse, error) {
+	var input struct {
+		TemplateID  uint                     `json:"template_id"`
+		Name        string                   `json:"name"`
+		Description *string                  `json:"description"`
+		Arguments   *[]models.PromptArgument `json:"arguments"`
+		Template    string                   `json:"template"`
+	}
+	if err := UnmarshalArgs(args, &input); err != nil {
+		return ErrorResponse(err), nil
+	}
+
+	template, err := s.db.GetPromptTemplate(input.TemplateID)
+	if err != nil {
+		return ErrorResponse(fmt.Errorf("prompt template not found: %w", err)), nil
+	}
+	if err := requireOwnerOrAdmin(ctx, templateOwner(template), "this prompt template"); err != nil {
+		return ErrorResponse(err), nil
+	}
+
+	if input.Name != "" {
+		if err := service.CheckPromptName(input.Name); err != nil {
+			return ErrorResponse(err), nil
+		}
+		template.Name = input.Name
+	}
+	if input.Description != nil {
+		template.Description = *input.Description
+	}
+	if input.Arguments != nil {
+		template.Arguments = *input.Arguments
+	}
+	if input.Template != "" {
+		if err := service.ValidatePromptTemplate(input.Template); err != nil {
+			return ErrorResponse(err), nil
+		}
+		template.Template = input.Template
+	}
+
+	if err := s.db.UpdatePromptTemplate(template); err != nil {
+		return ErrorResponse(err), nil
+	}
+
+	return SuccessResponse(template), nil
+}
+
+func (s *EnhancedMCPServer) deletePromptTemplate(ctx context.Context, args []byte) (*ToolResponse, error) {
+	var input struct {
+		TemplateID uint `json:"template_id"`
+	}
+	if err := UnmarshalArgs(args, &input); err != nil {
+		return ErrorResponse(err), nil
+	}
+
+	template, err := s.db.GetPromptTemplate(input.TemplateID)
+	if err != nil {
+		return ErrorResponse(fmt.Errorf("prompt template not found: %w", err)), nil
+	}
+	if err := requireOwnerOrAdmin(ctx, templateOwner(template), "this prompt template"); err != nil {
+		return ErrorResponse(err), nil
+	}
+	if err := s.db.DeletePromptTemplate(input.TemplateID); err != nil {
+		return ErrorResponse(err), nil
+	}
+
+	return SuccessResponse(map[string]interface{}{
+		"message": "Prompt template deleted successfully",
+	}), nil
+}
+
+// templateOwner returns the creator of a prompt template, or 0 when unknown
+func templateOwner(template *models.PromptTemplate) uint {
+	if template.CreatedBy == nil {
+		return 0
+	}
+	return *template.CreatedBy
+}
+
+// promptArgumentsSchema describes the arguments of a prompt template
+func promptArgumentsSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "array",
+		"items": map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"name":        map[string]string{"type": "string"},
+				"description": map[string]string{"type": "string"},
+				"required":    map[string]string{"type": "boolean"},
+			},
+			"required": []string{"name"},
+		},
+	}
+}
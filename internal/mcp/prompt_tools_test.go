@@ -0,0 +1,35 @@
+package mcp
+
+import "testing"
+
+func TestPromptTemplateTools(t *testing.T) {
+	_, handler := newTestServer(t)
+	alice := map[string]string{"X-Test-User": "1", "X-Test-Scopes": "read,write"}
+	bob := map[string]string{"X-Test-User": "2", "X-Test-Scopes": "read,write"}
+	mustCallTool(t, handler, nil, "create_user", `{"username":"alice","email":"alice@example.com","password":"secret123"}`)
+	mustCallTool(t, handler, nil, "create_user", `{"username":"bob","email":"bob@example.com","password":"secret123"}`)
+
+	t.Run("built-in prompt names are reserved", func(t *testing.T) {
+		if res := callTool(t, handler, alice, "create_prompt_template", `{"name":"daily_standup","template":"Say hi"}`); res["isError"] != true {
+			t.Errorf("replaced a built-in prompt: %v", res)
+		}
+		template := structured(mustCallTool(t, handler, alice, "create_prompt_template", `{"name":"retro","template":"What went well?"}`))
+		if template["created_by"] != float64(1) {
+			t.Errorf("created_by = %v, want alice", template["created_by"])
+		}
+		if res := callTool(t, handler, alice, "update_prompt_template", `{"template_id":1,"name":"release_notes"}`); res["isError"] != true {
+			t.Errorf("renamed a template to a built-in prompt: %v", res)
+		}
+	})
+
+	t.Run("only creators and admins change templates", func(t *testing.T) {
+		if res := callTool(t, handler, bob, "update_prompt_template", `{"template_id":1,"template":"Blame alice"}`); res["isError"] != true {
+			t.Errorf("bob edited alice's template: %v", res)
+		}
+		if res := callTool(t, handler, bob, "delete_prompt_template", `{"template_id":1}`); res["isError"] != true {
+			t.Errorf("bob deleted alice's template: %v", res)
+		}
+		mustCallTool(t, handler, alice, "update_prompt_template", `{"template_id":1,"template":"What went well? What did not?"}`)
+		mustCallTool(t, handler, nil, "delete_prompt_template", `{"template_id":1}`)
+	})
+}
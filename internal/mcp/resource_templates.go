@@ -0,0 +1,635 @@
+package mcp
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/headless-pm/headless-project-management/internal/models"
+)
+
+const markdownMimeType = "text/markdown"
+
+// boardColumns are the task statuses shown on a project board, in order
+var boardColumns = []models.TaskStatus{
+	models.TaskStatusTodo,
+	models.TaskStatusInProgress,
+	models.TaskStatusReview,
+	models.TaskStatusDone,
+}
+
+var priorityRank = map[models.TaskPriority]int{
+	models.TaskPriorityUrgent: 0,
+	models.TaskPriorityHigh:   1,
+	models.TaskPriorityMedium: 2,
+	models.TaskPriorityLow:    3,
+}
+
+// ListResourceTemplates returns the parameterised resources for individual
+// entities. Each renders as JSON, or as Markdown with ?format=markdown.
+func (s *EnhancedMCPServer) ListResourceTemplates() []ResourceTemplate {
+	return []ResourceTemplate{
+		{
+			URITemplate: "project://{id}{?format}",
+			Name:        "Project",
+			Description: "A project with task counts by status, epics, the active sprint and milestones. format is json (default) or markdown",
+			MimeType:    "application/json",
+		},
+		{
+			URITemplate: "project://{id}/board{?format}",
+			Name:        "Project Board",
+			Description: "The tasks of a project grouped into board columns by status. format is json (default) or markdown",
+			MimeType:    "application/json",
+		},
+		{
+			URITemplate: "task://{id}{?format}",
+			Name:        "Task",
+			Description: "A task with its comments, activity, dependencies, subtasks and attachments. format is json (default) or markdown",
+			MimeType:    "application/json",
+		},
+		{
+			URITemplate: "epic://{id}{?format}",
+			Name:        "Epic",
+			Description: "An epic with its progress and tasks. format is json (default) or markdown",
+			MimeType:    "application/json",
+		},
+		{
+			URITemplate: "user://{id}/assigned{?format}",
+			Name:        "Assigned Tasks",
+			Description: "The open tasks assigned to a user across projects, most urgent first. format is json (default) or markdown",
+			MimeType:    "application/json",
+		},
+	}
+}
+
+// entityURI is a parsed entity resource URI such as task://12?format=markdown
+type entityURI struct {
+	kind     string
+	id       uint
+	view     string // Path after the ID, e.g. "board"
+	markdown bool
+}
+
+// parseEntityURI parses the URIs of ListResourceTemplates; ok is false for
+// URIs of other shapes
+func parseEntityURI(uri string) (entityURI, bool, error) {
+	parsed, err := url.Parse(uri)
+	if err != nil {
+		return entityURI{}, false, nil
+	}
+	switch parsed.Scheme {
+	case "project", "task", "epic", "user":
+	default:
+		return entityURI{}, false, nil
+	}
+
+	id, err := strconv.ParseUint(parsed.Host, 10, 32)
+	if err != nil {
+		return entityURI{}, true, fmt.Errorf("invalid %s ID in %s", parsed.Scheme, uri)
+	}
+
+	entity := entityURI{
+		kind: parsed.Scheme,
+		id:   uint(id),
+		view: strings.Trim(parsed.Path, "/"),
+	}
+	switch format := parsed.Query().Get("format"); format {
+	case "", "json":
+	case "markdown", "md":
+		entity.markdown = true
+	default:
+		return entityURI{}, true, fmt.Errorf("unsupported format %q: use json or markdown", format)
+	}
+	return entity, true, nil
+}
+
+// baseResourceURI strips the query, so subscriptions to any format of a resource
+// are notified together
+func baseResourceURI(uri string) string {
+	if i := strings.Index(uri, "?"); i >= 0 {
+		return uri[:i]
+	}
+	return uri
+}
+
+// getEntityResource reads a resource of ListResourceTemplates
+func (s *EnhancedMCPServer) getEntityResource(uri string, entity entityURI) (*ResourceContent, error) {
+	var data interface{}
+	var markdown func() string
+	var err error
+
+	switch {
+	case entity.kind == "project" && entity.view == "":
+		var project map[string]interface{}
+		project, err = s.projectResource(entity.id)
+		data, markdown = project, func() string { return projectMarkdown(project) }
+	case entity.kind == "project" && entity.view == "board":
+		var board map[string]interface{}
+		board, err = s.boardResource(entity.id)
+		data, markdown = board, func() string { return boardMarkdown(board) }
+	case entity.kind == "task" && entity.view == "":
+		var task map[string]interface{}
+		task, err = s.taskResource(entity.id)
+		data, markdown = task, func() string { return taskMarkdown(task) }
+	case entity.kind == "epic" && entity.view == "":
+		var epic *models.Epic
+		epic, err = s.epicResource(entity.id)
+		data, markdown = epic, func() string { return epicMarkdown(epic) }
+	case entity.kind == "user" && entity.view == "assigned":
+		var assigned map[string]interface{}
+		assigned, err = s.assignedResource(entity.id)
+		data, markdown = assigned, func() string { return assignedMarkdown(assigned) }
+	default:
+		return nil, fmt.Errorf("resource not found: %s", uri)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if entity.markdown {
+		return &ResourceContent{
+			URI:      uri,
+			MimeType: markdownMimeType,
+			Content:  markdown(),
+		}, nil
+	}
+	return &ResourceContent{
+		URI:      uri,
+		MimeType: "application/json",
+		Content:  data,
+	}, nil
+}
+
+func (s *EnhancedMCPServer) projectResource(id uint) (map[string]interface{}, error) {
+	project, err := s.db.GetProject(id)
+	if err != nil {
+		return nil, fmt.Errorf("project %d not found", id)
+	}
+	// Tasks and epics are summarized below; project://{id}/board lists the tasks
+	project.Tasks = nil
+	project.Epics = nil
+
+	type statusCount struct {
+		Status models.TaskStatus
+		Count  int64
+	}
+	var counts []statusCount
+	if err := s.db.Model(&models.Task{}).
+		Select("status, COUNT(*) AS count").
+		Where("project_id = ?", id).
+		Group("status").
+		Scan(&counts).Error; err != nil {
+		return nil, err
+	}
+	taskCounts := make(map[models.TaskStatus]int64)
+	var total int64
+	for _, c := range counts {
+		taskCounts[c.Status] = c.Count
+		total += c.Count
+	}
+
+	epics, err := s.db.GetEpicsByProject(id)
+	if err != nil {
+		return nil, err
+	}
+	epicInfo := make([]map[string]interface{}, 0, len(epics))
+	for _, epic := range epics {
+		epicInfo = append(epicInfo, map[string]interface{}{
+			"id":       epic.ID,
+			"name":     epic.Name,
+			"status":   epic.Status,
+			"progress": epic.Progress,
+		})
+	}
+
+	milestones, err := s.db.ListMilestones(&id, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	result := map[string]interface{}{
+		"project":     project,
+		"total_tasks": total,
+		"task_counts": taskCounts,
+		"epics":       epicInfo,
+		"milestones":  milestones,
+	}
+	if sprint, err := s.db.GetActiveSprint(id); err == nil {
+		result["active_sprint"] = sprint
+	}
+	return result, nil
+}
+
+func (s *EnhancedMCPServer) boardResource(id uint) (map[string]interface{}, error) {
+	project, err := s.db.GetProject(id)
+	if err != nil {
+		return nil, fmt.Errorf("project %d not found", id)
+	}
+
+	var tasks []models.Task
+	if err := s.db.Where("project_id = ? AND status IN ?", id, boardColumns).
+		Preload("AssigneeUser").
+		Preload("Labels").
+		Find(&tasks).Error; err != nil {
+		return nil, err
+	}
+	sortTasksByPriority(tasks)
+
+	columns := make([]map[string]interface{}, 0, len(boardColumns))
+	for _, status := range boardColumns {
+		column := make([]map[string]interface{}, 0)
+		for _, task := range tasks {
+			if task.Status == status {
+				column = append(column, taskSummary(task))
+			}
+		}
+		columns = append(columns, map[string]interface{}{
+			"status": status,
+			"count":  len(column),
+			"tasks":  column,
+		})
+	}
+
+	return map[string]interface{}{
+		"project_id":   project.ID,
+		"project_name": project.Name,
+		"columns":      columns,
+	}, nil
+}
+
+func (s *EnhancedMCPServer) taskResource(id uint) (map[string]interface{}, error) {
+	var task models.Task
+	if err := s.db.Preload("Project").
+		Preload("AssigneeUser").
+		Preload("Epic").
+		Preload("Sprint").
+		Preload("Milestone").
+		Preload("Subtasks").
+		Preload("Attachments").
+		Preload("Labels").
+		First(&task, id).Error; err != nil {
+		return nil, fmt.Errorf("task %d not found", id)
+	}
+
+	comments, err := s.db.ListComments(id, true)
+	if err != nil {
+		return nil, err
+	}
+	activities, err := s.db.GetTaskActivities(id)
+	if err != nil {
+		return nil, err
+	}
+	dependencies, err := s.db.GetTaskDependencies(id)
+	if err != nil {
+		return nil, err
+	}
+	dependents, err := s.db.GetTaskDependents(id)
+	if err != nil {
+		return nil, err
+	}
+
+	dependsOn := make([]map[string]interface{}, 0, len(dependencies))
+	for _, dependency := range dependencies {
+		if dependency.DependsOn != nil {
+			dependsOn = append(dependsOn, dependencySummary(*dependency.DependsOn, dependency.Type))
+		}
+	}
+	blocks := make([]map[string]interface{}, 0, len(dependents))
+	for _, dependent := range dependents {
+		if dependent.Task != nil {
+			blocks = append(blocks, dependencySummary(*dependent.Task, dependent.Type))
+		}
+	}
+
+	return map[string]interface{}{
+		"task":        task,
+		"comments":    comments,
+		"activity":    activities,
+		"depends_on":  dependsOn,
+		"blocks":      blocks,
+		"attachments": task.Attachments,
+	}, nil
+}
+
+func (s *EnhancedMCPServer) epicResource(id uint) (*models.Epic, error) {
+	epic, err := s.db.GetEpic(id)
+	if err != nil {
+		return nil, fmt.Errorf("epic %d not found", id)
+	}
+	if progress, err := s.db.CalculateEpicProgress(id); err == nil {
+		epic.Progress = progress
+	}
+	sortTasksByPriority(epic.Tasks)
+	return epic, nil
+}
+
+func (s *EnhancedMCPServer) assignedResource(id uint) (map[string]interface{}, error) {
+	user, err := s.db.GetUserByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("user %d not found", id)
+	}
+
+	var tasks []models.Task
+	if err := s.db.Where("assignee_id = ? AND status NOT IN ?", id, []models.TaskStatus{models.TaskStatusDone, models.TaskStatusCancelled}).
+		Preload("Project").
+		Preload("Labels").
+		Find(&tasks).Error; err != nil {
+		return nil, err
+	}
+	sortTasksByPriority(tasks)
+
+	summaries := make([]map[string]interface{}, 0, len(tasks))
+	for _, task := range tasks {
+		summary := taskSummary(task)
+		if task.Project != nil {
+			summary["project_name"] = task.Project.Name
+		}
+		summaries = append(summaries, summary)
+	}
+
+	return map[string]interface{}{
+		"user_id":  user.ID,
+		"username": user.Username,
+		"tasks":    summaries,
+	}, nil
+}
+
+// sortTasksByPriority orders tasks from urgent to low, then by due date
+func sortTasksByPriority(tasks []models.Task) {
+	sort.SliceStable(tasks, func(i, j int) bool {
+		a, b := tasks[i], tasks[j]
+		if priorityRank[a.Priority] != priorityRank[b.Priority] {
+			return priorityRank[a.Priority] < priorityRank[b.Priority]
+		}
+		if a.DueDate != nil && b.DueDate != nil {
+			return a.DueDate.Before(*b.DueDate)
+		}
+		return a.DueDate != nil && b.DueDate == nil
+	})
+}
+
+func taskSummary(task models.Task) map[string]interface{} {
+	summary := map[string]interface{}{
+		"id":         task.ID,
+		"project_id": task.ProjectID,
+		"title":      task.Title,
+		"status":     task.Status,
+		"priority":   task.Priority,
+	}
+	if task.AssigneeUser != nil {
+		summary["assignee"] = task.AssigneeUser.Username
+	} else if task.Assignee != "" {
+		summary["assignee"] = task.Assignee
+	}
+	if task.StoryPoints != nil {
+		summary["story_points"] = *task.StoryPoints
+	}
+	if task.DueDate != nil {
+		summary["due_date"] = task.DueDate
+	}
+	if len(task.Labels) > 0 {
+		labels := make([]string, 0, len(task.Labels))
+		for _, label := range task.Labels {
+			labels = append(labels, label.Name)
+		}
+		summary["labels"] = labels
+	}
+	return summary
+}
+
+func dependencySummary(task models.Task, dependencyType string) map[string]interface{} {
+	return map[string]interface{}{
+		"task_id": task.ID,
+		"title":   task.Title,
+		"status":  task.Status,
+		"type":    dependencyType,
+	}
+}
+
+// Markdown renderings
+
+func projectMarkdown(data map[string]interface{}) string {
+	project := data["project"].(*models.Project)
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# %s\n\n", project.Name)
+	fmt.Fprintf(&b, "- ID: %d\n- Status: %s\n", project.ID, project.Status)
+	if project.StartDate != nil || project.EndDate != nil {
+		fmt.Fprintf(&b, "- Dates: %s to %s\n", markdownDate(project.StartDate), markdownDate(project.EndDate))
+	}
+	if project.Description != "" {
+		fmt.Fprintf(&b, "\n%s\n", project.Description)
+	}
+
+	counts := data["task_counts"].(map[models.TaskStatus]int64)
+	fmt.Fprintf(&b, "\n## Tasks (%d)\n\n", data["total_tasks"])
+	for _, status := range append(boardColumns, models.TaskStatusCancelled) {
+		fmt.Fprintf(&b, "- %s: %d\n", status, counts[status])
+	}
+
+	if sprint, ok := data["active_sprint"].(*models.Sprint); ok {
+		fmt.Fprintf(&b, "\n## Active sprint\n\n%s (%s to %s)", sprint.Name, markdownDate(sprint.StartDate), markdownDate(sprint.EndDate))
+		if sprint.Goal != "" {
+			fmt.Fprintf(&b, ": %s", sprint.Goal)
+		}
+		b.WriteString("\n")
+	}
+
+	if epics := data["epics"].([]map[string]interface{}); len(epics) > 0 {
+		b.WriteString("\n## Epics\n\n")
+		for _, epic := range epics {
+			fmt.Fprintf(&b, "- #%d %s (%s, %d%%)\n", epic["id"], epic["name"], epic["status"], epic["progress"])
+		}
+	}
+
+	if milestones := data["milestones"].([]models.Milestone); len(milestones) > 0 {
+		b.WriteString("\n## Milestones\n\n")
+		for _, milestone := range milestones {
+			fmt.Fprintf(&b, "- %s (%s, due %s)\n", milestone.Name, milestone.Status, markdownDate(milestone.DueDate))
+		}
+	}
+	return b.String()
+}
+
+func boardMarkdown(data map[string]interface{}) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s board\n", data["project_name"])
+	for _, column := range data["columns"].([]map[string]interface{}) {
+		fmt.Fprintf(&b, "\n## %s (%d)\n\n", column["status"], column["count"])
+		tasks := column["tasks"].([]map[string]interface{})
+		if len(tasks) == 0 {
+			b.WriteString("_No tasks_\n")
+		}
+		for _, task := range tasks {
+			fmt.Fprintf(&b, "- %s\n", taskSummaryMarkdown(task))
+		}
+	}
+	return b.String()
+}
+
+func taskMarkdown(data map[string]interface{}) string {
+	task := data["task"].(models.Task)
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# #%d %s\n\n", task.ID, task.Title)
+	fmt.Fprintf(&b, "- Status: %s\n- Priority: %s\n", task.Status, task.Priority)
+	if task.Project != nil {
+		fmt.Fprintf(&b, "- Project: %s (#%d)\n", task.Project.Name, task.ProjectID)
+	}
+	if task.AssigneeUser != nil {
+		fmt.Fprintf(&b, "- Assignee: @%s\n", task.AssigneeUser.Username)
+	} else if task.Assignee != "" {
+		fmt.Fprintf(&b, "- Assignee: @%s\n", task.Assignee)
+	}
+	if task.Epic != nil {
+		fmt.Fprintf(&b, "- Epic: %s (#%d)\n", task.Epic.Name, task.Epic.ID)
+	}
+	if task.Sprint != nil {
+		fmt.Fprintf(&b, "- Sprint: %s\n", task.Sprint.Name)
+	}
+	if task.Milestone != nil {
+		fmt.Fprintf(&b, "- Milestone: %s\n", task.Milestone.Name)
+	}
+	if task.StoryPoints != nil {
+		fmt.Fprintf(&b, "- Story points: %d\n", *task.StoryPoints)
+	}
+	if task.DueDate != nil {
+		fmt.Fprintf(&b, "- Due: %s\n", markdownDate(task.DueDate))
+	}
+	if len(task.Labels) > 0 {
+		labels := make([]string, 0, len(task.Labels))
+		for _, label := range task.Labels {
+			labels = append(labels, label.Name)
+		}
+		fmt.Fprintf(&b, "- Labels: %s\n", strings.Join(labels, ", "))
+	}
+	if task.Description != "" {
+		fmt.Fprintf(&b, "\n## Description\n\n%s\n", task.Description)
+	}
+
+	dependsOn := data["depends_on"].([]map[string]interface{})
+	blocks := data["blocks"].([]map[string]interface{})
+	if len(dependsOn) > 0 || len(blocks) > 0 {
+		b.WriteString("\n## Dependencies\n\n")
+		for _, dependency := range dependsOn {
+			fmt.Fprintf(&b, "- Depends on #%d %s (%s)\n", dependency["task_id"], dependency["title"], dependency["status"])
+		}
+		for _, dependent := range blocks {
+			fmt.Fprintf(&b, "- Blocks #%d %s (%s)\n", dependent["task_id"], dependent["title"], dependent["status"])
+		}
+	}
+
+	if len(task.Subtasks) > 0 {
+		b.WriteString("\n## Subtasks\n\n")
+		for _, subtask := range task.Subtasks {
+			check := " "
+			if subtask.Status == models.TaskStatusDone {
+				check = "x"
+			}
+			fmt.Fprintf(&b, "- [%s] #%d %s\n", check, subtask.ID, subtask.Title)
+		}
+	}
+
+	if len(task.Attachments) > 0 {
+		b.WriteString("\n## Attachments\n\n")
+		for _, attachment := range task.Attachments {
+			fmt.Fprintf(&b, "- %s (%s, %d bytes)\n", attachment.Filename, attachment.MimeType, attachment.Size)
+		}
+	}
+
+	if comments := data["comments"].([]models.Comment); len(comments) > 0 {
+		b.WriteString("\n## Comments\n")
+		for _, comment := range comments {
+			writeCommentMarkdown(&b, comment, 0)
+		}
+	}
+
+	if activities := data["activity"].([]models.Activity); len(activities) > 0 {
+		b.WriteString("\n## Activity\n\n")
+		for _, activity := range activities {
+			description := activity.Description
+			if description == "" {
+				description = activity.Action
+			}
+			fmt.Fprintf(&b, "- %s %s: %s\n", activity.CreatedAt.Format("2006-01-02 15:04"), activity.UserName, description)
+		}
+	}
+	return b.String()
+}
+
+func writeCommentMarkdown(b *strings.Builder, comment models.Comment, depth int) {
+	indent := strings.Repeat("  ", depth)
+	fmt.Fprintf(b, "\n%s- **@%s** %s\n", indent, comment.Author, comment.CreatedAt.Format("2006-01-02 15:04"))
+	for _, line := range strings.Split(comment.Content, "\n") {
+		fmt.Fprintf(b, "%s  %s\n", indent, line)
+	}
+	for _, reply := range comment.Replies {
+		writeCommentMarkdown(b, reply, depth+1)
+	}
+}
+
+func epicMarkdown(epic *models.Epic) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Epic #%d %s\n\n", epic.ID, epic.Name)
+	fmt.Fprintf(&b, "- Status: %s\n- Progress: %d%%\n", epic.Status, epic.Progress)
+	if epic.StartDate != nil || epic.EndDate != nil {
+		fmt.Fprintf(&b, "- Dates: %s to %s\n", markdownDate(epic.StartDate), markdownDate(epic.EndDate))
+	}
+	if epic.Description != "" {
+		fmt.Fprintf(&b, "\n%s\n", epic.Description)
+	}
+
+	fmt.Fprintf(&b, "\n## Tasks (%d)\n\n", len(epic.Tasks))
+	if len(epic.Tasks) == 0 {
+		b.WriteString("_No tasks_\n")
+	}
+	for _, task := range epic.Tasks {
+		fmt.Fprintf(&b, "- %s\n", taskSummaryMarkdown(taskSummary(task)))
+	}
+	return b.String()
+}
+
+func assignedMarkdown(data map[string]interface{}) string {
+	var b strings.Builder
+	tasks := data["tasks"].([]map[string]interface{})
+	fmt.Fprintf(&b, "# Open tasks assigned to @%s (%d)\n\n", data["username"], len(tasks))
+	if len(tasks) == 0 {
+		b.WriteString("_No open tasks_\n")
+	}
+	for _, task := range tasks {
+		line := taskSummaryMarkdown(task)
+		if project, ok := task["project_name"]; ok {
+			line += fmt.Sprintf(" in %s", project)
+		}
+		fmt.Fprintf(&b, "- %s\n", line)
+	}
+	return b.String()
+}
+
+// taskSummaryMarkdown renders a taskSummary as one line
+func taskSummaryMarkdown(task map[string]interface{}) string {
+	line := fmt.Sprintf("#%d [%s] %s (%s", task["id"], task["priority"], task["title"], task["status"])
+	if assignee, ok := task["assignee"]; ok {
+		line += fmt.Sprintf(", @%s", assignee)
+	}
+	if points, ok := task["story_points"]; ok {
+		line += fmt.Sprintf(", %d pts", points)
+	}
+	if due, ok := task["due_date"].(*time.Time); ok {
+		line += ", due " + markdownDate(due)
+	}
+	if labels, ok := task["labels"].([]string); ok {
+		line += ", " + strings.Join(labels, ", ")
+	}
+	return line + ")"
+}
+
+func markdownDate(t *time.Time) string {
+	if t == nil {
+		return "?"
+	}
+	return t.Format("2006-01-02")
+}
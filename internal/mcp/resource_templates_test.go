@@ -0,0 +1,116 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// readResource reads a resource and returns its contents, or the JSON-RPC error code
+func readResource(t *testing.T, handler http.Handler, headers map[string]string, uri string) (map[string]interface{}, int) {
+	t.Helper()
+	exchange := post(t, handler, fmt.Sprintf(`{"jsonrpc":"2.0","id":1,"method":"resources/read","params":{"uri":%q}}`, uri), headers)
+	if _, failed := exchange.message["error"]; failed {
+		return nil, errorCode(t, exchange.message)
+	}
+	contents, _ := result(t, exchange.message)["contents"].([]interface{})
+	if len(contents) != 1 {
+		t.Fatalf("%s returned %d contents, want 1", uri, len(contents))
+	}
+	return contents[0].(map[string]interface{}), 0
+}
+
+func TestParseEntityURI(t *testing.T) {
+	tests := []struct {
+		uri      string
+		want     entityURI
+		ok       bool
+		hasError bool
+	}{
+		{uri: "task://12", want: entityURI{kind: "task", id: 12}, ok: true},
+		{uri: "project://3/board?format=markdown", want: entityURI{kind: "project", id: 3, view: "board", markdown: true}, ok: true},
+		{uri: "user://4/assigned?format=json", want: entityURI{kind: "user", id: 4, view: "assigned"}, ok: true},
+		{uri: "epic://x", ok: true, hasError: true},
+		{uri: "task://1?format=xml", ok: true, hasError: true},
+		{uri: "projects://list"},
+	}
+	for _, tt := range tests {
+		got, ok, err := parseEntityURI(tt.uri)
+		if ok != tt.ok || (err != nil) != tt.hasError || (err == nil && got != tt.want) {
+			t.Errorf("parseEntityURI(%q) = %+v, %v, %v", tt.uri, got, ok, err)
+		}
+	}
+	if base := baseResourceURI("task://12?format=markdown"); base != "task://12" {
+		t.Errorf("baseResourceURI = %s, want task://12", base)
+	}
+}
+
+func TestResourceTemplates(t *testing.T) {
+	_, handler := newTestServer(t)
+	mustCallTool(t, handler, nil, "create_user", `{"username":"alice","email":"alice@example.com","password":"secret123"}`)
+	mustCallTool(t, handler, nil, "create_project", `{"name":"Apollo"}`)
+	mustCallTool(t, handler, nil, "create_task", `{"project_id":1,"title":"Paint fins","priority":"low","assignee_id":1}`)
+	mustCallTool(t, handler, nil, "create_task", `{"project_id":1,"title":"Fix leak","priority":"urgent","assignee_id":1}`)
+	mustCallTool(t, handler, nil, "create_task", `{"project_id":1,"title":"Launch","status":"done","assignee_id":1}`)
+
+	t.Run("project counts tasks by status", func(t *testing.T) {
+		content, code := readResource(t, handler, nil, "project://1")
+		if code != 0 {
+			t.Fatalf("read failed with code %d", code)
+		}
+		var project map[string]interface{}
+		if err := json.Unmarshal([]byte(content["text"].(string)), &project); err != nil {
+			t.Fatal(err)
+		}
+		counts, _ := project["task_counts"].(map[string]interface{})
+		if project["total_tasks"] != float64(3) || counts["todo"] != float64(2) || counts["done"] != float64(1) {
+			t.Errorf("project = %v, want 3 tasks, 2 todo and 1 done", project)
+		}
+	})
+
+	t.Run("board columns sort tasks by priority", func(t *testing.T) {
+		content, _ := readResource(t, handler, nil, "project://1/board")
+		var board struct {
+			Columns []struct {
+				Status string `json:"status"`
+				Tasks  []struct {
+					Title string `json:"title"`
+				} `json:"tasks"`
+			} `json:"columns"`
+		}
+		if err := json.Unmarshal([]byte(content["text"].(string)), &board); err != nil {
+			t.Fatal(err)
+		}
+		if len(board.Columns) != 4 || board.Columns[0].Status != "todo" {
+			t.Fatalf("columns = %+v, want todo first of four", board.Columns)
+		}
+		if todo := board.Columns[0].Tasks; len(todo) != 2 || todo[0].Title != "Fix leak" {
+			t.Errorf("todo column = %+v, want the urgent task first", todo)
+		}
+	})
+
+	t.Run("markdown renders for people", func(t *testing.T) {
+		content, _ := readResource(t, handler, nil, "task://2?format=markdown")
+		if content["mimeType"] != markdownMimeType || !strings.Contains(content["text"].(string), "Fix leak") {
+			t.Errorf("task markdown = %v", content)
+		}
+	})
+
+	t.Run("assigned work leaves out finished tasks", func(t *testing.T) {
+		content, _ := readResource(t, handler, nil, "user://1/assigned?format=markdown")
+		text := content["text"].(string)
+		if strings.Contains(text, "Launch") || strings.Index(text, "Fix leak") > strings.Index(text, "Paint fins") {
+			t.Errorf("assigned tasks:\n%s", text)
+		}
+	})
+
+	t.Run("missing entities are not found", func(t *testing.T) {
+		for _, uri := range []string{"task://99", "project://1/nothing", "epic://abc", "task://1?format=xml"} {
+			if _, code := readResource(t, handler, nil, uri); code != codeResourceNotFound {
+				t.Errorf("%s: got code %d, want %d", uri, code, codeResourceNotFound)
+			}
+		}
+	})
+}
@@ -2,6 +2,7 @@ package mcp
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -41,11 +42,21 @@ func (s *EnhancedMCPServer) ListResources() []Resource {
 			Description: "All labels across projects",
 			MimeType:    "application/json",
 		},
+		{
+			URI:         "notifications://me",
+			Name:        "My Notifications",
+			Description: "Unread notifications of the authenticated user: assignments, mentions, status changes, due dates and unblocked tasks",
+			MimeType:    "application/json",
+		},
 	}
 }
 
 // GetResource retrieves a specific resource by URI
 func (s *EnhancedMCPServer) GetResource(ctx context.Context, uri string) (*ResourceContent, error) {
+	if err := s.authorizeResource(ctx, uri); err != nil {
+		return nil, err
+	}
+
 	switch uri {
 	case "projects://list":
 		return s.getProjectsList()
@@ -57,11 +68,28 @@ func (s *EnhancedMCPServer) GetResource(ctx context.Context, uri string) (*Resou
 		return s.getActiveEpics()
 	case "labels://all":
 		return s.getAllLabels()
+	case "notifications://me":
+		return s.getMyNotifications(ctx)
 	default:
+		entity, ok, err := parseEntityURI(uri)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return s.getEntityResource(uri, entity)
+		}
 		return nil, fmt.Errorf("resource not found: %s", uri)
 	}
 }
 
+// resourceErrorCode is the JSON-RPC error code for a failed resource read
+func resourceErrorCode(err error) int {
+	if errors.Is(err, ErrPermissionDenied) {
+		return codeInvalidRequest
+	}
+	return codeResourceNotFound
+}
+
 func (s *EnhancedMCPServer) getProjectsList() (*ResourceContent, error) {
 	var projects []models.Project
 	if err := s.db.Find(&projects).Error; err != nil {
@@ -239,4 +267,27 @@ func (s *EnhancedMCPServer) getAllLabels() (*ResourceContent, error) {
 		MimeType: "application/json",
 		Content:  labelInfo,
 	}, nil
+}
+
+func (s *EnhancedMCPServer) getMyNotifications(ctx context.Context) (*ResourceContent, error) {
+	userID, ok := userIDFromContext(ctx)
+	if !ok {
+		return nil, fmt.Errorf("notifications://me requires a user API token")
+	}
+
+	notifications, err := s.db.ListNotifications(userID, true, 100)
+	if err != nil {
+		return nil, err
+	}
+	unreadCount, _ := s.db.CountUnreadNotifications(userID)
+
+	return &ResourceContent{
+		URI:      "notifications://me",
+		MimeType: "application/json",
+		Content: map[string]interface{}{
+			"user_id":       userID,
+			"unread_count":  unreadCount,
+			"notifications": notifications,
+		},
+	}, nil
 }
\ No newline at end of file
@@ -0,0 +1,338 @@
+package mcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// FieldError is one violation of a tool's input schema. Field is the path of
+// the offending argument, e.g. "labels[2]"; it is empty for the arguments object itself.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ArgumentError reports the arguments of a tool call that do not match its input schema
+type ArgumentError struct {
+	Tool   string       `json:"tool,omitempty"`
+	Fields []FieldError `json:"fields"`
+}
+
+func (e *ArgumentError) Error() string {
+	messages := make([]string, 0, len(e.Fields))
+	for _, field := range e.Fields {
+		if field.Field == "" {
+			messages = append(messages, field.Message)
+		} else {
+			messages = append(messages, field.Field+": "+field.Message)
+		}
+	}
+	if e.Tool == "" {
+		return "invalid arguments: " + strings.Join(messages, "; ")
+	}
+	return fmt.Sprintf("invalid arguments for %s: %s", e.Tool, strings.Join(messages, "; "))
+}
+
+// ArgumentErrorResponse returns the tool result for invalid arguments; the
+// content lists every violation so the caller can correct its call
+func ArgumentErrorResponse(err *ArgumentError) *ToolResponse {
+	content := map[string]interface{}{
+		"error":  err.Error(),
+		"fields": err.Fields,
+	}
+	if err.Tool != "" {
+		content["tool"] = err.Tool
+	}
+	return &ToolResponse{
+		Content: content,
+		IsError: true,
+	}
+}
+
+var (
+	toolSchemasOnce sync.Once
+	toolSchemas     map[string]map[string]interface{}
+)
+
+// toolSchema returns the input schema of a tool in its JSON form, as clients
+// see it in tools/list
+func toolSchema(name string) (map[string]interface{}, bool) {
+	toolSchemasOnce.Do(func() {
+		toolSchemas = make(map[string]map[string]interface{})
+		for _, tool := range toolDefinitions() {
+			toolSchemas[tool.Name] = normalizeSchema(tool.InputSchema)
+		}
+	})
+	schema, ok := toolSchemas[name]
+	return schema, ok
+}
+
+// normalizeSchema converts a schema declared with Go maps and slices of any
+// type into plain JSON values. Tool arguments are closed: properties that are
+// not declared are rejected unless the schema says otherwise.
+func normalizeSchema(schema map[string]interface{}) map[string]interface{} {
+	var normalized map[string]interface{}
+	data, _ := json.Marshal(schema)
+	_ = json.Unmarshal(data, &normalized)
+	if normalized == nil {
+		normalized = map[string]interface{}{"type": "object"}
+	}
+	if _, ok := normalized["additionalProperties"]; !ok {
+		normalized["additionalProperties"] = false
+	}
+	return normalized
+}
+
+// ValidateToolArguments checks the arguments of a tool call against the
+// tool's input schema. Missing arguments are treated as an empty object.
+func ValidateToolArguments(tool string, args json.RawMessage) *ArgumentError {
+	schema, ok := toolSchema(tool)
+	if !ok {
+		return nil
+	}
+
+	args = bytes.TrimSpace(args)
+	if len(args) == 0 || bytes.Equal(args, []byte("null")) {
+		args = []byte("{}")
+	}
+
+	var value interface{}
+	decoder := json.NewDecoder(bytes.NewReader(args))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		return &ArgumentError{Tool: tool, Fields: []FieldError{{Message: "arguments are not valid JSON"}}}
+	}
+
+	var fields []FieldError
+	validateValue("", schema, value, &fields)
+	if len(fields) == 0 {
+		return nil
+	}
+	return &ArgumentError{Tool: tool, Fields: fields}
+}
+
+// validateValue checks value against the subset of JSON Schema the tool
+// definitions use: type, enum, properties, required, additionalProperties,
+// items and the numeric, length and size bounds
+func validateValue(path string, schema map[string]interface{}, value interface{}, fields *[]FieldError) {
+	fail := func(format string, args ...interface{}) {
+		*fields = append(*fields, FieldError{Field: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if expected, ok := schema["type"].(string); ok && !hasType(value, expected) {
+		if path == "" {
+			fail("arguments must be an %s, got %s", expected, typeName(value))
+		} else {
+			fail("must be %s %s, got %s", article(expected), expected, typeName(value))
+		}
+		return
+	}
+	if expected, ok := schema["type"].([]interface{}); ok {
+		names := make([]string, 0, len(expected))
+		matched := false
+		for _, option := range expected {
+			name, _ := option.(string)
+			names = append(names, name)
+			matched = matched || hasType(value, name)
+		}
+		if !matched {
+			fail("must be one of the types %s, got %s", strings.Join(names, ", "), typeName(value))
+			return
+		}
+	}
+
+	if enum, ok := schema["enum"].([]interface{}); ok && !inEnum(value, enum) {
+		options := make([]string, len(enum))
+		for i, option := range enum {
+			options[i] = fmt.Sprintf("%v", option)
+		}
+		fail("must be one of: %s", strings.Join(options, ", "))
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		validateObject(path, schema, v, fields)
+	case []interface{}:
+		if min, ok := schema["minItems"].(float64); ok && float64(len(v)) < min {
+			fail("must have at least %v items", min)
+		}
+		if max, ok := schema["maxItems"].(float64); ok && float64(len(v)) > max {
+			fail("must have at most %v items", max)
+		}
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			for i, item := range v {
+				validateValue(fmt.Sprintf("%s[%d]", path, i), items, item, fields)
+			}
+		}
+	case string:
+		length := float64(utf8.RuneCountInString(v))
+		if min, ok := schema["minLength"].(float64); ok && length < min {
+			fail("must be at least %v characters", min)
+		}
+		if max, ok := schema["maxLength"].(float64); ok && length > max {
+			fail("must be at most %v characters", max)
+		}
+	case json.Number:
+		n, _ := v.Float64()
+		if min, ok := schema["minimum"].(float64); ok && n < min {
+			fail("must be at least %v", min)
+		}
+		if max, ok := schema["maximum"].(float64); ok && n > max {
+			fail("must be at most %v", max)
+		}
+	}
+}
+
+func validateObject(path string, schema map[string]interface{}, object map[string]interface{}, fields *[]FieldError) {
+	properties, _ := schema["properties"].(map[string]interface{})
+
+	if required, ok := schema["required"].([]interface{}); ok {
+		for _, name := range required {
+			key, _ := name.(string)
+			if value, present := object[key]; !present || value == nil {
+				*fields = append(*fields, FieldError{Field: joinPath(path, key), Message: "is required"})
+			}
+		}
+	}
+
+	// Report fields in a stable order
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value := object[key]
+		if property, ok := properties[key].(map[string]interface{}); ok {
+			// null stands for an omitted optional argument
+			if value != nil {
+				validateValue(joinPath(path, key), property, value, fields)
+			}
+			continue
+		}
+
+		switch additional := schema["additionalProperties"].(type) {
+		case bool:
+			if !additional {
+				*fields = append(*fields, FieldError{Field: joinPath(path, key), Message: unknownFieldMessage(properties)})
+			}
+		case map[string]interface{}:
+			validateValue(joinPath(path, key), additional, value, fields)
+		}
+	}
+}
+
+func unknownFieldMessage(properties map[string]interface{}) string {
+	if len(properties) == 0 {
+		return "unknown field; this tool takes no arguments"
+	}
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return "unknown field; expected one of: " + strings.Join(names, ", ")
+}
+
+func hasType(value interface{}, expected string) bool {
+	switch expected {
+	case "object":
+		_, ok := value.(map[string]interface{})
+		return ok
+	case "array":
+		_, ok := value.([]interface{})
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "number":
+		_, ok := value.(json.Number)
+		return ok
+	case "integer":
+		n, ok := value.(json.Number)
+		if !ok {
+			return false
+		}
+		f, ok := new(big.Float).SetString(n.String())
+		return ok && f.IsInt()
+	case "null":
+		return value == nil
+	}
+	return true
+}
+
+func typeName(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case json.Number:
+		if hasType(v, "integer") {
+			return "integer"
+		}
+		return "number"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+func inEnum(value interface{}, enum []interface{}) bool {
+	for _, option := range enum {
+		if n, ok := value.(json.Number); ok {
+			if f, err := n.Float64(); err == nil && f == option {
+				return true
+			}
+			continue
+		}
+		if value == option {
+			return true
+		}
+	}
+	return false
+}
+
+func article(typeName string) string {
+	if strings.ContainsRune("aeiou", rune(typeName[0])) {
+		return "an"
+	}
+	return "a"
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// decodeArgumentError turns a decoding failure of UnmarshalArgs into an
+// ArgumentError naming the field, when the JSON error says which one it is
+func decodeArgumentError(err error) error {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		return &ArgumentError{
+			Fields: []FieldError{{
+				Field:   typeErr.Field,
+				Message: fmt.Sprintf("cannot use %s as %s", typeErr.Value, typeErr.Type),
+			}},
+		}
+	}
+	return err
+}
@@ -0,0 +1,215 @@
+package mcp
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/headless-pm/headless-project-management/internal/database"
+)
+
+// Every property a tool schema declares must be decoded by its handler, and
+// every field the handler decodes must be declared, or arguments are either
+// rejected by the validator or silently dropped.
+func TestToolSchemasMatchHandlers(t *testing.T) {
+	inputs := handlerInputFields(t)
+
+	for name := range (&EnhancedMCPServer{}).getToolHandlers() {
+		schema, ok := toolSchema(name)
+		if !ok {
+			t.Errorf("%s: no tool definition", name)
+			continue
+		}
+		decoded, ok := inputs[name]
+		if !ok {
+			t.Errorf("%s: handler not found in getToolHandlers", name)
+			continue
+		}
+
+		declared := map[string]bool{}
+		if properties, ok := schema["properties"].(map[string]interface{}); ok {
+			for property := range properties {
+				declared[property] = true
+			}
+		}
+
+		if missing := difference(declared, decoded); len(missing) > 0 {
+			t.Errorf("%s: schema declares %s but the handler does not decode them", name, strings.Join(missing, ", "))
+		}
+		if missing := difference(decoded, declared); len(missing) > 0 {
+			t.Errorf("%s: handler decodes %s but the schema does not declare them", name, strings.Join(missing, ", "))
+		}
+	}
+}
+
+// handlerInputFields returns the JSON fields each tool's handler decodes its
+// arguments into. Handlers decode into a variable named input, of an inline
+// struct or a struct type of the package, so the fields are read from the
+// package source: the handlers come from the getToolHandlers map literal.
+func handlerInputFields(t *testing.T) map[string]map[string]bool {
+	t.Helper()
+
+	entries, err := os.ReadDir(".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fset := token.NewFileSet()
+	structs := map[string]*ast.StructType{}
+	methods := map[string]*ast.FuncDecl{}
+	for _, entry := range entries {
+		if !strings.HasSuffix(entry.Name(), ".go") || strings.HasSuffix(entry.Name(), "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, entry.Name(), nil, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, decl := range file.Decls {
+			switch decl := decl.(type) {
+			case *ast.GenDecl:
+				for _, spec := range decl.Specs {
+					if spec, ok := spec.(*ast.TypeSpec); ok {
+						if st, ok := spec.Type.(*ast.StructType); ok {
+							structs[spec.Name.Name] = st
+						}
+					}
+				}
+			case *ast.FuncDecl:
+				if decl.Recv != nil {
+					methods[decl.Name.Name] = decl
+				}
+			}
+		}
+	}
+
+	registry, ok := methods["getToolHandlers"]
+	if !ok {
+		t.Fatal("getToolHandlers not found")
+	}
+	inputs := map[string]map[string]bool{}
+	ast.Inspect(registry.Body, func(n ast.Node) bool {
+		pair, ok := n.(*ast.KeyValueExpr)
+		if !ok {
+			return true
+		}
+		key, ok := pair.Key.(*ast.BasicLit)
+		handler, isMethod := pair.Value.(*ast.SelectorExpr)
+		if !ok || !isMethod {
+			return true
+		}
+		tool, _ := strconv.Unquote(key.Value)
+		method, ok := methods[handler.Sel.Name]
+		if !ok {
+			t.Errorf("%s: method %s not found", tool, handler.Sel.Name)
+			return false
+		}
+		inputs[tool] = inputFields(t, method, structs)
+		return false
+	})
+	return inputs
+}
+
+// inputFields returns the JSON names of the fields of a handler's input variable
+func inputFields(t *testing.T, method *ast.FuncDecl, structs map[string]*ast.StructType) map[string]bool {
+	fields := map[string]bool{}
+	ast.Inspect(method.Body, func(n ast.Node) bool {
+		spec, ok := n.(*ast.ValueSpec)
+		if !ok || len(spec.Names) != 1 || spec.Names[0].Name != "input" {
+			return true
+		}
+		structFields(t, spec.Type, structs, fields)
+		return false
+	})
+	return fields
+}
+
+// externalInputTypes are the structs of other packages that input structs embed
+var externalInputTypes = map[string]reflect.Type{
+	"database.Plan": reflect.TypeOf(database.Plan{}),
+}
+
+// structFields adds the JSON names of the fields of an inline or package struct
+// type, including those promoted from embedded structs
+func structFields(t *testing.T, typ ast.Expr, structs map[string]*ast.StructType, fields map[string]bool) {
+	var st *ast.StructType
+	switch typ := typ.(type) {
+	case *ast.StructType:
+		st = typ
+	case *ast.Ident:
+		st = structs[typ.Name]
+	case *ast.SelectorExpr:
+		name := typ.X.(*ast.Ident).Name + "." + typ.Sel.Name
+		external, ok := externalInputTypes[name]
+		if !ok {
+			t.Errorf("input embeds %s; add it to externalInputTypes", name)
+			return
+		}
+		jsonFields(external, fields)
+		return
+	}
+	if st == nil {
+		return
+	}
+
+	for _, field := range st.Fields.List {
+		tag := ""
+		if field.Tag != nil {
+			tag, _ = strconv.Unquote(field.Tag.Value)
+		}
+		name, _, _ := strings.Cut(reflect.StructTag(tag).Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if len(field.Names) == 0 && name == "" {
+			structFields(t, field.Type, structs, fields)
+			continue
+		}
+		for _, ident := range field.Names {
+			if !ident.IsExported() {
+				continue
+			}
+			if name != "" {
+				fields[name] = true
+			} else {
+				fields[ident.Name] = true
+			}
+		}
+	}
+}
+
+// jsonFields adds the JSON names of the fields of a struct type, including
+// those of embedded structs
+func jsonFields(typ reflect.Type, fields map[string]bool) {
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			jsonFields(field.Type, fields)
+			continue
+		}
+		if !field.IsExported() || name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields[name] = true
+	}
+}
+
+func difference(a, b map[string]bool) []string {
+	var names []string
+	for name := range a {
+		if !b[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
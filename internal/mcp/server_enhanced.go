@@ -14,20 +14,48 @@ type EnhancedMCPServer struct {
 	db                *database.Database
 	embeddingProvider embeddings.EmbeddingProvider
 	embeddingWorker   *service.EmbeddingWorker
+	webhookDispatcher *service.WebhookDispatcher
+	sessions          *sessionManager
+	prompts           *service.PromptService
+	deletions         *service.DeletionService
 }
 
-// NewEnhancedMCPServer creates a new enhanced MCP server
-func NewEnhancedMCPServer(db *database.Database, embeddingProvider embeddings.EmbeddingProvider, embeddingWorker *service.EmbeddingWorker) *EnhancedMCPServer {
-	return &EnhancedMCPServer{
+// toolHandler runs a tool call. ctx is cancelled when the client cancels the
+// request or disconnects, and carries its progress token.
+type toolHandler func(ctx context.Context, args []byte) (*ToolResponse, error)
+
+// NewEnhancedMCPServer creates a new enhanced MCP server. Without a webhook
+// dispatcher, pinged and redelivered webhooks wait in the outbox.
+func NewEnhancedMCPServer(db *database.Database, embeddingProvider embeddings.EmbeddingProvider, embeddingWorker *service.EmbeddingWorker, webhookDispatcher *service.WebhookDispatcher) *EnhancedMCPServer {
+	server := &EnhancedMCPServer{
 		db:                db,
 		embeddingProvider: embeddingProvider,
 		embeddingWorker:   embeddingWorker,
+		webhookDispatcher: webhookDispatcher,
+		sessions:          newSessionManager(),
+		prompts:           service.NewPromptService(db),
+		deletions:         service.NewDeletionService(db),
+	}
+	if db != nil {
+		server.registerResourceNotifications(db.Events())
+		server.registerLogNotifications(db.Events())
 	}
+	return server
 }
 
-// ListTools returns the list of available tools (enhanced version)
-func (s *EnhancedMCPServer) ListTools() []Tool {
-	return toolDefinitions()
+// ListTools returns the tools the caller may use (enhanced version), with
+// the input schemas their arguments are validated against
+func (s *EnhancedMCPServer) ListTools(ctx context.Context) []Tool {
+	access := accessFromContext(ctx)
+	var tools []Tool
+	for _, tool := range toolDefinitions() {
+		if !canUseTool(access, tool.Name) {
+			continue
+		}
+		tool.InputSchema, _ = toolSchema(tool.Name)
+		tools = append(tools, tool)
+	}
+	return tools
 }
 
 // ExecuteTool executes a tool by name with the provided arguments
@@ -37,20 +65,31 @@ func (s *EnhancedMCPServer) ExecuteTool(ctx context.Context, call ToolCall) (*To
 		return ErrorResponse(ErrDatabaseNotConfigured), nil
 	}
 
-	// Route to appropriate handler based on tool name
-	handlers := s.getToolHandlers()
+	// Route to appropriate handler based on tool name. Handlers query the
+	// database with ctx, so their writes are attributed to its principal.
+	handlers := s.withContext(ctx).getToolHandlers()
 
 	handler, exists := handlers[call.Name]
 	if !exists {
 		return ErrorResponse(fmt.Errorf("unknown tool: %s", call.Name)), nil
 	}
 
-	return handler(call.Arguments)
+	if err := s.authorizeToolCall(ctx, call); err != nil {
+		return ErrorResponse(err), nil
+	}
+
+	// Reject arguments that do not match the tool's input schema before they
+	// reach the handler
+	if argErr := ValidateToolArguments(call.Name, call.Arguments); argErr != nil {
+		return ArgumentErrorResponse(argErr), nil
+	}
+
+	return handler(ctx, call.Arguments)
 }
 
 // getToolHandlers returns a map of tool names to their handler functions
-func (s *EnhancedMCPServer) getToolHandlers() map[string]func([]byte) (*ToolResponse, error) {
-	return map[string]func([]byte) (*ToolResponse, error){
+func (s *EnhancedMCPServer) getToolHandlers() map[string]toolHandler {
+	return map[string]toolHandler{
 		// Project Management (CRUD)
 		"create_project": s.createProject,
 		"get_project":    s.getProject,
@@ -72,6 +111,25 @@ func (s *EnhancedMCPServer) getToolHandlers() map[string]func([]byte) (*ToolResp
 		"delete_epic": s.deleteEpic,
 		"list_epics":  s.listEpics,
 
+		// Sprints
+		"create_sprint":           s.createSprint,
+		"get_sprint":              s.getSprint,
+		"update_sprint":           s.updateSprint,
+		"delete_sprint":           s.deleteSprint,
+		"list_sprints":            s.listSprints,
+		"add_task_to_sprint":      s.addTaskToSprint,
+		"remove_task_from_sprint": s.removeTaskFromSprint,
+		"start_sprint":            s.startSprint,
+		"complete_sprint":         s.completeSprint,
+
+		// Milestones
+		"create_milestone":         s.createMilestone,
+		"get_milestone":            s.getMilestone,
+		"update_milestone":         s.updateMilestone,
+		"delete_milestone":         s.deleteMilestone,
+		"list_milestones":          s.listMilestones,
+		"assign_task_to_milestone": s.assignTaskToMilestone,
+
 		// Labels
 		"create_label": s.createLabel,
 		"assign_label": s.assignLabel,
@@ -91,10 +149,61 @@ func (s *EnhancedMCPServer) getToolHandlers() map[string]func([]byte) (*ToolResp
 		"delete_user": s.deleteUser,
 
 		// Comments
-		"add_comment":    s.addComment,
-		"update_comment": s.updateComment,
-		"delete_comment": s.deleteComment,
-		"list_comments":  s.listComments,
+		"add_comment":             s.addComment,
+		"update_comment":          s.updateComment,
+		"delete_comment":          s.deleteComment,
+		"list_comments":           s.listComments,
+		"get_comment":             s.getComment,
+		"get_comment_history":     s.getCommentHistory,
+		"add_comment_reaction":    s.addCommentReaction,
+		"remove_comment_reaction": s.removeCommentReaction,
+
+		// Watchers and mentions
+		"watch_task":         s.watchTask,
+		"unwatch_task":       s.unwatchTask,
+		"list_task_watchers": s.listTaskWatchers,
+		"list_watched_tasks": s.listWatchedTasks,
+		"list_mentions":      s.listMentions,
+
+		// Notifications
+		"list_notifications":              s.listNotifications,
+		"mark_notifications_read":         s.markNotificationsRead,
+		"get_notification_preferences":    s.getNotificationPreferences,
+		"update_notification_preferences": s.updateNotificationPreferences,
+
+		// Webhooks
+		"create_webhook":          s.createWebhook,
+		"list_webhooks":           s.listWebhooks,
+		"update_webhook":          s.updateWebhook,
+		"delete_webhook":          s.deleteWebhook,
+		"ping_webhook":            s.pingWebhook,
+		"list_webhook_deliveries": s.listWebhookDeliveries,
+		"redeliver_webhook":       s.redeliverWebhook,
+
+		// Prompt Templates
+		"create_prompt_template": s.createPromptTemplate,
+		"update_prompt_template": s.updatePromptTemplate,
+		"delete_prompt_template": s.deletePromptTemplate,
+
+		// Time Tracking
+		"log_work":          s.logWork,
+		"list_worklogs":     s.listWorklogs,
+		"delete_worklog":    s.deleteWorklog,
+		"start_timer":       s.startTimer,
+		"stop_timer":        s.stopTimer,
+		"get_running_timer": s.getRunningTimer,
+		"get_timesheet":     s.getTimesheet,
+
+		// Activity Digests
+		"get_activity_digest": s.getActivityDigest,
+
+		// Plan Import
+		"import_plan": s.importPlan,
+
+		// Task Claims
+		"claim_next_task": s.claimNextTask,
+		"heartbeat_task":  s.heartbeatTask,
+		"release_task":    s.releaseTask,
 
 		// Task Dependencies
 		"add_task_dependency":        s.addTaskDependency,
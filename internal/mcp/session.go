@@ -0,0 +1,465 @@
+package mcp
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/headless-pm/headless-project-management/internal/events"
+	"github.com/headless-pm/headless-project-management/internal/models"
+)
+
+const (
+	// sessionHeader carries the session ID of the Streamable HTTP transport
+	sessionHeader = "Mcp-Session-Id"
+	// sessionIdleTimeout expires sessions without requests or an open stream
+	sessionIdleTimeout = time.Hour
+	// sessionOutboxSize bounds the notifications queued for a session without an open stream
+	sessionOutboxSize = 100
+	// sessionHeartbeat keeps idle notification streams open through proxies
+	sessionHeartbeat = 25 * time.Second
+)
+
+// JSONRPCNotification is a JSON-RPC message without an ID; the server sends
+// them to clients over the session stream
+type JSONRPCNotification struct {
+	JSONRPC string      `json:"jsonrpc"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params,omitempty"`
+}
+
+// mcpSession is the state of one client between initialize and DELETE: the
+// resources it subscribed to and the notifications waiting to be sent
+type mcpSession struct {
+	id       string
+	owner    string // Authenticated identity that created the session
+	userID   *uint
+	outbox   chan JSONRPCNotification
+	mu       sync.Mutex
+	subs     map[string]bool
+	lastSeen time.Time
+	streams  int
+	// protocolVersion is the version negotiated by initialize
+	protocolVersion string
+	// client is the clientInfo of initialize, e.g. "claude-code 1.0.3"
+	client string
+	// inflight cancels the requests being handled, by their JSON-encoded ID
+	inflight map[string]context.CancelFunc
+	// logLevel is the minimum level of the log messages sent to the session,
+	// empty until logging/setLevel
+	logLevel string
+}
+
+func (session *mcpSession) setClient(client string) {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	session.client = client
+}
+
+// clientName returns the client that initialized the session; it is empty for
+// clients without a session
+func (session *mcpSession) clientName() string {
+	if session == nil {
+		return ""
+	}
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	return session.client
+}
+
+func (session *mcpSession) setProtocolVersion(version string) {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	session.protocolVersion = version
+}
+
+// negotiatedVersion returns the protocol version of the session; it is empty
+// for clients without a session
+func (session *mcpSession) negotiatedVersion() string {
+	if session == nil {
+		return ""
+	}
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	return session.protocolVersion
+}
+
+// track makes a request cancellable by notifications/cancelled until the
+// returned function is called
+func (session *mcpSession) track(ctx context.Context, id interface{}) (context.Context, func()) {
+	ctx, cancel := context.WithCancel(ctx)
+	key := requestKey(id)
+
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	session.inflight[key] = cancel
+	return ctx, func() {
+		session.mu.Lock()
+		defer session.mu.Unlock()
+		delete(session.inflight, key)
+		cancel()
+	}
+}
+
+// cancelRequest stops a request of the session; requests that already
+// finished or are unknown are ignored
+func (session *mcpSession) cancelRequest(key string) {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	if cancel, ok := session.inflight[key]; ok {
+		cancel()
+	}
+}
+
+// requestKey identifies a request ID as JSON, so 1 and "1" stay different
+func requestKey(id interface{}) string {
+	data, _ := json.Marshal(id)
+	return string(data)
+}
+
+func (session *mcpSession) subscribe(uri string) {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	session.subs[uri] = true
+}
+
+func (session *mcpSession) unsubscribe(uri string) {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	delete(session.subs, uri)
+}
+
+// subscriptions returns the subscribed URIs of a resource in any format
+func (session *mcpSession) subscriptions(uri string) []string {
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	var uris []string
+	for sub := range session.subs {
+		if baseResourceURI(sub) == uri {
+			uris = append(uris, sub)
+		}
+	}
+	return uris
+}
+
+// notify queues a notification; it is dropped when the client is not reading
+func (session *mcpSession) notify(method string, params interface{}) {
+	select {
+	case session.outbox <- JSONRPCNotification{JSONRPC: "2.0", Method: method, Params: params}:
+	default:
+	}
+}
+
+type sessionManager struct {
+	mu       sync.Mutex
+	sessions map[string]*mcpSession
+}
+
+func newSessionManager() *sessionManager {
+	return &sessionManager{sessions: make(map[string]*mcpSession)}
+}
+
+func (m *sessionManager) create(owner string, userID *uint) (*mcpSession, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return nil, fmt.Errorf("failed to generate session ID: %w", err)
+	}
+
+	session := &mcpSession{
+		id:       hex.EncodeToString(b),
+		owner:    owner,
+		userID:   userID,
+		outbox:   make(chan JSONRPCNotification, sessionOutboxSize),
+		subs:     make(map[string]bool),
+		lastSeen: time.Now(),
+		inflight: make(map[string]context.CancelFunc),
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.expireLocked()
+	m.sessions[session.id] = session
+	return session, nil
+}
+
+// get returns a live session of the owner and marks it as used
+func (m *sessionManager) get(id, owner string) *mcpSession {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.expireLocked()
+
+	session := m.sessions[id]
+	if session == nil || session.owner != owner {
+		return nil
+	}
+	session.mu.Lock()
+	session.lastSeen = time.Now()
+	session.mu.Unlock()
+	return session
+}
+
+func (m *sessionManager) remove(id string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.sessions, id)
+}
+
+func (m *sessionManager) all() []*mcpSession {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	sessions := make([]*mcpSession, 0, len(m.sessions))
+	for _, session := range m.sessions {
+		sessions = append(sessions, session)
+	}
+	return sessions
+}
+
+func (m *sessionManager) expireLocked() {
+	cutoff := time.Now().Add(-sessionIdleTimeout)
+	for id, session := range m.sessions {
+		session.mu.Lock()
+		idle := session.streams == 0 && len(session.inflight) == 0 && session.lastSeen.Before(cutoff)
+		session.mu.Unlock()
+		if idle {
+			delete(m.sessions, id)
+		}
+	}
+}
+
+// notifyResourceUpdated tells the sessions subscribed to a resource that it changed
+func (m *sessionManager) notifyResourceUpdated(uri string, forUser *uint) {
+	for _, session := range m.all() {
+		if forUser != nil && (session.userID == nil || *session.userID != *forUser) {
+			continue
+		}
+		for _, sub := range session.subscriptions(uri) {
+			session.notify("notifications/resources/updated", gin.H{"uri": sub})
+		}
+	}
+}
+
+// NotifyToolsListChanged tells every session to fetch the tool list again
+func (s *EnhancedMCPServer) NotifyToolsListChanged() {
+	for _, session := range s.sessions.all() {
+		session.notify("notifications/tools/list_changed", nil)
+	}
+}
+
+// notifyPromptsListChanged tells every session to fetch the prompt list again
+func (s *EnhancedMCPServer) notifyPromptsListChanged() {
+	for _, session := range s.sessions.all() {
+		session.notify("notifications/prompts/list_changed", nil)
+	}
+}
+
+// registerResourceNotifications sends notifications/resources/updated for the
+// resources a domain event changes, and notifications/prompts/list_changed when
+// prompt templates change
+func (s *EnhancedMCPServer) registerResourceNotifications(bus *events.Bus) {
+	bus.SubscribeAll(func(event events.Event) {
+		switch e := event.(type) {
+		case events.ProjectCreated:
+			s.sessions.notifyResourceUpdated("projects://list", nil)
+		case events.ProjectUpdated:
+			s.sessions.notifyResourceUpdated("projects://list", nil)
+			s.notifyProjectResources(e.Project.ID)
+		case events.ProjectDeleted:
+			s.sessions.notifyResourceUpdated("projects://list", nil)
+			s.notifyProjectResources(e.ProjectID)
+		case events.TaskCreated:
+			s.notifyTaskResources(e.Task, true)
+		case events.TaskUpdated:
+			s.notifyTaskResources(e.Task, e.Changed("status"))
+			if e.Old.EpicID != nil && (e.Task.EpicID == nil || *e.Old.EpicID != *e.Task.EpicID) {
+				s.sessions.notifyResourceUpdated(fmt.Sprintf("epic://%d", *e.Old.EpicID), nil)
+			}
+			if e.Old.AssigneeID != nil && (e.Task.AssigneeID == nil || *e.Old.AssigneeID != *e.Task.AssigneeID) {
+				s.sessions.notifyResourceUpdated(fmt.Sprintf("user://%d/assigned", *e.Old.AssigneeID), nil)
+			}
+		case events.TaskDeleted:
+			s.notifyTaskResources(&e.Task, true)
+		case events.CommentAdded:
+			s.sessions.notifyResourceUpdated(fmt.Sprintf("task://%d", e.Comment.TaskID), nil)
+		case events.CommentUpdated:
+			s.sessions.notifyResourceUpdated(fmt.Sprintf("task://%d", e.Comment.TaskID), nil)
+		case events.CommentDeleted:
+			s.sessions.notifyResourceUpdated(fmt.Sprintf("task://%d", e.Comment.TaskID), nil)
+		case events.DependencyAdded:
+			s.notifyDependencyResources(e.Dependency)
+		case events.DependencyRemoved:
+			s.notifyDependencyResources(e.Dependency)
+		case events.EpicCreated:
+			s.sessions.notifyResourceUpdated("epics://active", nil)
+			s.sessions.notifyResourceUpdated(fmt.Sprintf("project://%d", e.Epic.ProjectID), nil)
+		case events.EpicUpdated:
+			s.sessions.notifyResourceUpdated("epics://active", nil)
+			s.sessions.notifyResourceUpdated(fmt.Sprintf("epic://%d", e.Epic.ID), nil)
+			s.sessions.notifyResourceUpdated(fmt.Sprintf("project://%d", e.Epic.ProjectID), nil)
+		case events.EpicDeleted:
+			s.sessions.notifyResourceUpdated("epics://active", nil)
+			s.sessions.notifyResourceUpdated(fmt.Sprintf("epic://%d", e.EpicID), nil)
+			s.sessions.notifyResourceUpdated(fmt.Sprintf("project://%d", e.ProjectID), nil)
+		case events.SprintStarted:
+			s.sessions.notifyResourceUpdated(fmt.Sprintf("project://%d", e.Sprint.ProjectID), nil)
+		case events.SprintCompleted:
+			s.sessions.notifyResourceUpdated(fmt.Sprintf("project://%d", e.Sprint.ProjectID), nil)
+		case events.NotificationSent:
+			s.sessions.notifyResourceUpdated("notifications://me", &e.Notification.UserID)
+		case events.PromptTemplatesChanged:
+			s.notifyPromptsListChanged()
+		}
+	})
+}
+
+func (s *EnhancedMCPServer) notifyTaskResources(task *models.Task, countsChanged bool) {
+	s.sessions.notifyResourceUpdated("tasks://overdue", nil)
+	s.sessions.notifyResourceUpdated("tasks://high-priority", nil)
+	s.sessions.notifyResourceUpdated(fmt.Sprintf("task://%d", task.ID), nil)
+	s.sessions.notifyResourceUpdated(fmt.Sprintf("project://%d/board", task.ProjectID), nil)
+	if task.EpicID != nil {
+		// Epic progress is computed from its tasks
+		s.sessions.notifyResourceUpdated("epics://active", nil)
+		s.sessions.notifyResourceUpdated(fmt.Sprintf("epic://%d", *task.EpicID), nil)
+	}
+	if task.AssigneeID != nil {
+		s.sessions.notifyResourceUpdated(fmt.Sprintf("user://%d/assigned", *task.AssigneeID), nil)
+	}
+	if countsChanged {
+		s.sessions.notifyResourceUpdated("projects://list", nil)
+		s.sessions.notifyResourceUpdated(fmt.Sprintf("project://%d", task.ProjectID), nil)
+	}
+}
+
+func (s *EnhancedMCPServer) notifyProjectResources(projectID uint) {
+	s.sessions.notifyResourceUpdated(fmt.Sprintf("project://%d", projectID), nil)
+	s.sessions.notifyResourceUpdated(fmt.Sprintf("project://%d/board", projectID), nil)
+}
+
+// notifyDependencyResources updates both tasks; each lists the other
+func (s *EnhancedMCPServer) notifyDependencyResources(dependency models.TaskDependency) {
+	s.sessions.notifyResourceUpdated(fmt.Sprintf("task://%d", dependency.TaskID), nil)
+	s.sessions.notifyResourceUpdated(fmt.Sprintf("task://%d", dependency.DependsOnID), nil)
+}
+
+// sessionOwner identifies the authenticated caller; sessions can only be used by their creator
+func sessionOwner(c *gin.Context) (string, *uint) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		return "", nil
+	}
+	if id, ok := userID.(uint); ok {
+		return fmt.Sprintf("user:%d", id), &id
+	}
+	return fmt.Sprint(userID), nil
+}
+
+// requestSession returns the session named by the Mcp-Session-Id header, or nil
+// for clients that do not use sessions. Unknown or expired sessions get a 404 so
+// the client starts a new one.
+func (s *EnhancedMCPServer) requestSession(c *gin.Context) (*mcpSession, bool) {
+	id := c.GetHeader(sessionHeader)
+	if id == "" {
+		return nil, true
+	}
+
+	owner, _ := sessionOwner(c)
+	session := s.sessions.get(id, owner)
+	if session == nil {
+		c.JSON(http.StatusNotFound, JSONRPCResponse{
+			JSONRPC: "2.0",
+			Error: &JSONRPCError{
+				Code:    codeSessionNotFound,
+				Message: "Session not found",
+			},
+		})
+		return nil, false
+	}
+	return session, true
+}
+
+// handleSessionStream opens the stream that carries server-initiated messages of a session
+func (s *EnhancedMCPServer) handleSessionStream(c *gin.Context) {
+	if c.GetHeader(sessionHeader) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": sessionHeader + " header is required"})
+		return
+	}
+	session, ok := s.requestSession(c)
+	if !ok {
+		return
+	}
+
+	session.mu.Lock()
+	session.streams++
+	session.mu.Unlock()
+	defer func() {
+		session.mu.Lock()
+		session.streams--
+		session.lastSeen = time.Now()
+		session.mu.Unlock()
+	}()
+
+	startEventStream(c)
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(sessionHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case notification := <-session.outbox:
+			if err := writeSSEMessage(c.Writer, notification); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(c.Writer, ": ping\n\n"); err != nil {
+				return
+			}
+		case <-c.Request.Context().Done():
+			return
+		}
+		c.Writer.Flush()
+	}
+}
+
+// handleDeleteSession ends a session
+func (s *EnhancedMCPServer) handleDeleteSession(c *gin.Context) {
+	if c.GetHeader(sessionHeader) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": sessionHeader + " header is required"})
+		return
+	}
+	session, ok := s.requestSession(c)
+	if !ok {
+		return
+	}
+
+	s.sessions.remove(session.id)
+	c.Status(http.StatusNoContent)
+}
+
+func acceptsEventStream(c *gin.Context) bool {
+	return strings.Contains(c.GetHeader("Accept"), "text/event-stream")
+}
+
+func startEventStream(c *gin.Context) {
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+}
+
+func writeSSEMessage(w gin.ResponseWriter, message interface{}) error {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "event: message\ndata: %s\n\n", data)
+	return err
+}
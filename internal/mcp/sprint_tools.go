@@ -0,0 +1,425 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/headless-pm/headless-project-management/internal/models"
+)
+
+// parseDateArg parses an optional YYYY-MM-DD tool argument
+func parseDateArg(name, value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s '%s': expected YYYY-MM-DD", name, value)
+	}
+	return &date, nil
+}
+
+// Sprint CRUD operations
+func (s *EnhancedMCPServer) createSprint(ctx context.Context, args []byte) (*ToolResponse, error) {
+	var input struct {
+		ProjectID uint   `json:"project_id"`
+		Name      string `json:"name"`
+		Goal      string `json:"goal"`
+		StartDate string `json:"start_date"`
+		EndDate   string `json:"end_date"`
+	}
+	if err := UnmarshalArgs(args, &input); err != nil {
+		return ErrorResponse(err), nil
+	}
+
+	startDate, err := parseDateArg("start_date", input.StartDate)
+	if err != nil {
+		return ErrorResponse(err), nil
+	}
+	endDate, err := parseDateArg("end_date", input.EndDate)
+	if err != nil {
+		return ErrorResponse(err), nil
+	}
+
+	sprint := &models.Sprint{
+		ProjectID: input.ProjectID,
+		Name:      input.Name,
+		Goal:      input.Goal,
+		Status:    models.SprintStatusPlanned,
+		StartDate: startDate,
+		EndDate:   endDate,
+	}
+
+	if err := s.db.CreateSprint(sprint); err != nil {
+		return ErrorResponse(fmt.Errorf("failed to create sprint: %w", err)), nil
+	}
+
+	return SuccessResponse(sprint), nil
+}
+
+func (s *EnhancedMCPServer) getSprint(ctx context.Context, args []byte) (*ToolResponse, error) {
+	var input struct {
+		SprintID uint `json:"sprint_id"`
+	}
+	if err := UnmarshalArgs(args, &input); err != nil {
+		return ErrorResponse(err), nil
+	}
+
+	sprint, err := s.db.GetSprint(input.SprintID)
+	if err != nil {
+		return ErrorResponse(fmt.Errorf("sprint not found: %w", err)), nil
+	}
+
+	return SuccessResponse(sprint), nil
+}
+
+func (s *EnhancedMCPServer) updateSprint(ctx context.Context, args []byte) (*ToolResponse, error) {
+	var input struct {
+		SprintID  uint   `json:"sprint_id"`
+		Name      string `json:"name"`
+		Goal      string `json:"goal"`
+		StartDate string `json:"start_date"`
+		EndDate   string `json:"end_date"`
+	}
+	if err := UnmarshalArgs(args, &input); err != nil {
+		return ErrorResponse(err), nil
+	}
+
+	var sprint models.Sprint
+	if err := s.db.First(&sprint, input.SprintID).Error; err != nil {
+		return ErrorResponse(fmt.Errorf("sprint not found: %w", err)), nil
+	}
+
+	if input.Name != "" {
+		sprint.Name = input.Name
+	}
+	if input.Goal != "" {
+		sprint.Goal = input.Goal
+	}
+	if input.StartDate != "" {
+		startDate, err := parseDateArg("start_date", input.StartDate)
+		if err != nil {
+			return ErrorResponse(err), nil
+		}
+		sprint.StartDate = startDate
+	}
+	if input.EndDate != "" {
+		endDate, err := parseDateArg("end_date", input.EndDate)
+		if err != nil {
+			return ErrorResponse(err), nil
+		}
+		sprint.EndDate = endDate
+	}
+
+	if err := s.db.UpdateSprint(&sprint); err != nil {
+		return ErrorResponse(fmt.Errorf("failed to update sprint: %w", err)), nil
+	}
+
+	return SuccessResponse(sprint), nil
+}
+
+func (s *EnhancedMCPServer) deleteSprint(ctx context.Context, args []byte) (*ToolResponse, error) {
+	var input struct {
+		SprintID uint `json:"sprint_id"`
+	}
+	if err := UnmarshalArgs(args, &input); err != nil {
+		return ErrorResponse(err), nil
+	}
+
+	if err := s.db.DeleteSprint(input.SprintID); err != nil {
+		return ErrorResponse(fmt.Errorf("failed to delete sprint: %w", err)), nil
+	}
+
+	return SuccessResponse(map[string]interface{}{
+		"status":    "deleted",
+		"sprint_id": input.SprintID,
+	}), nil
+}
+
+func (s *EnhancedMCPServer) listSprints(ctx context.Context, args []byte) (*ToolResponse, error) {
+	var input struct {
+		ProjectID uint   `json:"project_id,omitempty"`
+		Status    string `json:"status,omitempty"`
+	}
+	if err := UnmarshalArgs(args, &input); err != nil {
+		return ErrorResponse(err), nil
+	}
+
+	var projectID *uint
+	if input.ProjectID > 0 {
+		projectID = &input.ProjectID
+	}
+	var status *models.SprintStatus
+	if input.Status != "" {
+		st := models.SprintStatus(input.Status)
+		status = &st
+	}
+
+	sprints, err := s.db.ListSprints(projectID, status)
+	if err != nil {
+		return ErrorResponse(fmt.Errorf("failed to list sprints: %w", err)), nil
+	}
+
+	return SuccessResponse(sprints), nil
+}
+
+func (s *EnhancedMCPServer) addTaskToSprint(ctx context.Context, args []byte) (*ToolResponse, error) {
+	var input struct {
+		SprintID uint   `json:"sprint_id"`
+		TaskID   uint   `json:"task_id"`
+		TaskIDs  []uint `json:"task_ids"`
+	}
+	if err := UnmarshalArgs(args, &input); err != nil {
+		return ErrorResponse(err), nil
+	}
+
+	taskIDs := input.TaskIDs
+	if input.TaskID > 0 {
+		taskIDs = append(taskIDs, input.TaskID)
+	}
+	if len(taskIDs) == 0 {
+		return ErrorResponse(fmt.Errorf("task_id or task_ids is required")), nil
+	}
+
+	for _, taskID := range taskIDs {
+		if err := s.db.AddTaskToSprint(taskID, input.SprintID); err != nil {
+			return ErrorResponse(fmt.Errorf("failed to add task %d to sprint: %w", taskID, err)), nil
+		}
+	}
+
+	return SuccessResponse(map[string]interface{}{
+		"success":   true,
+		"sprint_id": input.SprintID,
+		"task_ids":  taskIDs,
+	}), nil
+}
+
+func (s *EnhancedMCPServer) removeTaskFromSprint(ctx context.Context, args []byte) (*ToolResponse, error) {
+	var input struct {
+		TaskID uint `json:"task_id"`
+	}
+	if err := UnmarshalArgs(args, &input); err != nil {
+		return ErrorResponse(err), nil
+	}
+
+	if err := s.db.RemoveTaskFromSprint(input.TaskID); err != nil {
+		return ErrorResponse(fmt.Errorf("failed to remove task from sprint: %w", err)), nil
+	}
+
+	return SuccessResponse(map[string]interface{}{
+		"success": true,
+		"task_id": input.TaskID,
+	}), nil
+}
+
+func (s *EnhancedMCPServer) startSprint(ctx context.Context, args []byte) (*ToolResponse, error) {
+	var input struct {
+		SprintID uint `json:"sprint_id"`
+	}
+	if err := UnmarshalArgs(args, &input); err != nil {
+		return ErrorResponse(err), nil
+	}
+
+	carried, err := s.db.StartSprint(input.SprintID)
+	if err != nil {
+		return ErrorResponse(fmt.Errorf("failed to start sprint: %w", err)), nil
+	}
+
+	sprint, err := s.db.GetSprint(input.SprintID)
+	if err != nil {
+		return ErrorResponse(err), nil
+	}
+
+	return SuccessResponse(map[string]interface{}{
+		"sprint":        sprint,
+		"carried_tasks": carried,
+	}), nil
+}
+
+func (s *EnhancedMCPServer) completeSprint(ctx context.Context, args []byte) (*ToolResponse, error) {
+	var input struct {
+		SprintID     uint `json:"sprint_id"`
+		NextSprintID uint `json:"next_sprint_id,omitempty"`
+	}
+	if err := UnmarshalArgs(args, &input); err != nil {
+		return ErrorResponse(err), nil
+	}
+
+	var nextSprintID *uint
+	if input.NextSprintID > 0 {
+		nextSprintID = &input.NextSprintID
+	}
+
+	carried, err := s.db.CompleteSprint(input.SprintID, nextSprintID)
+	if err != nil {
+		return ErrorResponse(fmt.Errorf("failed to complete sprint: %w", err)), nil
+	}
+
+	sprint, err := s.db.GetSprint(input.SprintID)
+	if err != nil {
+		return ErrorResponse(err), nil
+	}
+
+	return SuccessResponse(map[string]interface{}{
+		"sprint":        sprint,
+		"carried_tasks": carried,
+	}), nil
+}
+
+// Milestone CRUD operations
+func (s *EnhancedMCPServer) createMilestone(ctx context.Context, args []byte) (*ToolResponse, error) {
+	var input struct {
+		ProjectID   uint   `json:"project_id"`
+		Name        string `json:"name"`
+		Description string `json:"description"`
+		DueDate     string `json:"due_date"`
+	}
+	if err := UnmarshalArgs(args, &input); err != nil {
+		return ErrorResponse(err), nil
+	}
+
+	dueDate, err := parseDateArg("due_date", input.DueDate)
+	if err != nil {
+		return ErrorResponse(err), nil
+	}
+
+	milestone := &models.Milestone{
+		ProjectID:   input.ProjectID,
+		Name:        input.Name,
+		Description: input.Description,
+		Status:      models.MilestoneStatusPlanned,
+		DueDate:     dueDate,
+	}
+
+	if err := s.db.CreateMilestone(milestone); err != nil {
+		return ErrorResponse(fmt.Errorf("failed to create milestone: %w", err)), nil
+	}
+
+	return SuccessResponse(milestone), nil
+}
+
+func (s *EnhancedMCPServer) getMilestone(ctx context.Context, args []byte) (*ToolResponse, error) {
+	var input struct {
+		MilestoneID uint `json:"milestone_id"`
+	}
+	if err := UnmarshalArgs(args, &input); err != nil {
+		return ErrorResponse(err), nil
+	}
+
+	milestone, err := s.db.GetMilestone(input.MilestoneID)
+	if err != nil {
+		return ErrorResponse(fmt.Errorf("milestone not found: %w", err)), nil
+	}
+
+	return SuccessResponse(milestone), nil
+}
+
+func (s *EnhancedMCPServer) updateMilestone(ctx context.Context, args []byte) (*ToolResponse, error) {
+	var input struct {
+		MilestoneID uint   `json:"milestone_id"`
+		Name        string `json:"name"`
+		Description string `json:"description"`
+		Status      string `json:"status"`
+		DueDate     string `json:"due_date"`
+	}
+	if err := UnmarshalArgs(args, &input); err != nil {
+		return ErrorResponse(err), nil
+	}
+
+	var milestone models.Milestone
+	if err := s.db.First(&milestone, input.MilestoneID).Error; err != nil {
+		return ErrorResponse(fmt.Errorf("milestone not found: %w", err)), nil
+	}
+
+	if input.Name != "" {
+		milestone.Name = input.Name
+	}
+	if input.Description != "" {
+		milestone.Description = input.Description
+	}
+	if input.Status != "" {
+		if !models.IsValidMilestoneStatus(input.Status) {
+			return ErrorResponse(fmt.Errorf("invalid milestone status '%s'", input.Status)), nil
+		}
+		milestone.Status = models.MilestoneStatus(input.Status)
+	}
+	if input.DueDate != "" {
+		dueDate, err := parseDateArg("due_date", input.DueDate)
+		if err != nil {
+			return ErrorResponse(err), nil
+		}
+		milestone.DueDate = dueDate
+	}
+
+	if err := s.db.UpdateMilestone(&milestone); err != nil {
+		return ErrorResponse(fmt.Errorf("failed to update milestone: %w", err)), nil
+	}
+
+	return SuccessResponse(milestone), nil
+}
+
+func (s *EnhancedMCPServer) deleteMilestone(ctx context.Context, args []byte) (*ToolResponse, error) {
+	var input struct {
+		MilestoneID uint `json:"milestone_id"`
+	}
+	if err := UnmarshalArgs(args, &input); err != nil {
+		return ErrorResponse(err), nil
+	}
+
+	if err := s.db.DeleteMilestone(input.MilestoneID); err != nil {
+		return ErrorResponse(fmt.Errorf("failed to delete milestone: %w", err)), nil
+	}
+
+	return SuccessResponse(map[string]interface{}{
+		"status":       "deleted",
+		"milestone_id": input.MilestoneID,
+	}), nil
+}
+
+func (s *EnhancedMCPServer) listMilestones(ctx context.Context, args []byte) (*ToolResponse, error) {
+	var input struct {
+		ProjectID uint   `json:"project_id,omitempty"`
+		Status    string `json:"status,omitempty"`
+	}
+	if err := UnmarshalArgs(args, &input); err != nil {
+		return ErrorResponse(err), nil
+	}
+
+	var projectID *uint
+	if input.ProjectID > 0 {
+		projectID = &input.ProjectID
+	}
+	var status *models.MilestoneStatus
+	if input.Status != "" {
+		st := models.MilestoneStatus(input.Status)
+		status = &st
+	}
+
+	milestones, err := s.db.ListMilestones(projectID, status)
+	if err != nil {
+		return ErrorResponse(fmt.Errorf("failed to list milestones: %w", err)), nil
+	}
+
+	return SuccessResponse(milestones), nil
+}
+
+func (s *EnhancedMCPServer) assignTaskToMilestone(ctx context.Context, args []byte) (*ToolResponse, error) {
+	var input struct {
+		MilestoneID uint `json:"milestone_id"`
+		TaskID      uint `json:"task_id"`
+	}
+	if err := UnmarshalArgs(args, &input); err != nil {
+		return ErrorResponse(err), nil
+	}
+
+	if err := s.db.AssignTaskToMilestone(input.TaskID, input.MilestoneID); err != nil {
+		return ErrorResponse(fmt.Errorf("failed to assign task to milestone: %w", err)), nil
+	}
+
+	return SuccessResponse(map[string]interface{}{
+		"success":      true,
+		"milestone_id": input.MilestoneID,
+		"task_id":      input.TaskID,
+	}), nil
+}
@@ -0,0 +1,145 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// stdioWriter writes newline-delimited JSON messages; responses and
+// notifications are written from different goroutines
+type stdioWriter struct {
+	mu  sync.Mutex
+	out io.Writer
+}
+
+func (w *stdioWriter) write(message interface{}) error {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+	return w.writeRaw(data)
+}
+
+func (w *stdioWriter) writeRaw(data []byte) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if _, err := w.out.Write(data); err != nil {
+		return err
+	}
+	_, err := w.out.Write([]byte("\n"))
+	return err
+}
+
+// ServeStdio serves the MCP protocol over newline-delimited JSON-RPC, reading
+// requests from in and writing responses and notifications to out, until in is
+// closed or ctx is done. The whole connection is one session acting as userID,
+// which may be nil for an unscoped local user.
+func (s *EnhancedMCPServer) ServeStdio(ctx context.Context, in io.Reader, out io.Writer, userID *uint) error {
+	owner := "stdio"
+	if userID != nil {
+		owner = fmt.Sprintf("user:%d", *userID)
+		ctx = context.WithValue(ctx, userIDContextKey, *userID)
+	}
+	session, err := s.sessions.create(owner, userID)
+	if err != nil {
+		return err
+	}
+	defer s.sessions.remove(session.id)
+
+	writer := &stdioWriter{out: out}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Server-initiated notifications
+	go func() {
+		for {
+			select {
+			case notification := <-session.outbox:
+				_ = writer.write(notification)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	// Messages are handled in order, but cancellations are applied as soon as
+	// they are read, while the request they cancel still runs
+	lines := make(chan string)
+	scanErr := make(chan error, 1)
+	go func() {
+		defer close(lines)
+		scanner := bufio.NewScanner(in)
+		scanner.Buffer(make([]byte, 64*1024), maxRPCMessage)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" {
+				continue
+			}
+			if messages, batch, ok := decodeRPCBody([]byte(line)); ok && !batch && isCancellation(messages[0]) {
+				s.handleRPCMessages(ctx, session, messages, false)
+				continue
+			}
+			select {
+			case lines <- line:
+			case <-ctx.Done():
+				return
+			}
+		}
+		scanErr <- scanner.Err()
+	}()
+
+	for line := range lines {
+		messages, batch, ok := decodeRPCBody([]byte(line))
+		if !ok {
+			if err := writer.write(JSONRPCResponse{
+				JSONRPC: "2.0",
+				Error: &JSONRPCError{
+					Code:    codeParseError,
+					Message: "Parse error",
+				},
+			}); err != nil {
+				return err
+			}
+			continue
+		}
+
+		// Notifications get no response
+		if responses := s.handleRPCMessages(ctx, session, messages, batch); len(responses) > 0 {
+			if err := writer.write(rpcReply(responses, batch)); err != nil {
+				return err
+			}
+		}
+
+		// Notifications caused by the requests follow their responses
+		for pending := true; pending; {
+			select {
+			case notification := <-session.outbox:
+				if err := writer.write(notification); err != nil {
+					return err
+				}
+			default:
+				pending = false
+			}
+		}
+
+		if ctx.Err() != nil {
+			return nil
+		}
+	}
+	select {
+	case err := <-scanErr:
+		return err
+	default:
+		return nil
+	}
+}
+
+// isCancellation reports whether a message is a notifications/cancelled
+func isCancellation(message rpcMessage) bool {
+	return message.err == nil && message.notification && message.request.Method == "notifications/cancelled"
+}
@@ -0,0 +1,213 @@
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// StdioProxy bridges newline-delimited JSON-RPC on stdio to a remote server's
+// Streamable HTTP endpoint, so local agent tools can use a shared server
+type StdioProxy struct {
+	endpoint  string
+	token     string
+	client    *http.Client
+	writer    *stdioWriter
+	sessionID string
+}
+
+// NewStdioProxy creates a proxy to an MCP endpoint such as https://pm.example.com/mcp
+func NewStdioProxy(endpoint, token string) *StdioProxy {
+	return &StdioProxy{
+		endpoint: strings.TrimRight(endpoint, "/"),
+		token:    token,
+		client:   &http.Client{},
+	}
+}
+
+// Serve forwards each message read from in to the remote server and writes the
+// replies, and the notifications of the remote session, to out
+func (p *StdioProxy) Serve(ctx context.Context, in io.Reader, out io.Writer) error {
+	p.writer = &stdioWriter{out: out}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	defer p.closeSession()
+
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 64*1024), maxRPCMessage)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		hadSession := p.sessionID != ""
+		if err := p.forward(ctx, line); err != nil {
+			return err
+		}
+		if !hadSession && p.sessionID != "" {
+			go p.followSession(ctx, p.sessionID)
+		}
+	}
+	return scanner.Err()
+}
+
+func (p *StdioProxy) forward(ctx context.Context, message []byte) error {
+	req, err := p.newRequest(ctx, http.MethodPost, bytes.NewReader(message))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+	if p.sessionID != "" {
+		req.Header.Set(sessionHeader, p.sessionID)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return p.writeError(message, fmt.Sprintf("Remote server unavailable: %v", err))
+	}
+	defer resp.Body.Close()
+
+	if id := resp.Header.Get(sessionHeader); id != "" {
+		p.sessionID = id
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusAccepted:
+		return nil
+	case strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream"):
+		return p.copyEventStream(resp.Body)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return p.writeError(message, fmt.Sprintf("Failed to read remote response: %v", err))
+	}
+	var envelope struct {
+		JSONRPC string `json:"jsonrpc"`
+	}
+	var compact bytes.Buffer
+	if json.Unmarshal(body, &envelope) != nil || envelope.JSONRPC == "" || json.Compact(&compact, body) != nil {
+		// Not JSON-RPC, e.g. an authentication failure from the middleware
+		return p.writeError(message, fmt.Sprintf("Remote server responded with %s: %s", resp.Status, strings.TrimSpace(string(body))))
+	}
+	if resp.StatusCode == http.StatusNotFound && p.sessionID != "" {
+		// The remote session expired; the client has to initialize again
+		p.sessionID = ""
+		return p.writeError(message, "Session expired, initialize again")
+	}
+	return p.writer.writeRaw(compact.Bytes())
+}
+
+// followSession relays the server-initiated messages of the remote session,
+// reconnecting when the stream drops
+func (p *StdioProxy) followSession(ctx context.Context, sessionID string) {
+	delay := time.Second
+	for ctx.Err() == nil {
+		req, err := p.newRequest(ctx, http.MethodGet, nil)
+		if err != nil {
+			return
+		}
+		req.Header.Set("Accept", "text/event-stream")
+		req.Header.Set(sessionHeader, sessionID)
+
+		resp, err := p.client.Do(req)
+		if err == nil {
+			if resp.StatusCode != http.StatusOK {
+				// The server does not offer a stream or the session has ended
+				resp.Body.Close()
+				return
+			}
+			delay = time.Second
+			_ = p.copyEventStream(resp.Body)
+			resp.Body.Close()
+		}
+
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return
+		}
+		if delay < 30*time.Second {
+			delay *= 2
+		}
+	}
+}
+
+// copyEventStream writes the data of each server-sent event as one line
+func (p *StdioProxy) copyEventStream(body io.Reader) error {
+	reader := bufio.NewReader(body)
+	var data []string
+	for {
+		line, err := reader.ReadString('\n')
+		line = strings.TrimRight(line, "\r\n")
+
+		switch {
+		case strings.HasPrefix(line, "data:"):
+			data = append(data, strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+		case line == "" && len(data) > 0:
+			if werr := p.writer.writeRaw([]byte(strings.Join(data, "\n"))); werr != nil {
+				return werr
+			}
+			data = nil
+		}
+
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+func (p *StdioProxy) newRequest(ctx context.Context, method string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, p.endpoint, body)
+	if err != nil {
+		return nil, err
+	}
+	if p.token != "" {
+		req.Header.Set("Authorization", "Bearer "+p.token)
+	}
+	return req, nil
+}
+
+// writeError answers a request locally when the remote server could not
+func (p *StdioProxy) writeError(message []byte, text string) error {
+	var request JSONRPCRequest
+	_ = json.Unmarshal(message, &request)
+	if request.ID == nil {
+		return nil
+	}
+	return p.writer.write(JSONRPCResponse{
+		JSONRPC: "2.0",
+		Error: &JSONRPCError{
+			Code:    codeInternalError,
+			Message: text,
+		},
+		ID: request.ID,
+	})
+}
+
+func (p *StdioProxy) closeSession() {
+	if p.sessionID == "" {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, err := p.newRequest(ctx, http.MethodDelete, nil)
+	if err != nil {
+		return
+	}
+	req.Header.Set(sessionHeader, p.sessionID)
+	if resp, err := p.client.Do(req); err == nil {
+		resp.Body.Close()
+	}
+}
@@ -0,0 +1,121 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestServeStdioFraming(t *testing.T) {
+	server, _ := newTestServer(t)
+
+	input := "\n" + strings.Join([]string{
+		`  {"jsonrpc":"2.0","id":1,"method":"ping"}  `,
+		``,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"create_project","arguments":{"name":"Two\nlines"}}}`,
+		`{"jsonrpc":"2.0","id":3,"method":"ping"}`,
+	}, "\r\n") + "\n\n"
+
+	var out bytes.Buffer
+	if err := server.ServeStdio(context.Background(), strings.NewReader(input), &out, nil); err != nil {
+		t.Fatalf("ServeStdio: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines, want one per request:\n%s", len(lines), out.String())
+	}
+	for i, line := range lines {
+		var message map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &message); err != nil {
+			t.Fatalf("line %d is not one JSON message: %s", i+1, line)
+		}
+		if message["id"] != float64(i+1) {
+			t.Errorf("line %d answers %v, want %d", i+1, message["id"], i+1)
+		}
+	}
+	if !strings.Contains(lines[1], `Two\nlines`) {
+		t.Errorf("newline in a value was not escaped: %s", lines[1])
+	}
+}
+
+func TestStdioProxy(t *testing.T) {
+	var deleted string
+	remote := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		case http.MethodDelete:
+			deleted = r.Header.Get(sessionHeader)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer t0ken" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		body, _ := io.ReadAll(r.Body)
+		var request JSONRPCRequest
+		_ = json.Unmarshal(body, &request)
+		switch request.Method {
+		case "initialize":
+			// Pretty-printed replies are compacted to one line
+			w.Header().Set(sessionHeader, "s-1")
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, "{\n  \"jsonrpc\": \"2.0\",\n  \"id\": 1,\n  \"result\": {}\n}\n")
+		case "tools/call":
+			// Each server-sent event becomes one line
+			w.Header().Set("Content-Type", "text/event-stream")
+			fmt.Fprint(w, "event: message\ndata: {\"jsonrpc\":\"2.0\",\"method\":\"notifications/progress\"}\n\n")
+			fmt.Fprint(w, "event: message\ndata: {\"jsonrpc\":\"2.0\",\"id\":2,\"result\":{}}\n\n")
+		case "notifications/initialized":
+			w.WriteHeader(http.StatusAccepted)
+		default:
+			http.Error(w, "boom", http.StatusInternalServerError)
+		}
+	}))
+	defer remote.Close()
+
+	input := strings.Join([]string{
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{}}`,
+		`{"jsonrpc":"2.0","id":3,"method":"explode"}`,
+	}, "\n")
+	var out bytes.Buffer
+	proxy := NewStdioProxy(remote.URL+"/", "t0ken")
+	if err := proxy.Serve(context.Background(), strings.NewReader(input), &out); err != nil {
+		t.Fatalf("Serve: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	want := []string{
+		`{"jsonrpc":"2.0","id":1,"result":{}}`,
+		`{"jsonrpc":"2.0","method":"notifications/progress"}`,
+		`{"jsonrpc":"2.0","id":2,"result":{}}`,
+	}
+	if len(lines) != 4 {
+		t.Fatalf("got %d lines, want 4:\n%s", len(lines), out.String())
+	}
+	for i, line := range want {
+		if lines[i] != line {
+			t.Errorf("line %d = %s, want %s", i+1, lines[i], line)
+		}
+	}
+
+	var failure map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[3]), &failure); err != nil || failure["id"] != float64(3) || failure["error"] == nil {
+		t.Errorf("remote failure = %s, want an error for request 3", lines[3])
+	}
+	if deleted != "s-1" {
+		t.Errorf("closed session %q, want s-1", deleted)
+	}
+}
@@ -1,5 +1,7 @@
 package mcp
 
+import "github.com/headless-pm/headless-project-management/internal/models"
+
 func toolDefinitions() []Tool {
 	return []Tool{
 		// Project Management (5 tools)
@@ -11,9 +13,12 @@ func toolDefinitions() []Tool {
 				"properties": map[string]interface{}{
 					"name":        map[string]string{"type": "string"},
 					"description": map[string]string{"type": "string"},
+					"owner_id":    map[string]interface{}{"type": "integer", "description": "Owner user ID; defaults to you"},
 				},
 				"required": []string{"name"},
 			},
+			OutputSchema: objectOutput(models.Project{}),
+			Annotations:  additiveHints(),
 		},
 		{
 			Name:        "get_project",
@@ -21,10 +26,12 @@ func toolDefinitions() []Tool {
 			InputSchema: map[string]interface{}{
 				"type": "object",
 				"properties": map[string]interface{}{
-					"project_id": map[string]string{"type": "number"},
+					"project_id": map[string]string{"type": "integer"},
 				},
 				"required": []string{"project_id"},
 			},
+			OutputSchema: objectOutput(models.Project{}),
+			Annotations:  readOnlyHints(),
 		},
 		{
 			Name:        "update_project",
@@ -32,32 +39,40 @@ func toolDefinitions() []Tool {
 			InputSchema: map[string]interface{}{
 				"type": "object",
 				"properties": map[string]interface{}{
-					"project_id":  map[string]string{"type": "number"},
+					"project_id":  map[string]string{"type": "integer"},
 					"name":        map[string]string{"type": "string"},
 					"description": map[string]string{"type": "string"},
 					"status":      map[string]string{"type": "string"},
 				},
 				"required": []string{"project_id"},
 			},
+			OutputSchema: objectOutput(models.Project{}),
+			Annotations:  updateHints(),
 		},
 		{
 			Name:        "delete_project",
-			Description: "Delete a project",
+			Description: "Delete a project with all its tasks, epics, sprints, milestones, labels and webhooks. " + deletionFlow,
 			InputSchema: map[string]interface{}{
 				"type": "object",
-				"properties": map[string]interface{}{
-					"project_id": map[string]string{"type": "number"},
-				},
+				"properties": withDeletionArgs(map[string]interface{}{
+					"project_id": map[string]string{"type": "integer"},
+				}),
 				"required": []string{"project_id"},
 			},
+			OutputSchema: deletionOutput(map[string]interface{}{"project_id": typed("integer")}),
+			Annotations:  deleteHints(),
 		},
 		{
 			Name:        "list_projects",
 			Description: "List all projects",
 			InputSchema: map[string]interface{}{
-				"type":       "object",
-				"properties": map[string]interface{}{},
+				"type": "object",
+				"properties": map[string]interface{}{
+					"status": map[string]string{"type": "string"},
+				},
 			},
+			OutputSchema: listOutput(models.Project{}),
+			Annotations:  readOnlyHints(),
 		},
 
 		// Task Management (5 tools)
@@ -67,17 +82,22 @@ func toolDefinitions() []Tool {
 			InputSchema: map[string]interface{}{
 				"type": "object",
 				"properties": map[string]interface{}{
-					"project_id":  map[string]string{"type": "number"},
-					"title":       map[string]string{"type": "string"},
-					"description": map[string]string{"type": "string"},
-					"status":      map[string]string{"type": "string"},
-					"priority":    map[string]string{"type": "string"},
-					"assignee_id": map[string]string{"type": "number"},
-					"epic_id":     map[string]string{"type": "number"},
-					"labels":      map[string]interface{}{"type": "array", "items": map[string]string{"type": "string"}},
+					"project_id":   map[string]string{"type": "integer"},
+					"title":        map[string]string{"type": "string"},
+					"description":  map[string]string{"type": "string"},
+					"status":       map[string]string{"type": "string"},
+					"priority":     map[string]string{"type": "string"},
+					"assignee_id":  map[string]string{"type": "integer"},
+					"epic_id":      map[string]string{"type": "integer"},
+					"sprint_id":    map[string]string{"type": "integer"},
+					"milestone_id": map[string]string{"type": "integer"},
+					"due_date":     map[string]string{"type": "string", "description": "Due date (YYYY-MM-DD)"},
+					"labels":       map[string]interface{}{"type": "array", "items": map[string]string{"type": "string"}},
 				},
 				"required": []string{"project_id", "title"},
 			},
+			OutputSchema: objectOutput(models.Task{}),
+			Annotations:  additiveHints(),
 		},
 		{
 			Name:        "get_task",
@@ -85,10 +105,12 @@ func toolDefinitions() []Tool {
 			InputSchema: map[string]interface{}{
 				"type": "object",
 				"properties": map[string]interface{}{
-					"task_id": map[string]string{"type": "number"},
+					"task_id": map[string]string{"type": "integer"},
 				},
 				"required": []string{"task_id"},
 			},
+			OutputSchema: objectOutput(models.Task{}),
+			Annotations:  readOnlyHints(),
 		},
 		{
 			Name:        "update_task",
@@ -96,28 +118,34 @@ func toolDefinitions() []Tool {
 			InputSchema: map[string]interface{}{
 				"type": "object",
 				"properties": map[string]interface{}{
-					"task_id":     map[string]string{"type": "number"},
-					"title":       map[string]string{"type": "string"},
-					"description": map[string]string{"type": "string"},
-					"status":      map[string]string{"type": "string"},
-					"priority":    map[string]string{"type": "string"},
-					"assignee_id": map[string]string{"type": "number"},
-					"epic_id":     map[string]string{"type": "number"},
-					"labels":      map[string]interface{}{"type": "array", "items": map[string]string{"type": "string"}},
+					"task_id":      map[string]string{"type": "integer"},
+					"title":        map[string]string{"type": "string"},
+					"description":  map[string]string{"type": "string"},
+					"status":       map[string]string{"type": "string"},
+					"priority":     map[string]string{"type": "string"},
+					"assignee_id":  map[string]string{"type": "integer"},
+					"epic_id":      map[string]string{"type": "integer"},
+					"sprint_id":    map[string]string{"type": "integer"},
+					"milestone_id": map[string]string{"type": "integer"},
+					"labels":       map[string]interface{}{"type": "array", "items": map[string]string{"type": "string"}},
 				},
 				"required": []string{"task_id"},
 			},
+			OutputSchema: objectOutput(models.Task{}),
+			Annotations:  updateHints(),
 		},
 		{
 			Name:        "delete_task",
-			Description: "Delete a task",
+			Description: "Delete a task with its subtasks, comments, attachments and worklogs. " + deletionFlow,
 			InputSchema: map[string]interface{}{
 				"type": "object",
-				"properties": map[string]interface{}{
-					"task_id": map[string]string{"type": "number"},
-				},
+				"properties": withDeletionArgs(map[string]interface{}{
+					"task_id": map[string]string{"type": "integer"},
+				}),
 				"required": []string{"task_id"},
 			},
+			OutputSchema: deletionOutput(map[string]interface{}{"task_id": typed("integer")}),
+			Annotations:  deleteHints(),
 		},
 		{
 			Name:        "list_tasks",
@@ -125,12 +153,15 @@ func toolDefinitions() []Tool {
 			InputSchema: map[string]interface{}{
 				"type": "object",
 				"properties": map[string]interface{}{
-					"project_id":  map[string]string{"type": "number"},
-					"assignee_id": map[string]string{"type": "number"},
+					"project_id":  map[string]string{"type": "integer"},
+					"assignee_id": map[string]string{"type": "integer"},
 					"status":      map[string]string{"type": "string"},
-					"epic_id":     map[string]string{"type": "number"},
+					"epic_id":     map[string]string{"type": "integer"},
+					"sprint_id":   map[string]string{"type": "integer"},
 				},
 			},
+			OutputSchema: listOutput(models.Task{}),
+			Annotations:  readOnlyHints(),
 		},
 
 		// Epic Management (5 tools)
@@ -140,12 +171,14 @@ func toolDefinitions() []Tool {
 			InputSchema: map[string]interface{}{
 				"type": "object",
 				"properties": map[string]interface{}{
-					"project_id":  map[string]string{"type": "number"},
+					"project_id":  map[string]string{"type": "integer"},
 					"name":        map[string]string{"type": "string"},
 					"description": map[string]string{"type": "string"},
 				},
 				"required": []string{"project_id", "name"},
 			},
+			OutputSchema: objectOutput(models.Epic{}),
+			Annotations:  additiveHints(),
 		},
 		{
 			Name:        "get_epic",
@@ -153,10 +186,12 @@ func toolDefinitions() []Tool {
 			InputSchema: map[string]interface{}{
 				"type": "object",
 				"properties": map[string]interface{}{
-					"epic_id": map[string]string{"type": "number"},
+					"epic_id": map[string]string{"type": "integer"},
 				},
 				"required": []string{"epic_id"},
 			},
+			OutputSchema: objectOutput(models.Epic{}),
+			Annotations:  readOnlyHints(),
 		},
 		{
 			Name:        "update_epic",
@@ -164,25 +199,32 @@ func toolDefinitions() []Tool {
 			InputSchema: map[string]interface{}{
 				"type": "object",
 				"properties": map[string]interface{}{
-					"epic_id":     map[string]string{"type": "number"},
+					"epic_id":     map[string]string{"type": "integer"},
 					"name":        map[string]string{"type": "string"},
 					"description": map[string]string{"type": "string"},
 					"status":      map[string]string{"type": "string"},
 				},
 				"required": []string{"epic_id"},
 			},
+			OutputSchema: objectOutput(models.Epic{}),
+			Annotations:  updateHints(),
 		},
 		{
 			Name:        "delete_epic",
-			Description: "Delete an epic. Optionally cascade delete all tasks associated with the epic",
+			Description: "Delete an epic. Optionally cascade delete all tasks associated with the epic. " + deletionFlow,
 			InputSchema: map[string]interface{}{
 				"type": "object",
-				"properties": map[string]interface{}{
-					"epic_id":       map[string]string{"type": "number", "description": "ID of the epic to delete"},
+				"properties": withDeletionArgs(map[string]interface{}{
+					"epic_id":       map[string]string{"type": "integer", "description": "ID of the epic to delete"},
 					"cascade_tasks": map[string]string{"type": "boolean", "description": "If true, delete all tasks associated with this epic. If false, tasks will have their epic_id set to null"},
-				},
+				}),
 				"required": []string{"epic_id"},
 			},
+			OutputSchema: deletionOutput(map[string]interface{}{
+				"epic_id":       typed("integer"),
+				"cascade_tasks": typed("boolean"),
+			}),
+			Annotations: deleteHints(),
 		},
 		{
 			Name:        "list_epics",
@@ -190,9 +232,233 @@ func toolDefinitions() []Tool {
 			InputSchema: map[string]interface{}{
 				"type": "object",
 				"properties": map[string]interface{}{
-					"project_id": map[string]string{"type": "number"},
+					"project_id": map[string]string{"type": "integer"},
+				},
+			},
+			OutputSchema: listOutput(models.Epic{}),
+			Annotations:  readOnlyHints(),
+		},
+
+		// Sprint Management (9 tools)
+		{
+			Name:        "create_sprint",
+			Description: "Create a new sprint in a project",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"project_id": map[string]string{"type": "integer"},
+					"name":       map[string]string{"type": "string"},
+					"goal":       map[string]string{"type": "string"},
+					"start_date": map[string]string{"type": "string", "description": "Start date (YYYY-MM-DD)"},
+					"end_date":   map[string]string{"type": "string", "description": "End date (YYYY-MM-DD)"},
+				},
+				"required": []string{"project_id", "name"},
+			},
+			OutputSchema: objectOutput(models.Sprint{}),
+			Annotations:  additiveHints(),
+		},
+		{
+			Name:        "get_sprint",
+			Description: "Get sprint details and its tasks by ID",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"sprint_id": map[string]string{"type": "integer"},
+				},
+				"required": []string{"sprint_id"},
+			},
+			OutputSchema: objectOutput(models.Sprint{}),
+			Annotations:  readOnlyHints(),
+		},
+		{
+			Name:        "update_sprint",
+			Description: "Update an existing sprint. Use start_sprint and complete_sprint to change its status",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"sprint_id":  map[string]string{"type": "integer"},
+					"name":       map[string]string{"type": "string"},
+					"goal":       map[string]string{"type": "string"},
+					"start_date": map[string]string{"type": "string", "description": "Start date (YYYY-MM-DD)"},
+					"end_date":   map[string]string{"type": "string", "description": "End date (YYYY-MM-DD)"},
+				},
+				"required": []string{"sprint_id"},
+			},
+			OutputSchema: objectOutput(models.Sprint{}),
+			Annotations:  updateHints(),
+		},
+		{
+			Name:        "delete_sprint",
+			Description: "Delete a sprint. Its tasks are moved back to the backlog",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"sprint_id": map[string]string{"type": "integer"},
+				},
+				"required": []string{"sprint_id"},
+			},
+			OutputSchema: statusOutput("sprint_id"),
+			Annotations:  deleteHints(),
+		},
+		{
+			Name:        "list_sprints",
+			Description: "List sprints with optional project and status filters",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"project_id": map[string]string{"type": "integer"},
+					"status":     map[string]interface{}{"type": "string", "enum": []string{"planned", "active", "completed"}},
+				},
+			},
+			OutputSchema: listOutput(models.Sprint{}),
+			Annotations:  readOnlyHints(),
+		},
+		{
+			Name:        "add_task_to_sprint",
+			Description: "Add one or more tasks to a sprint",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"sprint_id": map[string]string{"type": "integer"},
+					"task_id":   map[string]string{"type": "integer"},
+					"task_ids":  map[string]interface{}{"type": "array", "items": map[string]string{"type": "integer"}},
+				},
+				"required": []string{"sprint_id"},
+			},
+			OutputSchema: fieldsOutput(map[string]interface{}{
+				"success":   typed("boolean"),
+				"sprint_id": typed("integer"),
+				"task_ids":  arrayOf(typed("integer")),
+			}, "success", "sprint_id", "task_ids"),
+			Annotations: updateHints(),
+		},
+		{
+			Name:        "remove_task_from_sprint",
+			Description: "Move a task out of its sprint and back to the backlog",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"task_id": map[string]string{"type": "integer"},
+				},
+				"required": []string{"task_id"},
+			},
+			OutputSchema: successOutput("task_id"),
+			Annotations:  deleteHints(),
+		},
+		{
+			Name:        "start_sprint",
+			Description: "Start a planned sprint. Unfinished tasks from the last completed sprint are carried over",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"sprint_id": map[string]string{"type": "integer"},
+				},
+				"required": []string{"sprint_id"},
+			},
+			OutputSchema: sprintTransitionOutput(),
+			Annotations:  additiveHints(),
+		},
+		{
+			Name:        "complete_sprint",
+			Description: "Complete an active sprint and carry unfinished tasks over to the next sprint",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"sprint_id":      map[string]string{"type": "integer"},
+					"next_sprint_id": map[string]string{"type": "integer", "description": "Sprint to carry unfinished tasks into. Defaults to the next planned sprint"},
+				},
+				"required": []string{"sprint_id"},
+			},
+			OutputSchema: sprintTransitionOutput(),
+			Annotations:  additiveHints(),
+		},
+
+		// Milestone Management (6 tools)
+		{
+			Name:        "create_milestone",
+			Description: "Create a new milestone in a project",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"project_id":  map[string]string{"type": "integer"},
+					"name":        map[string]string{"type": "string"},
+					"description": map[string]string{"type": "string"},
+					"due_date":    map[string]string{"type": "string", "description": "Due date (YYYY-MM-DD)"},
+				},
+				"required": []string{"project_id", "name"},
+			},
+			OutputSchema: objectOutput(models.Milestone{}),
+			Annotations:  additiveHints(),
+		},
+		{
+			Name:        "get_milestone",
+			Description: "Get milestone details and its tasks by ID",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"milestone_id": map[string]string{"type": "integer"},
+				},
+				"required": []string{"milestone_id"},
+			},
+			OutputSchema: objectOutput(models.Milestone{}),
+			Annotations:  readOnlyHints(),
+		},
+		{
+			Name:        "update_milestone",
+			Description: "Update an existing milestone",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"milestone_id": map[string]string{"type": "integer"},
+					"name":         map[string]string{"type": "string"},
+					"description":  map[string]string{"type": "string"},
+					"status":       map[string]interface{}{"type": "string", "enum": []string{"planned", "active", "completed", "cancelled"}},
+					"due_date":     map[string]string{"type": "string", "description": "Due date (YYYY-MM-DD)"},
+				},
+				"required": []string{"milestone_id"},
+			},
+			OutputSchema: objectOutput(models.Milestone{}),
+			Annotations:  updateHints(),
+		},
+		{
+			Name:        "delete_milestone",
+			Description: "Delete a milestone. Its tasks are kept and detached from it",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"milestone_id": map[string]string{"type": "integer"},
+				},
+				"required": []string{"milestone_id"},
+			},
+			OutputSchema: statusOutput("milestone_id"),
+			Annotations:  deleteHints(),
+		},
+		{
+			Name:        "list_milestones",
+			Description: "List milestones with optional project and status filters",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"project_id": map[string]string{"type": "integer"},
+					"status":     map[string]interface{}{"type": "string", "enum": []string{"planned", "active", "completed", "cancelled"}},
+				},
+			},
+			OutputSchema: listOutput(models.Milestone{}),
+			Annotations:  readOnlyHints(),
+		},
+		{
+			Name:        "assign_task_to_milestone",
+			Description: "Assign a task to a milestone",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"milestone_id": map[string]string{"type": "integer"},
+					"task_id":      map[string]string{"type": "integer"},
 				},
+				"required": []string{"milestone_id", "task_id"},
 			},
+			OutputSchema: successOutput("milestone_id", "task_id"),
+			Annotations:  updateHints(),
 		},
 
 		// Label Management (5 tools)
@@ -202,12 +468,14 @@ func toolDefinitions() []Tool {
 			InputSchema: map[string]interface{}{
 				"type": "object",
 				"properties": map[string]interface{}{
-					"project_id": map[string]string{"type": "number"},
+					"project_id": map[string]string{"type": "integer"},
 					"name":       map[string]string{"type": "string"},
 					"color":      map[string]string{"type": "string"},
 				},
 				"required": []string{"project_id", "name"},
 			},
+			OutputSchema: objectOutput(models.Label{}),
+			Annotations:  additiveHints(),
 		},
 		{
 			Name:        "assign_label",
@@ -215,11 +483,13 @@ func toolDefinitions() []Tool {
 			InputSchema: map[string]interface{}{
 				"type": "object",
 				"properties": map[string]interface{}{
-					"task_id":  map[string]string{"type": "number"},
-					"label_id": map[string]string{"type": "number"},
+					"task_id":  map[string]string{"type": "integer"},
+					"label_id": map[string]string{"type": "integer"},
 				},
 				"required": []string{"task_id", "label_id"},
 			},
+			OutputSchema: successOutput("task_id", "label_id"),
+			Annotations:  setHints(),
 		},
 		{
 			Name:        "list_labels",
@@ -227,10 +497,12 @@ func toolDefinitions() []Tool {
 			InputSchema: map[string]interface{}{
 				"type": "object",
 				"properties": map[string]interface{}{
-					"project_id": map[string]string{"type": "number"},
+					"project_id": map[string]string{"type": "integer"},
 				},
 				"required": []string{"project_id"},
 			},
+			OutputSchema: listOutput(models.Label{}),
+			Annotations:  readOnlyHints(),
 		},
 		{
 			Name:        "update_label",
@@ -238,12 +510,14 @@ func toolDefinitions() []Tool {
 			InputSchema: map[string]interface{}{
 				"type": "object",
 				"properties": map[string]interface{}{
-					"label_id": map[string]string{"type": "number"},
+					"label_id": map[string]string{"type": "integer"},
 					"name":     map[string]string{"type": "string"},
 					"color":    map[string]string{"type": "string"},
 				},
 				"required": []string{"label_id"},
 			},
+			OutputSchema: objectOutput(models.Label{}),
+			Annotations:  updateHints(),
 		},
 		{
 			Name:        "delete_label",
@@ -251,10 +525,12 @@ func toolDefinitions() []Tool {
 			InputSchema: map[string]interface{}{
 				"type": "object",
 				"properties": map[string]interface{}{
-					"label_id": map[string]string{"type": "number"},
+					"label_id": map[string]string{"type": "integer"},
 				},
 				"required": []string{"label_id"},
 			},
+			OutputSchema: messageOutput(),
+			Annotations:  deleteHints(),
 		},
 
 		// Assignee Management (2 tools)
@@ -264,11 +540,13 @@ func toolDefinitions() []Tool {
 			InputSchema: map[string]interface{}{
 				"type": "object",
 				"properties": map[string]interface{}{
-					"task_id":     map[string]string{"type": "number"},
-					"assignee_id": map[string]string{"type": "number"},
+					"task_id":     map[string]string{"type": "integer"},
+					"assignee_id": map[string]string{"type": "integer"},
 				},
 				"required": []string{"task_id", "assignee_id"},
 			},
+			OutputSchema: objectOutput(models.Task{}),
+			Annotations:  updateHints(),
 		},
 		{
 			Name:        "list_assignees",
@@ -276,16 +554,18 @@ func toolDefinitions() []Tool {
 			InputSchema: map[string]interface{}{
 				"type": "object",
 				"properties": map[string]interface{}{
-					"project_id": map[string]string{"type": "number"},
+					"project_id": map[string]string{"type": "integer"},
 				},
 				"required": []string{"project_id"},
 			},
+			OutputSchema: listOutput(models.User{}),
+			Annotations:  readOnlyHints(),
 		},
 
 		// User Management (5 tools)
 		{
 			Name:        "create_user",
-			Description: "Create a new user",
+			Description: "Create a new user. Agents are users of type agent; their changes are attributed to them and to the client they run as",
 			InputSchema: map[string]interface{}{
 				"type": "object",
 				"properties": map[string]interface{}{
@@ -295,9 +575,13 @@ func toolDefinitions() []Tool {
 					"first_name": map[string]string{"type": "string"},
 					"last_name":  map[string]string{"type": "string"},
 					"role":       map[string]interface{}{"type": "string", "enum": []string{"admin", "member"}},
+					"type":       userTypeSchema(),
+					"client":     map[string]string{"type": "string", "description": "Model or MCP client an agent runs as. Set from the client of its first MCP session if empty"},
 				},
 				"required": []string{"username", "email", "password"},
 			},
+			OutputSchema: objectOutput(models.User{}),
+			Annotations:  additiveHints(),
 		},
 		{
 			Name:        "list_users",
@@ -306,6 +590,8 @@ func toolDefinitions() []Tool {
 				"type":       "object",
 				"properties": map[string]interface{}{},
 			},
+			OutputSchema: listOutput(models.User{}),
+			Annotations:  readOnlyHints(),
 		},
 		{
 			Name:        "get_user",
@@ -313,10 +599,12 @@ func toolDefinitions() []Tool {
 			InputSchema: map[string]interface{}{
 				"type": "object",
 				"properties": map[string]interface{}{
-					"user_id": map[string]string{"type": "number"},
+					"user_id": map[string]string{"type": "integer"},
 				},
 				"required": []string{"user_id"},
 			},
+			OutputSchema: objectOutput(models.User{}),
+			Annotations:  readOnlyHints(),
 		},
 		{
 			Name:        "update_user",
@@ -324,76 +612,217 @@ func toolDefinitions() []Tool {
 			InputSchema: map[string]interface{}{
 				"type": "object",
 				"properties": map[string]interface{}{
-					"user_id":    map[string]string{"type": "number"},
+					"user_id":    map[string]string{"type": "integer"},
 					"username":   map[string]string{"type": "string"},
 					"email":      map[string]string{"type": "string"},
 					"first_name": map[string]string{"type": "string"},
 					"last_name":  map[string]string{"type": "string"},
 					"role":       map[string]interface{}{"type": "string", "enum": []string{"admin", "member"}},
 					"is_active":  map[string]string{"type": "boolean"},
+					"type":       userTypeSchema(),
+					"client":     map[string]string{"type": "string", "description": "Model or MCP client an agent runs as"},
 				},
 				"required": []string{"user_id"},
 			},
+			OutputSchema: objectOutput(models.User{}),
+			Annotations:  updateHints(),
 		},
 		{
 			Name:        "delete_user",
-			Description: "Delete a user",
+			Description: "Delete a user. Their tasks are unassigned and their projects pass to the system user. " + deletionFlow,
 			InputSchema: map[string]interface{}{
 				"type": "object",
-				"properties": map[string]interface{}{
-					"user_id": map[string]string{"type": "number"},
-				},
+				"properties": withDeletionArgs(map[string]interface{}{
+					"user_id": map[string]string{"type": "integer"},
+				}),
 				"required": []string{"user_id"},
 			},
+			OutputSchema: deletionOutput(map[string]interface{}{"user_id": typed("integer")}),
+			Annotations:  deleteHints(),
 		},
 
-		// Comments/Notes (4 tools)
+		// Comments/Notes (8 tools)
 		{
 			Name:        "add_comment",
-			Description: "Add a comment/note to a task",
+			Description: "Add a comment/note to a task, or a reply to another comment with parent_id. With a user token the comment is by you; otherwise the author is a username or author_id. attachment_ids links files already uploaded to the task",
 			InputSchema: map[string]interface{}{
 				"type": "object",
 				"properties": map[string]interface{}{
-					"task_id": map[string]string{"type": "number"},
-					"content": map[string]string{"type": "string"},
-					"author":  map[string]string{"type": "string"},
+					"task_id":        map[string]string{"type": "integer"},
+					"content":        map[string]string{"type": "string"},
+					"author":         map[string]string{"type": "string"},
+					"author_id":      map[string]string{"type": "integer"},
+					"parent_id":      map[string]string{"type": "integer"},
+					"attachment_ids": map[string]interface{}{"type": "array", "items": map[string]string{"type": "integer"}},
 				},
-				"required": []string{"task_id", "content", "author"},
+				"required": []string{"task_id", "content"},
 			},
+			OutputSchema: objectOutput(models.Comment{}),
+			Annotations:  additiveHints(),
 		},
 		{
 			Name:        "update_comment",
-			Description: "Update an existing comment/note",
+			Description: "Update an existing comment/note. Only its author or an admin can. The previous content is kept in the comment history",
 			InputSchema: map[string]interface{}{
 				"type": "object",
 				"properties": map[string]interface{}{
-					"comment_id": map[string]string{"type": "number"},
+					"comment_id": map[string]string{"type": "integer"},
 					"content":    map[string]string{"type": "string"},
+					"editor_id":  map[string]string{"type": "integer"},
 				},
 				"required": []string{"comment_id", "content"},
 			},
+			OutputSchema: objectOutput(models.Comment{}),
+			Annotations:  updateHints(),
 		},
 		{
 			Name:        "delete_comment",
-			Description: "Delete a comment/note",
+			Description: "Delete a comment/note and its replies. Only its author or an admin can",
 			InputSchema: map[string]interface{}{
 				"type": "object",
 				"properties": map[string]interface{}{
-					"comment_id": map[string]string{"type": "number"},
+					"comment_id": map[string]string{"type": "integer"},
 				},
 				"required": []string{"comment_id"},
 			},
+			OutputSchema: fieldsOutput(map[string]interface{}{
+				"deleted":    typed("boolean"),
+				"comment_id": typed("integer"),
+				"task_id":    typed("integer"),
+			}, "deleted", "comment_id", "task_id"),
+			Annotations: deleteHints(),
 		},
 		{
 			Name:        "list_comments",
-			Description: "List all comments/notes for a task",
+			Description: "List all comments/notes for a task with their reactions and attachments. Set threaded to nest replies under their parent",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"task_id":  map[string]string{"type": "integer"},
+					"threaded": map[string]string{"type": "boolean"},
+				},
+				"required": []string{"task_id"},
+			},
+			OutputSchema: listOutput(models.Comment{}),
+			Annotations:  readOnlyHints(),
+		},
+		{
+			Name:        "get_comment",
+			Description: "Get a comment with its author, reactions, attachments and edit history",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"comment_id": map[string]string{"type": "integer"},
+				},
+				"required": []string{"comment_id"},
+			},
+			OutputSchema: objectOutput(models.Comment{}),
+			Annotations:  readOnlyHints(),
+		},
+		{
+			Name:        "get_comment_history",
+			Description: "List the previous versions of an edited comment, oldest first",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"comment_id": map[string]string{"type": "integer"},
+				},
+				"required": []string{"comment_id"},
+			},
+			OutputSchema: listOutput(models.CommentRevision{}),
+			Annotations:  readOnlyHints(),
+		},
+		{
+			Name:        "add_comment_reaction",
+			Description: "React to a comment with an emoji",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"comment_id": map[string]string{"type": "integer"},
+					"user_id":    map[string]string{"type": "integer"},
+					"emoji":      map[string]string{"type": "string"},
+				},
+				"required": []string{"comment_id", "user_id", "emoji"},
+			},
+			OutputSchema: objectOutput(models.CommentReaction{}),
+			Annotations:  setHints(),
+		},
+		{
+			Name:        "remove_comment_reaction",
+			Description: "Remove an emoji reaction from a comment",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"comment_id": map[string]string{"type": "integer"},
+					"user_id":    map[string]string{"type": "integer"},
+					"emoji":      map[string]string{"type": "string"},
+				},
+				"required": []string{"comment_id", "user_id", "emoji"},
+			},
+			OutputSchema: messageOutput(),
+			Annotations:  deleteHints(),
+		},
+
+		// Plan Import (1 tool)
+		{
+			Name:        "import_plan",
+			Description: "Create a whole project plan in one transaction: epics with their tasks, tasks outside epics, subtasks, labels and dependencies between tasks by local ref. Either everything is created or nothing is. Returns the IDs of the created items by ref. With dry_run the plan is only validated, including dependency cycles",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"project_id": map[string]string{"type": "integer"},
+					"epics":      arrayOf(planEpicSchema()),
+					"tasks":      arrayOf(planTaskSchema(planSubtaskDepth)),
+					"dry_run":    map[string]string{"type": "boolean", "description": "Only validate the plan and report what it would create"},
+				},
+				"required": []string{"project_id"},
+			},
+			OutputSchema: planOutput(),
+			Annotations:  additiveHints(),
+		},
+
+		// Task Claims (3 tools)
+		{
+			Name:        "claim_next_task",
+			Description: "Claim the best available task of a project for yourself, usually as an agent: the most urgent, then the one due first, among tasks to do that are unassigned or assigned to you, not claimed, not blocked by dependencies and only carrying labels you are qualified for. The task is assigned to you, set in progress and leased; send heartbeat_task before the lease expires or the task returns to the pool",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"project_id":    map[string]string{"type": "integer"},
+					"labels":        map[string]interface{}{"type": "array", "items": map[string]string{"type": "string"}, "description": "Labels you are qualified for. Tasks with other labels are skipped; without labels every task qualifies"},
+					"lease_seconds": map[string]interface{}{"type": "integer", "minimum": 1, "description": "How long the lease lasts without a heartbeat. Defaults to 600"},
+				},
+				"required": []string{"project_id"},
+			},
+			OutputSchema: claimOutput(),
+			Annotations:  additiveHints(),
+		},
+		{
+			Name:        "heartbeat_task",
+			Description: "Extend your lease on a claimed task. Fails if the lease already expired and the task returned to the pool",
 			InputSchema: map[string]interface{}{
 				"type": "object",
 				"properties": map[string]interface{}{
-					"task_id": map[string]string{"type": "number"},
+					"task_id":       map[string]string{"type": "integer"},
+					"lease_seconds": map[string]interface{}{"type": "integer", "minimum": 1, "description": "New lease from now. Defaults to 600"},
 				},
 				"required": []string{"task_id"},
 			},
+			OutputSchema: objectOutput(models.TaskLease{}),
+			Annotations:  updateHints(),
+		},
+		{
+			Name:        "release_task",
+			Description: "Give up your lease on a claimed task. A task still in progress is unassigned and returned to the pool",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"task_id": map[string]string{"type": "integer"},
+				},
+				"required": []string{"task_id"},
+			},
+			OutputSchema: objectOutput(models.Task{}),
+			Annotations:  updateHints(),
 		},
 
 		// Task Dependencies (7 tools)
@@ -403,12 +832,14 @@ func toolDefinitions() []Tool {
 			InputSchema: map[string]interface{}{
 				"type": "object",
 				"properties": map[string]interface{}{
-					"task_id":      map[string]string{"type": "number"},
-					"depends_on_id": map[string]string{"type": "number"},
-					"type":         map[string]string{"type": "string"},
+					"task_id":       map[string]string{"type": "integer"},
+					"depends_on_id": map[string]string{"type": "integer"},
+					"type":          map[string]string{"type": "string"},
 				},
 				"required": []string{"task_id", "depends_on_id"},
 			},
+			OutputSchema: objectOutput(models.TaskDependency{}),
+			Annotations:  additiveHints(),
 		},
 		{
 			Name:        "remove_task_dependency",
@@ -416,10 +847,12 @@ func toolDefinitions() []Tool {
 			InputSchema: map[string]interface{}{
 				"type": "object",
 				"properties": map[string]interface{}{
-					"dependency_id": map[string]string{"type": "number"},
+					"dependency_id": map[string]string{"type": "integer"},
 				},
 				"required": []string{"dependency_id"},
 			},
+			OutputSchema: statusOutput(),
+			Annotations:  deleteHints(),
 		},
 		{
 			Name:        "list_task_dependencies",
@@ -427,10 +860,12 @@ func toolDefinitions() []Tool {
 			InputSchema: map[string]interface{}{
 				"type": "object",
 				"properties": map[string]interface{}{
-					"task_id": map[string]string{"type": "number"},
+					"task_id": map[string]string{"type": "integer"},
 				},
 				"required": []string{"task_id"},
 			},
+			OutputSchema: listOutput(models.TaskDependency{}),
+			Annotations:  readOnlyHints(),
 		},
 		{
 			Name:        "get_task_dependency_chain",
@@ -438,10 +873,12 @@ func toolDefinitions() []Tool {
 			InputSchema: map[string]interface{}{
 				"type": "object",
 				"properties": map[string]interface{}{
-					"task_id": map[string]string{"type": "number"},
+					"task_id": map[string]string{"type": "integer"},
 				},
 				"required": []string{"task_id"},
 			},
+			OutputSchema: listOutput(models.Task{}),
+			Annotations:  readOnlyHints(),
 		},
 		{
 			Name:        "get_task_dependent_chain",
@@ -449,10 +886,12 @@ func toolDefinitions() []Tool {
 			InputSchema: map[string]interface{}{
 				"type": "object",
 				"properties": map[string]interface{}{
-					"task_id": map[string]string{"type": "number"},
+					"task_id": map[string]string{"type": "integer"},
 				},
 				"required": []string{"task_id"},
 			},
+			OutputSchema: listOutput(models.Task{}),
+			Annotations:  readOnlyHints(),
 		},
 		{
 			Name:        "can_start_task",
@@ -460,10 +899,15 @@ func toolDefinitions() []Tool {
 			InputSchema: map[string]interface{}{
 				"type": "object",
 				"properties": map[string]interface{}{
-					"task_id": map[string]string{"type": "number"},
+					"task_id": map[string]string{"type": "integer"},
 				},
 				"required": []string{"task_id"},
 			},
+			OutputSchema: fieldsOutput(map[string]interface{}{
+				"can_start":      typed("boolean"),
+				"blocking_tasks": nullable(arrayOf(objectOutput(models.Task{}))),
+			}, "can_start", "blocking_tasks"),
+			Annotations: readOnlyHints(),
 		},
 		{
 			Name:        "get_project_dependency_graph",
@@ -471,10 +915,408 @@ func toolDefinitions() []Tool {
 			InputSchema: map[string]interface{}{
 				"type": "object",
 				"properties": map[string]interface{}{
-					"project_id": map[string]string{"type": "number"},
+					"project_id": map[string]string{"type": "integer"},
 				},
 				"required": []string{"project_id"},
 			},
+			OutputSchema: typed("object"),
+			Annotations:  readOnlyHints(),
+		},
+
+		// Watchers and Mentions (5 tools)
+		{
+			Name:        "watch_task",
+			Description: "Watch a task to be notified of its changes. Creators, assignees and commenters watch tasks automatically",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"task_id": map[string]string{"type": "integer"},
+					"user_id": map[string]string{"type": "integer", "description": "Defaults to you. Only admins can name another user"},
+				},
+				"required": []string{"task_id"},
+			},
+			OutputSchema: messageOutput(),
+			Annotations:  setHints(),
+		},
+		{
+			Name:        "unwatch_task",
+			Description: "Stop watching a task",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"task_id": map[string]string{"type": "integer"},
+					"user_id": map[string]string{"type": "integer", "description": "Defaults to you. Only admins can name another user"},
+				},
+				"required": []string{"task_id"},
+			},
+			OutputSchema: messageOutput(),
+			Annotations:  deleteHints(),
+		},
+		{
+			Name:        "list_task_watchers",
+			Description: "List the users watching a task",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"task_id": map[string]string{"type": "integer"},
+				},
+				"required": []string{"task_id"},
+			},
+			OutputSchema: listOutput(models.User{}),
+			Annotations:  readOnlyHints(),
+		},
+		{
+			Name:        "list_watched_tasks",
+			Description: "List the tasks you are watching (admins can name any user)",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"user_id": map[string]string{"type": "integer"},
+				},
+			},
+			OutputSchema: listOutput(models.Task{}),
+			Annotations:  readOnlyHints(),
+		},
+
+		{
+			Name:        "list_mentions",
+			Description: "List the tasks whose description or comments @mention you (admins can name any user)",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"user_id":  map[string]string{"type": "integer"},
+					"username": map[string]string{"type": "string"},
+				},
+			},
+			OutputSchema: listOutput(models.Task{}),
+			Annotations:  readOnlyHints(),
+		},
+
+		// Notifications (4 tools)
+		{
+			Name:        "list_notifications",
+			Description: "List your notifications (assignments, mentions, status changes on watched tasks, due dates, unblocked tasks)",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"unread_only": map[string]string{"type": "boolean"},
+					"limit":       map[string]string{"type": "integer", "description": "Maximum number of notifications (default 50)"},
+				},
+			},
+			OutputSchema: fieldsOutput(map[string]interface{}{
+				"notifications": nullable(arrayOf(objectOutput(models.Notification{}))),
+				"unread_count":  typed("integer"),
+			}, "notifications", "unread_count"),
+			Annotations: readOnlyHints(),
+		},
+		{
+			Name:        "mark_notifications_read",
+			Description: "Mark your notifications as read. Omit notification_ids to mark all of them",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"notification_ids": map[string]interface{}{"type": "array", "items": map[string]string{"type": "integer"}},
+				},
+			},
+			OutputSchema: fieldsOutput(map[string]interface{}{"updated": typed("integer")}, "updated"),
+			Annotations:  setHints(),
+		},
+		{
+			Name:        "get_notification_preferences",
+			Description: "Get which notification types you have enabled",
+			InputSchema: map[string]interface{}{
+				"type":       "object",
+				"properties": map[string]interface{}{},
+			},
+			OutputSchema: preferencesOutput(),
+			Annotations:  readOnlyHints(),
+		},
+		{
+			Name:        "update_notification_preferences",
+			Description: "Enable or disable notification types for yourself, e.g. {\"due_soon\": false}. Types: assigned, mentioned, status_changed, due_soon, dependency_unblocked",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"preferences": map[string]interface{}{"type": "object", "additionalProperties": map[string]string{"type": "boolean"}},
+				},
+				"required": []string{"preferences"},
+			},
+			OutputSchema: preferencesOutput(),
+			Annotations:  updateHints(),
+		},
+
+		// Webhooks (7 tools)
+		{
+			Name:        "create_webhook",
+			Description: "Subscribe a URL to project events. Omit project_id for all projects and events for all events. Deliveries are signed with the returned secret (X-Webhook-Signature: sha256=HMAC of \"<X-Webhook-Timestamp>.<body>\")",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"name":       map[string]string{"type": "string"},
+					"url":        map[string]string{"type": "string"},
+					"project_id": map[string]string{"type": "integer"},
+					"events":     map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string", "enum": webhookEventNames()}},
+					"secret":     map[string]string{"type": "string"},
+				},
+				"required": []string{"name", "url"},
+			},
+			OutputSchema: fieldsOutput(map[string]interface{}{
+				"webhook": objectOutput(models.Webhook{}),
+				"secret":  typed("string"),
+			}, "webhook", "secret"),
+			Annotations: additiveHints(),
+		},
+		{
+			Name:        "list_webhooks",
+			Description: "List webhook subscriptions and the events they can subscribe to",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"project_id": map[string]string{"type": "integer"},
+				},
+			},
+			OutputSchema: fieldsOutput(map[string]interface{}{
+				"webhooks":         nullable(arrayOf(objectOutput(models.Webhook{}))),
+				"available_events": arrayOf(typed("string")),
+			}, "webhooks", "available_events"),
+			Annotations: readOnlyHints(),
+		},
+		{
+			Name:        "update_webhook",
+			Description: "Update a webhook subscription; set active to false to pause deliveries",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"webhook_id": map[string]string{"type": "integer"},
+					"name":       map[string]string{"type": "string"},
+					"url":        map[string]string{"type": "string"},
+					"events":     map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string", "enum": webhookEventNames()}},
+					"secret":     map[string]string{"type": "string"},
+					"active":     map[string]string{"type": "boolean"},
+				},
+				"required": []string{"webhook_id"},
+			},
+			OutputSchema: objectOutput(models.Webhook{}),
+			Annotations:  updateHints(),
+		},
+		{
+			Name:        "delete_webhook",
+			Description: "Delete a webhook subscription and its delivery log",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"webhook_id": map[string]string{"type": "integer"},
+				},
+				"required": []string{"webhook_id"},
+			},
+			OutputSchema: messageOutput(),
+			Annotations:  deleteHints(),
+		},
+		{
+			Name:        "ping_webhook",
+			Description: "Send a ping event to a webhook and return the delivery result",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"webhook_id": map[string]string{"type": "integer"},
+				},
+				"required": []string{"webhook_id"},
+			},
+			OutputSchema: objectOutput(models.WebhookDelivery{}),
+			Annotations:  externalHints(),
+		},
+		{
+			Name:        "list_webhook_deliveries",
+			Description: "List the delivery log of a webhook, newest first",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"webhook_id": map[string]string{"type": "integer"},
+					"status":     map[string]interface{}{"type": "string", "enum": []string{"pending", "delivering", "succeeded", "failed"}},
+					"limit":      map[string]string{"type": "integer"},
+				},
+				"required": []string{"webhook_id"},
+			},
+			OutputSchema: listOutput(models.WebhookDelivery{}),
+			Annotations:  readOnlyHints(),
+		},
+		{
+			Name:        "redeliver_webhook",
+			Description: "Send the payload of an earlier delivery again",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"delivery_id": map[string]string{"type": "integer"},
+				},
+				"required": []string{"delivery_id"},
+			},
+			OutputSchema: objectOutput(models.WebhookDelivery{}),
+			Annotations:  externalHints(),
+		},
+
+		// Prompt Templates (3 tools)
+		{
+			Name:        "create_prompt_template",
+			Description: "Add a team prompt served through prompts/list and prompts/get. The template is a Go text/template with .Args, .Project (from the project argument) and .Now, and functions such as openTasks, backlog, untriaged, activity, completedTasks, activeSprint, velocity, epic, time and task. Built-in prompt names cannot be reused",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"name":        map[string]string{"type": "string"},
+					"description": map[string]string{"type": "string"},
+					"arguments":   promptArgumentsSchema(),
+					"template":    map[string]string{"type": "string"},
+				},
+				"required": []string{"name", "template"},
+			},
+			OutputSchema: objectOutput(models.PromptTemplate{}),
+			Annotations:  additiveHints(),
+		},
+		{
+			Name:        "update_prompt_template",
+			Description: "Update a team prompt template. Only its creator or an admin can",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"template_id": map[string]string{"type": "integer"},
+					"name":        map[string]string{"type": "string"},
+					"description": map[string]string{"type": "string"},
+					"arguments":   promptArgumentsSchema(),
+					"template":    map[string]string{"type": "string"},
+				},
+				"required": []string{"template_id"},
+			},
+			OutputSchema: objectOutput(models.PromptTemplate{}),
+			Annotations:  updateHints(),
+		},
+		{
+			Name:        "delete_prompt_template",
+			Description: "Delete a team prompt template. Only its creator or an admin can",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"template_id": map[string]string{"type": "integer"},
+				},
+				"required": []string{"template_id"},
+			},
+			OutputSchema: messageOutput(),
+			Annotations:  deleteHints(),
+		},
+
+		// Time Tracking (7 tools)
+		{
+			Name:        "log_work",
+			Description: "Log time you spent on a task. The task's actual hours are rolled up from its worklogs",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"task_id":          map[string]string{"type": "integer"},
+					"duration_minutes": map[string]string{"type": "integer", "description": "Time spent in minutes"},
+					"started_at":       map[string]string{"type": "string", "description": "When the work started (RFC3339). Defaults to now minus the duration"},
+					"note":             map[string]string{"type": "string"},
+				},
+				"required": []string{"task_id", "duration_minutes"},
+			},
+			OutputSchema: objectOutput(models.Worklog{}),
+			Annotations:  additiveHints(),
+		},
+		{
+			Name:        "list_worklogs",
+			Description: "List your worklogs, optionally filtered by task (admins can list any user's, or everyone's)",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"task_id": map[string]string{"type": "integer"},
+					"user_id": map[string]string{"type": "integer"},
+				},
+			},
+			OutputSchema: listOutput(models.Worklog{}),
+			Annotations:  readOnlyHints(),
+		},
+		{
+			Name:        "delete_worklog",
+			Description: "Delete one of your worklogs (admins can delete any)",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"worklog_id": map[string]string{"type": "integer"},
+				},
+				"required": []string{"worklog_id"},
+			},
+			OutputSchema: messageOutput(),
+			Annotations:  deleteHints(),
+		},
+		{
+			Name:        "start_timer",
+			Description: "Start your timer on a task. You can only have one running timer",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"task_id": map[string]string{"type": "integer"},
+					"note":    map[string]string{"type": "string"},
+				},
+				"required": []string{"task_id"},
+			},
+			OutputSchema: objectOutput(models.Worklog{}),
+			Annotations:  additiveHints(),
+		},
+		{
+			Name:        "stop_timer",
+			Description: "Stop your running timer and record it as a worklog",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"note": map[string]string{"type": "string", "description": "Replaces the note given when the timer started"},
+				},
+			},
+			OutputSchema: objectOutput(models.Worklog{}),
+			Annotations:  additiveHints(),
+		},
+		{
+			Name:        "get_running_timer",
+			Description: "Get your running timer, if any",
+			InputSchema: map[string]interface{}{
+				"type":       "object",
+				"properties": map[string]interface{}{},
+			},
+			OutputSchema: fieldsOutput(map[string]interface{}{
+				"running":         typed("boolean"),
+				"worklog":         objectOutput(models.Worklog{}),
+				"elapsed_minutes": typed("integer"),
+			}, "running"),
+			Annotations: readOnlyHints(),
+		},
+		{
+			Name:        "get_timesheet",
+			Description: "Get a timesheet report of your logged time, optionally per project, as JSON or CSV (admins can report on any user, or everyone)",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"user_id":    map[string]string{"type": "integer"},
+					"project_id": map[string]string{"type": "integer"},
+					"from":       map[string]string{"type": "string", "description": "First day to include (YYYY-MM-DD)"},
+					"to":         map[string]string{"type": "string", "description": "Last day to include (YYYY-MM-DD)"},
+					"format":     map[string]interface{}{"type": "string", "enum": []string{"json", "csv"}},
+				},
+			},
+			OutputSchema: timesheetOutput(),
+			Annotations:  readOnlyHints(),
+		},
+		{
+			Name:        "get_activity_digest",
+			Description: "Summarise activity over a period for a standup or status report: per user the tasks completed, started, created and commented on, and the assigned tasks that are blocked. Filter by user, project or user type; as JSON or Markdown",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"user_id":    map[string]string{"type": "integer"},
+					"project_id": map[string]string{"type": "integer"},
+					"user_type":  map[string]interface{}{"type": "string", "enum": []string{string(models.UserTypeHuman), string(models.UserTypeAgent)}},
+					"since":      map[string]string{"type": "string", "description": "Start of the period, as YYYY-MM-DD or a duration ago like 24h, 7d or 2w (default 24h)"},
+					"until":      map[string]string{"type": "string", "description": "End of the period, in the same format (default now)"},
+					"format":     map[string]interface{}{"type": "string", "enum": []string{"json", "markdown"}},
+				},
+			},
+			OutputSchema: digestOutput(),
+			Annotations:  readOnlyHints(),
 		},
 	}
-}
\ No newline at end of file
+}
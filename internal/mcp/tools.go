@@ -1,15 +1,16 @@
 package mcp
 
 import (
+	"context"
 	"fmt"
-	"time"
 
 	"github.com/headless-pm/headless-project-management/internal/models"
+	"github.com/headless-pm/headless-project-management/internal/service"
 	"github.com/headless-pm/headless-project-management/pkg/auth"
 )
 
 // Project CRUD operations
-func (s *EnhancedMCPServer) createProject(args []byte) (*ToolResponse, error) {
+func (s *EnhancedMCPServer) createProject(ctx context.Context, args []byte) (*ToolResponse, error) {
 	var input ProjectInput
 	if err := UnmarshalArgs(args, &input); err != nil {
 		return ErrorResponse(err), nil
@@ -18,9 +19,16 @@ func (s *EnhancedMCPServer) createProject(args []byte) (*ToolResponse, error) {
 	project := &models.Project{
 		Name:        input.Name,
 		Description: input.Description,
-		OwnerID:     input.OwnerID,
 		Status:      models.ProjectStatusActive,
 	}
+	// Without an owner_id the database records the caller as the owner
+	if input.OwnerID != 0 {
+		ownerID, err := actingUser(ctx, "create_project", input.OwnerID)
+		if err != nil {
+			return ErrorResponse(err), nil
+		}
+		project.OwnerID = ownerID
+	}
 
 	if err := s.db.CreateProject(project); err != nil {
 		return ErrorResponse(err), nil
@@ -29,7 +37,7 @@ func (s *EnhancedMCPServer) createProject(args []byte) (*ToolResponse, error) {
 	return SuccessResponse(project), nil
 }
 
-func (s *EnhancedMCPServer) getProject(args []byte) (*ToolResponse, error) {
+func (s *EnhancedMCPServer) getProject(ctx context.Context, args []byte) (*ToolResponse, error) {
 	var input struct {
 		ProjectID uint `json:"project_id"`
 	}
@@ -45,7 +53,7 @@ func (s *EnhancedMCPServer) getProject(args []byte) (*ToolResponse, error) {
 	return SuccessResponse(project), nil
 }
 
-func (s *EnhancedMCPServer) updateProject(args []byte) (*ToolResponse, error) {
+func (s *EnhancedMCPServer) updateProject(ctx context.Context, args []byte) (*ToolResponse, error) {
 	var input struct {
 		ID          uint   `json:"project_id"`
 		Name        string `json:"name"`
@@ -78,26 +86,26 @@ func (s *EnhancedMCPServer) updateProject(args []byte) (*ToolResponse, error) {
 	return SuccessResponse(project), nil
 }
 
-func (s *EnhancedMCPServer) deleteProject(args []byte) (*ToolResponse, error) {
+func (s *EnhancedMCPServer) deleteProject(ctx context.Context, args []byte) (*ToolResponse, error) {
 	var input struct {
 		ProjectID uint `json:"project_id"`
+		deletionArgs
 	}
 	if err := UnmarshalArgs(args, &input); err != nil {
 		return ErrorResponse(err), nil
 	}
 
-	if err := s.db.DeleteProject(input.ProjectID); err != nil {
-		return ErrorResponse(fmt.Errorf("failed to delete project: %w", err)), nil
-	}
-
-	return SuccessResponse(map[string]string{"status": "deleted"}), nil
+	target := service.DeletionTarget{Entity: "project", ID: input.ProjectID}
+	return s.runDeletion(target, input.deletionArgs, map[string]interface{}{"project_id": input.ProjectID}), nil
 }
 
-func (s *EnhancedMCPServer) listProjects(args []byte) (*ToolResponse, error) {
+func (s *EnhancedMCPServer) listProjects(ctx context.Context, args []byte) (*ToolResponse, error) {
 	var input struct {
 		Status string `json:"status"`
 	}
-	_ = UnmarshalArgs(args, &input)
+	if err := UnmarshalArgs(args, &input); err != nil {
+		return ErrorResponse(err), nil
+	}
 
 	var status *models.ProjectStatus
 	if input.Status != "" {
@@ -114,7 +122,7 @@ func (s *EnhancedMCPServer) listProjects(args []byte) (*ToolResponse, error) {
 }
 
 // Task CRUD operations
-func (s *EnhancedMCPServer) createTask(args []byte) (*ToolResponse, error) {
+func (s *EnhancedMCPServer) createTask(ctx context.Context, args []byte) (*ToolResponse, error) {
 	var input TaskInput
 	if err := UnmarshalArgs(args, &input); err != nil {
 		return ErrorResponse(err), nil
@@ -128,6 +136,13 @@ func (s *EnhancedMCPServer) createTask(args []byte) (*ToolResponse, error) {
 		Priority:    models.TaskPriorityMedium,
 	}
 
+	if input.Status != "" {
+		if !models.IsValidTaskStatus(input.Status) {
+			return ErrorResponse(fmt.Errorf("invalid status '%s'. Valid values: %v",
+				input.Status, models.GetValidTaskStatuses())), nil
+		}
+		task.Status = models.TaskStatus(input.Status)
+	}
 	if input.Priority != "" {
 		if !models.IsValidTaskPriority(input.Priority) {
 			return ErrorResponse(fmt.Errorf("invalid priority '%s'. Valid values: %v",
@@ -141,17 +156,35 @@ func (s *EnhancedMCPServer) createTask(args []byte) (*ToolResponse, error) {
 	if input.EpicID > 0 {
 		task.EpicID = &input.EpicID
 	}
-	if input.DueDate != "" {
-		dueDate, err := time.Parse("2006-01-02", input.DueDate)
-		if err == nil {
-			task.DueDate = &dueDate
-		}
+	dueDate, err := parseDateArg("due_date", input.DueDate)
+	if err != nil {
+		return ErrorResponse(err), nil
+	}
+	task.DueDate = dueDate
+
+	// The sprint and milestone are checked up front so that no task is left
+	// behind when they are invalid
+	if err := s.db.CheckTaskPlacement(input.ProjectID, input.SprintID, input.MilestoneID); err != nil {
+		return ErrorResponse(err), nil
 	}
 
 	if err := s.db.CreateTask(task); err != nil {
 		return ErrorResponse(err), nil
 	}
 
+	if input.SprintID > 0 {
+		if err := s.db.AddTaskToSprint(task.ID, input.SprintID); err != nil {
+			return ErrorResponse(err), nil
+		}
+		task.SprintID = &input.SprintID
+	}
+	if input.MilestoneID > 0 {
+		if err := s.db.AssignTaskToMilestone(task.ID, input.MilestoneID); err != nil {
+			return ErrorResponse(err), nil
+		}
+		task.MilestoneID = &input.MilestoneID
+	}
+
 	// Handle labels
 	if len(input.Labels) > 0 {
 		s.db.AssignLabelsToTask(task.ID, input.ProjectID, input.Labels)
@@ -160,7 +193,7 @@ func (s *EnhancedMCPServer) createTask(args []byte) (*ToolResponse, error) {
 	return SuccessResponse(task), nil
 }
 
-func (s *EnhancedMCPServer) getTask(args []byte) (*ToolResponse, error) {
+func (s *EnhancedMCPServer) getTask(ctx context.Context, args []byte) (*ToolResponse, error) {
 	var input struct {
 		TaskID uint `json:"task_id"`
 	}
@@ -176,14 +209,18 @@ func (s *EnhancedMCPServer) getTask(args []byte) (*ToolResponse, error) {
 	return SuccessResponse(task), nil
 }
 
-func (s *EnhancedMCPServer) updateTask(args []byte) (*ToolResponse, error) {
+func (s *EnhancedMCPServer) updateTask(ctx context.Context, args []byte) (*ToolResponse, error) {
 	var input struct {
-		ID          uint   `json:"task_id"`
-		Title       string `json:"title"`
-		Description string `json:"description"`
-		Status      string `json:"status"`
-		Priority    string `json:"priority"`
-		AssigneeID  uint   `json:"assignee_id"`
+		ID          uint     `json:"task_id"`
+		Title       string   `json:"title"`
+		Description string   `json:"description"`
+		Status      string   `json:"status"`
+		Priority    string   `json:"priority"`
+		AssigneeID  uint     `json:"assignee_id"`
+		EpicID      uint     `json:"epic_id"`
+		SprintID    uint     `json:"sprint_id"`
+		MilestoneID uint     `json:"milestone_id"`
+		Labels      []string `json:"labels"`
 	}
 	if err := UnmarshalArgs(args, &input); err != nil {
 		return ErrorResponse(err), nil
@@ -217,37 +254,63 @@ func (s *EnhancedMCPServer) updateTask(args []byte) (*ToolResponse, error) {
 	if input.AssigneeID > 0 {
 		task.AssigneeID = &input.AssigneeID
 	}
+	if input.EpicID > 0 {
+		task.EpicID = &input.EpicID
+	}
+	if input.SprintID > 0 && (task.SprintID == nil || *task.SprintID != input.SprintID) {
+		if err := s.db.AddTaskToSprint(task.ID, input.SprintID); err != nil {
+			return ErrorResponse(err), nil
+		}
+		task.SprintID = &input.SprintID
+	}
+	if input.MilestoneID > 0 && (task.MilestoneID == nil || *task.MilestoneID != input.MilestoneID) {
+		if err := s.db.AssignTaskToMilestone(task.ID, input.MilestoneID); err != nil {
+			return ErrorResponse(err), nil
+		}
+		task.MilestoneID = &input.MilestoneID
+	}
 
 	if err := s.db.UpdateTask(task); err != nil {
 		return ErrorResponse(err), nil
 	}
 
+	// Labels given replace the task's labels
+	if input.Labels != nil {
+		if err := s.db.AssignLabelsToTask(task.ID, task.ProjectID, input.Labels); err != nil {
+			return ErrorResponse(err), nil
+		}
+		if task, err = s.db.GetTask(task.ID); err != nil {
+			return ErrorResponse(err), nil
+		}
+	}
+
 	return SuccessResponse(task), nil
 }
 
-func (s *EnhancedMCPServer) deleteTask(args []byte) (*ToolResponse, error) {
+func (s *EnhancedMCPServer) deleteTask(ctx context.Context, args []byte) (*ToolResponse, error) {
 	var input struct {
 		TaskID uint `json:"task_id"`
+		deletionArgs
 	}
 	if err := UnmarshalArgs(args, &input); err != nil {
 		return ErrorResponse(err), nil
 	}
 
-	if err := s.db.DeleteTask(input.TaskID); err != nil {
-		return ErrorResponse(fmt.Errorf("failed to delete task: %w", err)), nil
-	}
-
-	return SuccessResponse(map[string]string{"status": "deleted"}), nil
+	target := service.DeletionTarget{Entity: "task", ID: input.TaskID}
+	return s.runDeletion(target, input.deletionArgs, map[string]interface{}{"task_id": input.TaskID}), nil
 }
 
-func (s *EnhancedMCPServer) listTasks(args []byte) (*ToolResponse, error) {
+func (s *EnhancedMCPServer) listTasks(ctx context.Context, args []byte) (*ToolResponse, error) {
 	var input struct {
 		ProjectID  uint   `json:"project_id"`
 		Status     string `json:"status"`
 		AssigneeID uint   `json:"assignee_id"`
 		EpicID     uint   `json:"epic_id"`
+		SprintID   uint   `json:"sprint_id"`
+	}
+	if err := UnmarshalArgs(args, &input); err != nil {
+		return ErrorResponse(err), nil
 	}
-	_ = UnmarshalArgs(args, &input)
 
 	query := s.db.DB
 	if input.ProjectID > 0 {
@@ -262,6 +325,9 @@ func (s *EnhancedMCPServer) listTasks(args []byte) (*ToolResponse, error) {
 	if input.EpicID > 0 {
 		query = query.Where("epic_id = ?", input.EpicID)
 	}
+	if input.SprintID > 0 {
+		query = query.Where("sprint_id = ?", input.SprintID)
+	}
 
 	var tasks []models.Task
 	if err := query.Preload("Labels").Find(&tasks).Error; err != nil {
@@ -272,7 +338,7 @@ func (s *EnhancedMCPServer) listTasks(args []byte) (*ToolResponse, error) {
 }
 
 // Epic CRUD operations
-func (s *EnhancedMCPServer) createEpic(args []byte) (*ToolResponse, error) {
+func (s *EnhancedMCPServer) createEpic(ctx context.Context, args []byte) (*ToolResponse, error) {
 	var input struct {
 		ProjectID   uint   `json:"project_id"`
 		Name        string `json:"name"`
@@ -296,7 +362,7 @@ func (s *EnhancedMCPServer) createEpic(args []byte) (*ToolResponse, error) {
 	return SuccessResponse(epic), nil
 }
 
-func (s *EnhancedMCPServer) getEpic(args []byte) (*ToolResponse, error) {
+func (s *EnhancedMCPServer) getEpic(ctx context.Context, args []byte) (*ToolResponse, error) {
 	var input struct {
 		EpicID uint `json:"epic_id"`
 	}
@@ -312,7 +378,7 @@ func (s *EnhancedMCPServer) getEpic(args []byte) (*ToolResponse, error) {
 	return SuccessResponse(epic), nil
 }
 
-func (s *EnhancedMCPServer) updateEpic(args []byte) (*ToolResponse, error) {
+func (s *EnhancedMCPServer) updateEpic(ctx context.Context, args []byte) (*ToolResponse, error) {
 	var input struct {
 		EpicID      uint   `json:"epic_id"`
 		Name        string `json:"name,omitempty"`
@@ -345,34 +411,32 @@ func (s *EnhancedMCPServer) updateEpic(args []byte) (*ToolResponse, error) {
 	return SuccessResponse(epic), nil
 }
 
-func (s *EnhancedMCPServer) deleteEpic(args []byte) (*ToolResponse, error) {
+func (s *EnhancedMCPServer) deleteEpic(ctx context.Context, args []byte) (*ToolResponse, error) {
 	var input struct {
 		EpicID       uint `json:"epic_id"`
 		CascadeTasks bool `json:"cascade_tasks,omitempty"`
+		deletionArgs
 	}
 	if err := UnmarshalArgs(args, &input); err != nil {
 		return ErrorResponse(err), nil
 	}
 
-	// Use the new DeleteEpicWithOptions function
-	if err := s.db.DeleteEpicWithOptions(input.EpicID, input.CascadeTasks); err != nil {
-		return ErrorResponse(fmt.Errorf("failed to delete epic: %w", err)), nil
-	}
-
+	target := service.DeletionTarget{Entity: "epic", ID: input.EpicID, Cascade: input.CascadeTasks}
 	result := map[string]interface{}{
-		"status": "deleted",
 		"epic_id": input.EpicID,
 		"cascade_tasks": input.CascadeTasks,
 	}
 
-	return SuccessResponse(result), nil
+	return s.runDeletion(target, input.deletionArgs, result), nil
 }
 
-func (s *EnhancedMCPServer) listEpics(args []byte) (*ToolResponse, error) {
+func (s *EnhancedMCPServer) listEpics(ctx context.Context, args []byte) (*ToolResponse, error) {
 	var input struct {
 		ProjectID uint `json:"project_id,omitempty"`
 	}
-	_ = UnmarshalArgs(args, &input)
+	if err := UnmarshalArgs(args, &input); err != nil {
+		return ErrorResponse(err), nil
+	}
 
 	var projectID *uint
 	if input.ProjectID > 0 {
@@ -388,7 +452,7 @@ func (s *EnhancedMCPServer) listEpics(args []byte) (*ToolResponse, error) {
 }
 
 // Label operations
-func (s *EnhancedMCPServer) createLabel(args []byte) (*ToolResponse, error) {
+func (s *EnhancedMCPServer) createLabel(ctx context.Context, args []byte) (*ToolResponse, error) {
 	var input struct {
 		ProjectID uint   `json:"project_id"`
 		Name      string `json:"name"`
@@ -421,7 +485,7 @@ func (s *EnhancedMCPServer) createLabel(args []byte) (*ToolResponse, error) {
 	return SuccessResponse(label), nil
 }
 
-func (s *EnhancedMCPServer) assignLabel(args []byte) (*ToolResponse, error) {
+func (s *EnhancedMCPServer) assignLabel(ctx context.Context, args []byte) (*ToolResponse, error) {
 	var input struct {
 		TaskID  uint `json:"task_id"`
 		LabelID uint `json:"label_id"`
@@ -441,7 +505,7 @@ func (s *EnhancedMCPServer) assignLabel(args []byte) (*ToolResponse, error) {
 	}), nil
 }
 
-func (s *EnhancedMCPServer) listLabels(args []byte) (*ToolResponse, error) {
+func (s *EnhancedMCPServer) listLabels(ctx context.Context, args []byte) (*ToolResponse, error) {
 	var input struct {
 		ProjectID uint `json:"project_id"`
 	}
@@ -457,7 +521,7 @@ func (s *EnhancedMCPServer) listLabels(args []byte) (*ToolResponse, error) {
 	return SuccessResponse(labels), nil
 }
 
-func (s *EnhancedMCPServer) updateLabel(args []byte) (*ToolResponse, error) {
+func (s *EnhancedMCPServer) updateLabel(ctx context.Context, args []byte) (*ToolResponse, error) {
 	var input struct {
 		LabelID uint   `json:"label_id"`
 		Name    string `json:"name"`
@@ -488,7 +552,7 @@ func (s *EnhancedMCPServer) updateLabel(args []byte) (*ToolResponse, error) {
 	return SuccessResponse(label), nil
 }
 
-func (s *EnhancedMCPServer) deleteLabel(args []byte) (*ToolResponse, error) {
+func (s *EnhancedMCPServer) deleteLabel(ctx context.Context, args []byte) (*ToolResponse, error) {
 	var input struct {
 		LabelID uint `json:"label_id"`
 	}
@@ -506,7 +570,7 @@ func (s *EnhancedMCPServer) deleteLabel(args []byte) (*ToolResponse, error) {
 }
 
 // Assignee operations
-func (s *EnhancedMCPServer) assignTask(args []byte) (*ToolResponse, error) {
+func (s *EnhancedMCPServer) assignTask(ctx context.Context, args []byte) (*ToolResponse, error) {
 	var input struct {
 		TaskID     uint `json:"task_id"`
 		AssigneeID uint `json:"assignee_id"`
@@ -528,7 +592,7 @@ func (s *EnhancedMCPServer) assignTask(args []byte) (*ToolResponse, error) {
 	return SuccessResponse(task), nil
 }
 
-func (s *EnhancedMCPServer) listAssignees(args []byte) (*ToolResponse, error) {
+func (s *EnhancedMCPServer) listAssignees(ctx context.Context, args []byte) (*ToolResponse, error) {
 	var input struct {
 		ProjectID uint `json:"project_id"`
 	}
@@ -549,7 +613,7 @@ func (s *EnhancedMCPServer) listAssignees(args []byte) (*ToolResponse, error) {
 }
 
 // User management operations
-func (s *EnhancedMCPServer) createUser(args []byte) (*ToolResponse, error) {
+func (s *EnhancedMCPServer) createUser(ctx context.Context, args []byte) (*ToolResponse, error) {
 	var input struct {
 		Username  string `json:"username"`
 		Email     string `json:"email"`
@@ -557,6 +621,8 @@ func (s *EnhancedMCPServer) createUser(args []byte) (*ToolResponse, error) {
 		FirstName string `json:"first_name"`
 		LastName  string `json:"last_name"`
 		Role      string `json:"role"`
+		Type      string `json:"type"`
+		Client    string `json:"client"`
 	}
 	if err := UnmarshalArgs(args, &input); err != nil {
 		return ErrorResponse(err), nil
@@ -588,8 +654,13 @@ func (s *EnhancedMCPServer) createUser(args []byte) (*ToolResponse, error) {
 		FirstName: input.FirstName,
 		LastName:  input.LastName,
 		Role:      role,
+		Type:      models.UserTypeHuman,
+		Client:    input.Client,
 		IsActive:  true,
 	}
+	if input.Type != "" {
+		user.Type = models.UserType(input.Type)
+	}
 
 	if err := s.db.CreateUser(user); err != nil {
 		return ErrorResponse(fmt.Errorf("failed to create user: %w", err)), nil
@@ -600,7 +671,7 @@ func (s *EnhancedMCPServer) createUser(args []byte) (*ToolResponse, error) {
 	return SuccessResponse(user), nil
 }
 
-func (s *EnhancedMCPServer) listUsers(args []byte) (*ToolResponse, error) {
+func (s *EnhancedMCPServer) listUsers(ctx context.Context, args []byte) (*ToolResponse, error) {
 	users, err := s.db.ListUsers()
 	if err != nil {
 		return ErrorResponse(fmt.Errorf("failed to list users: %w", err)), nil
@@ -614,7 +685,7 @@ func (s *EnhancedMCPServer) listUsers(args []byte) (*ToolResponse, error) {
 	return SuccessResponse(users), nil
 }
 
-func (s *EnhancedMCPServer) getUserByID(args []byte) (*ToolResponse, error) {
+func (s *EnhancedMCPServer) getUserByID(ctx context.Context, args []byte) (*ToolResponse, error) {
 	var input struct {
 		UserID uint `json:"user_id"`
 	}
@@ -632,15 +703,17 @@ func (s *EnhancedMCPServer) getUserByID(args []byte) (*ToolResponse, error) {
 	return SuccessResponse(user), nil
 }
 
-func (s *EnhancedMCPServer) updateUser(args []byte) (*ToolResponse, error) {
+func (s *EnhancedMCPServer) updateUser(ctx context.Context, args []byte) (*ToolResponse, error) {
 	var input struct {
-		UserID    uint   `json:"user_id"`
-		Username  string `json:"username"`
-		Email     string `json:"email"`
-		FirstName string `json:"first_name"`
-		LastName  string `json:"last_name"`
-		Role      string `json:"role"`
-		IsActive  *bool  `json:"is_active"`
+		UserID    uint    `json:"user_id"`
+		Username  string  `json:"username"`
+		Email     string  `json:"email"`
+		FirstName string  `json:"first_name"`
+		LastName  string  `json:"last_name"`
+		Role      string  `json:"role"`
+		IsActive  *bool   `json:"is_active"`
+		Type      string  `json:"type"`
+		Client    *string `json:"client"`
 	}
 	if err := UnmarshalArgs(args, &input); err != nil {
 		return ErrorResponse(err), nil
@@ -675,6 +748,12 @@ func (s *EnhancedMCPServer) updateUser(args []byte) (*ToolResponse, error) {
 	if input.IsActive != nil {
 		user.IsActive = *input.IsActive
 	}
+	if input.Type != "" {
+		user.Type = models.UserType(input.Type)
+	}
+	if input.Client != nil {
+		user.Client = *input.Client
+	}
 
 	if err := s.db.UpdateUser(user); err != nil {
 		return ErrorResponse(fmt.Errorf("failed to update user: %w", err)), nil
@@ -685,51 +764,59 @@ func (s *EnhancedMCPServer) updateUser(args []byte) (*ToolResponse, error) {
 	return SuccessResponse(user), nil
 }
 
-func (s *EnhancedMCPServer) deleteUser(args []byte) (*ToolResponse, error) {
+func (s *EnhancedMCPServer) deleteUser(ctx context.Context, args []byte) (*ToolResponse, error) {
 	var input struct {
 		UserID uint `json:"user_id"`
+		deletionArgs
 	}
 	if err := UnmarshalArgs(args, &input); err != nil {
 		return ErrorResponse(err), nil
 	}
 
-	if err := s.db.DeleteUser(input.UserID); err != nil {
-		return ErrorResponse(fmt.Errorf("failed to delete user: %w", err)), nil
-	}
-
-	return SuccessResponse(map[string]interface{}{
-		"message": fmt.Sprintf("User %d deleted successfully", input.UserID),
-	}), nil
+	target := service.DeletionTarget{Entity: "user", ID: input.UserID}
+	return s.runDeletion(target, input.deletionArgs, map[string]interface{}{"user_id": input.UserID}), nil
 }
 
 // Comment operations
-func (s *EnhancedMCPServer) addComment(args []byte) (*ToolResponse, error) {
+func (s *EnhancedMCPServer) addComment(ctx context.Context, args []byte) (*ToolResponse, error) {
 	var input struct {
-		TaskID  uint   `json:"task_id"`
-		Content string `json:"content"`
-		Author  string `json:"author"`
+		TaskID        uint   `json:"task_id"`
+		Content       string `json:"content"`
+		Author        string `json:"author"`
+		AuthorID      *uint  `json:"author_id"`
+		ParentID      *uint  `json:"parent_id"`
+		AttachmentIDs []uint `json:"attachment_ids"`
 	}
 	if err := UnmarshalArgs(args, &input); err != nil {
 		return ErrorResponse(err), nil
 	}
 
 	comment := &models.Comment{
-		TaskID:  input.TaskID,
-		Content: input.Content,
-		Author:  input.Author,
+		TaskID:   input.TaskID,
+		ParentID: input.ParentID,
+		Content:  input.Content,
+		Author:   input.Author,
+		AuthorID: input.AuthorID,
 	}
 
-	if err := s.db.AddComment(comment); err != nil {
+	if err := s.db.AddCommentWithAttachments(comment, input.AttachmentIDs); err != nil {
 		return ErrorResponse(err), nil
 	}
 
+	if len(input.AttachmentIDs) > 0 {
+		if detailed, err := s.db.GetCommentWithDetails(comment.ID); err == nil {
+			comment = detailed
+		}
+	}
+
 	return SuccessResponse(comment), nil
 }
 
-func (s *EnhancedMCPServer) updateComment(args []byte) (*ToolResponse, error) {
+func (s *EnhancedMCPServer) updateComment(ctx context.Context, args []byte) (*ToolResponse, error) {
 	var input struct {
 		CommentID uint   `json:"comment_id"`
 		Content   string `json:"content"`
+		EditorID  *uint  `json:"editor_id"`
 	}
 	if err := UnmarshalArgs(args, &input); err != nil {
 		return ErrorResponse(err), nil
@@ -740,36 +827,41 @@ func (s *EnhancedMCPServer) updateComment(args []byte) (*ToolResponse, error) {
 	if err != nil {
 		return ErrorResponse(fmt.Errorf("comment not found: %w", err)), nil
 	}
+	if err := requireOwnerOrAdmin(ctx, commentAuthor(comment), "this comment"); err != nil {
+		return ErrorResponse(err), nil
+	}
 
-	// Update the comment content
-	if err := s.db.UpdateComment(input.CommentID, input.Content); err != nil {
+	// Update the comment content, keeping the previous version in its history
+	if err := s.db.EditComment(input.CommentID, input.Content, input.EditorID); err != nil {
 		return ErrorResponse(err), nil
 	}
 
 	// Return the updated comment
-	comment.Content = input.Content
+	comment, err = s.db.GetCommentWithDetails(input.CommentID)
+	if err != nil {
+		return ErrorResponse(err), nil
+	}
 	return SuccessResponse(comment), nil
 }
 
-func (s *EnhancedMCPServer) listComments(args []byte) (*ToolResponse, error) {
+func (s *EnhancedMCPServer) listComments(ctx context.Context, args []byte) (*ToolResponse, error) {
 	var input struct {
-		TaskID uint `json:"task_id"`
+		TaskID   uint `json:"task_id"`
+		Threaded bool `json:"threaded"`
 	}
 	if err := UnmarshalArgs(args, &input); err != nil {
 		return ErrorResponse(err), nil
 	}
 
-	var comments []models.Comment
-	if err := s.db.Where("task_id = ?", input.TaskID).
-		Order("created_at DESC").
-		Find(&comments).Error; err != nil {
+	comments, err := s.db.ListComments(input.TaskID, input.Threaded)
+	if err != nil {
 		return ErrorResponse(err), nil
 	}
 
 	return SuccessResponse(comments), nil
 }
 
-func (s *EnhancedMCPServer) deleteComment(args []byte) (*ToolResponse, error) {
+func (s *EnhancedMCPServer) deleteComment(ctx context.Context, args []byte) (*ToolResponse, error) {
 	var input struct {
 		CommentID uint `json:"comment_id"`
 	}
@@ -782,6 +874,9 @@ func (s *EnhancedMCPServer) deleteComment(args []byte) (*ToolResponse, error) {
 	if err != nil {
 		return ErrorResponse(fmt.Errorf("comment not found: %w", err)), nil
 	}
+	if err := requireOwnerOrAdmin(ctx, commentAuthor(comment), "this comment"); err != nil {
+		return ErrorResponse(err), nil
+	}
 
 	// Delete the comment
 	if err := s.db.DeleteComment(input.CommentID); err != nil {
@@ -795,8 +890,16 @@ func (s *EnhancedMCPServer) deleteComment(args []byte) (*ToolResponse, error) {
 	}), nil
 }
 
+// commentAuthor returns the user who wrote a comment, or 0 for free-text authors
+func commentAuthor(comment *models.Comment) uint {
+	if comment.AuthorID == nil {
+		return 0
+	}
+	return *comment.AuthorID
+}
+
 // Task dependency operations
-func (s *EnhancedMCPServer) addTaskDependency(args []byte) (*ToolResponse, error) {
+func (s *EnhancedMCPServer) addTaskDependency(ctx context.Context, args []byte) (*ToolResponse, error) {
 	var input struct {
 		TaskID      uint   `json:"task_id"`
 		DependsOnID uint   `json:"depends_on_id"`
@@ -832,7 +935,7 @@ func (s *EnhancedMCPServer) addTaskDependency(args []byte) (*ToolResponse, error
 	return SuccessResponse(dep), nil
 }
 
-func (s *EnhancedMCPServer) removeTaskDependency(args []byte) (*ToolResponse, error) {
+func (s *EnhancedMCPServer) removeTaskDependency(ctx context.Context, args []byte) (*ToolResponse, error) {
 	var input struct {
 		DependencyID uint `json:"dependency_id"`
 	}
@@ -840,14 +943,14 @@ func (s *EnhancedMCPServer) removeTaskDependency(args []byte) (*ToolResponse, er
 		return ErrorResponse(err), nil
 	}
 
-	if err := s.db.Delete(&models.TaskDependency{}, input.DependencyID).Error; err != nil {
+	if err := s.db.RemoveTaskDependency(input.DependencyID); err != nil {
 		return ErrorResponse(err), nil
 	}
 
 	return SuccessResponse(map[string]string{"status": "removed"}), nil
 }
 
-func (s *EnhancedMCPServer) listTaskDependencies(args []byte) (*ToolResponse, error) {
+func (s *EnhancedMCPServer) listTaskDependencies(ctx context.Context, args []byte) (*ToolResponse, error) {
 	var input struct {
 		TaskID uint `json:"task_id"`
 	}
@@ -864,7 +967,7 @@ func (s *EnhancedMCPServer) listTaskDependencies(args []byte) (*ToolResponse, er
 }
 
 // Get the full dependency chain for a task (all transitive dependencies)
-func (s *EnhancedMCPServer) getTaskDependencyChain(args []byte) (*ToolResponse, error) {
+func (s *EnhancedMCPServer) getTaskDependencyChain(ctx context.Context, args []byte) (*ToolResponse, error) {
 	var input struct {
 		TaskID uint `json:"task_id"`
 	}
@@ -881,7 +984,7 @@ func (s *EnhancedMCPServer) getTaskDependencyChain(args []byte) (*ToolResponse,
 }
 
 // Get tasks that depend on this task
-func (s *EnhancedMCPServer) getTaskDependentChain(args []byte) (*ToolResponse, error) {
+func (s *EnhancedMCPServer) getTaskDependentChain(ctx context.Context, args []byte) (*ToolResponse, error) {
 	var input struct {
 		TaskID uint `json:"task_id"`
 	}
@@ -898,7 +1001,7 @@ func (s *EnhancedMCPServer) getTaskDependentChain(args []byte) (*ToolResponse, e
 }
 
 // Check if a task can start based on its dependencies
-func (s *EnhancedMCPServer) canStartTask(args []byte) (*ToolResponse, error) {
+func (s *EnhancedMCPServer) canStartTask(ctx context.Context, args []byte) (*ToolResponse, error) {
 	var input struct {
 		TaskID uint `json:"task_id"`
 	}
@@ -930,7 +1033,7 @@ func (s *EnhancedMCPServer) canStartTask(args []byte) (*ToolResponse, error) {
 }
 
 // Get the full dependency graph for a project
-func (s *EnhancedMCPServer) getProjectDependencyGraph(args []byte) (*ToolResponse, error) {
+func (s *EnhancedMCPServer) getProjectDependencyGraph(ctx context.Context, args []byte) (*ToolResponse, error) {
 	var input struct {
 		ProjectID uint `json:"project_id"`
 	}
@@ -0,0 +1,52 @@
+package mcp
+
+import "testing"
+
+func TestTaskTools(t *testing.T) {
+	_, handler := newTestServer(t)
+	mustCallTool(t, handler, nil, "create_project", `{"name":"Apollo"}`)
+	mustCallTool(t, handler, nil, "create_epic", `{"project_id":1,"name":"Launch"}`)
+
+	t.Run("create_task takes a status and due date", func(t *testing.T) {
+		task := structured(mustCallTool(t, handler, nil, "create_task",
+			`{"project_id":1,"title":"Fuel","status":"in_progress","due_date":"2030-01-02"}`))
+		if task["status"] != "in_progress" {
+			t.Errorf("status = %v, want in_progress", task["status"])
+		}
+		if due, _ := task["due_date"].(string); len(due) < 10 || due[:10] != "2030-01-02" {
+			t.Errorf("due_date = %v, want 2030-01-02", task["due_date"])
+		}
+		if res := callTool(t, handler, nil, "create_task", `{"project_id":1,"title":"Oops","due_date":"soon"}`); res["isError"] != true {
+			t.Errorf("accepted an invalid due date: %v", res)
+		}
+	})
+
+	t.Run("update_task sets the epic and labels", func(t *testing.T) {
+		task := structured(mustCallTool(t, handler, nil, "update_task", `{"task_id":1,"epic_id":1,"labels":["fuel","risk"]}`))
+		if task["epic_id"] != float64(1) {
+			t.Errorf("epic_id = %v, want 1", task["epic_id"])
+		}
+		labels, _ := task["labels"].([]interface{})
+		if len(labels) != 2 {
+			t.Errorf("labels = %v, want fuel and risk", task["labels"])
+		}
+	})
+
+	t.Run("tasks only go into open sprints of their project", func(t *testing.T) {
+		mustCallTool(t, handler, nil, "create_project", `{"name":"Gemini"}`)
+		mustCallTool(t, handler, nil, "create_sprint", `{"project_id":2,"name":"Foreign"}`)
+		mustCallTool(t, handler, nil, "create_sprint", `{"project_id":1,"name":"Liftoff"}`)
+
+		if res := callTool(t, handler, nil, "create_task", `{"project_id":1,"title":"Stray","sprint_id":1}`); res["isError"] != true {
+			t.Errorf("created a task in another project's sprint: %v", res)
+		}
+		if res := callTool(t, handler, nil, "update_task", `{"task_id":1,"sprint_id":1}`); res["isError"] != true {
+			t.Errorf("moved a task into another project's sprint: %v", res)
+		}
+
+		task := structured(mustCallTool(t, handler, nil, "create_task", `{"project_id":1,"title":"Countdown","sprint_id":2}`))
+		if task["sprint_id"] != float64(2) {
+			t.Errorf("sprint_id = %v, want 2", task["sprint_id"])
+		}
+	})
+}
@@ -1,14 +1,28 @@
 package mcp
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
+	"fmt"
 )
 
 // Tool represents an MCP tool definition
 type Tool struct {
-	Name        string                 `json:"name"`
-	Description string                 `json:"description"`
-	InputSchema map[string]interface{} `json:"inputSchema"`
+	Name         string                 `json:"name"`
+	Description  string                 `json:"description"`
+	InputSchema  map[string]interface{} `json:"inputSchema"`
+	OutputSchema map[string]interface{} `json:"outputSchema,omitempty"`
+	Annotations  *ToolAnnotations       `json:"annotations,omitempty"`
+}
+
+// ToolAnnotations are the hints clients use to decide how careful to be with
+// a tool, e.g. whether to ask before calling it
+type ToolAnnotations struct {
+	ReadOnlyHint    bool `json:"readOnlyHint"`
+	DestructiveHint bool `json:"destructiveHint"`
+	IdempotentHint  bool `json:"idempotentHint"`
+	OpenWorldHint   bool `json:"openWorldHint"`
 }
 
 // ToolCall represents a request to execute a tool
@@ -32,6 +46,14 @@ type Resource struct {
 	Metadata    map[string]interface{} `json:"metadata,omitempty"`
 }
 
+// ResourceTemplate describes a family of resources by an RFC 6570 URI template
+type ResourceTemplate struct {
+	URITemplate string `json:"uriTemplate"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	MimeType    string `json:"mimeType"`
+}
+
 // ResourceContent represents the content of a resource
 type ResourceContent struct {
 	URI      string      `json:"uri"`
@@ -39,6 +61,25 @@ type ResourceContent struct {
 	Content  interface{} `json:"content"`
 }
 
+// textContent converts the content to the text resource contents of
+// resources/read; content that is not text is sent as JSON
+func (c *ResourceContent) textContent() map[string]interface{} {
+	text, ok := c.Content.(string)
+	if !ok {
+		data, err := json.Marshal(c.Content)
+		if err != nil {
+			text = fmt.Sprintf("%v", c.Content)
+		} else {
+			text = string(data)
+		}
+	}
+	return map[string]interface{}{
+		"uri":      c.URI,
+		"mimeType": c.MimeType,
+		"text":     text,
+	}
+}
+
 // Common input structures for tools
 type ProjectInput struct {
 	Name        string `json:"name"`
@@ -50,9 +91,12 @@ type TaskInput struct {
 	ProjectID   uint     `json:"project_id"`
 	Title       string   `json:"title"`
 	Description string   `json:"description"`
+	Status      string   `json:"status"`
 	Priority    string   `json:"priority"`
 	AssigneeID  uint     `json:"assignee_id"`
 	EpicID      uint     `json:"epic_id"`
+	SprintID    uint     `json:"sprint_id"`
+	MilestoneID uint     `json:"milestone_id"`
 	DueDate     string   `json:"due_date"`
 	Labels      []string `json:"labels"`
 }
@@ -67,13 +111,24 @@ func SuccessResponse(content interface{}) *ToolResponse {
 
 // Helper function to create an error response
 func ErrorResponse(err error) *ToolResponse {
+	var argErr *ArgumentError
+	if errors.As(err, &argErr) {
+		return ArgumentErrorResponse(argErr)
+	}
 	return &ToolResponse{
 		Content: err.Error(),
 		IsError: true,
 	}
 }
 
-// Helper function to unmarshal arguments
+// Helper function to unmarshal arguments. Type mismatches are reported as an
+// ArgumentError naming the field.
 func UnmarshalArgs(args json.RawMessage, target interface{}) error {
-	return json.Unmarshal(args, target)
+	if len(bytes.TrimSpace(args)) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(args, target); err != nil {
+		return decodeArgumentError(err)
+	}
+	return nil
 }
\ No newline at end of file
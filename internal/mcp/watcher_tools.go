@@ -0,0 +1,118 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+)
+
+// Watcher operations act for the caller; admins can name another user
+func (s *EnhancedMCPServer) watchTask(ctx context.Context, args []byte) (*ToolResponse, error) {
+	var input struct {
+		TaskID uint `json:"task_id"`
+		UserID uint `json:"user_id"`
+	}
+	if err := UnmarshalArgs(args, &input); err != nil {
+		return ErrorResponse(err), nil
+	}
+	userID, err := actingUser(ctx, "watch_task", input.UserID)
+	if err != nil {
+		return ErrorResponse(err), nil
+	}
+
+	if err := s.db.AddWatcher(input.TaskID, userID); err != nil {
+		return ErrorResponse(err), nil
+	}
+
+	return SuccessResponse(map[string]string{
+		"message": fmt.Sprintf("User %d is now watching task %d", userID, input.TaskID),
+	}), nil
+}
+
+func (s *EnhancedMCPServer) unwatchTask(ctx context.Context, args []byte) (*ToolResponse, error) {
+	var input struct {
+		TaskID uint `json:"task_id"`
+		UserID uint `json:"user_id"`
+	}
+	if err := UnmarshalArgs(args, &input); err != nil {
+		return ErrorResponse(err), nil
+	}
+	userID, err := actingUser(ctx, "unwatch_task", input.UserID)
+	if err != nil {
+		return ErrorResponse(err), nil
+	}
+
+	if err := s.db.RemoveWatcher(input.TaskID, userID); err != nil {
+		return ErrorResponse(err), nil
+	}
+
+	return SuccessResponse(map[string]string{
+		"message": fmt.Sprintf("User %d stopped watching task %d", userID, input.TaskID),
+	}), nil
+}
+
+func (s *EnhancedMCPServer) listTaskWatchers(ctx context.Context, args []byte) (*ToolResponse, error) {
+	var input struct {
+		TaskID uint `json:"task_id"`
+	}
+	if err := UnmarshalArgs(args, &input); err != nil {
+		return ErrorResponse(err), nil
+	}
+
+	watchers, err := s.db.GetTaskWatchers(input.TaskID)
+	if err != nil {
+		return ErrorResponse(err), nil
+	}
+
+	return SuccessResponse(watchers), nil
+}
+
+func (s *EnhancedMCPServer) listWatchedTasks(ctx context.Context, args []byte) (*ToolResponse, error) {
+	var input struct {
+		UserID uint `json:"user_id"`
+	}
+	if err := UnmarshalArgs(args, &input); err != nil {
+		return ErrorResponse(err), nil
+	}
+
+	userID, err := actingUser(ctx, "list_watched_tasks", input.UserID)
+	if err != nil {
+		return ErrorResponse(err), nil
+	}
+
+	tasks, err := s.db.GetWatchedTasks(userID)
+	if err != nil {
+		return ErrorResponse(err), nil
+	}
+
+	return SuccessResponse(tasks), nil
+}
+
+// Mention operations
+func (s *EnhancedMCPServer) listMentions(ctx context.Context, args []byte) (*ToolResponse, error) {
+	var input struct {
+		UserID   uint   `json:"user_id"`
+		Username string `json:"username"`
+	}
+	if err := UnmarshalArgs(args, &input); err != nil {
+		return ErrorResponse(err), nil
+	}
+
+	if input.UserID == 0 && input.Username != "" {
+		user, err := s.db.GetUserByUsername(input.Username)
+		if err != nil {
+			return ErrorResponse(fmt.Errorf("user '%s' not found", input.Username)), nil
+		}
+		input.UserID = user.ID
+	}
+	userID, err := actingUser(ctx, "list_mentions", input.UserID)
+	if err != nil {
+		return ErrorResponse(err), nil
+	}
+
+	tasks, err := s.db.GetTasksMentioningUser(userID)
+	if err != nil {
+		return ErrorResponse(err), nil
+	}
+
+	return SuccessResponse(tasks), nil
+}
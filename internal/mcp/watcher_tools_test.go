@@ -0,0 +1,52 @@
+package mcp
+
+import "testing"
+
+func TestWatcherTools(t *testing.T) {
+	server, handler := newTestServer(t)
+	alice := map[string]string{"X-Test-User": "1", "X-Test-Scopes": "read,write"}
+	mustCallTool(t, handler, nil, "create_user", `{"username":"alice","email":"alice@example.com","password":"secret123"}`)
+	mustCallTool(t, handler, nil, "create_user", `{"username":"bob","email":"bob@example.com","password":"secret123"}`)
+	mustCallTool(t, handler, nil, "create_project", `{"name":"Watching"}`)
+	mustCallTool(t, handler, nil, "create_task", `{"project_id":1,"title":"Watched"}`)
+
+	watchers := func() []uint {
+		ids, _ := server.db.GetTaskWatcherIDs(1)
+		return ids
+	}
+
+	mustCallTool(t, handler, alice, "watch_task", `{"task_id":1}`)
+	if ids := watchers(); len(ids) != 1 || ids[0] != 1 {
+		t.Errorf("watchers are %v, want alice", ids)
+	}
+
+	if res := callTool(t, handler, alice, "watch_task", `{"task_id":1,"user_id":2}`); res["isError"] != true {
+		t.Errorf("alice subscribed bob: %v", res)
+	}
+	mustCallTool(t, handler, nil, "watch_task", `{"task_id":1,"user_id":2}`)
+	if res := callTool(t, handler, alice, "unwatch_task", `{"task_id":1,"user_id":2}`); res["isError"] != true {
+		t.Errorf("alice unsubscribed bob: %v", res)
+	}
+	if ids := watchers(); len(ids) != 2 {
+		t.Errorf("watchers are %v, want alice and bob", ids)
+	}
+
+	if res := callTool(t, handler, alice, "list_watched_tasks", `{"user_id":2}`); res["isError"] != true {
+		t.Errorf("alice listed bob's watched tasks: %v", res)
+	}
+	if watched := structured(mustCallTool(t, handler, alice, "list_watched_tasks", `{}`)); len(watched["items"].([]interface{})) != 1 {
+		t.Errorf("alice watches %v", watched)
+	}
+	mustCallTool(t, handler, nil, "list_watched_tasks", `{"user_id":2}`)
+
+	if res := callTool(t, handler, alice, "list_mentions", `{"username":"bob"}`); res["isError"] != true {
+		t.Errorf("alice listed bob's mentions: %v", res)
+	}
+	mustCallTool(t, handler, alice, "list_mentions", `{}`)
+	mustCallTool(t, handler, nil, "list_mentions", `{"user_id":2}`)
+
+	mustCallTool(t, handler, alice, "unwatch_task", `{"task_id":1}`)
+	if ids := watchers(); len(ids) != 1 || ids[0] != 2 {
+		t.Errorf("watchers are %v, want bob", ids)
+	}
+}
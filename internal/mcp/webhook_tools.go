@@ -0,0 +1,209 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/headless-pm/headless-project-management/internal/models"
+)
+
+// Webhook operations
+func (s *EnhancedMCPServer) createWebhook(ctx context.Context, args []byte) (*ToolResponse, error) {
+	var input struct {
+		Name      string   `json:"name"`
+		URL       string   `json:"url"`
+		ProjectID *uint    `json:"project_id"`
+		Events    []string `json:"events"`
+		Secret    string   `json:"secret"`
+	}
+	if err := UnmarshalArgs(args, &input); err != nil {
+		return ErrorResponse(err), nil
+	}
+
+	if input.ProjectID != nil {
+		if _, err := s.db.GetProject(*input.ProjectID); err != nil {
+			return ErrorResponse(fmt.Errorf("project not found: %w", err)), nil
+		}
+	}
+
+	webhook := &models.Webhook{
+		Name:      input.Name,
+		URL:       input.URL,
+		ProjectID: input.ProjectID,
+		Events:    strings.Join(input.Events, ","),
+		Secret:    input.Secret,
+		Active:    true,
+	}
+	if err := s.db.CreateWebhook(webhook); err != nil {
+		return ErrorResponse(err), nil
+	}
+
+	// The secret is only returned here; deliveries are signed with it
+	return SuccessResponse(map[string]interface{}{
+		"webhook": webhook,
+		"secret":  webhook.Secret,
+	}), nil
+}
+
+func (s *EnhancedMCPServer) listWebhooks(ctx context.Context, args []byte) (*ToolResponse, error) {
+	var input struct {
+		ProjectID *uint `json:"project_id"`
+	}
+	if err := UnmarshalArgs(args, &input); err != nil {
+		return ErrorResponse(err), nil
+	}
+
+	webhooks, err := s.db.ListWebhooks(input.ProjectID)
+	if err != nil {
+		return ErrorResponse(err), nil
+	}
+
+	return SuccessResponse(map[string]interface{}{
+		"webhooks":         webhooks,
+		"available_events": models.GetWebhookEvents(),
+	}), nil
+}
+
+func (s *EnhancedMCPServer) updateWebhook(ctx context.Context, args []byte) (*ToolResponse, error) {
+	var input struct {
+		WebhookID uint      `json:"webhook_id"`
+		Name      string    `json:"name"`
+		URL       string    `json:"url"`
+		Events    *[]string `json:"events"`
+		Secret    string    `json:"secret"`
+		Active    *bool     `json:"active"`
+	}
+	if err := UnmarshalArgs(args, &input); err != nil {
+		return ErrorResponse(err), nil
+	}
+
+	webhook, err := s.db.GetWebhook(input.WebhookID)
+	if err != nil {
+		return ErrorResponse(fmt.Errorf("webhook not found: %w", err)), nil
+	}
+
+	if input.Name != "" {
+		webhook.Name = input.Name
+	}
+	if input.URL != "" {
+		webhook.URL = input.URL
+	}
+	if input.Events != nil {
+		webhook.Events = strings.Join(*input.Events, ",")
+	}
+	if input.Secret != "" {
+		webhook.Secret = input.Secret
+	}
+	if input.Active != nil {
+		webhook.Active = *input.Active
+	}
+
+	if err := s.db.UpdateWebhook(webhook); err != nil {
+		return ErrorResponse(err), nil
+	}
+
+	return SuccessResponse(webhook), nil
+}
+
+func (s *EnhancedMCPServer) deleteWebhook(ctx context.Context, args []byte) (*ToolResponse, error) {
+	var input struct {
+		WebhookID uint `json:"webhook_id"`
+	}
+	if err := UnmarshalArgs(args, &input); err != nil {
+		return ErrorResponse(err), nil
+	}
+
+	if _, err := s.db.GetWebhook(input.WebhookID); err != nil {
+		return ErrorResponse(fmt.Errorf("webhook not found: %w", err)), nil
+	}
+
+	if err := s.db.DeleteWebhook(input.WebhookID); err != nil {
+		return ErrorResponse(err), nil
+	}
+
+	return SuccessResponse(map[string]string{
+		"message": fmt.Sprintf("Webhook %d deleted successfully", input.WebhookID),
+	}), nil
+}
+
+func (s *EnhancedMCPServer) pingWebhook(ctx context.Context, args []byte) (*ToolResponse, error) {
+	var input struct {
+		WebhookID uint `json:"webhook_id"`
+	}
+	if err := UnmarshalArgs(args, &input); err != nil {
+		return ErrorResponse(err), nil
+	}
+
+	delivery, err := s.db.PingWebhook(input.WebhookID)
+	if err != nil {
+		return ErrorResponse(fmt.Errorf("webhook not found: %w", err)), nil
+	}
+
+	return s.deliverWebhookNow(delivery)
+}
+
+func (s *EnhancedMCPServer) listWebhookDeliveries(ctx context.Context, args []byte) (*ToolResponse, error) {
+	var input struct {
+		WebhookID uint   `json:"webhook_id"`
+		Status    string `json:"status"`
+		Limit     int    `json:"limit"`
+	}
+	if err := UnmarshalArgs(args, &input); err != nil {
+		return ErrorResponse(err), nil
+	}
+
+	var status *models.WebhookDeliveryStatus
+	if input.Status != "" {
+		s := models.WebhookDeliveryStatus(input.Status)
+		status = &s
+	}
+	if input.Limit <= 0 {
+		input.Limit = 50
+	}
+
+	deliveries, err := s.db.ListWebhookDeliveries(input.WebhookID, status, input.Limit)
+	if err != nil {
+		return ErrorResponse(err), nil
+	}
+
+	return SuccessResponse(deliveries), nil
+}
+
+func (s *EnhancedMCPServer) redeliverWebhook(ctx context.Context, args []byte) (*ToolResponse, error) {
+	var input struct {
+		DeliveryID uint `json:"delivery_id"`
+	}
+	if err := UnmarshalArgs(args, &input); err != nil {
+		return ErrorResponse(err), nil
+	}
+
+	delivery, err := s.db.RedeliverWebhookDelivery(input.DeliveryID)
+	if err != nil {
+		return ErrorResponse(fmt.Errorf("delivery not found: %w", err)), nil
+	}
+
+	return s.deliverWebhookNow(delivery)
+}
+
+// deliverWebhookNow sends a queued delivery right away when the server has a dispatcher
+func (s *EnhancedMCPServer) deliverWebhookNow(delivery *models.WebhookDelivery) (*ToolResponse, error) {
+	if s.webhookDispatcher == nil {
+		return SuccessResponse(delivery), nil
+	}
+
+	sent, err := s.webhookDispatcher.Deliver(delivery.ID)
+	if err != nil {
+		return ErrorResponse(err), nil
+	}
+	return SuccessResponse(sent), nil
+}
+
+// webhookEventNames lists the subscribable events for the tool schemas
+func webhookEventNames() []string {
+	var names []string
+	for _, event := range models.GetWebhookEvents() {
+		names = append(names, string(event))
+	}
+	return names
+}
@@ -0,0 +1,236 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/headless-pm/headless-project-management/internal/database"
+	"github.com/headless-pm/headless-project-management/internal/models"
+)
+
+// Worklog operations record the time of the caller
+func (s *EnhancedMCPServer) logWork(ctx context.Context, args []byte) (*ToolResponse, error) {
+	var input struct {
+		TaskID          uint   `json:"task_id"`
+		DurationMinutes int    `json:"duration_minutes"`
+		StartedAt       string `json:"started_at"`
+		Note            string `json:"note"`
+	}
+	if err := UnmarshalArgs(args, &input); err != nil {
+		return ErrorResponse(err), nil
+	}
+	userID, err := requireUser(ctx, "log_work")
+	if err != nil {
+		return ErrorResponse(err), nil
+	}
+
+	if input.DurationMinutes <= 0 {
+		return ErrorResponse(fmt.Errorf("duration_minutes must be greater than 0")), nil
+	}
+
+	worklog := &models.Worklog{
+		TaskID:          input.TaskID,
+		UserID:          userID,
+		DurationMinutes: input.DurationMinutes,
+		Note:            input.Note,
+	}
+
+	if input.StartedAt != "" {
+		startedAt, err := time.Parse(time.RFC3339, input.StartedAt)
+		if err != nil {
+			return ErrorResponse(fmt.Errorf("invalid started_at '%s': expected RFC3339", input.StartedAt)), nil
+		}
+		worklog.StartedAt = startedAt
+	} else {
+		// Logged after the fact: the work ended now
+		worklog.StartedAt = time.Now().Add(-time.Duration(input.DurationMinutes) * time.Minute)
+	}
+
+	if err := s.db.CreateWorklog(worklog); err != nil {
+		return ErrorResponse(fmt.Errorf("failed to log work: %w", err)), nil
+	}
+
+	return SuccessResponse(worklog), nil
+}
+
+func (s *EnhancedMCPServer) listWorklogs(ctx context.Context, args []byte) (*ToolResponse, error) {
+	var input struct {
+		TaskID *uint `json:"task_id"`
+		UserID *uint `json:"user_id"`
+	}
+	if err := UnmarshalArgs(args, &input); err != nil {
+		return ErrorResponse(err), nil
+	}
+
+	userID, err := worklogUser(ctx, "list_worklogs", input.UserID)
+	if err != nil {
+		return ErrorResponse(err), nil
+	}
+
+	worklogs, err := s.db.ListWorklogs(input.TaskID, userID)
+	if err != nil {
+		return ErrorResponse(err), nil
+	}
+
+	return SuccessResponse(worklogs), nil
+}
+
+// worklogUser resolves the user whose worklogs a tool reads: the one named, or
+// the caller. Only admins read other users' worklogs, and everyone's when they
+// name no user, for which nil is returned.
+func worklogUser(ctx context.Context, tool string, requested *uint) (*uint, error) {
+	if requested == nil && isAdminCaller(ctx) {
+		return nil, nil
+	}
+	var id uint
+	if requested != nil {
+		id = *requested
+	}
+	userID, err := actingUser(ctx, tool, id)
+	if err != nil {
+		return nil, err
+	}
+	return &userID, nil
+}
+
+func (s *EnhancedMCPServer) deleteWorklog(ctx context.Context, args []byte) (*ToolResponse, error) {
+	var input struct {
+		WorklogID uint `json:"worklog_id"`
+	}
+	if err := UnmarshalArgs(args, &input); err != nil {
+		return ErrorResponse(err), nil
+	}
+
+	worklog, err := s.db.GetWorklog(input.WorklogID)
+	if err != nil {
+		return ErrorResponse(fmt.Errorf("worklog not found: %w", err)), nil
+	}
+	if err := requireOwnerOrAdmin(ctx, worklog.UserID, "a worklog"); err != nil {
+		return ErrorResponse(err), nil
+	}
+
+	if err := s.db.DeleteWorklog(input.WorklogID); err != nil {
+		return ErrorResponse(err), nil
+	}
+
+	return SuccessResponse(map[string]string{
+		"message": fmt.Sprintf("Worklog %d deleted successfully", input.WorklogID),
+	}), nil
+}
+
+// Timer operations
+func (s *EnhancedMCPServer) startTimer(ctx context.Context, args []byte) (*ToolResponse, error) {
+	var input struct {
+		TaskID uint   `json:"task_id"`
+		Note   string `json:"note"`
+	}
+	if err := UnmarshalArgs(args, &input); err != nil {
+		return ErrorResponse(err), nil
+	}
+	userID, err := requireUser(ctx, "start_timer")
+	if err != nil {
+		return ErrorResponse(err), nil
+	}
+
+	worklog, err := s.db.StartTimer(input.TaskID, userID, input.Note)
+	if err != nil {
+		return ErrorResponse(err), nil
+	}
+
+	return SuccessResponse(worklog), nil
+}
+
+func (s *EnhancedMCPServer) stopTimer(ctx context.Context, args []byte) (*ToolResponse, error) {
+	var input struct {
+		Note string `json:"note"`
+	}
+	if err := UnmarshalArgs(args, &input); err != nil {
+		return ErrorResponse(err), nil
+	}
+	userID, err := requireUser(ctx, "stop_timer")
+	if err != nil {
+		return ErrorResponse(err), nil
+	}
+
+	worklog, err := s.db.StopTimer(userID, input.Note)
+	if err != nil {
+		return ErrorResponse(err), nil
+	}
+
+	return SuccessResponse(worklog), nil
+}
+
+func (s *EnhancedMCPServer) getRunningTimer(ctx context.Context, args []byte) (*ToolResponse, error) {
+	userID, err := requireUser(ctx, "get_running_timer")
+	if err != nil {
+		return ErrorResponse(err), nil
+	}
+
+	worklog, err := s.db.GetRunningTimer(userID)
+	if err != nil {
+		return SuccessResponse(map[string]interface{}{
+			"running": false,
+		}), nil
+	}
+
+	return SuccessResponse(map[string]interface{}{
+		"running":         true,
+		"worklog":         worklog,
+		"elapsed_minutes": int(time.Since(worklog.StartedAt).Minutes()),
+	}), nil
+}
+
+// Timesheet reports
+func (s *EnhancedMCPServer) getTimesheet(ctx context.Context, args []byte) (*ToolResponse, error) {
+	var input struct {
+		UserID    *uint  `json:"user_id"`
+		ProjectID *uint  `json:"project_id"`
+		From      string `json:"from"`
+		To        string `json:"to"`
+		Format    string `json:"format"`
+	}
+	if err := UnmarshalArgs(args, &input); err != nil {
+		return ErrorResponse(err), nil
+	}
+
+	from, err := parseDateArg("from", input.From)
+	if err != nil {
+		return ErrorResponse(err), nil
+	}
+	to, err := parseDateArg("to", input.To)
+	if err != nil {
+		return ErrorResponse(err), nil
+	}
+	if to != nil {
+		// The end date is inclusive
+		end := to.AddDate(0, 0, 1)
+		to = &end
+	}
+
+	userID, err := worklogUser(ctx, "get_timesheet", input.UserID)
+	if err != nil {
+		return ErrorResponse(err), nil
+	}
+
+	timesheet, err := s.db.GetTimesheet(database.TimesheetFilter{
+		UserID:    userID,
+		ProjectID: input.ProjectID,
+		From:      from,
+		To:        to,
+	})
+	if err != nil {
+		return ErrorResponse(err), nil
+	}
+
+	if input.Format == "csv" {
+		var buf bytes.Buffer
+		if err := timesheet.WriteCSV(&buf); err != nil {
+			return ErrorResponse(err), nil
+		}
+		return SuccessResponse(buf.String()), nil
+	}
+
+	return SuccessResponse(timesheet), nil
+}
@@ -0,0 +1,66 @@
+package mcp
+
+import "testing"
+
+func TestWorklogTools(t *testing.T) {
+	_, handler := newTestServer(t)
+	alice := map[string]string{"X-Test-User": "1", "X-Test-Scopes": "read,write"}
+	bob := map[string]string{"X-Test-User": "2", "X-Test-Scopes": "read,write"}
+	mustCallTool(t, handler, nil, "create_user", `{"username":"alice","email":"alice@example.com","password":"secret123"}`)
+	mustCallTool(t, handler, nil, "create_user", `{"username":"bob","email":"bob@example.com","password":"secret123"}`)
+	mustCallTool(t, handler, nil, "create_project", `{"name":"Billing"}`)
+	mustCallTool(t, handler, nil, "create_task", `{"project_id":1,"title":"Invoice"}`)
+
+	t.Run("time is logged for the caller", func(t *testing.T) {
+		worklog := structured(mustCallTool(t, handler, alice, "log_work", `{"task_id":1,"duration_minutes":30}`))
+		if worklog["user_id"] != float64(1) {
+			t.Errorf("worklog is for user %v, want alice", worklog["user_id"])
+		}
+		if res := callTool(t, handler, bob, "log_work", `{"task_id":1,"user_id":1,"duration_minutes":30}`); res["isError"] != true {
+			t.Errorf("bob logged time naming alice: %v", res)
+		}
+	})
+
+	t.Run("timers belong to the caller", func(t *testing.T) {
+		mustCallTool(t, handler, alice, "start_timer", `{"task_id":1}`)
+		if running := structured(mustCallTool(t, handler, bob, "get_running_timer", `{}`)); running["running"] != false {
+			t.Errorf("bob sees alice's timer: %v", running)
+		}
+		if res := callTool(t, handler, bob, "stop_timer", `{}`); res["isError"] != true {
+			t.Errorf("bob stopped a timer he never started: %v", res)
+		}
+		if running := structured(mustCallTool(t, handler, alice, "get_running_timer", `{}`)); running["running"] != true {
+			t.Errorf("alice's timer is not running: %v", running)
+		}
+		mustCallTool(t, handler, alice, "stop_timer", `{}`)
+	})
+
+	t.Run("worklogs and timesheets are read by their owner and admins", func(t *testing.T) {
+		if worklogs := structured(mustCallTool(t, handler, bob, "list_worklogs", `{"task_id":1}`)); len(worklogs["items"].([]interface{})) != 0 {
+			t.Errorf("bob lists alice's worklogs: %v", worklogs)
+		}
+		if res := callTool(t, handler, bob, "list_worklogs", `{"user_id":1}`); res["isError"] != true {
+			t.Errorf("bob listed alice's worklogs by her ID: %v", res)
+		}
+		if res := callTool(t, handler, bob, "get_timesheet", `{"user_id":1}`); res["isError"] != true {
+			t.Errorf("bob got alice's timesheet: %v", res)
+		}
+		if sheet := structured(mustCallTool(t, handler, bob, "get_timesheet", `{"project_id":1}`)); sheet["total_hours"] != float64(0) {
+			t.Errorf("bob's project timesheet includes alice's time: %v", sheet)
+		}
+		if sheet := structured(mustCallTool(t, handler, alice, "get_timesheet", `{}`)); sheet["total_hours"] == float64(0) {
+			t.Errorf("alice's timesheet is empty: %v", sheet)
+		}
+		if worklogs := structured(mustCallTool(t, handler, nil, "list_worklogs", `{}`)); len(worklogs["items"].([]interface{})) != 2 {
+			t.Errorf("admin lists %v", worklogs)
+		}
+	})
+
+	t.Run("only owners and admins delete worklogs", func(t *testing.T) {
+		if res := callTool(t, handler, bob, "delete_worklog", `{"worklog_id":1}`); res["isError"] != true {
+			t.Errorf("bob deleted alice's worklog: %v", res)
+		}
+		mustCallTool(t, handler, alice, "delete_worklog", `{"worklog_id":1}`)
+		mustCallTool(t, handler, nil, "delete_worklog", `{"worklog_id":2}`)
+	})
+}
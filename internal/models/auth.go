@@ -13,6 +13,8 @@ type User struct {
 	LastName     string    `json:"last_name"`
 	Avatar       string    `json:"avatar"`
 	Role         UserRole  `json:"role" gorm:"default:'member'"`
+	Type         UserType  `json:"type" gorm:"default:'human'"`
+	Client       string    `json:"client,omitempty"` // Model or MCP client an agent runs as
 	IsActive     bool      `json:"is_active" gorm:"default:true"`
 	LastLogin    time.Time `json:"last_login"`
 	CreatedAt    time.Time `json:"created_at"`
@@ -29,6 +31,19 @@ const (
 	UserRoleViewer  UserRole = "viewer"
 )
 
+// UserType tells people apart from agents and service accounts acting through API tokens
+type UserType string
+
+const (
+	UserTypeHuman UserType = "human"
+	UserTypeAgent UserType = "agent"
+)
+
+// IsAgent reports whether the user is an agent or service account
+func (u *User) IsAgent() bool {
+	return u.Type == UserTypeAgent
+}
+
 type Session struct {
 	ID        uint      `json:"id" gorm:"primaryKey"`
 	UserID    uint      `json:"user_id" gorm:"not null"`
@@ -0,0 +1,49 @@
+package models
+
+import "time"
+
+// CommentReaction is an emoji reaction left by a user on a comment
+type CommentReaction struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	CommentID uint      `json:"comment_id" gorm:"not null;uniqueIndex:idx_comment_reaction"`
+	UserID    uint      `json:"user_id" gorm:"not null;uniqueIndex:idx_comment_reaction"`
+	Emoji     string    `json:"emoji" gorm:"not null;uniqueIndex:idx_comment_reaction"`
+	CreatedAt time.Time `json:"created_at"`
+	User      *User     `json:"user,omitempty" gorm:"foreignKey:UserID"`
+}
+
+// CommentRevision keeps the content a comment had before an edit
+type CommentRevision struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	CommentID  uint      `json:"comment_id" gorm:"not null;index"`
+	Content    string    `json:"content" gorm:"not null"`
+	EditedByID *uint     `json:"edited_by_id,omitempty"`
+	EditedBy   string    `json:"edited_by"`
+	CreatedAt  time.Time `json:"created_at"` // When the content was replaced
+}
+
+// ReactionSummary counts the users that reacted to a comment with one emoji
+type ReactionSummary struct {
+	Emoji string   `json:"emoji"`
+	Count int      `json:"count"`
+	Users []string `json:"users"`
+}
+
+// SummarizeReactions groups reactions by emoji in order of first use
+func SummarizeReactions(reactions []CommentReaction) []ReactionSummary {
+	var summaries []ReactionSummary
+	index := make(map[string]int)
+	for _, reaction := range reactions {
+		i, ok := index[reaction.Emoji]
+		if !ok {
+			i = len(summaries)
+			index[reaction.Emoji] = i
+			summaries = append(summaries, ReactionSummary{Emoji: reaction.Emoji})
+		}
+		summaries[i].Count++
+		if reaction.User != nil {
+			summaries[i].Users = append(summaries[i].Users, reaction.User.Username)
+		}
+	}
+	return summaries
+}
@@ -0,0 +1,43 @@
+package models
+
+import (
+	"regexp"
+	"time"
+)
+
+// MentionPattern matches an @username mention; the username is the first submatch.
+// Usernames may contain dots and dashes but not end with them, so "@alice." mentions alice.
+var MentionPattern = regexp.MustCompile(`(?:^|[^\w@.])@([A-Za-z0-9_](?:[A-Za-z0-9_.-]*[A-Za-z0-9_])?)`)
+
+// ExtractMentions returns the distinct usernames mentioned in a text, in order of appearance
+func ExtractMentions(text string) []string {
+	var usernames []string
+	seen := make(map[string]bool)
+	for _, match := range MentionPattern.FindAllStringSubmatch(text, -1) {
+		if !seen[match[1]] {
+			seen[match[1]] = true
+			usernames = append(usernames, match[1])
+		}
+	}
+	return usernames
+}
+
+type MentionSource string
+
+const (
+	MentionSourceComment     MentionSource = "comment"
+	MentionSourceDescription MentionSource = "description"
+)
+
+// Mention links a user to the task description or comment that mentions them
+type Mention struct {
+	ID        uint          `json:"id" gorm:"primaryKey"`
+	UserID    uint          `json:"user_id" gorm:"not null;index"`
+	TaskID    uint          `json:"task_id" gorm:"not null;index"`
+	CommentID *uint         `json:"comment_id,omitempty" gorm:"index"`
+	Source    MentionSource `json:"source" gorm:"not null"`
+	CreatedAt time.Time     `json:"created_at"`
+	User      *User         `json:"user,omitempty" gorm:"foreignKey:UserID"`
+	Task      *Task         `json:"task,omitempty" gorm:"foreignKey:TaskID"`
+	Comment   *Comment      `json:"comment,omitempty" gorm:"foreignKey:CommentID"`
+}
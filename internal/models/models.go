@@ -92,6 +92,24 @@ const (
 	MilestoneStatusCancelled MilestoneStatus = "cancelled"
 )
 
+// IsValidSprintStatus checks if the given sprint status is valid
+func IsValidSprintStatus(status string) bool {
+	switch SprintStatus(status) {
+	case SprintStatusPlanned, SprintStatusActive, SprintStatusCompleted:
+		return true
+	}
+	return false
+}
+
+// IsValidMilestoneStatus checks if the given milestone status is valid
+func IsValidMilestoneStatus(status string) bool {
+	switch MilestoneStatus(status) {
+	case MilestoneStatusPlanned, MilestoneStatusActive, MilestoneStatusCompleted, MilestoneStatusCancelled:
+		return true
+	}
+	return false
+}
+
 type EpicStatus string
 
 const (
@@ -123,6 +141,8 @@ type Project struct {
 	DeletedAt   *time.Time    `json:"deleted_at" gorm:"index"`
 	Tasks       []Task        `json:"tasks,omitempty" gorm:"foreignKey:ProjectID"`
 	Epics       []Epic        `json:"epics,omitempty" gorm:"foreignKey:ProjectID"`
+	Sprints     []Sprint      `json:"sprints,omitempty" gorm:"foreignKey:ProjectID"`
+	Milestones  []Milestone   `json:"milestones,omitempty" gorm:"foreignKey:ProjectID"`
 	Owner       *User         `json:"owner,omitempty" gorm:"foreignKey:OwnerID"`
 	// Team removed - simplified model
 	Members     []User        `json:"members,omitempty" gorm:"many2many:project_members;"`
@@ -133,7 +153,8 @@ type Task struct {
 	ProjectID       uint         `json:"project_id" gorm:"not null"`
 	ParentID        *uint        `json:"parent_id,omitempty"`
 	EpicID          *uint        `json:"epic_id,omitempty"`
-	// MilestoneID and SprintID removed - simplified model
+	SprintID        *uint        `json:"sprint_id,omitempty"`
+	MilestoneID     *uint        `json:"milestone_id,omitempty"`
 	Title           string       `json:"title" gorm:"not null"`
 	Description     string       `json:"description"`
 	Status          TaskStatus   `json:"status" gorm:"default:'todo'"`
@@ -154,7 +175,8 @@ type Task struct {
 	Project         *Project     `json:"project,omitempty" gorm:"foreignKey:ProjectID"`
 	Parent          *Task        `json:"parent,omitempty" gorm:"foreignKey:ParentID"`
 	Epic            *Epic        `json:"epic,omitempty" gorm:"foreignKey:EpicID"`
-	// Milestone and Sprint removed - simplified model
+	Sprint          *Sprint      `json:"sprint,omitempty" gorm:"foreignKey:SprintID"`
+	Milestone       *Milestone   `json:"milestone,omitempty" gorm:"foreignKey:MilestoneID"`
 	AssigneeUser    *User        `json:"assignee_user,omitempty" gorm:"foreignKey:AssigneeID"`
 	Creator         *User        `json:"creator,omitempty" gorm:"foreignKey:CreatedBy"`
 	Updater         *User        `json:"updater,omitempty" gorm:"foreignKey:UpdatedBy"`
@@ -165,6 +187,7 @@ type Task struct {
 	Watchers        []User       `json:"watchers,omitempty" gorm:"many2many:task_watchers;"`
 	Dependencies    []TaskDependency `json:"dependencies,omitempty" gorm:"foreignKey:TaskID"`
 	Activities      []Activity   `json:"activities,omitempty" gorm:"foreignKey:TaskID"`
+	Worklogs        []Worklog    `json:"worklogs,omitempty" gorm:"foreignKey:TaskID"`
 }
 
 // TaskDependency represents a dependency between tasks
@@ -178,12 +201,20 @@ type TaskDependency struct {
 }
 
 type Comment struct {
-	ID        uint      `json:"id" gorm:"primaryKey"`
-	TaskID    uint      `json:"task_id" gorm:"not null"`
-	Content   string    `json:"content" gorm:"not null"`
-	Author    string    `json:"author" gorm:"not null"`
-	CreatedAt time.Time `json:"created_at"`
-	Task      *Task     `json:"task,omitempty" gorm:"foreignKey:TaskID"`
+	ID          uint              `json:"id" gorm:"primaryKey"`
+	TaskID      uint              `json:"task_id" gorm:"not null"`
+	ParentID    *uint             `json:"parent_id,omitempty" gorm:"index"` // Comment this one replies to
+	Content     string            `json:"content" gorm:"not null"`
+	Author      string            `json:"author" gorm:"not null"` // Username of the author, kept if the user is deleted
+	AuthorID    *uint             `json:"author_id,omitempty" gorm:"index"`
+	CreatedAt   time.Time         `json:"created_at"`
+	EditedAt    *time.Time        `json:"edited_at,omitempty"`
+	Task        *Task             `json:"task,omitempty" gorm:"foreignKey:TaskID"`
+	AuthorUser  *User             `json:"author_user,omitempty" gorm:"foreignKey:AuthorID"`
+	Replies     []Comment         `json:"replies,omitempty" gorm:"foreignKey:ParentID"`
+	Reactions   []CommentReaction `json:"reactions,omitempty" gorm:"foreignKey:CommentID"`
+	Revisions   []CommentRevision `json:"revisions,omitempty" gorm:"foreignKey:CommentID"`
+	Attachments []Attachment      `json:"attachments,omitempty" gorm:"foreignKey:CommentID"`
 }
 
 // Activity represents an audit log entry for task changes
@@ -197,6 +228,7 @@ type Activity struct {
 	OldValue    string    `json:"old_value"`    // Previous value
 	NewValue    string    `json:"new_value"`    // New value
 	Description string    `json:"description"`  // Human-readable description
+	Client      string    `json:"client,omitempty"` // MCP client or model of the agent that made the change
 	CreatedAt   time.Time `json:"created_at"`
 	Task        *Task     `json:"task,omitempty" gorm:"foreignKey:TaskID"`
 	User        *User     `json:"user,omitempty" gorm:"foreignKey:UserID"`
@@ -205,6 +237,7 @@ type Activity struct {
 type Attachment struct {
 	ID        uint      `json:"id" gorm:"primaryKey"`
 	TaskID    uint      `json:"task_id" gorm:"not null"`
+	CommentID *uint     `json:"comment_id,omitempty" gorm:"index"`
 	Filename  string    `json:"filename" gorm:"not null"`
 	Path      string    `json:"path" gorm:"not null"`
 	Size      int64     `json:"size"`
@@ -235,4 +268,64 @@ type Epic struct {
 	DeletedAt   *time.Time `json:"deleted_at" gorm:"index"`
 	Project     *Project   `json:"project,omitempty" gorm:"foreignKey:ProjectID"`
 	Tasks       []Task     `json:"tasks,omitempty" gorm:"foreignKey:EpicID"`
-}
\ No newline at end of file
+}
+
+// Sprint represents a time-boxed iteration within a project
+type Sprint struct {
+	ID          uint         `json:"id" gorm:"primaryKey"`
+	ProjectID   uint         `json:"project_id" gorm:"not null"`
+	Name        string       `json:"name" gorm:"not null"`
+	Goal        string       `json:"goal"`
+	Status      SprintStatus `json:"status" gorm:"default:'planned'"`
+	StartDate   *time.Time   `json:"start_date"`
+	EndDate     *time.Time   `json:"end_date"`
+	CompletedAt *time.Time   `json:"completed_at,omitempty"`
+	CreatedAt   time.Time    `json:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at"`
+	DeletedAt   *time.Time   `json:"deleted_at" gorm:"index"`
+	Project     *Project     `json:"project,omitempty" gorm:"foreignKey:ProjectID"`
+	Tasks       []Task       `json:"tasks,omitempty" gorm:"foreignKey:SprintID"`
+}
+
+// Milestone represents a project checkpoint that groups tasks by a target date
+type Milestone struct {
+	ID          uint            `json:"id" gorm:"primaryKey"`
+	ProjectID   uint            `json:"project_id" gorm:"not null"`
+	Name        string          `json:"name" gorm:"not null"`
+	Description string          `json:"description"`
+	Status      MilestoneStatus `json:"status" gorm:"default:'planned'"`
+	DueDate     *time.Time      `json:"due_date"`
+	CompletedAt *time.Time      `json:"completed_at,omitempty"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+	DeletedAt   *time.Time      `json:"deleted_at" gorm:"index"`
+	Project     *Project        `json:"project,omitempty" gorm:"foreignKey:ProjectID"`
+	Tasks       []Task          `json:"tasks,omitempty" gorm:"foreignKey:MilestoneID"`
+}
+
+// Worklog records time a user spent on a task. A worklog without EndedAt is a running timer.
+type Worklog struct {
+	ID              uint       `json:"id" gorm:"primaryKey"`
+	TaskID          uint       `json:"task_id" gorm:"not null;index"`
+	UserID          uint       `json:"user_id" gorm:"not null;index"`
+	StartedAt       time.Time  `json:"started_at" gorm:"not null"`
+	EndedAt         *time.Time `json:"ended_at,omitempty"`
+	DurationMinutes int        `json:"duration_minutes"`
+	Note            string     `json:"note"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+	Task            *Task      `json:"task,omitempty" gorm:"foreignKey:TaskID"`
+	User            *User      `json:"user,omitempty" gorm:"foreignKey:UserID"`
+}
+// TaskLease marks a task as claimed by a user, usually an agent, until
+// ExpiresAt. Heartbeats extend the lease; an expired lease returns the task to the pool.
+type TaskLease struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	TaskID      uint      `json:"task_id" gorm:"not null;uniqueIndex"`
+	UserID      uint      `json:"user_id" gorm:"not null;index"`
+	ClaimedAt   time.Time `json:"claimed_at" gorm:"not null"`
+	HeartbeatAt time.Time `json:"heartbeat_at" gorm:"not null"`
+	ExpiresAt   time.Time `json:"expires_at" gorm:"not null;index"`
+	Task        *Task     `json:"task,omitempty" gorm:"foreignKey:TaskID"`
+	User        *User     `json:"user,omitempty" gorm:"foreignKey:UserID"`
+}
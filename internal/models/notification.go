@@ -0,0 +1,61 @@
+package models
+
+import (
+	"time"
+)
+
+type NotificationType string
+
+const (
+	NotificationTypeAssigned            NotificationType = "assigned"
+	NotificationTypeMentioned           NotificationType = "mentioned"
+	NotificationTypeStatusChanged       NotificationType = "status_changed"
+	NotificationTypeDueSoon             NotificationType = "due_soon"
+	NotificationTypeDependencyUnblocked NotificationType = "dependency_unblocked"
+)
+
+// GetNotificationTypes returns all notification types a user can receive
+func GetNotificationTypes() []NotificationType {
+	return []NotificationType{
+		NotificationTypeAssigned,
+		NotificationTypeMentioned,
+		NotificationTypeStatusChanged,
+		NotificationTypeDueSoon,
+		NotificationTypeDependencyUnblocked,
+	}
+}
+
+// IsValidNotificationType checks if a notification type is valid
+func IsValidNotificationType(notificationType string) bool {
+	for _, t := range GetNotificationTypes() {
+		if string(t) == notificationType {
+			return true
+		}
+	}
+	return false
+}
+
+// Notification is an entry in a user's in-app inbox
+type Notification struct {
+	ID        uint             `json:"id" gorm:"primaryKey"`
+	UserID    uint             `json:"user_id" gorm:"not null;index"`
+	Type      NotificationType `json:"type" gorm:"not null;index"`
+	TaskID    *uint            `json:"task_id,omitempty" gorm:"index"`
+	ProjectID *uint            `json:"project_id,omitempty"`
+	ActorName string           `json:"actor_name"` // Who triggered the notification
+	Title     string           `json:"title" gorm:"not null"`
+	Message   string           `json:"message"`
+	ReadAt    *time.Time       `json:"read_at,omitempty"`
+	CreatedAt time.Time        `json:"created_at"`
+	User      *User            `json:"user,omitempty" gorm:"foreignKey:UserID"`
+	Task      *Task            `json:"task,omitempty" gorm:"foreignKey:TaskID"`
+}
+
+// NotificationPreference turns a notification type on or off for a user.
+// Types without a preference row are enabled.
+type NotificationPreference struct {
+	ID      uint             `json:"id" gorm:"primaryKey"`
+	UserID  uint             `json:"user_id" gorm:"not null;uniqueIndex:idx_notification_pref"`
+	Type    NotificationType `json:"type" gorm:"not null;uniqueIndex:idx_notification_pref"`
+	Enabled bool             `json:"enabled"`
+}
@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+)
+
+// PromptArgument describes an argument of an MCP prompt
+type PromptArgument struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+}
+
+// PromptTemplate is a team-defined MCP prompt. Template is a Go text/template
+// rendered with the prompt arguments and live project data. Built-in prompt
+// names cannot be reused.
+type PromptTemplate struct {
+	ID          uint             `json:"id" gorm:"primaryKey"`
+	Name        string           `json:"name" gorm:"not null;uniqueIndex"`
+	Description string           `json:"description"`
+	Arguments   []PromptArgument `json:"arguments" gorm:"type:text;serializer:json"`
+	Template    string           `json:"template" gorm:"type:text;not null"`
+	CreatedBy   *uint            `json:"created_by,omitempty"`
+	CreatedAt   time.Time        `json:"created_at"`
+	UpdatedAt   time.Time        `json:"updated_at"`
+}
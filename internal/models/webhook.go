@@ -0,0 +1,140 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+type WebhookEvent string
+
+const (
+	WebhookEventPing              WebhookEvent = "ping"
+	WebhookEventProjectCreated    WebhookEvent = "project.created"
+	WebhookEventProjectUpdated    WebhookEvent = "project.updated"
+	WebhookEventProjectDeleted    WebhookEvent = "project.deleted"
+	WebhookEventTaskCreated       WebhookEvent = "task.created"
+	WebhookEventTaskUpdated       WebhookEvent = "task.updated"
+	WebhookEventTaskStatusChanged WebhookEvent = "task.status_changed"
+	WebhookEventTaskAssigned      WebhookEvent = "task.assigned"
+	WebhookEventTaskDeleted       WebhookEvent = "task.deleted"
+	WebhookEventCommentAdded      WebhookEvent = "comment.added"
+	WebhookEventCommentUpdated    WebhookEvent = "comment.updated"
+	WebhookEventCommentDeleted    WebhookEvent = "comment.deleted"
+	WebhookEventDependencyAdded   WebhookEvent = "dependency.added"
+	WebhookEventDependencyRemoved WebhookEvent = "dependency.removed"
+	WebhookEventEpicCreated       WebhookEvent = "epic.created"
+	WebhookEventEpicUpdated       WebhookEvent = "epic.updated"
+	WebhookEventEpicCompleted     WebhookEvent = "epic.completed"
+	WebhookEventSprintStarted     WebhookEvent = "sprint.started"
+	WebhookEventSprintCompleted   WebhookEvent = "sprint.completed"
+)
+
+// GetWebhookEvents returns the events webhooks can subscribe to
+func GetWebhookEvents() []WebhookEvent {
+	return []WebhookEvent{
+		WebhookEventProjectCreated,
+		WebhookEventProjectUpdated,
+		WebhookEventProjectDeleted,
+		WebhookEventTaskCreated,
+		WebhookEventTaskUpdated,
+		WebhookEventTaskStatusChanged,
+		WebhookEventTaskAssigned,
+		WebhookEventTaskDeleted,
+		WebhookEventCommentAdded,
+		WebhookEventCommentUpdated,
+		WebhookEventCommentDeleted,
+		WebhookEventDependencyAdded,
+		WebhookEventDependencyRemoved,
+		WebhookEventEpicCreated,
+		WebhookEventEpicUpdated,
+		WebhookEventEpicCompleted,
+		WebhookEventSprintStarted,
+		WebhookEventSprintCompleted,
+	}
+}
+
+func IsValidWebhookEvent(event string) bool {
+	for _, e := range GetWebhookEvents() {
+		if string(e) == event {
+			return true
+		}
+	}
+	return false
+}
+
+// Webhook is a subscription that receives project events over HTTP. Webhooks
+// without a project receive the events of every project.
+type Webhook struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	ProjectID *uint     `json:"project_id,omitempty" gorm:"index"`
+	Name      string    `json:"name" gorm:"not null"`
+	URL       string    `json:"url" gorm:"not null"`
+	Secret    string    `json:"-" gorm:"not null"` // HMAC key used to sign deliveries
+	Events    string    `json:"events"`            // Comma-separated events, empty for all events
+	Active    bool      `json:"active" gorm:"default:true"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Project   *Project  `json:"project,omitempty" gorm:"foreignKey:ProjectID"`
+}
+
+// EventList returns the events the webhook subscribes to; nil means all events
+func (w *Webhook) EventList() []string {
+	var events []string
+	for _, event := range strings.Split(w.Events, ",") {
+		if event = strings.TrimSpace(event); event != "" {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+// Subscribes reports whether the webhook receives an event. Pings are always delivered.
+func (w *Webhook) Subscribes(event WebhookEvent) bool {
+	events := w.EventList()
+	if len(events) == 0 || event == WebhookEventPing {
+		return true
+	}
+	for _, e := range events {
+		if e == string(event) || e == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending    WebhookDeliveryStatus = "pending"
+	WebhookDeliveryDelivering WebhookDeliveryStatus = "delivering"
+	WebhookDeliverySucceeded  WebhookDeliveryStatus = "succeeded"
+	WebhookDeliveryFailed     WebhookDeliveryStatus = "failed"
+)
+
+// WebhookDelivery is an outbox entry and the log of its delivery attempts
+type WebhookDelivery struct {
+	ID             uint                  `json:"id" gorm:"primaryKey"`
+	WebhookID      uint                  `json:"webhook_id" gorm:"not null;index"`
+	Event          WebhookEvent          `json:"event" gorm:"not null"`
+	Payload        string                `json:"payload" gorm:"type:text;not null"`
+	Status         WebhookDeliveryStatus `json:"status" gorm:"not null;index;default:'pending'"`
+	Attempts       int                   `json:"attempts"`
+	NextAttemptAt  *time.Time            `json:"next_attempt_at,omitempty" gorm:"index"`
+	LastAttemptAt  *time.Time            `json:"last_attempt_at,omitempty"`
+	ResponseStatus int                   `json:"response_status,omitempty"`
+	ResponseBody   string                `json:"response_body,omitempty"`
+	Error          string                `json:"error,omitempty"`
+	DurationMs     int64                 `json:"duration_ms,omitempty"`
+	RedeliveryOf   *uint                 `json:"redelivery_of,omitempty"` // Delivery this one repeats
+	CreatedAt      time.Time             `json:"created_at"`
+	UpdatedAt      time.Time             `json:"updated_at"`
+	Webhook        *Webhook              `json:"webhook,omitempty" gorm:"foreignKey:WebhookID"`
+}
+
+// WebhookPayload is the JSON body posted to webhook URLs
+type WebhookPayload struct {
+	Event     WebhookEvent `json:"event"`
+	ProjectID *uint        `json:"project_id,omitempty"`
+	Timestamp time.Time    `json:"timestamp"`
+	Data      interface{}  `json:"data"`
+}
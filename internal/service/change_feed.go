@@ -0,0 +1,317 @@
+package service
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/headless-pm/headless-project-management/internal/events"
+	"github.com/headless-pm/headless-project-management/internal/models"
+)
+
+const (
+	// changeFeedBufferSize is the number of recent changes kept for clients resuming with Last-Event-ID
+	changeFeedBufferSize = 1000
+	// changeSubscriberBuffer bounds the changes queued for one client; slower clients are disconnected
+	changeSubscriberBuffer = 64
+)
+
+// ChangeEvent is one entry of the real-time change feed
+type ChangeEvent struct {
+	ID         uint64               `json:"id"`
+	Type       string               `json:"type"`        // Domain event name, e.g. "task.updated"
+	EntityType string               `json:"entity_type"` // task, comment, epic or dependency
+	EntityID   uint                 `json:"entity_id"`
+	ProjectID  uint                 `json:"project_id"`
+	TaskIDs    []uint               `json:"task_ids,omitempty"` // Tasks the change touches
+	EpicID     *uint                `json:"epic_id,omitempty"`
+	Changes    []events.FieldChange `json:"changes,omitempty"`
+	Actor      *events.Actor        `json:"actor,omitempty"`
+	Data       interface{}          `json:"data,omitempty"`
+	Timestamp  time.Time            `json:"timestamp"`
+}
+
+// Signal returns the change without its field changes, actor and data. It tells
+// a client what changed so it can reload, without the content of the change.
+func (e ChangeEvent) Signal() ChangeEvent {
+	e.Changes = nil
+	e.Actor = nil
+	e.Data = nil
+	return e
+}
+
+// ChangeFilter selects the changes a client receives; zero values match everything
+type ChangeFilter struct {
+	ProjectID   uint
+	EntityTypes []string
+	TaskID      uint
+	EpicID      uint
+}
+
+// ParseEntityTypes splits a comma-separated list of entity types
+func ParseEntityTypes(value string) []string {
+	var types []string
+	for _, t := range strings.Split(value, ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			types = append(types, t)
+		}
+	}
+	return types
+}
+
+func (f ChangeFilter) Matches(event *ChangeEvent) bool {
+	if f.ProjectID != 0 && event.ProjectID != f.ProjectID {
+		return false
+	}
+	if len(f.EntityTypes) > 0 {
+		found := false
+		for _, t := range f.EntityTypes {
+			if t == event.EntityType {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if f.TaskID != 0 {
+		found := false
+		for _, id := range event.TaskIDs {
+			if id == f.TaskID {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if f.EpicID != 0 {
+		if event.EpicID == nil || *event.EpicID != f.EpicID {
+			return false
+		}
+	}
+	return true
+}
+
+// ChangeFeed fans task, comment, epic and dependency changes out to streaming
+// clients. Recent changes are kept in a ring buffer so a client that reconnects
+// with the ID of the last change it saw receives what it missed.
+type ChangeFeed struct {
+	mu          sync.Mutex
+	buffer      []ChangeEvent
+	start       int
+	count       int
+	nextID      uint64
+	subscribers map[*ChangeSubscription]struct{}
+}
+
+// ChangeSubscription is a client's live view of the feed
+type ChangeSubscription struct {
+	feed   *ChangeFeed
+	filter ChangeFilter
+	ch     chan ChangeEvent
+	closed bool
+}
+
+func NewChangeFeed(bufferSize int) *ChangeFeed {
+	return &ChangeFeed{
+		buffer: make([]ChangeEvent, bufferSize),
+		// IDs start at the current time so that they keep increasing across
+		// restarts and stale Last-Event-IDs are recognised
+		nextID:      uint64(time.Now().UnixMicro()),
+		subscribers: make(map[*ChangeSubscription]struct{}),
+	}
+}
+
+// Register subscribes the feed to the domain events it streams
+func (f *ChangeFeed) Register(bus *events.Bus) {
+	bus.Subscribe(func(event events.Event) {
+		if change := changeEventFor(event); change != nil {
+			f.Publish(change)
+		}
+	}, events.NameTaskCreated, events.NameTaskUpdated, events.NameTaskDeleted,
+		events.NameCommentAdded, events.NameCommentUpdated, events.NameCommentDeleted,
+		events.NameEpicCreated, events.NameEpicUpdated, events.NameEpicDeleted,
+		events.NameDependencyAdded, events.NameDependencyRemoved)
+}
+
+// Publish assigns the change an ID, buffers it and sends it to matching subscribers.
+// Subscribers that cannot keep up are closed and have to resume from their last ID.
+func (f *ChangeFeed) Publish(change *ChangeEvent) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	change.ID = f.nextID
+	f.nextID++
+	if change.Timestamp.IsZero() {
+		change.Timestamp = time.Now().UTC()
+	}
+
+	if len(f.buffer) > 0 {
+		f.buffer[(f.start+f.count)%len(f.buffer)] = *change
+		if f.count < len(f.buffer) {
+			f.count++
+		} else {
+			f.start = (f.start + 1) % len(f.buffer)
+		}
+	}
+
+	for sub := range f.subscribers {
+		if !sub.filter.Matches(change) {
+			continue
+		}
+		select {
+		case sub.ch <- *change:
+		default:
+			f.closeLocked(sub)
+		}
+	}
+}
+
+// Subscribe starts following the feed. With a lastEventID the buffered changes
+// after it are returned for replay; complete is false when some of them were
+// already dropped from the buffer, or the ID is unknown, and the client should
+// reload its state instead.
+func (f *ChangeFeed) Subscribe(filter ChangeFilter, lastEventID uint64) (sub *ChangeSubscription, replay []ChangeEvent, complete bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	sub = &ChangeSubscription{
+		feed:   f,
+		filter: filter,
+		ch:     make(chan ChangeEvent, changeSubscriberBuffer),
+	}
+	f.subscribers[sub] = struct{}{}
+
+	if lastEventID == 0 {
+		return sub, nil, true
+	}
+
+	oldest := f.nextID
+	if f.count > 0 {
+		oldest = f.buffer[f.start].ID
+	}
+	complete = lastEventID+1 >= oldest && lastEventID < f.nextID
+	for i := 0; i < f.count; i++ {
+		change := f.buffer[(f.start+i)%len(f.buffer)]
+		if change.ID > lastEventID && filter.Matches(&change) {
+			replay = append(replay, change)
+		}
+	}
+	return sub, replay, complete
+}
+
+// Events delivers the subscription's changes; it is closed when the subscription ends
+func (s *ChangeSubscription) Events() <-chan ChangeEvent {
+	return s.ch
+}
+
+func (s *ChangeSubscription) Close() {
+	s.feed.mu.Lock()
+	defer s.feed.mu.Unlock()
+	s.feed.closeLocked(s)
+}
+
+func (f *ChangeFeed) closeLocked(sub *ChangeSubscription) {
+	if sub.closed {
+		return
+	}
+	sub.closed = true
+	delete(f.subscribers, sub)
+	close(sub.ch)
+}
+
+// changeEventFor converts a domain event into a change feed entry
+func changeEventFor(event events.Event) *ChangeEvent {
+	change := &ChangeEvent{Type: event.Name()}
+
+	switch e := event.(type) {
+	case events.TaskCreated:
+		setTaskChange(change, e.Task)
+		change.Actor = &e.Actor
+	case events.TaskUpdated:
+		setTaskChange(change, e.Task)
+		change.Changes = e.Diff
+		change.Actor = &e.Actor
+	case events.TaskDeleted:
+		setTaskChange(change, &e.Task)
+	case events.CommentAdded:
+		setCommentChange(change, e.Comment, e.ProjectID)
+		change.Actor = &e.Actor
+	case events.CommentUpdated:
+		setCommentChange(change, e.Comment, e.ProjectID)
+		change.Actor = &e.Actor
+	case events.CommentDeleted:
+		setCommentChange(change, e.Comment, e.ProjectID)
+	case events.EpicCreated:
+		setEpicChange(change, e.Epic)
+	case events.EpicUpdated:
+		setEpicChange(change, e.Epic)
+	case events.EpicDeleted:
+		change.EntityType = "epic"
+		change.EntityID = e.EpicID
+		change.ProjectID = e.ProjectID
+		change.EpicID = &e.EpicID
+	case events.DependencyAdded:
+		setDependencyChange(change, e.Dependency, e.ProjectID)
+	case events.DependencyRemoved:
+		setDependencyChange(change, e.Dependency, e.ProjectID)
+	default:
+		return nil
+	}
+	return change
+}
+
+func setTaskChange(change *ChangeEvent, task *models.Task) {
+	change.EntityType = "task"
+	change.EntityID = task.ID
+	change.ProjectID = task.ProjectID
+	change.TaskIDs = []uint{task.ID}
+	change.EpicID = task.EpicID
+	snapshot := *task
+	change.Data = &snapshot
+}
+
+func setCommentChange(change *ChangeEvent, comment *models.Comment, projectID uint) {
+	change.EntityType = "comment"
+	change.EntityID = comment.ID
+	change.ProjectID = projectID
+	change.TaskIDs = []uint{comment.TaskID}
+	snapshot := *comment
+	change.Data = &snapshot
+}
+
+func setEpicChange(change *ChangeEvent, epic *models.Epic) {
+	change.EntityType = "epic"
+	change.EntityID = epic.ID
+	change.ProjectID = epic.ProjectID
+	change.EpicID = &epic.ID
+	snapshot := *epic
+	change.Data = &snapshot
+}
+
+func setDependencyChange(change *ChangeEvent, dependency models.TaskDependency, projectID uint) {
+	change.EntityType = "dependency"
+	change.EntityID = dependency.ID
+	change.ProjectID = projectID
+	change.TaskIDs = []uint{dependency.TaskID, dependency.DependsOnID}
+	change.Data = dependency
+}
+
+// Global instance for the application
+var globalChangeFeed *ChangeFeed
+
+// InitializeChangeFeed creates the application's change feed and subscribes it to the bus
+func InitializeChangeFeed(bus *events.Bus) *ChangeFeed {
+	if globalChangeFeed == nil {
+		globalChangeFeed = NewChangeFeed(changeFeedBufferSize)
+		globalChangeFeed.Register(bus)
+	}
+	return globalChangeFeed
+}
+
+func GetChangeFeed() *ChangeFeed {
+	return globalChangeFeed
+}
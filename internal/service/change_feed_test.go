@@ -0,0 +1,104 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/headless-pm/headless-project-management/internal/events"
+	"github.com/headless-pm/headless-project-management/internal/models"
+)
+
+func TestChangeFeedResume(t *testing.T) {
+	feed := NewChangeFeed(3)
+	publish := func(projectID uint) *ChangeEvent {
+		change := &ChangeEvent{Type: events.NameTaskUpdated, EntityType: "task", ProjectID: projectID}
+		feed.Publish(change)
+		return change
+	}
+
+	first := publish(1)
+	publish(2)
+	third := publish(1)
+
+	t.Run("replays the changes after the last ID", func(t *testing.T) {
+		sub, replay, complete := feed.Subscribe(ChangeFilter{ProjectID: 1}, first.ID)
+		defer sub.Close()
+		if !complete {
+			t.Error("resume within the buffer reported missed changes")
+		}
+		if len(replay) != 1 || replay[0].ID != third.ID {
+			t.Errorf("replay = %+v, want only change %d", replay, third.ID)
+		}
+	})
+
+	t.Run("a fresh client gets no replay", func(t *testing.T) {
+		sub, replay, complete := feed.Subscribe(ChangeFilter{}, 0)
+		defer sub.Close()
+		if !complete || len(replay) != 0 {
+			t.Errorf("replay = %v complete = %v, want none and complete", replay, complete)
+		}
+	})
+
+	t.Run("changes dropped from the buffer ask for a reload", func(t *testing.T) {
+		// The buffer keeps the last three changes, so second is dropped
+		publish(2)
+		publish(2)
+		sub, replay, complete := feed.Subscribe(ChangeFilter{}, first.ID)
+		defer sub.Close()
+		if complete {
+			t.Error("resume past the buffer reported no missed changes")
+		}
+		if len(replay) != 3 || replay[0].ID != third.ID {
+			t.Errorf("replay = %+v, want the three buffered changes", replay)
+		}
+
+		unknown, _, complete := feed.Subscribe(ChangeFilter{}, third.ID+100)
+		defer unknown.Close()
+		if complete {
+			t.Error("an unknown last ID reported no missed changes")
+		}
+	})
+
+	t.Run("live changes follow the filter", func(t *testing.T) {
+		sub, _, _ := feed.Subscribe(ChangeFilter{ProjectID: 1}, 0)
+		defer sub.Close()
+		publish(2)
+		live := publish(1)
+		if change := <-sub.Events(); change.ID != live.ID {
+			t.Errorf("received change %d, want %d", change.ID, live.ID)
+		}
+	})
+
+	t.Run("slow subscribers are disconnected", func(t *testing.T) {
+		sub, _, _ := feed.Subscribe(ChangeFilter{}, 0)
+		for i := 0; i <= changeSubscriberBuffer; i++ {
+			publish(1)
+		}
+		received := 0
+		for range sub.Events() {
+			received++
+		}
+		if received != changeSubscriberBuffer {
+			t.Errorf("received %d changes before disconnect, want %d", received, changeSubscriberBuffer)
+		}
+	})
+}
+
+func TestChangeEventSignal(t *testing.T) {
+	task := &models.Task{ID: 7, ProjectID: 1, Title: "Secret plans"}
+	change := changeEventFor(events.TaskUpdated{
+		Task:  task,
+		Diff:  []events.FieldChange{{Field: "title"}},
+		Actor: events.Actor{Name: "alice"},
+	})
+
+	signal := change.Signal()
+	if signal.Data != nil || signal.Changes != nil || signal.Actor != nil {
+		t.Errorf("signal = %+v, want no content", signal)
+	}
+	if signal.EntityID != 7 || signal.ProjectID != 1 || signal.Type != events.NameTaskUpdated {
+		t.Errorf("signal = %+v, want what changed", signal)
+	}
+	if change.Data == nil {
+		t.Error("Signal modified the change")
+	}
+}
@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/headless-pm/headless-project-management/internal/database"
+)
+
+// confirmationTTL is how long the confirmation token of a dry run stays valid
+const confirmationTTL = 5 * time.Minute
+
+var (
+	// ErrConfirmationRequired is returned for deletions without a confirmation token
+	ErrConfirmationRequired = errors.New("confirmation required: run the deletion as a dry run first and pass its confirmation token")
+	// ErrInvalidConfirmation is returned for tokens that are malformed, expired or issued for another deletion
+	ErrInvalidConfirmation = errors.New("invalid or expired confirmation token")
+	// ErrDeletionChanged is returned when the data to delete changed after the dry run
+	ErrDeletionChanged = errors.New("the deletion impact changed since the dry run; review a new dry run")
+	// ErrUnknownDeletion is returned for targets that are not a project, task, epic or user
+	ErrUnknownDeletion = errors.New("unknown deletion target")
+)
+
+// confirmationKey signs confirmation tokens. It is generated per process, so
+// tokens issued over REST and MCP are interchangeable but do not survive a restart.
+var confirmationKey = func() []byte {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		panic(fmt.Sprintf("deletion: generating confirmation key: %v", err))
+	}
+	return key
+}()
+
+// DeletionTarget names what to delete. Cascade only applies to epics, whose
+// tasks are deleted with them instead of being unlinked.
+type DeletionTarget struct {
+	Entity  string // project, task, epic or user
+	ID      uint
+	Cascade bool
+}
+
+// DeletionPlan is the result of a dry run: what the deletion would do and the
+// token that confirms it
+type DeletionPlan struct {
+	Impact            *database.DeletionImpact `json:"impact"`
+	ConfirmationToken string                   `json:"confirmation_token"`
+	ExpiresAt         time.Time                `json:"expires_at"`
+}
+
+// DeletionService guards destructive deletes behind a dry run. A dry run
+// reports the impact and issues a short-lived token bound to the target and
+// the impact; the deletion only happens with that token and only if the
+// impact is still the same.
+type DeletionService struct {
+	db  *database.Database
+	now func() time.Time
+}
+
+func NewDeletionService(db *database.Database) *DeletionService {
+	return &DeletionService{db: db, now: time.Now}
+}
+
+// WithContext returns a copy of the service whose deletions run with ctx,
+// attributed to its principal and stopped when it is cancelled
+func (s *DeletionService) WithContext(ctx context.Context) *DeletionService {
+	scoped := *s
+	scoped.db = s.db.WithContext(ctx)
+	return &scoped
+}
+
+// Preview computes the impact of deleting target and issues its confirmation token
+func (s *DeletionService) Preview(target DeletionTarget) (*DeletionPlan, error) {
+	impact, err := s.impact(target)
+	if err != nil {
+		return nil, err
+	}
+
+	expiresAt := s.now().Add(confirmationTTL).UTC().Truncate(time.Second)
+	return &DeletionPlan{
+		Impact:            impact,
+		ConfirmationToken: s.sign(target, impact, expiresAt),
+		ExpiresAt:         expiresAt,
+	}, nil
+}
+
+// Delete deletes target after checking its confirmation token and returns the
+// impact the deletion had
+func (s *DeletionService) Delete(target DeletionTarget, token string) (*database.DeletionImpact, error) {
+	if token == "" {
+		return nil, ErrConfirmationRequired
+	}
+
+	expiresAt, err := parseConfirmationExpiry(token)
+	if err != nil || !s.now().Before(expiresAt) {
+		return nil, ErrInvalidConfirmation
+	}
+
+	impact, err := s.impact(target)
+	if err != nil {
+		return nil, err
+	}
+
+	// The target is checked before the impact: a token for another deletion is
+	// invalid, a token for this one whose impact changed asks for a new dry run
+	issued := strings.Split(token, ".")
+	expected := strings.Split(s.sign(target, impact, expiresAt), ".")
+	if len(issued) != 3 || !hmac.Equal([]byte(issued[1]), []byte(expected[1])) {
+		return nil, ErrInvalidConfirmation
+	}
+	if !hmac.Equal([]byte(issued[2]), []byte(expected[2])) {
+		return nil, ErrDeletionChanged
+	}
+
+	if err := s.delete(target); err != nil {
+		return nil, err
+	}
+	return impact, nil
+}
+
+func (s *DeletionService) impact(target DeletionTarget) (*database.DeletionImpact, error) {
+	switch target.Entity {
+	case "project":
+		return s.db.ProjectDeletionImpact(target.ID)
+	case "task":
+		return s.db.TaskDeletionImpact(target.ID)
+	case "epic":
+		return s.db.EpicDeletionImpact(target.ID, target.Cascade)
+	case "user":
+		return s.db.UserDeletionImpact(target.ID)
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnknownDeletion, target.Entity)
+}
+
+func (s *DeletionService) delete(target DeletionTarget) error {
+	switch target.Entity {
+	case "project":
+		return s.db.DeleteProject(target.ID)
+	case "task":
+		return s.db.DeleteTask(target.ID)
+	case "epic":
+		return s.db.DeleteEpicWithOptions(target.ID, target.Cascade)
+	case "user":
+		return s.db.DeleteUser(target.ID)
+	}
+	return fmt.Errorf("%w: %s", ErrUnknownDeletion, target.Entity)
+}
+
+// sign builds a token of the form "<expiry>.<target mac>.<impact mac>". The
+// separate target part lets Delete tell a changed impact from a token issued
+// for another deletion.
+func (s *DeletionService) sign(target DeletionTarget, impact *database.DeletionImpact, expiresAt time.Time) string {
+	expiry := strconv.FormatInt(expiresAt.Unix(), 10)
+	subject := fmt.Sprintf("%s:%d:%t:%s", target.Entity, target.ID, target.Cascade, expiry)
+
+	targetMAC := hmac.New(sha256.New, confirmationKey)
+	targetMAC.Write([]byte(subject))
+	targetSum := base64.RawURLEncoding.EncodeToString(targetMAC.Sum(nil)[:16])
+
+	data, _ := json.Marshal(impact)
+	digest := sha256.Sum256(data)
+	impactMAC := hmac.New(sha256.New, confirmationKey)
+	impactMAC.Write([]byte(subject + ":" + hex.EncodeToString(digest[:])))
+	impactSum := base64.RawURLEncoding.EncodeToString(impactMAC.Sum(nil)[:16])
+	return expiry + "." + targetSum + "." + impactSum
+}
+
+func parseConfirmationExpiry(token string) (time.Time, error) {
+	expiry, _, found := strings.Cut(token, ".")
+	if !found {
+		return time.Time{}, ErrInvalidConfirmation
+	}
+	seconds, err := strconv.ParseInt(expiry, 10, 64)
+	if err != nil {
+		return time.Time{}, ErrInvalidConfirmation
+	}
+	return time.Unix(seconds, 0).UTC(), nil
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/headless-pm/headless-project-management/internal/database"
+)
+
+// DueSoonNotifier periodically notifies assignees about tasks that are about to be due
+type DueSoonNotifier struct {
+	db       *database.Database
+	interval time.Duration
+	window   time.Duration
+	stop     chan struct{}
+	wg       sync.WaitGroup
+	running  bool
+	mu       sync.Mutex
+}
+
+func NewDueSoonNotifier(db *database.Database, interval, window time.Duration) *DueSoonNotifier {
+	return &DueSoonNotifier{
+		db:       db,
+		interval: interval,
+		window:   window,
+	}
+}
+
+func (n *DueSoonNotifier) Start() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.running {
+		return
+	}
+	n.running = true
+	n.stop = make(chan struct{})
+
+	n.wg.Add(1)
+	go n.run()
+
+	log.Printf("Due soon notifier started (every %s, tasks due within %s)", n.interval, n.window)
+}
+
+func (n *DueSoonNotifier) Stop() {
+	n.mu.Lock()
+	if !n.running {
+		n.mu.Unlock()
+		return
+	}
+	n.running = false
+	close(n.stop)
+	n.mu.Unlock()
+
+	n.wg.Wait()
+	log.Println("Due soon notifier stopped")
+}
+
+func (n *DueSoonNotifier) run() {
+	defer n.wg.Done()
+
+	ticker := time.NewTicker(n.interval)
+	defer ticker.Stop()
+
+	n.check()
+	for {
+		select {
+		case <-ticker.C:
+			n.check()
+		case <-n.stop:
+			return
+		}
+	}
+}
+
+func (n *DueSoonNotifier) check() {
+	sent, err := n.db.NotifyDueSoonTasks(n.window)
+	if err != nil {
+		log.Printf("Failed to check for tasks due soon: %v", err)
+		return
+	}
+	if sent > 0 {
+		log.Printf("Sent %d due soon notifications", sent)
+	}
+}
@@ -4,8 +4,14 @@ import (
 	"log"
 	"sync"
 	"time"
+
+	"github.com/headless-pm/headless-project-management/internal/events"
 )
 
+// embeddingMaxRetries is how often a failed embedding is retried before the
+// worker gives up
+const embeddingMaxRetries = 3
+
 type EmbeddingJob struct {
 	EntityType string
 	EntityID   uint
@@ -86,12 +92,19 @@ func (w *EmbeddingWorker) processJob(job EmbeddingJob) {
 
 	if err != nil {
 		log.Printf("Failed to generate embedding for %s:%d - %v", job.EntityType, job.EntityID, err)
+		w.publish(events.EmbeddingFailed{
+			EntityType: job.EntityType,
+			EntityID:   job.EntityID,
+			Attempt:    job.Retry + 1,
+			Err:        err.Error(),
+			Final:      job.Retry >= embeddingMaxRetries,
+		})
 
 		// Retry logic
-		if job.Retry < 3 {
+		if job.Retry < embeddingMaxRetries {
 			job.Retry++
 			time.Sleep(time.Second * time.Duration(job.Retry))
-			w.QueueJob(job.EntityType, job.EntityID)
+			w.queue(job)
 		}
 	} else {
 		log.Printf("Successfully generated embedding for %s:%d", job.EntityType, job.EntityID)
@@ -99,6 +112,15 @@ func (w *EmbeddingWorker) processJob(job EmbeddingJob) {
 }
 
 func (w *EmbeddingWorker) QueueJob(entityType string, entityID uint) {
+	w.queue(EmbeddingJob{
+		EntityType: entityType,
+		EntityID:   entityID,
+		Retry:      0,
+	})
+}
+
+// queue adds a job, keeping its retry count
+func (w *EmbeddingWorker) queue(job EmbeddingJob) {
 	w.mu.Lock()
 	defer w.mu.Unlock()
 
@@ -107,15 +129,19 @@ func (w *EmbeddingWorker) QueueJob(entityType string, entityID uint) {
 	}
 
 	select {
-	case w.jobQueue <- EmbeddingJob{
-		EntityType: entityType,
-		EntityID:   entityID,
-		Retry:      0,
-	}:
+	case w.jobQueue <- job:
 		// Job queued successfully
 	default:
 		// Queue is full, log and skip
-		log.Printf("Embedding queue full, skipping %s:%d", entityType, entityID)
+		log.Printf("Embedding queue full, skipping %s:%d", job.EntityType, job.EntityID)
+	}
+}
+
+// publish tells subscribers of the database about a failure, e.g. the MCP
+// sessions of the task's creator
+func (w *EmbeddingWorker) publish(event events.Event) {
+	if w.vectorService != nil && w.vectorService.db != nil {
+		w.vectorService.db.Events().Publish(event)
 	}
 }
 
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"github.com/headless-pm/headless-project-management/internal/database"
+	"github.com/headless-pm/headless-project-management/internal/events"
+	"github.com/headless-pm/headless-project-management/internal/models"
+)
+
+// RegisterNotificationSubscriber sends inbox notifications for task changes and mentions
+func RegisterNotificationSubscriber(bus *events.Bus, db *database.Database) {
+	bus.Subscribe(func(event events.Event) {
+		switch e := event.(type) {
+		case events.TaskCreated:
+			db.NotifyAssigned(e.Task, e.Actor.ID, e.Actor.Name)
+		case events.TaskUpdated:
+			db.NotifyTaskChanges(e.Old, e.Task, e.Actor.Name)
+		case events.TaskUnblocked:
+			db.NotifyDependencyUnblocked(e.TaskID, e.Completed, e.Actor.Name)
+		case events.UsersMentioned:
+			db.NotifyMentioned(e.UserIDs, e.TaskID, e.ProjectID, e.TaskTitle, e.CommentID, e.Actor.ID, e.Actor.Name)
+		}
+	}, events.NameTaskCreated, events.NameTaskUpdated, events.NameTaskUnblocked, events.NameUsersMentioned)
+}
+
+// RegisterWebhookSubscriber queues webhook deliveries for domain events. Deliveries
+// are written to the outbox and sent by the WebhookDispatcher. Events are only
+// published once their change is committed, so a crash right after a commit
+// loses its deliveries: webhooks are delivered at most once in that case.
+func RegisterWebhookSubscriber(bus *events.Bus, db *database.Database) {
+	bus.SubscribeAll(func(event events.Event) {
+		switch e := event.(type) {
+		case events.ProjectCreated:
+			db.QueueWebhookEvent(models.WebhookEventProjectCreated, &e.Project.ID, e.Project)
+		case events.ProjectUpdated:
+			db.QueueWebhookEvent(models.WebhookEventProjectUpdated, &e.Project.ID, e.Project)
+		case events.ProjectDeleted:
+			db.QueueWebhookEvent(models.WebhookEventProjectDeleted, &e.ProjectID, map[string]interface{}{"id": e.ProjectID})
+		case events.TaskCreated:
+			db.QueueWebhookEvent(models.WebhookEventTaskCreated, &e.Task.ProjectID, e.Task)
+		case events.TaskUpdated:
+			queueTaskUpdateWebhooks(db, e)
+		case events.TaskDeleted:
+			db.QueueWebhookEvent(models.WebhookEventTaskDeleted, &e.Task.ProjectID, e.Task)
+		case events.CommentAdded:
+			db.QueueWebhookEvent(models.WebhookEventCommentAdded, &e.ProjectID, e.Comment)
+		case events.CommentUpdated:
+			db.QueueWebhookEvent(models.WebhookEventCommentUpdated, &e.ProjectID, e.Comment)
+		case events.CommentDeleted:
+			db.QueueWebhookEvent(models.WebhookEventCommentDeleted, &e.ProjectID, e.Comment)
+		case events.DependencyAdded:
+			db.QueueWebhookEvent(models.WebhookEventDependencyAdded, &e.ProjectID, e.Dependency)
+		case events.DependencyRemoved:
+			db.QueueWebhookEvent(models.WebhookEventDependencyRemoved, &e.ProjectID, e.Dependency)
+		case events.EpicCreated:
+			db.QueueWebhookEvent(models.WebhookEventEpicCreated, &e.Epic.ProjectID, e.Epic)
+		case events.EpicUpdated:
+			db.QueueWebhookEvent(models.WebhookEventEpicUpdated, &e.Epic.ProjectID, e.Epic)
+			if e.Epic.Status == models.EpicStatusCompleted && e.Old.Status != models.EpicStatusCompleted {
+				db.QueueWebhookEvent(models.WebhookEventEpicCompleted, &e.Epic.ProjectID, e.Epic)
+			}
+		case events.SprintStarted:
+			db.QueueWebhookEvent(models.WebhookEventSprintStarted, &e.Sprint.ProjectID, map[string]interface{}{
+				"sprint":        e.Sprint,
+				"carried_tasks": e.CarriedTasks,
+			})
+		case events.SprintCompleted:
+			data := map[string]interface{}{
+				"sprint":        e.Sprint,
+				"carried_tasks": e.CarriedTasks,
+			}
+			if e.NextSprintID != nil {
+				data["next_sprint_id"] = *e.NextSprintID
+			}
+			db.QueueWebhookEvent(models.WebhookEventSprintCompleted, &e.Sprint.ProjectID, data)
+		}
+	})
+}
+
+func queueTaskUpdateWebhooks(db *database.Database, e events.TaskUpdated) {
+	task := e.Task
+	db.QueueWebhookEvent(models.WebhookEventTaskUpdated, &task.ProjectID, task)
+
+	if e.Changed("status") {
+		db.QueueWebhookEvent(models.WebhookEventTaskStatusChanged, &task.ProjectID, map[string]interface{}{
+			"task":       task,
+			"old_status": e.Old.Status,
+			"new_status": task.Status,
+		})
+	}
+
+	if task.AssigneeID != nil && e.Changed("assignee_id") {
+		db.QueueWebhookEvent(models.WebhookEventTaskAssigned, &task.ProjectID, map[string]interface{}{
+			"task":                 task,
+			"assignee_id":          task.AssigneeID,
+			"previous_assignee_id": e.Old.AssigneeID,
+		})
+	}
+}
+
+// RegisterEmbeddingSubscriber keeps the search embeddings of projects and tasks
+// up to date. Task embeddings include comments, so comment events re-index the task.
+func RegisterEmbeddingSubscriber(bus *events.Bus, db *database.Database, worker *EmbeddingWorker) {
+	bus.Subscribe(func(event events.Event) {
+		switch e := event.(type) {
+		case events.ProjectCreated:
+			worker.QueueJob("project", e.Project.ID)
+		case events.ProjectUpdated:
+			worker.QueueJob("project", e.Project.ID)
+		case events.ProjectDeleted:
+			_ = db.DeleteEmbedding("project", e.ProjectID)
+		case events.TaskCreated:
+			worker.QueueJob("task", e.Task.ID)
+		case events.TaskUpdated:
+			worker.QueueJob("task", e.Task.ID)
+		case events.TaskDeleted:
+			_ = db.DeleteEmbedding("task", e.Task.ID)
+		case events.CommentAdded:
+			worker.QueueJob("task", e.Comment.TaskID)
+		case events.CommentUpdated:
+			worker.QueueJob("task", e.Comment.TaskID)
+		case events.CommentDeleted:
+			worker.QueueJob("task", e.Comment.TaskID)
+		}
+	}, events.NameProjectCreated, events.NameProjectUpdated, events.NameProjectDeleted,
+		events.NameTaskCreated, events.NameTaskUpdated, events.NameTaskDeleted,
+		events.NameCommentAdded, events.NameCommentUpdated, events.NameCommentDeleted)
+}
@@ -0,0 +1,20 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/headless-pm/headless-project-management/internal/database"
+	"gorm.io/gorm/logger"
+)
+
+// Helpers shared by the tests of the services
+
+// newTestDatabase opens a fresh database in a temporary directory
+func newTestDatabase(t *testing.T) *database.Database {
+	t.Helper()
+	db, err := database.NewDatabaseWithLogger(t.TempDir(), logger.Discard)
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	return db
+}
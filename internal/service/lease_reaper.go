@@ -0,0 +1,83 @@
+package service
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/headless-pm/headless-project-management/internal/database"
+)
+
+// LeaseReaper periodically returns tasks whose lease expired without a heartbeat to the pool
+type LeaseReaper struct {
+	db       *database.Database
+	interval time.Duration
+	stop     chan struct{}
+	wg       sync.WaitGroup
+	running  bool
+	mu       sync.Mutex
+}
+
+func NewLeaseReaper(db *database.Database, interval time.Duration) *LeaseReaper {
+	return &LeaseReaper{
+		db:       db,
+		interval: interval,
+	}
+}
+
+func (r *LeaseReaper) Start() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.running {
+		return
+	}
+	r.running = true
+	r.stop = make(chan struct{})
+
+	r.wg.Add(1)
+	go r.run()
+
+	log.Printf("Lease reaper started (every %s)", r.interval)
+}
+
+func (r *LeaseReaper) Stop() {
+	r.mu.Lock()
+	if !r.running {
+		r.mu.Unlock()
+		return
+	}
+	r.running = false
+	close(r.stop)
+	r.mu.Unlock()
+
+	r.wg.Wait()
+	log.Println("Lease reaper stopped")
+}
+
+func (r *LeaseReaper) run() {
+	defer r.wg.Done()
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	r.reap()
+	for {
+		select {
+		case <-ticker.C:
+			r.reap()
+		case <-r.stop:
+			return
+		}
+	}
+}
+
+func (r *LeaseReaper) reap() {
+	expired, err := r.db.ExpireTaskLeases(time.Now())
+	if err != nil {
+		log.Printf("Failed to expire task leases: %v", err)
+		return
+	}
+	if expired > 0 {
+		log.Printf("Returned %d tasks with expired leases to the pool", expired)
+	}
+}
@@ -0,0 +1,415 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/headless-pm/headless-project-management/internal/database"
+	"github.com/headless-pm/headless-project-management/internal/models"
+)
+
+// ErrPromptNotFound is returned for prompt names that are neither built in nor
+// defined as a template
+var ErrPromptNotFound = errors.New("prompt not found")
+
+// priorityOrder sorts tasks from urgent to low in SQL
+const priorityOrder = "CASE priority WHEN 'urgent' THEN 0 WHEN 'high' THEN 1 WHEN 'medium' THEN 2 ELSE 3 END"
+
+// Prompt describes a prompt offered to MCP clients
+type Prompt struct {
+	Name        string                  `json:"name"`
+	Description string                  `json:"description,omitempty"`
+	Arguments   []models.PromptArgument `json:"arguments,omitempty"`
+	Custom      bool                    `json:"custom,omitempty"` // Defined by the team rather than built in
+}
+
+// PromptMessage is one message of a rendered prompt
+type PromptMessage struct {
+	Role    string        `json:"role"`
+	Content PromptContent `json:"content"`
+}
+
+type PromptContent struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// PromptResult is a rendered prompt as returned by prompts/get
+type PromptResult struct {
+	Description string          `json:"description,omitempty"`
+	Messages    []PromptMessage `json:"messages"`
+}
+
+// PromptService lists and renders the built-in workflow prompts and the
+// prompt templates stored in the database
+type PromptService struct {
+	db *database.Database
+}
+
+func NewPromptService(db *database.Database) *PromptService {
+	return &PromptService{db: db}
+}
+
+// WithContext returns a copy of the service that queries the database with ctx
+func (s *PromptService) WithContext(ctx context.Context) *PromptService {
+	return &PromptService{db: s.db.WithContext(ctx)}
+}
+
+// ListPrompts returns the built-in prompts and the team templates. Built-in
+// prompts cannot be replaced by a template of the same name.
+func (s *PromptService) ListPrompts() ([]Prompt, error) {
+	templates, err := s.db.ListPromptTemplates()
+	if err != nil {
+		return nil, err
+	}
+
+	prompts := make(map[string]Prompt)
+	for _, t := range templates {
+		prompts[t.Name] = Prompt{
+			Name:        t.Name,
+			Description: t.Description,
+			Arguments:   t.Arguments,
+			Custom:      true,
+		}
+	}
+	for _, builtin := range builtinPrompts {
+		prompts[builtin.Name] = builtin.Prompt
+	}
+
+	list := make([]Prompt, 0, len(prompts))
+	for _, prompt := range prompts {
+		list = append(list, prompt)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list, nil
+}
+
+// GetPrompt renders a prompt with the given arguments and the current project data
+func (s *PromptService) GetPrompt(name string, args map[string]string) (*PromptResult, error) {
+	prompt, text, err := s.lookup(name)
+	if err != nil {
+		return nil, err
+	}
+	if args == nil {
+		args = map[string]string{}
+	}
+	for _, argument := range prompt.Arguments {
+		if argument.Required && strings.TrimSpace(args[argument.Name]) == "" {
+			return nil, fmt.Errorf("missing required argument: %s", argument.Name)
+		}
+	}
+
+	renderer := &promptRenderer{db: s.db, now: time.Now()}
+	if ref := args["project"]; ref != "" {
+		project, err := s.ResolveProject(ref)
+		if err != nil {
+			return nil, err
+		}
+		renderer.project = project
+	}
+
+	tmpl, err := template.New(name).Funcs(renderer.funcs()).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid prompt template: %w", err)
+	}
+	var out strings.Builder
+	data := map[string]interface{}{
+		"Args":    args,
+		"Project": renderer.project,
+		"Now":     renderer.now,
+	}
+	if err := tmpl.Execute(&out, data); err != nil {
+		return nil, fmt.Errorf("failed to render prompt: %w", err)
+	}
+
+	return &PromptResult{
+		Description: prompt.Description,
+		Messages: []PromptMessage{
+			{
+				Role:    "user",
+				Content: PromptContent{Type: "text", Text: strings.TrimSpace(out.String())},
+			},
+		},
+	}, nil
+}
+
+// ValidatePromptTemplate checks that a template parses with the prompt functions
+func ValidatePromptTemplate(text string) error {
+	renderer := &promptRenderer{}
+	if _, err := template.New("prompt").Funcs(renderer.funcs()).Parse(text); err != nil {
+		return fmt.Errorf("invalid prompt template: %w", err)
+	}
+	return nil
+}
+
+// CheckPromptName rejects template names taken by built-in prompts
+func CheckPromptName(name string) error {
+	name = strings.TrimSpace(name)
+	for _, builtin := range builtinPrompts {
+		if builtin.Name == name {
+			return fmt.Errorf("prompt name %q is reserved for a built-in prompt", name)
+		}
+	}
+	return nil
+}
+
+func (s *PromptService) lookup(name string) (Prompt, string, error) {
+	for _, builtin := range builtinPrompts {
+		if builtin.Name == name {
+			return builtin.Prompt, builtin.Template, nil
+		}
+	}
+
+	var templates []models.PromptTemplate
+	if err := s.db.Where("name = ?", name).Limit(1).Find(&templates).Error; err != nil {
+		return Prompt{}, "", err
+	}
+	if len(templates) > 0 {
+		t := templates[0]
+		return Prompt{Name: t.Name, Description: t.Description, Arguments: t.Arguments, Custom: true}, t.Template, nil
+	}
+	return Prompt{}, "", fmt.Errorf("%w: %s", ErrPromptNotFound, name)
+}
+
+// ResolveProject finds a project by ID or by name
+func (s *PromptService) ResolveProject(ref string) (*models.Project, error) {
+	if id, err := strconv.ParseUint(ref, 10, 32); err == nil {
+		project, err := s.db.GetProject(uint(id))
+		if err != nil {
+			return nil, fmt.Errorf("project %s not found", ref)
+		}
+		return project, nil
+	}
+	var project models.Project
+	if err := s.db.Where("LOWER(name) = LOWER(?)", ref).First(&project).Error; err != nil {
+		return nil, fmt.Errorf("project %q not found", ref)
+	}
+	return &project, nil
+}
+
+// promptRenderer provides the template functions of one rendering; queries are
+// scoped to the project argument
+type promptRenderer struct {
+	db      *database.Database
+	project *models.Project
+	now     time.Time
+}
+
+func (r *promptRenderer) funcs() template.FuncMap {
+	return template.FuncMap{
+		"openTasks":      r.openTasks,
+		"tasksByStatus":  r.tasksByStatus,
+		"backlog":        r.backlog,
+		"untriaged":      r.untriaged,
+		"completedTasks": r.completedTasks,
+		"activity":       r.activity,
+		"activeSprint":   r.activeSprint,
+		"velocity":       r.velocity,
+		"epic":           r.epic,
+		"time":           r.parseTime,
+		"task":           formatPromptTask,
+		"date":           func(t time.Time) string { return t.Format("2006-01-02") },
+		"first":          firstTasks,
+		"atoi":           atoiDefault,
+		"default":        defaultString,
+		"join":           strings.Join,
+		"lower":          strings.ToLower,
+	}
+}
+
+func (r *promptRenderer) projectID() (uint, error) {
+	if r.project == nil {
+		return 0, fmt.Errorf("this prompt needs the project argument")
+	}
+	return r.project.ID, nil
+}
+
+func (r *promptRenderer) tasks(where string, args ...interface{}) ([]models.Task, error) {
+	projectID, err := r.projectID()
+	if err != nil {
+		return nil, err
+	}
+	var tasks []models.Task
+	err = r.db.Where("project_id = ?", projectID).
+		Where(where, args...).
+		Preload("AssigneeUser").
+		Preload("Labels").
+		Order(priorityOrder).
+		Order("id ASC").
+		Find(&tasks).Error
+	return tasks, err
+}
+
+func (r *promptRenderer) openTasks() ([]models.Task, error) {
+	return r.tasks("status NOT IN ?", []models.TaskStatus{models.TaskStatusDone, models.TaskStatusCancelled})
+}
+
+func (r *promptRenderer) tasksByStatus(status string) ([]models.Task, error) {
+	return r.tasks("status = ?", status)
+}
+
+// backlog returns the todo tasks not planned into a sprint
+func (r *promptRenderer) backlog() ([]models.Task, error) {
+	return r.tasks("status = ? AND sprint_id IS NULL", models.TaskStatusTodo)
+}
+
+// untriaged returns the todo tasks nobody has picked up or planned yet
+func (r *promptRenderer) untriaged() ([]models.Task, error) {
+	return r.tasks("status = ? AND assignee_id IS NULL AND sprint_id IS NULL", models.TaskStatusTodo)
+}
+
+func (r *promptRenderer) completedTasks(since, until time.Time) ([]models.Task, error) {
+	return r.tasks("status = ? AND completed_at >= ? AND completed_at < ?", models.TaskStatusDone, since, until)
+}
+
+// activity returns the task activity of the project in a time range, oldest first
+func (r *promptRenderer) activity(since, until time.Time) ([]models.Activity, error) {
+	projectID, err := r.projectID()
+	if err != nil {
+		return nil, err
+	}
+	var activities []models.Activity
+	err = r.db.Joins("JOIN tasks ON tasks.id = activities.task_id").
+		Where("tasks.project_id = ? AND activities.created_at >= ? AND activities.created_at < ?", projectID, since, until).
+		Preload("Task").
+		Order("activities.created_at ASC").
+		Find(&activities).Error
+	return activities, err
+}
+
+// activeSprint returns the active sprint with its tasks, or nil
+func (r *promptRenderer) activeSprint() (*models.Sprint, error) {
+	projectID, err := r.projectID()
+	if err != nil {
+		return nil, err
+	}
+	sprint, err := r.db.GetActiveSprint(projectID)
+	if err != nil {
+		return nil, nil
+	}
+	return sprint, nil
+}
+
+// velocity is the average of story points done in the last n completed sprints
+func (r *promptRenderer) velocity(n int) (float64, error) {
+	projectID, err := r.projectID()
+	if err != nil {
+		return 0, err
+	}
+	var sprintIDs []uint
+	if err := r.db.Model(&models.Sprint{}).
+		Where("project_id = ? AND status = ?", projectID, models.SprintStatusCompleted).
+		Order("completed_at DESC").
+		Limit(n).
+		Pluck("id", &sprintIDs).Error; err != nil {
+		return 0, err
+	}
+	if len(sprintIDs) == 0 {
+		return 0, nil
+	}
+
+	var points int64
+	if err := r.db.Model(&models.Task{}).
+		Where("sprint_id IN ? AND status = ?", sprintIDs, models.TaskStatusDone).
+		Select("COALESCE(SUM(story_points), 0)").
+		Scan(&points).Error; err != nil {
+		return 0, err
+	}
+	return float64(points) / float64(len(sprintIDs)), nil
+}
+
+func (r *promptRenderer) epic(ref string) (*models.Epic, error) {
+	id, err := strconv.ParseUint(ref, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid epic ID %q", ref)
+	}
+	epic, err := r.db.GetEpic(uint(id))
+	if err != nil {
+		return nil, fmt.Errorf("epic %s not found", ref)
+	}
+	return epic, nil
+}
+
+// parseTime accepts a YYYY-MM-DD date or a duration before now such as 24h, 7d or 2w
+func (r *promptRenderer) parseTime(value string) (time.Time, error) {
+	return ParseRelativeTime(value, r.now)
+}
+
+// ParseRelativeTime parses a point in time given as YYYY-MM-DD or as a
+// duration before now such as 24h, 7d or 2w; empty and "now" mean now
+func ParseRelativeTime(value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" || value == "now" {
+		return now, nil
+	}
+	if date, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return date, nil
+	}
+
+	unit := value[len(value)-1]
+	if n, err := strconv.Atoi(value[:len(value)-1]); err == nil && n >= 0 {
+		switch unit {
+		case 'd':
+			return now.AddDate(0, 0, -n), nil
+		case 'w':
+			return now.AddDate(0, 0, -7*n), nil
+		}
+	}
+	if d, err := time.ParseDuration(value); err == nil {
+		return now.Add(-d), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q: use YYYY-MM-DD or a duration like 24h, 7d or 2w", value)
+}
+
+// formatPromptTask renders a task as one line, e.g. "#12 [high] Fix login (in_progress, @alice, 3 pts)"
+func formatPromptTask(task models.Task) string {
+	details := []string{string(task.Status)}
+	if task.AssigneeUser != nil {
+		details = append(details, "@"+task.AssigneeUser.Username)
+	} else if task.Assignee != "" {
+		details = append(details, "@"+task.Assignee)
+	} else {
+		details = append(details, "unassigned")
+	}
+	if task.StoryPoints != nil {
+		details = append(details, fmt.Sprintf("%d pts", *task.StoryPoints))
+	}
+	if task.DueDate != nil {
+		details = append(details, "due "+task.DueDate.Format("2006-01-02"))
+	}
+	if len(task.Labels) > 0 {
+		names := make([]string, 0, len(task.Labels))
+		for _, label := range task.Labels {
+			names = append(names, label.Name)
+		}
+		details = append(details, "labels: "+strings.Join(names, ", "))
+	}
+	return fmt.Sprintf("#%d [%s] %s (%s)", task.ID, task.Priority, task.Title, strings.Join(details, ", "))
+}
+
+func firstTasks(n int, tasks []models.Task) []models.Task {
+	if n > 0 && len(tasks) > n {
+		return tasks[:n]
+	}
+	return tasks
+}
+
+func atoiDefault(value string, fallback int) int {
+	if n, err := strconv.Atoi(strings.TrimSpace(value)); err == nil {
+		return n
+	}
+	return fallback
+}
+
+func defaultString(fallback, value string) string {
+	if strings.TrimSpace(value) == "" {
+		return fallback
+	}
+	return value
+}
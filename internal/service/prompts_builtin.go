@@ -0,0 +1,137 @@
+package service
+
+import "github.com/headless-pm/headless-project-management/internal/models"
+
+type builtinPrompt struct {
+	Prompt
+	Template string
+}
+
+var projectArgument = models.PromptArgument{
+	Name:        "project",
+	Description: "Project ID or name",
+	Required:    true,
+}
+
+// builtinPrompts are the project-management workflows offered to every team.
+// Templates use the same functions as team templates, see promptRenderer.funcs.
+var builtinPrompts = []builtinPrompt{
+	{
+		Prompt: Prompt{
+			Name:        "daily_standup",
+			Description: "Summarize recent activity and open work as a daily standup report",
+			Arguments: []models.PromptArgument{
+				projectArgument,
+				{Name: "since", Description: "Start of the period: YYYY-MM-DD or a duration such as 24h or 3d (default 24h)"},
+				{Name: "until", Description: "End of the period: YYYY-MM-DD or a duration before now (default now)"},
+			},
+		},
+		Template: `{{$since := time (default "24h" (index .Args "since"))}}{{$until := time (index .Args "until")}}
+Prepare the daily standup for project "{{.Project.Name}}" covering {{date $since}} to {{date $until}}.
+Group the update by person: what they did, what they are working on next, and anything blocking them.
+Call out tasks in review that need a reviewer and work that looks stalled.
+
+Activity in the period:
+{{range activity $since $until}}- {{.CreatedAt.Format "2006-01-02 15:04"}} {{default "system" .UserName}}: {{if .Description}}{{.Description}}{{else}}{{.Action}}{{end}}{{if .Task}} (#{{.Task.ID}} {{.Task.Title}}){{end}}
+{{else}}- No activity recorded.
+{{end}}
+In progress:
+{{range tasksByStatus "in_progress"}}- {{task .}}
+{{else}}- Nothing in progress.
+{{end}}
+In review:
+{{range tasksByStatus "review"}}- {{task .}}
+{{else}}- Nothing in review.
+{{end}}`,
+	},
+	{
+		Prompt: Prompt{
+			Name:        "sprint_planning",
+			Description: "Propose the next sprint from the prioritized backlog and recent velocity",
+			Arguments: []models.PromptArgument{
+				projectArgument,
+				{Name: "capacity", Description: "Story points the team can take on (default: average of the last 3 sprints)"},
+			},
+		},
+		Template: `{{$velocity := velocity 3}}
+Plan the next sprint for project "{{.Project.Name}}".
+{{if index .Args "capacity"}}The team has a capacity of {{index .Args "capacity"}} story points.{{else if $velocity}}Recent velocity is {{printf "%.1f" $velocity}} story points per sprint; use it as the capacity.{{else}}No velocity data is available yet; propose a conservative scope.{{end}}
+Select tasks from the backlog in priority order, respecting dependencies, and fit them to the capacity.
+Estimate tasks without story points, propose a sprint goal, and list what was left out and why.
+{{with activeSprint}}
+The current sprint "{{.Name}}" is still active{{if .Goal}} with the goal "{{.Goal}}"{{end}}; carry over its unfinished tasks:
+{{range .Tasks}}{{if and (ne .Status "done") (ne .Status "cancelled")}}- {{task .}}
+{{end}}{{end}}{{end}}
+Backlog:
+{{range backlog}}- {{task .}}
+{{else}}- The backlog is empty.
+{{end}}`,
+	},
+	{
+		Prompt: Prompt{
+			Name:        "triage_tasks",
+			Description: "Triage unassigned, unplanned tasks: priority, labels, owner and duplicates",
+			Arguments: []models.PromptArgument{
+				projectArgument,
+				{Name: "limit", Description: "Maximum number of tasks to triage (default 20)"},
+			},
+		},
+		Template: `{{$tasks := untriaged}}
+Triage the untriaged tasks of project "{{.Project.Name}}" ({{len $tasks}} waiting).
+For each task suggest a priority, labels, story points and who should own it,
+flag duplicates and tasks that need more information, and recommend the ones to close.
+
+Untriaged tasks:
+{{range first (atoi (index .Args "limit") 20) $tasks}}- {{task .}}{{if .Description}}
+  {{.Description}}{{end}}
+{{else}}- Nothing to triage.
+{{end}}`,
+	},
+	{
+		Prompt: Prompt{
+			Name:        "release_notes",
+			Description: "Draft release notes from the tasks completed in a period",
+			Arguments: []models.PromptArgument{
+				projectArgument,
+				{Name: "since", Description: "Start of the period: YYYY-MM-DD or a duration such as 14d (default 14d)"},
+				{Name: "until", Description: "End of the period: YYYY-MM-DD or a duration before now (default now)"},
+				{Name: "version", Description: "Version being released"},
+			},
+		},
+		Template: `{{$since := time (default "14d" (index .Args "since"))}}{{$until := time (index .Args "until")}}
+Write release notes{{with index .Args "version"}} for version {{.}}{{end}} of project "{{.Project.Name}}" covering {{date $since}} to {{date $until}}.
+Group the changes into new features, improvements and bug fixes using the labels and descriptions,
+write them for users rather than developers, and leave out internal-only work.
+
+Completed tasks:
+{{range completedTasks $since $until}}- {{task .}}{{if .Description}}
+  {{.Description}}{{end}}
+{{else}}- No tasks were completed in this period.
+{{end}}`,
+	},
+	{
+		Prompt: Prompt{
+			Name:        "epic_breakdown",
+			Description: "Break an epic down into concrete, estimable tasks",
+			Arguments: []models.PromptArgument{
+				{Name: "epic_id", Description: "ID of the epic to break down", Required: true},
+				{Name: "guidance", Description: "Extra constraints or focus for the breakdown"},
+			},
+		},
+		Template: `{{$epic := epic (index .Args "epic_id")}}
+Break down the epic "{{$epic.Name}}" (#{{$epic.ID}}, {{$epic.Status}}) into tasks.
+{{if $epic.Description}}
+Epic description:
+{{$epic.Description}}
+{{end}}{{with index .Args "guidance"}}
+Guidance: {{.}}
+{{end}}
+Propose tasks small enough to finish in a few days, each with a title, description,
+priority, story points and dependencies on other tasks. Do not repeat existing tasks.
+
+Existing tasks in the epic:
+{{range $epic.Tasks}}- {{task .}}
+{{else}}- None yet.
+{{end}}`,
+	},
+}
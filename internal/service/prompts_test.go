@@ -0,0 +1,100 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/headless-pm/headless-project-management/internal/models"
+)
+
+func TestPromptRendering(t *testing.T) {
+	db := newTestDatabase(t)
+	project := &models.Project{Name: "Apollo"}
+	if err := db.CreateProject(project); err != nil {
+		t.Fatal(err)
+	}
+	for _, title := range []string{"Fuel the rocket", "Paint the fins"} {
+		if err := db.CreateTask(&models.Task{ProjectID: project.ID, Title: title}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	prompts := NewPromptService(db)
+
+	t.Run("built-in prompts render project data", func(t *testing.T) {
+		result, err := prompts.GetPrompt("triage_tasks", map[string]string{"project": "apollo", "limit": "1"})
+		if err != nil {
+			t.Fatalf("GetPrompt: %v", err)
+		}
+		text := result.Messages[0].Content.Text
+		if !strings.Contains(text, `project "Apollo" (2 waiting)`) {
+			t.Errorf("prompt does not name the project and its tasks:\n%s", text)
+		}
+		if strings.Count(text, "\n- ") != 1 {
+			t.Errorf("limit was not applied:\n%s", text)
+		}
+	})
+
+	t.Run("arguments are checked", func(t *testing.T) {
+		if _, err := prompts.GetPrompt("triage_tasks", nil); err == nil || !strings.Contains(err.Error(), "project") {
+			t.Errorf("missing project = %v, want a missing argument error", err)
+		}
+		if _, err := prompts.GetPrompt("triage_tasks", map[string]string{"project": "Gemini"}); err == nil {
+			t.Error("rendered a prompt for an unknown project")
+		}
+		if _, err := prompts.GetPrompt("nothing", nil); !errors.Is(err, ErrPromptNotFound) {
+			t.Errorf("unknown prompt = %v, want ErrPromptNotFound", err)
+		}
+	})
+
+	t.Run("team templates render with the prompt functions", func(t *testing.T) {
+		template := &models.PromptTemplate{
+			Name:      "count_open",
+			Arguments: []models.PromptArgument{{Name: "project", Required: true}, {Name: "who"}},
+			Template:  `{{index .Args "who"}}: {{len openTasks}} open in {{.Project.Name}}`,
+		}
+		if err := ValidatePromptTemplate(template.Template); err != nil {
+			t.Fatalf("ValidatePromptTemplate: %v", err)
+		}
+		if err := db.CreatePromptTemplate(template); err != nil {
+			t.Fatal(err)
+		}
+		result, err := prompts.GetPrompt("count_open", map[string]string{"project": "1", "who": "Ops"})
+		if err != nil {
+			t.Fatalf("GetPrompt: %v", err)
+		}
+		if text := result.Messages[0].Content.Text; text != "Ops: 2 open in Apollo" {
+			t.Errorf("rendered %q", text)
+		}
+		if err := ValidatePromptTemplate(`{{unknownFunc}}`); err == nil {
+			t.Error("accepted a template with an unknown function")
+		}
+	})
+
+	t.Run("built-in prompts cannot be replaced", func(t *testing.T) {
+		if err := CheckPromptName(" daily_standup "); err == nil {
+			t.Error("accepted a built-in prompt name")
+		}
+		if err := CheckPromptName("standup_notes"); err != nil {
+			t.Errorf("rejected a free name: %v", err)
+		}
+
+		// A template stored under a built-in name before names were reserved
+		if err := db.CreatePromptTemplate(&models.PromptTemplate{Name: "triage_tasks", Template: "Ignore the tasks"}); err != nil {
+			t.Fatal(err)
+		}
+		result, err := prompts.GetPrompt("triage_tasks", map[string]string{"project": "Apollo"})
+		if err != nil {
+			t.Fatalf("GetPrompt: %v", err)
+		}
+		if strings.Contains(result.Messages[0].Content.Text, "Ignore the tasks") {
+			t.Error("a stored template replaced a built-in prompt")
+		}
+		list, _ := prompts.ListPrompts()
+		for _, prompt := range list {
+			if prompt.Name == "triage_tasks" && prompt.Custom {
+				t.Error("prompts/list offers the stored template instead of the built-in prompt")
+			}
+		}
+	})
+}
@@ -0,0 +1,247 @@
+package service
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/headless-pm/headless-project-management/internal/database"
+	"github.com/headless-pm/headless-project-management/internal/events"
+	"github.com/headless-pm/headless-project-management/internal/models"
+)
+
+const (
+	// webhookMaxAttempts is the number of attempts before a delivery is marked failed
+	webhookMaxAttempts = 8
+	// webhookRetryBase is the delay before the first retry; it doubles after every attempt
+	webhookRetryBase = 30 * time.Second
+	webhookRetryMax  = time.Hour
+	// webhookBatchSize bounds the deliveries sent per tick
+	webhookBatchSize = 50
+	// webhookMaxResponseBody bounds the response body kept in the delivery log
+	webhookMaxResponseBody = 2048
+)
+
+// WebhookDispatcher sends queued webhook deliveries, retrying failed ones with
+// exponential backoff. The outbox lives in the database so queued deliveries
+// survive restarts; see RegisterWebhookSubscriber for events that are not queued.
+type WebhookDispatcher struct {
+	db       *database.Database
+	client   *http.Client
+	interval time.Duration
+	stop     chan struct{}
+	wg       sync.WaitGroup
+	running  bool
+	mu       sync.Mutex
+}
+
+func NewWebhookDispatcher(db *database.Database, interval time.Duration) *WebhookDispatcher {
+	return &WebhookDispatcher{
+		db:       db,
+		client:   &http.Client{Timeout: 10 * time.Second},
+		interval: interval,
+	}
+}
+
+func (d *WebhookDispatcher) Start() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.running {
+		return
+	}
+	d.running = true
+	d.stop = make(chan struct{})
+
+	// Deliveries in flight when the server last stopped are sent again
+	if n, err := d.db.ResetInterruptedWebhookDeliveries(); err != nil {
+		log.Printf("Failed to reset interrupted webhook deliveries: %v", err)
+	} else if n > 0 {
+		log.Printf("Requeued %d interrupted webhook deliveries", n)
+	}
+
+	d.wg.Add(1)
+	go d.run()
+
+	log.Printf("Webhook dispatcher started (every %s)", d.interval)
+}
+
+func (d *WebhookDispatcher) Stop() {
+	d.mu.Lock()
+	if !d.running {
+		d.mu.Unlock()
+		return
+	}
+	d.running = false
+	close(d.stop)
+	d.mu.Unlock()
+
+	d.wg.Wait()
+	log.Println("Webhook dispatcher stopped")
+}
+
+func (d *WebhookDispatcher) run() {
+	defer d.wg.Done()
+
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	d.dispatch()
+	for {
+		select {
+		case <-ticker.C:
+			d.dispatch()
+		case <-d.stop:
+			return
+		}
+	}
+}
+
+func (d *WebhookDispatcher) dispatch() {
+	ids, err := d.db.ListDueWebhookDeliveries(webhookBatchSize)
+	if err != nil {
+		log.Printf("Failed to load due webhook deliveries: %v", err)
+		return
+	}
+
+	for _, id := range ids {
+		select {
+		case <-d.stop:
+			return
+		default:
+		}
+		if _, err := d.Deliver(id); err != nil {
+			log.Printf("Failed to deliver webhook delivery %d: %v", id, err)
+		}
+	}
+}
+
+// Deliver sends a pending delivery right away and records the attempt. It returns
+// the delivery as stored afterwards; a delivery that is already being sent or is
+// finished is returned unchanged.
+func (d *WebhookDispatcher) Deliver(id uint) (*models.WebhookDelivery, error) {
+	delivery, err := d.db.ClaimWebhookDelivery(id)
+	if err != nil {
+		return nil, err
+	}
+	if delivery == nil {
+		return d.db.GetWebhookDelivery(id)
+	}
+
+	d.attempt(delivery)
+
+	if err := d.db.SaveWebhookDelivery(delivery); err != nil {
+		return nil, err
+	}
+	if delivery.Status != models.WebhookDeliverySucceeded {
+		d.db.Events().Publish(events.WebhookDeliveryFailed{
+			Delivery: delivery,
+			Final:    delivery.Status == models.WebhookDeliveryFailed,
+		})
+	}
+	delivery.Webhook = nil
+	return delivery, nil
+}
+
+func (d *WebhookDispatcher) attempt(delivery *models.WebhookDelivery) {
+	now := time.Now()
+	delivery.Attempts++
+	delivery.LastAttemptAt = &now
+	delivery.ResponseStatus = 0
+	delivery.ResponseBody = ""
+	delivery.Error = ""
+
+	if delivery.Webhook != nil && !delivery.Webhook.Active && delivery.Event != models.WebhookEventPing {
+		// Disabled webhooks keep their log; deliveries can be redelivered once re-enabled
+		delivery.Error = "webhook is disabled"
+		delivery.Status = models.WebhookDeliveryFailed
+		delivery.NextAttemptAt = nil
+		return
+	}
+
+	err := d.send(delivery)
+	delivery.DurationMs = time.Since(now).Milliseconds()
+
+	if err == nil {
+		delivery.Status = models.WebhookDeliverySucceeded
+		delivery.NextAttemptAt = nil
+		return
+	}
+
+	delivery.Error = err.Error()
+	if delivery.Webhook == nil || delivery.Attempts >= webhookMaxAttempts {
+		delivery.Status = models.WebhookDeliveryFailed
+		delivery.NextAttemptAt = nil
+		return
+	}
+
+	next := now.Add(webhookRetryDelay(delivery.Attempts))
+	delivery.Status = models.WebhookDeliveryPending
+	delivery.NextAttemptAt = &next
+}
+
+func (d *WebhookDispatcher) send(delivery *models.WebhookDelivery) error {
+	webhook := delivery.Webhook
+	if webhook == nil {
+		return fmt.Errorf("webhook %d no longer exists", delivery.WebhookID)
+	}
+
+	body := []byte(delivery.Payload)
+	timestamp := time.Now().Unix()
+
+	req, err := http.NewRequest(http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "headless-pm-webhooks/1.0")
+	req.Header.Set("X-Webhook-Event", string(delivery.Event))
+	req.Header.Set("X-Webhook-Delivery", strconv.FormatUint(uint64(delivery.ID), 10))
+	req.Header.Set("X-Webhook-Timestamp", strconv.FormatInt(timestamp, 10))
+	req.Header.Set("X-Webhook-Signature", SignWebhookPayload(webhook.Secret, timestamp, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, webhookMaxResponseBody))
+	delivery.ResponseStatus = resp.StatusCode
+	delivery.ResponseBody = string(respBody)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("endpoint responded with %s", resp.Status)
+	}
+	return nil
+}
+
+// SignWebhookPayload returns the X-Webhook-Signature header value for a delivery:
+// "sha256=" followed by the hex HMAC-SHA256 of "<timestamp>.<body>" keyed with the
+// webhook secret. Receivers should recompute it and reject stale timestamps.
+func SignWebhookPayload(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// webhookRetryDelay returns the backoff after a failed attempt: 30s, 1m, 2m, ... up to an hour
+func webhookRetryDelay(attempts int) time.Duration {
+	delay := webhookRetryBase
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= webhookRetryMax {
+			return webhookRetryMax
+		}
+	}
+	return delay
+}
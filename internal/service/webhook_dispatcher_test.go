@@ -0,0 +1,144 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/headless-pm/headless-project-management/internal/models"
+)
+
+func TestSignWebhookPayload(t *testing.T) {
+	body := []byte(`{"event":"ping"}`)
+	mac := hmac.New(sha256.New, []byte("s3cret"))
+	mac.Write([]byte("1700000000." + string(body)))
+	want := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+	if got := SignWebhookPayload("s3cret", 1700000000, body); got != want {
+		t.Errorf("signature = %s, want %s", got, want)
+	}
+	if SignWebhookPayload("other", 1700000000, body) == want {
+		t.Error("signature does not depend on the secret")
+	}
+	if SignWebhookPayload("s3cret", 1700000001, body) == want {
+		t.Error("signature does not depend on the timestamp")
+	}
+}
+
+func TestWebhookRetryDelay(t *testing.T) {
+	for attempts, want := range map[int]time.Duration{
+		1:  30 * time.Second,
+		2:  time.Minute,
+		3:  2 * time.Minute,
+		7:  32 * time.Minute,
+		8:  time.Hour,
+		20: time.Hour,
+	} {
+		if got := webhookRetryDelay(attempts); got != want {
+			t.Errorf("webhookRetryDelay(%d) = %s, want %s", attempts, got, want)
+		}
+	}
+}
+
+func TestWebhookDelivery(t *testing.T) {
+	db := newTestDatabase(t)
+
+	var failures int
+	var signatureOK bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		timestamp, _ := strconv.ParseInt(r.Header.Get("X-Webhook-Timestamp"), 10, 64)
+		signatureOK = r.Header.Get("X-Webhook-Signature") == SignWebhookPayload("s3cret", timestamp, body)
+		if failures > 0 {
+			failures--
+			http.Error(w, "try later", http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	webhook := &models.Webhook{Name: "CI", URL: server.URL, Secret: "s3cret", Active: true}
+	if err := db.CreateWebhook(webhook); err != nil {
+		t.Fatalf("CreateWebhook: %v", err)
+	}
+	dispatcher := NewWebhookDispatcher(db, time.Hour)
+
+	t.Run("signed with the webhook secret", func(t *testing.T) {
+		queued, err := db.PingWebhook(webhook.ID)
+		if err != nil {
+			t.Fatalf("PingWebhook: %v", err)
+		}
+		delivery, err := dispatcher.Deliver(queued.ID)
+		if err != nil {
+			t.Fatalf("Deliver: %v", err)
+		}
+		if delivery.Status != models.WebhookDeliverySucceeded || delivery.ResponseStatus != http.StatusNoContent {
+			t.Errorf("delivery = %s (%d), want succeeded", delivery.Status, delivery.ResponseStatus)
+		}
+		if !signatureOK {
+			t.Error("the receiver could not verify the signature")
+		}
+	})
+
+	t.Run("failed attempts back off and retry", func(t *testing.T) {
+		failures = 2
+		queued, _ := db.PingWebhook(webhook.ID)
+
+		before := time.Now()
+		delivery, err := dispatcher.Deliver(queued.ID)
+		if err != nil {
+			t.Fatalf("Deliver: %v", err)
+		}
+		if delivery.Status != models.WebhookDeliveryPending || delivery.NextAttemptAt == nil {
+			t.Fatalf("delivery = %s, want pending with a next attempt", delivery.Status)
+		}
+		if wait := delivery.NextAttemptAt.Sub(before); wait < 30*time.Second || wait > 31*time.Second {
+			t.Errorf("first retry in %s, want 30s", wait)
+		}
+		if due, _ := db.ListDueWebhookDeliveries(10); len(due) != 0 {
+			t.Errorf("retry is due before its backoff: %v", due)
+		}
+
+		// A delivery that is not pending is left alone
+		if err := db.Model(delivery).Update("status", models.WebhookDeliveryDelivering).Error; err != nil {
+			t.Fatal(err)
+		}
+		if again, _ := dispatcher.Deliver(queued.ID); again.Attempts != 1 {
+			t.Errorf("claimed delivery was sent again: %d attempts", again.Attempts)
+		}
+		db.Model(delivery).Update("status", models.WebhookDeliveryPending)
+
+		delivery, _ = dispatcher.Deliver(queued.ID)
+		if delivery.Attempts != 2 || delivery.NextAttemptAt == nil {
+			t.Fatalf("second attempt = %+v, want pending", delivery)
+		}
+		if wait := time.Until(*delivery.NextAttemptAt); wait < 59*time.Second || wait > time.Minute {
+			t.Errorf("second retry in %s, want 1m", wait)
+		}
+
+		delivery, _ = dispatcher.Deliver(queued.ID)
+		if delivery.Status != models.WebhookDeliverySucceeded || delivery.Attempts != 3 {
+			t.Errorf("third attempt = %s after %d attempts, want succeeded after 3", delivery.Status, delivery.Attempts)
+		}
+	})
+
+	t.Run("gives up after the last attempt", func(t *testing.T) {
+		failures = webhookMaxAttempts
+		queued, _ := db.PingWebhook(webhook.ID)
+		var delivery *models.WebhookDelivery
+		for i := 0; i < webhookMaxAttempts; i++ {
+			delivery, _ = dispatcher.Deliver(queued.ID)
+		}
+		if delivery.Status != models.WebhookDeliveryFailed || delivery.NextAttemptAt != nil {
+			t.Errorf("delivery = %s, want failed without a next attempt", delivery.Status)
+		}
+		failures = 0
+	})
+}
@@ -6,8 +6,12 @@ AUTH_HEADER="Authorization: Bearer test-token"
 
 # Delete all existing tasks
 echo "Deleting existing tasks..."
+# Deletes are confirmed with the token of a dry run
 for i in {1..20}; do
-  curl -X DELETE "$BASE_URL/tasks/$i" -H "$AUTH_HEADER" 2>/dev/null
+  TOKEN=$(curl -s -X DELETE "$BASE_URL/tasks/$i?dry_run=true" -H "$AUTH_HEADER" | sed -n 's/.*"confirmation_token":"\([^"]*\)".*/\1/p')
+  if [ -n "$TOKEN" ]; then
+    curl -X DELETE "$BASE_URL/tasks/$i?confirm=$TOKEN" -H "$AUTH_HEADER" 2>/dev/null
+  fi
 done
 
 # Create labels
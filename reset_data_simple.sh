@@ -5,8 +5,12 @@ BASE_URL="http://localhost:8080/api"
 
 # Delete all existing tasks
 echo "Deleting existing tasks..."
+# Deletes are confirmed with the token of a dry run
 for i in {1..20}; do
-  curl -X DELETE "$BASE_URL/tasks/$i" 2>/dev/null
+  TOKEN=$(curl -s -X DELETE "$BASE_URL/tasks/$i?dry_run=true" | sed -n 's/.*"confirmation_token":"\([^"]*\)".*/\1/p')
+  if [ -n "$TOKEN" ]; then
+    curl -X DELETE "$BASE_URL/tasks/$i?confirm=$TOKEN" 2>/dev/null
+  fi
 done
 
 # Create tasks with full metadata